@@ -12,9 +12,11 @@ import (
 	"api-go-arquitetura/internal/api"
 	"api-go-arquitetura/internal/api/handlers"
 	"api-go-arquitetura/internal/api/middleware"
+	"api-go-arquitetura/internal/audit"
 	"api-go-arquitetura/internal/cache"
 	"api-go-arquitetura/internal/config"
 	"api-go-arquitetura/internal/database"
+	"api-go-arquitetura/internal/events"
 	"api-go-arquitetura/internal/logger"
 	"api-go-arquitetura/internal/metrics"
 	"api-go-arquitetura/internal/repository"
@@ -81,17 +83,73 @@ func main() {
 	if err := database.CreateIndexes(ctxIndex, client, cfg.Database, "produtos"); err != nil {
 		logger.WithField("error", err).Warn("Erro ao criar índices (continuando mesmo assim)")
 	}
+	if err := database.CreatePriceHistoryIndexes(ctxIndex, client, cfg.Database, repository.PriceHistoryCollection); err != nil {
+		logger.WithField("error", err).Warn("Erro ao criar índices do histórico de preços (continuando mesmo assim)")
+	}
+	if err := database.CreateAuditIndexes(ctxIndex, client, cfg.Database, audit.Collection); err != nil {
+		logger.WithField("error", err).Warn("Erro ao criar índices do log de auditoria (continuando mesmo assim)")
+	}
+
+	// Sincronizar contador de IDs com os produtos existentes
+	counterStore := repository.NewMongoCounterStore(client.Database(cfg.Database).Collection("counters"))
+	ctxCounter, cancelCounter := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancelCounter()
+	if err := repository.SyncCounterWithCollection(ctxCounter, counterStore, repository.ProdutoCounterName, col); err != nil {
+		logger.WithField("error", err).Fatal("Erro ao sincronizar contador de IDs")
+	}
 
 	// Criar repositório
-	prodRepo := repository.NewProdutoRepository(col)
+	idAllocator := repository.NewIDAllocator(counterStore, repository.ProdutoCounterName, cfg.IDBlockSize)
+	prodRepo := repository.NewProdutoRepositoryWithAllocator(col, idAllocator)
+
+	// Outbox de eventos de domínio: os eventos são gravados na transação de cada alteração
+	var outbox *events.MongoOutbox
+	if cfg.OutboxEnabled {
+		if err := database.CreateOutboxIndexes(ctxIndex, client, cfg.Database, events.CollectionOutbox); err != nil {
+			logger.WithField("error", err).Warn("Erro ao criar índices do outbox (continuando mesmo assim)")
+		}
+		outbox = events.NewMongoOutbox(client.Database(cfg.Database))
+		prodRepo = repository.NewProdutoRepositoryWithOutbox(col, idAllocator, outbox)
+	}
+
+	// Coleção e repositório de categorias
+	catCol, err := database.GetCollection(client, cfg.Database, "categorias")
+	if err != nil {
+		logger.WithField("error", err).Fatal("Erro ao obter coleção de categorias")
+	}
+	if err := database.CreateCategoriaIndexes(ctxIndex, client, cfg.Database, "categorias"); err != nil {
+		logger.WithField("error", err).Warn("Erro ao criar índices de categorias (continuando mesmo assim)")
+	}
+	if err := repository.SyncCounterWithCollection(ctxCounter, counterStore, repository.CategoriaCounterName, catCol); err != nil {
+		logger.WithField("error", err).Fatal("Erro ao sincronizar contador de IDs de categorias")
+	}
+	catRepo := repository.NewCategoriaRepositoryWithAllocator(catCol, repository.NewSequentialAllocator(counterStore, repository.CategoriaCounterName))
+
+	// Coleções e repositórios de webhooks
+	webhookCol, err := database.GetCollection(client, cfg.Database, "webhooks")
+	if err != nil {
+		logger.WithField("error", err).Fatal("Erro ao obter coleção de webhooks")
+	}
+	if err := database.CreateWebhookIndexes(ctxIndex, client, cfg.Database, "webhooks"); err != nil {
+		logger.WithField("error", err).Warn("Erro ao criar índices de webhooks (continuando mesmo assim)")
+	}
+	if err := database.CreateWebhookDeliveryIndexes(ctxIndex, client, cfg.Database, repository.WebhookDeliveryCollection); err != nil {
+		logger.WithField("error", err).Warn("Erro ao criar índices de entregas de webhooks (continuando mesmo assim)")
+	}
+	if err := repository.SyncCounterWithCollection(ctxCounter, counterStore, repository.WebhookCounterName, webhookCol); err != nil {
+		logger.WithField("error", err).Fatal("Erro ao sincronizar contador de IDs de webhooks")
+	}
+	webhookRepo := repository.NewWebhookRepositoryWithAllocator(webhookCol, repository.NewSequentialAllocator(counterStore, repository.WebhookCounterName))
+	deliveryRepo := repository.NewWebhookDeliveryRepository(client.Database(cfg.Database))
 
 	// Inicializar cache
 	var cacheInstance cache.Cache
-	if cfg.CacheType == "redis" {
+	switch cfg.CacheType {
+	case "redis":
 		redisCache, err := cache.NewRedisCache(cfg.RedisAddr, cfg.RedisPassword, cfg.RedisDB)
 		if err != nil {
 			logger.WithField("error", err).Warn("Erro ao conectar ao Redis, usando cache em memória")
-			cacheInstance = cache.NewMemoryCache()
+			cacheInstance = newMemoryCache(cfg)
 		} else {
 			cacheInstance = redisCache
 			logger.WithFields(map[string]interface{}{
@@ -99,16 +157,51 @@ func main() {
 				"addr": cfg.RedisAddr,
 			}).Info("Cache Redis inicializado")
 		}
-	} else {
-		cacheInstance = cache.NewMemoryCache()
-		logger.WithField("type", "memory").Info("Cache em memória inicializado")
+	case "layered":
+		layeredCache, err := cache.NewLayeredCache(cache.LayeredCacheOptions{
+			Addr:     cfg.RedisAddr,
+			Password: cfg.RedisPassword,
+			DB:       cfg.RedisDB,
+			Local:    localCacheOptions(cfg),
+			LocalTTL: cfg.CacheLocalTTL,
+		})
+		if err != nil {
+			logger.WithField("error", err).Warn("Erro ao conectar ao Redis, usando cache em memória")
+			cacheInstance = newMemoryCache(cfg)
+		} else {
+			cacheInstance = layeredCache
+			logger.WithFields(map[string]interface{}{
+				"type":      "layered",
+				"addr":      cfg.RedisAddr,
+				"local_ttl": cfg.CacheLocalTTL.String(),
+			}).Info("Cache em duas camadas (memória + Redis) inicializado")
+		}
+	default:
+		cacheInstance = newMemoryCache(cfg)
+		logger.WithFields(map[string]interface{}{
+			"type":        "memory",
+			"max_entries": cfg.CacheMaxEntries,
+			"max_bytes":   cfg.CacheMaxBytes,
+			"policy":      cfg.CacheEvictionPolicy,
+		}).Info("Cache em memória inicializado")
 	}
 
 	// Criar service e injetar o repositório e cache
-	prodService := service.NewProdutoServiceWithTTL(prodRepo, cacheInstance, cfg.CacheTTL)
+	auditSink := audit.NewMongoSink(client.Database(cfg.Database))
+	prodService := service.NewProdutoServiceWithOptions(prodRepo, cacheInstance, service.ProdutoServiceOptions{
+		Categorias: catRepo,
+		Auditor:    auditSink,
+		TTL:        cfg.CacheTTL,
+		StaleTTL:   cfg.CacheStaleTTL,
+	})
+	catService := service.NewCategoriaService(catRepo, prodRepo)
+	webhookService := service.NewWebhookService(webhookRepo, deliveryRepo)
 
-	// Criar handler e injetar o service
+	// Criar handlers e injetar os services
 	produtoHandler := handlers.NewProdutoHandler(prodService)
+	categoriaHandler := handlers.NewCategoriaHandler(catService)
+	auditHandler := handlers.NewAuditHandler(auditSink)
+	webhookHandler := handlers.NewWebhookHandler(webhookService)
 
 	// Criar health check handler com verificação de banco de dados
 	healthCheckFunc := func(ctx context.Context) error {
@@ -116,8 +209,35 @@ func main() {
 	}
 	healthCheckHandler := handlers.NewHealthCheckHandler(healthCheckFunc)
 
+	// Criar limpeza agendada da lixeira
+	trashPurger := service.NewTrashPurger(prodService, cfg.TrashRetention, cfg.PurgeInterval)
+	trashPurger.Start()
+	adminHandler := handlers.NewAdminHandler(trashPurger)
+
+	// Criar liberação agendada das reservas de estoque expiradas
+	reservationSweeper := service.NewReservationSweeper(prodService, cfg.ReservationSweepInterval)
+	reservationSweeper.Start()
+
+	// Criar relay que publica os eventos do outbox no publisher configurado e
+	// cria as entregas dos webhooks inscritos
+	var outboxRelay *service.OutboxRelay
+	if outbox != nil {
+		publisher := events.NewMultiPublisher(newOutboxPublisher(cfg), service.NewWebhookDispatcher(webhookRepo, deliveryRepo))
+		outboxRelay = service.NewOutboxRelay(outbox, publisher, cfg.OutboxRelayInterval)
+		outboxRelay.Start()
+	}
+
+	// Criar envio agendado das entregas de webhooks
+	webhookWorker := service.NewWebhookWorker(webhookRepo, deliveryRepo, cfg.WebhookDeliveryInterval, database.RetryOptions{
+		MaxAttempts:  cfg.WebhookMaxAttempts,
+		InitialDelay: cfg.WebhookRetryInitialDelay,
+		MaxDelay:     cfg.WebhookRetryMaxDelay,
+		Multiplier:   2.0,
+	})
+	webhookWorker.Start()
+
 	// Criar router e injetar os handlers
-	router := api.NewRouter(produtoHandler, healthCheckHandler)
+	router := api.NewRouter(produtoHandler, healthCheckHandler, adminHandler, categoriaHandler, auditHandler, webhookHandler)
 
 	// Rota do Swagger
 	router.PathPrefix("/swagger/").Handler(httpSwagger.WrapHandler)
@@ -128,6 +248,13 @@ func main() {
 	// Configurar CORS
 	middleware.SetCORSConfig(&cfg)
 
+	// Autor da auditoria: X-Actor só é verificado atrás de um gateway confiável
+	middleware.SetTrustActorHeader(cfg.TrustActorHeader)
+
+	// Configurar idempotência (POST /produtos) com um armazenamento próprio
+	idempotencyStore := newIdempotencyStore(cfg)
+	middleware.SetIdempotencyConfig(idempotencyStore, cfg.IdempotencyTTL)
+
 	// Aplicar middlewares
 	handler := middleware.ApplyMiddlewares(router)
 
@@ -168,8 +295,76 @@ func main() {
 		logger.WithField("error", err).Fatal("Erro ao encerrar servidor")
 	}
 
+	// Encerrar workers em background
+	trashPurger.Stop()
+	reservationSweeper.Stop()
+	if outboxRelay != nil {
+		outboxRelay.Stop()
+	}
+	webhookWorker.Stop()
+	if err := cacheInstance.Close(); err != nil {
+		logger.WithField("error", err).Warn("Erro ao encerrar cache")
+	}
+	if err := idempotencyStore.Close(); err != nil {
+		logger.WithField("error", err).Warn("Erro ao encerrar armazenamento de idempotência")
+	}
+
 	logger.Info("Servidor encerrado com sucesso")
 	
 	// Fazer shutdown do logger (flush final para Loki)
 	logger.Shutdown()
 }
+
+// newMemoryCache cria o cache em memória com os limites da configuração
+func newMemoryCache(cfg config.Config) cache.Cache {
+	return cache.NewMemoryCacheWithOptions(memoryCacheOptions(cfg))
+}
+
+// memoryCacheOptions retorna os limites do cache em memória (ou do L1) da configuração
+func memoryCacheOptions(cfg config.Config) cache.MemoryCacheOptions {
+	opts := cache.DefaultMemoryCacheOptions()
+	opts.MaxEntries = cfg.CacheMaxEntries
+	opts.MaxBytes = cfg.CacheMaxBytes
+	opts.Policy = cache.EvictionPolicy(cfg.CacheEvictionPolicy)
+	opts.Name = "app"
+	return opts
+}
+
+// localCacheOptions retorna os limites do L1 do cache em duas camadas, que tem
+// métricas próprias (cache="l1")
+func localCacheOptions(cfg config.Config) cache.MemoryCacheOptions {
+	opts := memoryCacheOptions(cfg)
+	opts.Name = "l1"
+	return opts
+}
+
+// newIdempotencyStore cria o armazenamento dos registros de idempotência, separado
+// do cache da aplicação: sob pressão, o cache descarta entradas, e um registro
+// descartado permitiria executar a mesma operação duas vezes
+// Com Redis (redis ou layered), os registros ficam apenas no Redis, sem L1; sem
+// Redis, em memória com limites próprios e sem remoção: quando cheio, novas chaves
+// são recusadas (503) em vez de descartar registros que ainda valem
+func newIdempotencyStore(cfg config.Config) cache.Cache {
+	if cfg.CacheType == "redis" || cfg.CacheType == "layered" {
+		store, err := cache.NewRedisCache(cfg.RedisAddr, cfg.RedisPassword, cfg.RedisDB)
+		if err == nil {
+			return store
+		}
+		logger.WithField("error", err).Warn("Erro ao conectar ao Redis, usando memória para os registros de idempotência")
+	}
+	return cache.NewMemoryCacheWithOptions(cache.MemoryCacheOptions{
+		Name:            "idempotency",
+		MaxEntries:      cfg.IdempotencyMaxEntries,
+		MaxBytes:        cfg.IdempotencyMaxBytes,
+		CleanupInterval: time.Minute,
+		NoEviction:      true,
+	})
+}
+
+// newOutboxPublisher cria o publisher dos eventos do outbox conforme a configuração
+func newOutboxPublisher(cfg config.Config) events.Publisher {
+	if cfg.OutboxPublisher == "http" {
+		return events.NewHTTPPublisher(cfg.OutboxWebhookURL, 0)
+	}
+	return events.NewLogPublisher()
+}
@@ -0,0 +1,69 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"api-go-arquitetura/internal/dto"
+	"api-go-arquitetura/internal/errors"
+	"api-go-arquitetura/internal/service"
+	"api-go-arquitetura/internal/utils"
+)
+
+// minPurgeRetention é a menor retenção aceita em olderThan sem force=true
+// Protege contra a remoção definitiva de toda a lixeira por engano
+const minPurgeRetention = time.Hour
+
+// AdminHandler gerencia as operações administrativas da API
+type AdminHandler struct {
+	purger *service.TrashPurger
+}
+
+// NewAdminHandler cria uma nova instância do AdminHandler
+func NewAdminHandler(purger *service.TrashPurger) *AdminHandler {
+	return &AdminHandler{
+		purger: purger,
+	}
+}
+
+// PurgeTrash remove permanentemente os produtos antigos da lixeira
+// @Summary Limpa a lixeira de produtos
+// @Description Remove permanentemente os produtos removidos há mais tempo que a retenção configurada
+// @Tags admin
+// @Produce json
+// @Param olderThan query string false "Retenção customizada (duração Go, ex: 72h; mínimo 1h sem force)"
+// @Param force query bool false "Aceita olderThan menor que 1h"
+// @Success 200 {object} dto.PurgeResponse
+// @Failure 400 {object} errors.APIError
+// @Failure 500 {object} errors.APIError
+// @Router /api/v1/admin/produtos/purge [post]
+// POST /api/v1/admin/produtos/purge?olderThan=72h
+// As rotas administrativas não têm autenticação própria: devem ser protegidas
+// (rede interna, gateway ou proxy autenticado)
+func (h *AdminHandler) PurgeTrash(w http.ResponseWriter, r *http.Request) {
+	retention := h.purger.Retention()
+	if value := r.URL.Query().Get("olderThan"); value != "" {
+		parsed, err := time.ParseDuration(value)
+		if err != nil || parsed < 0 {
+			utils.ErrorResponse(w, errors.ErrInvalidInput.WithDetailsf("olderThan inválido: %s", value))
+			return
+		}
+		if parsed < minPurgeRetention && r.URL.Query().Get("force") != "true" {
+			utils.ErrorResponse(w, errors.ErrInvalidInput.WithDetailsf("olderThan menor que %s requer force=true", minPurgeRetention))
+			return
+		}
+		retention = parsed
+	}
+
+	result, err := h.purger.RunWithRetention(r.Context(), service.PurgeTriggerManual, retention)
+	if err != nil {
+		utils.ErrorResponse(w, err)
+		return
+	}
+
+	utils.SuccessResponse(w, http.StatusOK, dto.PurgeResponse{
+		Purged:    result.Purged,
+		Retention: result.Retention.String(),
+		Cutoff:    result.Cutoff,
+	})
+}
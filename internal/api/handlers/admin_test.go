@@ -0,0 +1,80 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"api-go-arquitetura/internal/dto"
+	"api-go-arquitetura/internal/model"
+	"api-go-arquitetura/internal/service"
+)
+
+func TestAdminHandler_PurgeTrash(t *testing.T) {
+	mockService := NewMockProdutoService()
+	purger := service.NewTrashPurger(mockService, 24*time.Hour, 0)
+	handler := NewAdminHandler(purger)
+
+	created, _ := mockService.Create(context.Background(), model.Produto{Nome: "Notebook", Preco: 3500.00})
+	_ = mockService.Delete(context.Background(), created.ID, nil)
+
+	t.Run("deve manter produtos dentro da retenção", func(t *testing.T) {
+		req := httptest.NewRequest("POST", "/api/v1/admin/produtos/purge", nil)
+		w := httptest.NewRecorder()
+
+		handler.PurgeTrash(w, req)
+
+		if w.Code != http.StatusOK {
+			t.Fatalf("Status esperado %d, obtido %d", http.StatusOK, w.Code)
+		}
+		var response dto.PurgeResponse
+		if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+			t.Fatalf("Erro ao decodificar resposta: %v", err)
+		}
+		if response.Purged != 0 {
+			t.Errorf("Nenhum produto deveria ser removido, obtido %d", response.Purged)
+		}
+	})
+
+	t.Run("deve rejeitar olderThan menor que o mínimo sem force", func(t *testing.T) {
+		for _, value := range []string{"0s", "59m"} {
+			req := httptest.NewRequest("POST", "/api/v1/admin/produtos/purge?olderThan="+value, nil)
+			w := httptest.NewRecorder()
+
+			handler.PurgeTrash(w, req)
+
+			if w.Code != http.StatusBadRequest {
+				t.Errorf("olderThan=%s: status esperado %d, obtido %d", value, http.StatusBadRequest, w.Code)
+			}
+		}
+	})
+
+	t.Run("deve remover produtos com olderThan customizado e force", func(t *testing.T) {
+		req := httptest.NewRequest("POST", "/api/v1/admin/produtos/purge?olderThan=0s&force=true", nil)
+		w := httptest.NewRecorder()
+
+		handler.PurgeTrash(w, req)
+
+		var response dto.PurgeResponse
+		if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+			t.Fatalf("Erro ao decodificar resposta: %v", err)
+		}
+		if response.Purged != 1 {
+			t.Errorf("Esperado 1 produto removido, obtido %d", response.Purged)
+		}
+	})
+
+	t.Run("deve rejeitar olderThan inválido", func(t *testing.T) {
+		req := httptest.NewRequest("POST", "/api/v1/admin/produtos/purge?olderThan=ontem", nil)
+		w := httptest.NewRecorder()
+
+		handler.PurgeTrash(w, req)
+
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("Status esperado %d, obtido %d", http.StatusBadRequest, w.Code)
+		}
+	})
+}
@@ -0,0 +1,84 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"api-go-arquitetura/internal/audit"
+	"api-go-arquitetura/internal/dto"
+	"api-go-arquitetura/internal/errors"
+	"api-go-arquitetura/internal/utils"
+)
+
+// AuditHandler gerencia a consulta ao log de auditoria
+type AuditHandler struct {
+	sink audit.Sink
+}
+
+// NewAuditHandler cria uma nova instância do AuditHandler
+func NewAuditHandler(sink audit.Sink) *AuditHandler {
+	return &AuditHandler{
+		sink: sink,
+	}
+}
+
+// GetAuditLog lista os registros de auditoria de uma entidade
+// @Summary Consulta o log de auditoria
+// @Description Retorna as alterações registradas (antes/depois de cada campo), com autor (X-Actor de um gateway confiável), autor declarado pelo cliente (claimedActor) e request ID, da mais recente para a mais antiga
+// @Tags audit
+// @Produce json
+// @Param entity query string true "Entidade (ex: produto)"
+// @Param id query int false "ID da entidade"
+// @Param action query string false "Ação (create, update, patch, delete, restore)"
+// @Param actor query string false "Autor da alteração"
+// @Param page query int false "Número da página (padrão: 1)" default(1)
+// @Param pageSize query int false "Tamanho da página (padrão: 10, máximo: 100)" default(10)
+// @Success 200 {object} dto.AuditLogResponse
+// @Failure 400 {object} errors.APIError
+// @Failure 500 {object} errors.APIError
+// @Router /api/v1/audit [get]
+// GET /api/v1/audit?entity=produto&id=1&page=1&pageSize=10
+func (h *AuditHandler) GetAuditLog(w http.ResponseWriter, r *http.Request) {
+	values := r.URL.Query()
+	query := audit.Query{
+		Entity: values.Get("entity"),
+		Action: values.Get("action"),
+		Actor:  values.Get("actor"),
+	}
+	if query.Entity == "" {
+		utils.ErrorResponse(w, errors.ErrInvalidInput.WithDetails("entity é obrigatório"))
+		return
+	}
+	if value := values.Get("id"); value != "" {
+		id, err := strconv.Atoi(value)
+		if err != nil || id <= 0 {
+			utils.ErrorResponse(w, errors.ErrInvalidID)
+			return
+		}
+		query.EntityID = id
+	}
+
+	pagination := dto.PaginationRequest{
+		Page:     getIntQuery(r, "page", 1),
+		PageSize: getIntQuery(r, "pageSize", 10),
+	}
+	pagination.Validate()
+	query.Skip, query.Limit = pagination.GetSkip(), pagination.GetLimit()
+
+	ctx := r.Context()
+	total, err := h.sink.Count(ctx, query)
+	if err != nil {
+		utils.ErrorResponse(w, errors.WrapError(err, errors.ErrDatabase))
+		return
+	}
+	entries, err := h.sink.Find(ctx, query)
+	if err != nil {
+		utils.ErrorResponse(w, errors.WrapError(err, errors.ErrDatabase))
+		return
+	}
+
+	utils.SuccessResponse(w, http.StatusOK, dto.AuditLogResponse{
+		Entries:    entries,
+		Pagination: dto.NewPaginationResponse(pagination.Page, pagination.PageSize, int(total)),
+	})
+}
@@ -0,0 +1,93 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"api-go-arquitetura/internal/api/middleware"
+	"api-go-arquitetura/internal/audit"
+	"api-go-arquitetura/internal/dto"
+	"api-go-arquitetura/internal/utils"
+)
+
+func TestAuditHandler_GetAuditLog(t *testing.T) {
+	sink := audit.NewMemorySink()
+	ctx := context.Background()
+	_ = sink.Record(ctx, audit.Entry{ID: "1", Entity: audit.EntityProduto, EntityID: 1, Action: audit.ActionCreate, Actor: "maria"})
+	_ = sink.Record(ctx, audit.Entry{ID: "2", Entity: audit.EntityProduto, EntityID: 2, Action: audit.ActionCreate, Actor: "joao"})
+	_ = sink.Record(ctx, audit.Entry{ID: "3", Entity: audit.EntityProduto, EntityID: 1, Action: audit.ActionUpdate, Actor: "joao",
+		Changes: map[string]audit.Change{"preco": {Before: 10.0, After: 12.0}}})
+	handler := NewAuditHandler(sink)
+
+	get := func(query string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		handler.GetAuditLog(w, httptest.NewRequest("GET", "/api/v1/audit?"+query, nil))
+		return w
+	}
+
+	t.Run("deve filtrar por entidade e ID, mais recentes primeiro", func(t *testing.T) {
+		w := get("entity=produto&id=1")
+		if w.Code != http.StatusOK {
+			t.Fatalf("Status esperado %d, obtido %d: %s", http.StatusOK, w.Code, w.Body.String())
+		}
+		var response dto.AuditLogResponse
+		if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+			t.Fatalf("Erro ao decodificar resposta: %v", err)
+		}
+		if response.Pagination.TotalItems != 2 || len(response.Entries) != 2 {
+			t.Fatalf("Esperados 2 registros, obtidos %d", len(response.Entries))
+		}
+		if response.Entries[0].ID != "3" || response.Entries[0].Changes["preco"].After != 12.0 {
+			t.Errorf("Registro mais recente incorreto: %+v", response.Entries[0])
+		}
+	})
+
+	t.Run("deve filtrar por autor e paginar", func(t *testing.T) {
+		var response dto.AuditLogResponse
+		_ = json.NewDecoder(get("entity=produto&actor=joao&pageSize=1&page=2").Body).Decode(&response)
+		if response.Pagination.TotalItems != 2 || len(response.Entries) != 1 || response.Entries[0].ID != "2" {
+			t.Errorf("Página incorreta: %+v", response)
+		}
+	})
+
+	t.Run("deve validar os parâmetros", func(t *testing.T) {
+		for _, query := range []string{"id=1", "entity=produto&id=abc"} {
+			if w := get(query); w.Code != http.StatusBadRequest {
+				t.Errorf("%s: status esperado %d, obtido %d", query, http.StatusBadRequest, w.Code)
+			}
+		}
+	})
+}
+
+func TestRequestContext(t *testing.T) {
+	var actor, claimed, requestID string
+	h := middleware.RequestIDMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := requestContext(r)
+		actor, claimed, requestID = utils.ActorFromContext(ctx), utils.ClaimedActorFromContext(ctx), utils.RequestIDFromContext(ctx)
+	}))
+	serve := func() {
+		req := httptest.NewRequest("POST", "/api/v1/produtos", nil)
+		req.Header.Set(middleware.ActorHeader, "maria")
+		req.Header.Set(middleware.RequestIDHeader, "req-1")
+		h.ServeHTTP(httptest.NewRecorder(), req)
+	}
+
+	t.Run("sem gateway confiável X-Actor é apenas declarado", func(t *testing.T) {
+		serve()
+		if actor != "" || claimed != "maria" || requestID != "req-1" {
+			t.Errorf("Esperado autor declarado maria e request ID req-1, obtido %q, %q e %q", actor, claimed, requestID)
+		}
+	})
+
+	t.Run("com gateway confiável X-Actor é o autor", func(t *testing.T) {
+		middleware.SetTrustActorHeader(true)
+		defer middleware.SetTrustActorHeader(false)
+		serve()
+		if actor != "maria" || claimed != "" {
+			t.Errorf("Esperado autor maria sem autor declarado, obtido %q e %q", actor, claimed)
+		}
+	})
+}
@@ -0,0 +1,180 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+
+	"api-go-arquitetura/internal/dto"
+	"api-go-arquitetura/internal/errors"
+	"api-go-arquitetura/internal/service"
+	"api-go-arquitetura/internal/utils"
+	"api-go-arquitetura/internal/validator"
+)
+
+// CategoriaHandler gerencia os handlers de categoria
+type CategoriaHandler struct {
+	service service.CategoriaService
+}
+
+// NewCategoriaHandler cria uma nova instância do CategoriaHandler
+func NewCategoriaHandler(svc service.CategoriaService) *CategoriaHandler {
+	return &CategoriaHandler{
+		service: svc,
+	}
+}
+
+// GetCategorias lista todas as categorias, ordenadas por path
+// @Summary Lista categorias
+// @Tags categorias
+// @Produce json
+// @Success 200 {object} dto.CategoriaListResponse
+// @Failure 500 {object} errors.APIError
+// @Router /api/v1/categorias [get]
+// GET /api/v1/categorias
+func (h *CategoriaHandler) GetCategorias(w http.ResponseWriter, r *http.Request) {
+	categorias, err := h.service.FindAll(r.Context())
+	if err != nil {
+		utils.ErrorResponse(w, err)
+		return
+	}
+	utils.SuccessResponse(w, http.StatusOK, dto.ToCategoriaListResponse(categorias))
+}
+
+// GetCategoriasTree retorna as categorias como árvore
+// @Summary Árvore de categorias
+// @Tags categorias
+// @Produce json
+// @Success 200 {array} dto.CategoriaTreeNode
+// @Failure 500 {object} errors.APIError
+// @Router /api/v1/categorias/tree [get]
+// GET /api/v1/categorias/tree
+func (h *CategoriaHandler) GetCategoriasTree(w http.ResponseWriter, r *http.Request) {
+	categorias, err := h.service.FindAll(r.Context())
+	if err != nil {
+		utils.ErrorResponse(w, err)
+		return
+	}
+	utils.SuccessResponse(w, http.StatusOK, dto.ToCategoriaTree(categorias))
+}
+
+// GetCategoria obtém uma categoria por ID
+// @Summary Obtém uma categoria
+// @Tags categorias
+// @Produce json
+// @Param id path int true "ID da categoria"
+// @Success 200 {object} dto.CategoriaResponse
+// @Failure 400 {object} errors.APIError
+// @Failure 404 {object} errors.APIError
+// @Router /api/v1/categorias/{id} [get]
+// GET /api/v1/categorias/{id}
+func (h *CategoriaHandler) GetCategoria(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		utils.ErrorResponse(w, errors.ErrInvalidID)
+		return
+	}
+
+	categoria, err := h.service.FindByID(r.Context(), id)
+	if err != nil {
+		utils.ErrorResponse(w, err)
+		return
+	}
+	utils.SuccessResponse(w, http.StatusOK, dto.FromCategoria(categoria))
+}
+
+// CreateCategoria cria uma nova categoria
+// @Summary Cria uma categoria
+// @Description O slug é gerado a partir do nome quando omitido; parentId posiciona a categoria na árvore
+// @Tags categorias
+// @Accept json
+// @Produce json
+// @Param categoria body dto.CreateCategoriaRequest true "Dados da categoria"
+// @Success 201 {object} dto.CategoriaResponse
+// @Failure 400 {object} errors.APIError
+// @Failure 409 {object} errors.APIError
+// @Failure 422 {object} errors.APIError
+// @Router /api/v1/categorias [post]
+// POST /api/v1/categorias
+func (h *CategoriaHandler) CreateCategoria(w http.ResponseWriter, r *http.Request) {
+	var request dto.CreateCategoriaRequest
+	if err := utils.DecodeJSON(r.Body, &request); err != nil {
+		utils.BadRequestResponse(w, "Erro ao decodificar JSON: "+err.Error())
+		return
+	}
+	if validationErrors := validator.Validate(&request); len(validationErrors) > 0 {
+		utils.ValidationErrorResponse(w, validationErrors)
+		return
+	}
+
+	created, err := h.service.Create(r.Context(), request.ToModel())
+	if err != nil {
+		utils.ErrorResponse(w, err)
+		return
+	}
+	utils.SuccessResponse(w, http.StatusCreated, dto.FromCategoria(created))
+}
+
+// UpdateCategoria atualiza uma categoria; alterar parentId move suas subcategorias junto
+// @Summary Atualiza uma categoria
+// @Tags categorias
+// @Accept json
+// @Produce json
+// @Param id path int true "ID da categoria"
+// @Param categoria body dto.UpdateCategoriaRequest true "Dados da categoria"
+// @Success 200 {object} dto.CategoriaResponse
+// @Failure 400 {object} errors.APIError
+// @Failure 404 {object} errors.APIError
+// @Failure 409 {object} errors.APIError
+// @Failure 422 {object} errors.APIError
+// @Router /api/v1/categorias/{id} [put]
+// PUT /api/v1/categorias/{id}
+func (h *CategoriaHandler) UpdateCategoria(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		utils.ErrorResponse(w, errors.ErrInvalidID)
+		return
+	}
+
+	var request dto.UpdateCategoriaRequest
+	if err := utils.DecodeJSON(r.Body, &request); err != nil {
+		utils.BadRequestResponse(w, "Erro ao decodificar JSON: "+err.Error())
+		return
+	}
+	if validationErrors := validator.Validate(&request); len(validationErrors) > 0 {
+		utils.ValidationErrorResponse(w, validationErrors)
+		return
+	}
+
+	updated, err := h.service.Update(r.Context(), id, request.ToModel())
+	if err != nil {
+		utils.ErrorResponse(w, err)
+		return
+	}
+	utils.SuccessResponse(w, http.StatusOK, dto.FromCategoria(updated))
+}
+
+// DeleteCategoria remove uma categoria sem subcategorias e sem produtos
+// @Summary Remove uma categoria
+// @Tags categorias
+// @Param id path int true "ID da categoria"
+// @Success 204 "Removida"
+// @Failure 400 {object} errors.APIError
+// @Failure 404 {object} errors.APIError
+// @Failure 409 {object} errors.APIError
+// @Router /api/v1/categorias/{id} [delete]
+// DELETE /api/v1/categorias/{id}
+func (h *CategoriaHandler) DeleteCategoria(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		utils.ErrorResponse(w, errors.ErrInvalidID)
+		return
+	}
+
+	if err := h.service.Delete(r.Context(), id); err != nil {
+		utils.ErrorResponse(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
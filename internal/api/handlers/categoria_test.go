@@ -0,0 +1,156 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+
+	"api-go-arquitetura/internal/dto"
+	"api-go-arquitetura/internal/errors"
+	"api-go-arquitetura/internal/model"
+)
+
+// MockCategoriaService é um mock do CategoriaService para testes
+type MockCategoriaService struct {
+	categorias []model.Categoria
+	nextID     int
+}
+
+func NewMockCategoriaService() *MockCategoriaService {
+	return &MockCategoriaService{nextID: 1}
+}
+
+func (m *MockCategoriaService) Create(ctx context.Context, categoria model.Categoria) (model.Categoria, error) {
+	var parent *model.Categoria
+	if categoria.ParentID != 0 {
+		p, err := m.FindByID(ctx, categoria.ParentID)
+		if err != nil {
+			return model.Categoria{}, errors.ErrCategoriaInvalida
+		}
+		parent = &p
+	}
+	if categoria.Slug == "" {
+		categoria.Slug = categoria.Nome
+	}
+	categoria.ID = m.nextID
+	categoria.PlaceUnder(parent)
+	m.nextID++
+	m.categorias = append(m.categorias, categoria)
+	return categoria, nil
+}
+
+func (m *MockCategoriaService) FindByID(ctx context.Context, id int) (model.Categoria, error) {
+	for _, c := range m.categorias {
+		if c.ID == id {
+			return c, nil
+		}
+	}
+	return model.Categoria{}, errors.ErrCategoriaNotFound
+}
+
+func (m *MockCategoriaService) FindAll(ctx context.Context) ([]model.Categoria, error) {
+	return m.categorias, nil
+}
+
+func (m *MockCategoriaService) Update(ctx context.Context, id int, categoria model.Categoria) (model.Categoria, error) {
+	for i, c := range m.categorias {
+		if c.ID == id {
+			categoria.ID = id
+			categoria.Slug = c.Slug
+			categoria.PlaceUnder(nil)
+			m.categorias[i] = categoria
+			return categoria, nil
+		}
+	}
+	return model.Categoria{}, errors.ErrCategoriaNotFound
+}
+
+func (m *MockCategoriaService) Delete(ctx context.Context, id int) error {
+	for _, c := range m.categorias {
+		if c.ParentID == id {
+			return errors.ErrCategoriaEmUso
+		}
+	}
+	for i, c := range m.categorias {
+		if c.ID == id {
+			m.categorias = append(m.categorias[:i], m.categorias[i+1:]...)
+			return nil
+		}
+	}
+	return errors.ErrCategoriaNotFound
+}
+
+func newCategoriaRouter(handler *CategoriaHandler) *mux.Router {
+	router := mux.NewRouter()
+	router.HandleFunc("/api/v1/categorias", handler.GetCategorias).Methods("GET")
+	router.HandleFunc("/api/v1/categorias/tree", handler.GetCategoriasTree).Methods("GET")
+	router.HandleFunc("/api/v1/categorias/{id}", handler.GetCategoria).Methods("GET")
+	router.HandleFunc("/api/v1/categorias", handler.CreateCategoria).Methods("POST")
+	router.HandleFunc("/api/v1/categorias/{id}", handler.UpdateCategoria).Methods("PUT")
+	router.HandleFunc("/api/v1/categorias/{id}", handler.DeleteCategoria).Methods("DELETE")
+	return router
+}
+
+func TestCategoriaHandler(t *testing.T) {
+	router := newCategoriaRouter(NewCategoriaHandler(NewMockCategoriaService()))
+
+	do := func(method, path, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("deve criar categorias e montar a árvore", func(t *testing.T) {
+		if w := do("POST", "/api/v1/categorias", `{"nome":"eletronicos"}`); w.Code != http.StatusCreated {
+			t.Fatalf("Status esperado %d, obtido %d: %s", http.StatusCreated, w.Code, w.Body.String())
+		}
+		w := do("POST", "/api/v1/categorias", `{"nome":"notebooks","parentId":1}`)
+		if w.Code != http.StatusCreated {
+			t.Fatalf("Status esperado %d, obtido %d: %s", http.StatusCreated, w.Code, w.Body.String())
+		}
+		var created dto.CategoriaResponse
+		if err := json.NewDecoder(w.Body).Decode(&created); err != nil {
+			t.Fatalf("Erro ao decodificar resposta: %v", err)
+		}
+		if created.Path != "eletronicos/notebooks" || created.ParentID != 1 {
+			t.Errorf("Categoria incorreta: %+v", created)
+		}
+
+		w = do("GET", "/api/v1/categorias/tree", "")
+		var tree []dto.CategoriaTreeNode
+		if err := json.NewDecoder(w.Body).Decode(&tree); err != nil {
+			t.Fatalf("Erro ao decodificar resposta: %v", err)
+		}
+		if len(tree) != 1 || len(tree[0].Subcategorias) != 1 || tree[0].Subcategorias[0].Slug != "notebooks" {
+			t.Errorf("Árvore incorreta: %+v", tree)
+		}
+	})
+
+	t.Run("deve validar a requisição", func(t *testing.T) {
+		if w := do("POST", "/api/v1/categorias", `{"slug":"sem-nome"}`); w.Code != http.StatusUnprocessableEntity {
+			t.Errorf("Status esperado %d, obtido %d", http.StatusUnprocessableEntity, w.Code)
+		}
+		if w := do("POST", "/api/v1/categorias", `{"nome":"orfa","parentId":99}`); w.Code != http.StatusUnprocessableEntity {
+			t.Errorf("Status esperado %d, obtido %d", http.StatusUnprocessableEntity, w.Code)
+		}
+	})
+
+	t.Run("deve retornar 404 e 409", func(t *testing.T) {
+		if w := do("GET", "/api/v1/categorias/99", ""); w.Code != http.StatusNotFound {
+			t.Errorf("Status esperado %d, obtido %d", http.StatusNotFound, w.Code)
+		}
+		if w := do("DELETE", "/api/v1/categorias/1", ""); w.Code != http.StatusConflict {
+			t.Errorf("Status esperado %d, obtido %d", http.StatusConflict, w.Code)
+		}
+		if w := do("DELETE", "/api/v1/categorias/2", ""); w.Code != http.StatusNoContent {
+			t.Errorf("Status esperado %d, obtido %d", http.StatusNoContent, w.Code)
+		}
+	})
+}
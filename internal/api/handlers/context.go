@@ -0,0 +1,19 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+
+	"api-go-arquitetura/internal/api/middleware"
+	"api-go-arquitetura/internal/utils"
+)
+
+// requestContext retorna o contexto da requisição com o request ID e o autor
+// (X-Actor) disponíveis para as camadas de serviço e repositório, que os
+// registram no histórico de preços e no log de auditoria. Sem gateway confiável,
+// o autor informado pelo cliente é guardado apenas como autor declarado
+func requestContext(r *http.Request) context.Context {
+	ctx := utils.WithRequestID(r.Context(), middleware.GetRequestID(r))
+	ctx = utils.WithClaimedActor(ctx, middleware.GetClaimedActor(r))
+	return utils.WithActor(ctx, middleware.GetActor(r))
+}
@@ -0,0 +1,58 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"api-go-arquitetura/internal/errors"
+)
+
+// formatETag gera o ETag (forte) de um produto a partir da sua versão
+func formatETag(version int) string {
+	return fmt.Sprintf(`"%d"`, version)
+}
+
+// formatFieldsETag gera o ETag de uma representação parcial (?fields=) do produto
+// Difere do ETag completo para que caches não confundam as representações;
+// por isso não é aceito em If-Match
+func formatFieldsETag(version int, fields []string) string {
+	if len(fields) == 0 {
+		return formatETag(version)
+	}
+	return fmt.Sprintf(`"%d-%s"`, version, strings.Join(fields, "."))
+}
+
+// parseIfMatch extrai a versão esperada do header If-Match
+// Retorna nil quando o header está ausente ou é "*" (qualquer versão); "0" é a
+// versão dos produtos criados antes do campo version
+// Um header que não pode ser interpretado resulta em 400; um ETag que nunca
+// corresponde a uma versão (fraco, lista ou representação parcial), em 412
+func parseIfMatch(r *http.Request) (*int, error) {
+	value := strings.TrimSpace(r.Header.Get("If-Match"))
+	if value == "" || value == "*" {
+		return nil, nil
+	}
+
+	// If-Match usa comparação forte: ETags fracos (W/) nunca correspondem
+	if strings.HasPrefix(value, "W/") || strings.Contains(value, ",") {
+		return nil, errors.ErrPreconditionFailed.WithDetailsf("If-Match não suportado: %s", value)
+	}
+
+	if len(value) < 2 || !strings.HasPrefix(value, `"`) || !strings.HasSuffix(value, `"`) {
+		return nil, errors.ErrInvalidInput.WithDetailsf("If-Match malformado: %s", value)
+	}
+	tag := value[1 : len(value)-1]
+	version, err := strconv.Atoi(tag)
+	if err != nil {
+		// ETag de representação parcial ("<versão>-<campos>"): bem formado, mas nunca corresponde
+		if prefix, _, found := strings.Cut(tag, "-"); found {
+			if _, err := strconv.Atoi(prefix); err == nil {
+				return nil, errors.ErrPreconditionFailed.WithDetailsf("If-Match não corresponde a uma versão: %s", value)
+			}
+		}
+		return nil, errors.ErrInvalidInput.WithDetailsf("If-Match malformado: %s", value)
+	}
+	return &version, nil
+}
@@ -2,6 +2,7 @@ package handlers
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"strconv"
@@ -29,6 +30,9 @@ func NewProdutoHandler(svc service.ProdutoService) *ProdutoHandler {
 }
 
 // GetProdutos lista todos os produtos (com suporte a paginação, filtros e ordenação)
+// Emite o ETag do conteúdo e responde 304 Not Modified para If-None-Match correspondente
+// Não emite Last-Modified: o maior UpdatedAt da página não muda quando um produto
+// sai do resultado (exclusão ou filtro), e o 304 devolveria uma lista desatualizada
 // @Summary Lista produtos com paginação, filtros e ordenação
 // @Description Retorna uma lista paginada de produtos com suporte a filtros e ordenação
 // @Tags produtos
@@ -40,9 +44,20 @@ func NewProdutoHandler(svc service.ProdutoService) *ProdutoHandler {
 // @Param precoMin query number false "Preço mínimo"
 // @Param precoMax query number false "Preço máximo"
 // @Param descricao query string false "Filtro por descrição (busca parcial, case-insensitive)"
-// @Param sort query string false "Campo para ordenação (id, nome, preco, descricao, created_at, updated_at)" default(id)
+// @Param q query string false "Busca textual em nome e descrição (com stemming em português)"
+// @Param filter query string false "Expressão de filtro campo:operador:valor separada por vírgulas (ex.: preco:gte:100,nome:startswith:Note)"
+// @Param categoria query int false "Filtro por ID de categoria"
+// @Param subcategorias query bool false "Com categoria, inclui os produtos das subcategorias" default(false)
+// @Param facets query string false "Facetas incluídas na resposta (stats, preco, tags, categoria)"
+// @Param fields query string false "Campos retornados, separados por vírgula (id, nome, preco, descricao, tags, categoriaId, estoque, reservado, version); id é sempre incluído"
+// @Param buckets query int false "Número de faixas do histograma de preço (máximo 50)" default(10)
+// @Param sort query string false "Campo para ordenação (id, nome, preco, descricao, created_at, updated_at, score); com q, o padrão é score" default(id)
 // @Param order query string false "Ordem de ordenação (asc, desc)" default(asc)
+// @Param cursor query string false "Paginação por cursor: vazio para a primeira página, depois o nextCursor da resposta anterior (não combinar com page)"
+// @Param If-None-Match header string false "ETag obtido em uma resposta anterior"
 // @Success 200 {object} dto.PaginatedResponse
+// @Success 200 {object} dto.CursorProdutoListResponse
+// @Success 304 "Não modificado"
 // @Failure 400 {object} errors.APIError
 // @Failure 500 {object} errors.APIError
 // @Router /api/v1/produtos [get]
@@ -56,19 +71,14 @@ func (h *ProdutoHandler) GetProdutos(w http.ResponseWriter, r *http.Request) {
 		PageSize: getIntQuery(r, "pageSize", 10),
 	}
 
-	// Parse de filtros
-	filter := dto.FilterRequest{
-		Nome:      getStringQuery(r, "nome"),
-		PrecoMin:  getFloatQuery(r, "precoMin"),
-		PrecoMax:  getFloatQuery(r, "precoMax"),
-		Descricao: getStringQuery(r, "descricao"),
+	// Parse de filtros, ordenação e campos
+	filter := getFilterQuery(r)
+	sort := getSortQuery(r)
+	fields, err := dto.ParseFields(r.URL.Query().Get("fields"))
+	if err != nil {
+		utils.ErrorResponse(w, errors.ErrInvalidInput.WithDetails(err.Error()))
+		return
 	}
-
-	// Parse de ordenação
-	sort := dto.GetSortFromQuery(
-		r.URL.Query().Get("sort"),
-		r.URL.Query().Get("order"),
-	)
 	
 	// Validar ordenação
 	if validationErrors := validator.Validate(&sort); len(validationErrors) > 0 {
@@ -76,10 +86,21 @@ func (h *ProdutoHandler) GetProdutos(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Paginação por cursor: ?cursor= (vazio) inicia na primeira página e cada
+	// resposta traz o nextCursor da seguinte
+	if r.URL.Query().Has("cursor") {
+		if r.URL.Query().Get("page") != "" {
+			utils.ErrorResponse(w, errors.ErrInvalidInput.WithDetails("use page ou cursor, não ambos"))
+			return
+		}
+		h.getProdutosByCursor(w, r, filter, sort, fields)
+		return
+	}
+
 	// Se não há filtros e paginação padrão, usar método antigo para compatibilidade
 	if filter.IsEmpty() && pagination.Page == 1 && pagination.PageSize == 10 && sort.Field == "" {
 		// Verificar se há parâmetros de query explícitos
-		if r.URL.Query().Get("page") == "" && r.URL.Query().Get("pageSize") == "" && r.URL.Query().Get("sort") == "" {
+		if r.URL.Query().Get("page") == "" && r.URL.Query().Get("pageSize") == "" && r.URL.Query().Get("sort") == "" && r.URL.Query().Get("facets") == "" && len(fields) == 0 {
 			// Usar método antigo (sem paginação)
 			produtos, err := h.service.FindAll(ctx)
 			if err != nil {
@@ -87,25 +108,117 @@ func (h *ProdutoHandler) GetProdutos(w http.ResponseWriter, r *http.Request) {
 				return
 			}
 			response := dto.ToProdutoListResponse(produtos)
+			body, err := json.Marshal(response)
+			if err != nil {
+				utils.ErrorResponse(w, errors.WrapError(err, errors.ErrInternalServer))
+				return
+			}
+			etag := utils.ContentETag(body)
+			utils.SetCacheValidators(w, etag, time.Time{})
+			if utils.NotModified(w, r, etag, time.Time{}) {
+				return
+			}
 			utils.SuccessResponse(w, http.StatusOK, response)
 			return
 		}
 	}
 
 	// Usar método paginado
-	produtos, paginationResp, err := h.service.FindAllPaginated(ctx, pagination, filter, sort)
+	page, err := h.service.FindAllPaginated(ctx, pagination, filter, sort, fields...)
 	if err != nil {
-		utils.ErrorResponse(w, errors.WrapError(err, errors.ErrDatabase))
+		if errors.IsAPIError(err) {
+			utils.ErrorResponse(w, err)
+		} else {
+			utils.ErrorResponse(w, errors.WrapError(err, errors.ErrDatabase))
+		}
+		return
+	}
+
+	// Facetas opcionais (?facets=), calculadas sobre todo o resultado filtrado
+	facets, err := h.listFacets(r, filter)
+	if err != nil {
+		utils.ErrorResponse(w, err)
+		return
+	}
+	etag, err := facetsETag(page.ETag, facets)
+	if err != nil {
+		utils.ErrorResponse(w, errors.WrapError(err, errors.ErrInternalServer))
+		return
+	}
+
+	// Validadores derivados do conteúdo da página em cache
+	utils.SetCacheValidators(w, etag, time.Time{})
+	if utils.NotModified(w, r, etag, time.Time{}) {
 		return
 	}
 
 	// Converter models para DTOs
-	produtosDTO := dto.FromModelList(produtos)
-	response := dto.ToPaginatedResponse(produtosDTO, paginationResp)
+	produtosDTO := dto.SelectFields(dto.FromModelList(page.Produtos), fields)
+	response := dto.ToPaginatedResponse(produtosDTO, page.Pagination)
+	response.Facets = facets
 
 	utils.SuccessResponse(w, http.StatusOK, response)
 }
 
+// getProdutosByCursor lista produtos no modo de paginação por cursor (keyset)
+func (h *ProdutoHandler) getProdutosByCursor(w http.ResponseWriter, r *http.Request, filter dto.FilterRequest, sort dto.SortRequest, fields []string) {
+	pagination := dto.CursorPaginationRequest{
+		Cursor:   r.URL.Query().Get("cursor"),
+		PageSize: getIntQuery(r, "pageSize", 10),
+	}
+
+	page, err := h.service.FindAllByCursor(r.Context(), pagination, filter, sort, fields...)
+	if err != nil {
+		utils.ErrorResponse(w, err)
+		return
+	}
+
+	facets, err := h.listFacets(r, filter)
+	if err != nil {
+		utils.ErrorResponse(w, err)
+		return
+	}
+	etag, err := facetsETag(page.ETag, facets)
+	if err != nil {
+		utils.ErrorResponse(w, errors.WrapError(err, errors.ErrInternalServer))
+		return
+	}
+
+	utils.SetCacheValidators(w, etag, time.Time{})
+	if utils.NotModified(w, r, etag, time.Time{}) {
+		return
+	}
+
+	utils.SuccessResponse(w, http.StatusOK, dto.CursorProdutoListResponse{
+		Produtos:   dto.SelectFields(dto.FromModelList(page.Produtos), fields),
+		Pagination: page.Pagination,
+		Facets:     facets,
+	})
+}
+
+// getFilterQuery obtém os filtros da listagem a partir da query string
+func getFilterQuery(r *http.Request) dto.FilterRequest {
+	return dto.FilterRequest{
+		Nome:      getStringQuery(r, "nome"),
+		PrecoMin:  getFloatQuery(r, "precoMin"),
+		PrecoMax:  getFloatQuery(r, "precoMax"),
+		Descricao: getStringQuery(r, "descricao"),
+		Q:         getStringQuery(r, "q"),
+		Filter:    getStringQuery(r, "filter"),
+		Categoria: getIntPtrQuery(r, "categoria"),
+		// Subcategorias inválido é tratado como false, como os demais filtros
+		Subcategorias: r.URL.Query().Get("subcategorias") == "true",
+	}
+}
+
+// getSortQuery obtém a ordenação da listagem a partir da query string
+func getSortQuery(r *http.Request) dto.SortRequest {
+	return dto.GetSortFromQuery(
+		r.URL.Query().Get("sort"),
+		r.URL.Query().Get("order"),
+	)
+}
+
 // getIntQuery obtém um parâmetro de query como int
 func getIntQuery(r *http.Request, key string, defaultValue int) int {
 	value := r.URL.Query().Get(key)
@@ -119,6 +232,19 @@ func getIntQuery(r *http.Request, key string, defaultValue int) int {
 	return result
 }
 
+// getIntPtrQuery obtém um parâmetro de query como int (retorna nil se vazio ou inválido)
+func getIntPtrQuery(r *http.Request, key string) *int {
+	value := r.URL.Query().Get(key)
+	if value == "" {
+		return nil
+	}
+	result, err := strconv.Atoi(value)
+	if err != nil {
+		return nil
+	}
+	return &result
+}
+
 // getStringQuery obtém um parâmetro de query como string (retorna nil se vazio)
 func getStringQuery(r *http.Request, key string) *string {
 	value := r.URL.Query().Get(key)
@@ -141,7 +267,24 @@ func getFloatQuery(r *http.Request, key string) *float64 {
 	return &result
 }
 
+// getBoolQuery obtém um parâmetro de query como bool
+// Retorna INVALID_INPUT se o valor não for um booleano válido
+func getBoolQuery(r *http.Request, key string, defaultValue bool) (bool, error) {
+	value := r.URL.Query().Get(key)
+	if value == "" {
+		return defaultValue, nil
+	}
+	result, err := strconv.ParseBool(value)
+	if err != nil {
+		return defaultValue, errors.ErrInvalidInput.WithDetailsf("%s deve ser true ou false", key)
+	}
+	return result, nil
+}
+
 // GetProduto obtém um produto por ID
+// Retorna a versão do produto no header ETag e UpdatedAt em Last-Modified,
+// respondendo 304 Not Modified para If-None-Match / If-Modified-Since correspondentes
+// Com ?fields=id,nome,preco, apenas os campos pedidos são lidos e retornados
 // GET /api/produtos/{id}
 func (h *ProdutoHandler) GetProduto(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
@@ -151,8 +294,14 @@ func (h *ProdutoHandler) GetProduto(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	fields, err := dto.ParseFields(r.URL.Query().Get("fields"))
+	if err != nil {
+		utils.ErrorResponse(w, errors.ErrInvalidInput.WithDetails(err.Error()))
+		return
+	}
+
 	ctx := r.Context()
-	produto, err := h.service.FindByID(ctx, id)
+	produto, err := h.service.FindByID(ctx, id, fields...)
 	if err != nil {
 		if errors.IsAPIError(err) {
 			utils.ErrorResponse(w, err)
@@ -162,8 +311,15 @@ func (h *ProdutoHandler) GetProduto(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Responder 304 se o cliente já possui a versão atual
+	etag := formatFieldsETag(produto.Version, fields)
+	utils.SetCacheValidators(w, etag, produto.UpdatedAt)
+	if utils.NotModified(w, r, etag, produto.UpdatedAt) {
+		return
+	}
+
 	// Converter model para DTO
-	response := dto.FromModel(produto)
+	response := dto.FromModel(produto).WithFields(fields)
 
 	utils.SuccessResponse(w, http.StatusOK, response)
 }
@@ -188,7 +344,7 @@ func (h *ProdutoHandler) CreateProduto(w http.ResponseWriter, r *http.Request) {
 	// Converter DTO para model
 	produto := request.ToModel()
 
-	ctx := r.Context()
+	ctx := requestContext(r)
 	created, err := h.service.Create(ctx, produto)
 	if err != nil {
 		utils.ErrorResponse(w, err)
@@ -202,6 +358,7 @@ func (h *ProdutoHandler) CreateProduto(w http.ResponseWriter, r *http.Request) {
 }
 
 // UpdateProduto atualiza um produto completamente
+// Aceita o header If-Match com o ETag obtido no GET (responde 412 se a versão estiver desatualizada)
 // PUT /api/produtos/{id}
 func (h *ProdutoHandler) UpdateProduto(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
@@ -225,11 +382,18 @@ func (h *ProdutoHandler) UpdateProduto(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Versão esperada para controle de concorrência otimista
+	expectedVersion, err := parseIfMatch(r)
+	if err != nil {
+		utils.ErrorResponse(w, err)
+		return
+	}
+
 	// Converter DTO para model
 	produto := request.ToModel()
 
-	ctx := r.Context()
-	updated, err := h.service.Update(ctx, id, produto)
+	ctx := requestContext(r)
+	updated, err := h.service.Update(ctx, id, produto, expectedVersion)
 	if err != nil {
 		utils.ErrorResponse(w, err)
 		return
@@ -238,10 +402,12 @@ func (h *ProdutoHandler) UpdateProduto(w http.ResponseWriter, r *http.Request) {
 	// Converter model para DTO de resposta
 	response := dto.FromModel(updated)
 
+	w.Header().Set("ETag", formatETag(updated.Version))
 	utils.SuccessResponse(w, http.StatusOK, response)
 }
 
 // PatchProduto atualiza um produto parcialmente
+// Aceita o header If-Match com o ETag obtido no GET (responde 412 se a versão estiver desatualizada)
 // PATCH /api/produtos/{id}
 func (h *ProdutoHandler) PatchProduto(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
@@ -265,11 +431,18 @@ func (h *ProdutoHandler) PatchProduto(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Versão esperada para controle de concorrência otimista
+	expectedVersion, err := parseIfMatch(r)
+	if err != nil {
+		utils.ErrorResponse(w, err)
+		return
+	}
+
 	// Converter DTO para map
 	updates := request.ToMap()
 
-	ctx := r.Context()
-	updated, err := h.service.Patch(ctx, id, updates)
+	ctx := requestContext(r)
+	updated, err := h.service.Patch(ctx, id, updates, expectedVersion)
 	if err != nil {
 		utils.ErrorResponse(w, err)
 		return
@@ -278,10 +451,12 @@ func (h *ProdutoHandler) PatchProduto(w http.ResponseWriter, r *http.Request) {
 	// Converter model para DTO de resposta
 	response := dto.FromModel(updated)
 
+	w.Header().Set("ETag", formatETag(updated.Version))
 	utils.SuccessResponse(w, http.StatusOK, response)
 }
 
 // DeleteProduto deleta um produto
+// Aceita o header If-Match com o ETag obtido no GET (responde 412 se a versão estiver desatualizada)
 // DELETE /api/produtos/{id}
 func (h *ProdutoHandler) DeleteProduto(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
@@ -291,8 +466,15 @@ func (h *ProdutoHandler) DeleteProduto(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	ctx := r.Context()
-	if err := h.service.Delete(ctx, id); err != nil {
+	// Versão esperada para controle de concorrência otimista
+	expectedVersion, err := parseIfMatch(r)
+	if err != nil {
+		utils.ErrorResponse(w, err)
+		return
+	}
+
+	ctx := requestContext(r)
+	if err := h.service.Delete(ctx, id, expectedVersion); err != nil {
 		utils.ErrorResponse(w, err)
 		return
 	}
@@ -300,6 +482,71 @@ func (h *ProdutoHandler) DeleteProduto(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusNoContent)
 }
 
+// RestoreProduto restaura um produto removido (soft delete)
+// @Summary Restaura um produto da lixeira
+// @Description Desfaz a remoção lógica de um produto
+// @Tags produtos
+// @Produce json
+// @Param id path int true "ID do produto"
+// @Success 200 {object} dto.ProdutoResponse
+// @Failure 400 {object} errors.APIError
+// @Failure 404 {object} errors.APIError
+// @Router /api/v1/produtos/{id}/restore [post]
+// POST /api/v1/produtos/{id}/restore
+func (h *ProdutoHandler) RestoreProduto(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	id, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		utils.ErrorResponse(w, errors.ErrInvalidID)
+		return
+	}
+
+	ctx := requestContext(r)
+	restored, err := h.service.Restore(ctx, id)
+	if err != nil {
+		utils.ErrorResponse(w, err)
+		return
+	}
+
+	utils.SuccessResponse(w, http.StatusOK, dto.FromModel(restored))
+}
+
+// GetProdutosTrash lista os produtos da lixeira
+// @Summary Lista produtos removidos
+// @Description Retorna uma lista paginada dos produtos removidos (soft delete)
+// @Tags produtos
+// @Produce json
+// @Param page query int false "Número da página (padrão: 1)" default(1)
+// @Param pageSize query int false "Tamanho da página (padrão: 10, máximo: 100)" default(10)
+// @Param sort query string false "Campo para ordenação (id, nome, preco, descricao, created_at, updated_at, deleted_at)" default(deleted_at)
+// @Param order query string false "Ordem de ordenação (asc, desc)" default(desc)
+// @Success 200 {object} dto.PaginatedProdutoListResponse
+// @Failure 400 {object} errors.APIError
+// @Failure 500 {object} errors.APIError
+// @Router /api/v1/produtos/trash [get]
+// GET /api/v1/produtos/trash?page=1&pageSize=10&sort=deleted_at&order=desc
+func (h *ProdutoHandler) GetProdutosTrash(w http.ResponseWriter, r *http.Request) {
+	pagination := dto.PaginationRequest{
+		Page:     getIntQuery(r, "page", 1),
+		PageSize: getIntQuery(r, "pageSize", 10),
+	}
+
+	sort := dto.SortRequest{}
+	if r.URL.Query().Get("sort") != "" {
+		sort = dto.GetSortFromQuery(r.URL.Query().Get("sort"), r.URL.Query().Get("order"))
+	}
+
+	ctx := r.Context()
+	produtos, paginationResp, err := h.service.FindTrashPaginated(ctx, pagination, sort)
+	if err != nil {
+		utils.ErrorResponse(w, err)
+		return
+	}
+
+	response := dto.ToPaginatedResponse(dto.FromModelList(produtos), paginationResp)
+	utils.SuccessResponse(w, http.StatusOK, response)
+}
+
 // HealthCheckHandler gerencia o health check da API
 type HealthCheckHandler struct {
 	healthCheckFunc func(ctx context.Context) error
@@ -0,0 +1,169 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+
+	"api-go-arquitetura/internal/dto"
+	"api-go-arquitetura/internal/errors"
+	"api-go-arquitetura/internal/service"
+	"api-go-arquitetura/internal/utils"
+	"api-go-arquitetura/internal/validator"
+)
+
+// BatchProdutos aplica operações de criação, atualização e remoção em lote
+// @Summary Operações em lote
+// @Description Aplica até 1000 operações (create, update, patch, delete) e retorna o resultado de cada uma.
+// @Description Com atomic=true, as operações são aplicadas em uma transação: se alguma falhar, nenhuma é aplicada
+// @Tags produtos
+// @Accept json
+// @Produce json
+// @Param atomic query bool false "Aplicar todas as operações ou nenhuma" default(false)
+// @Param Idempotency-Key header string false "Chave para repetir o lote com segurança"
+// @Param request body dto.BatchRequest true "Operações do lote"
+// @Success 200 {object} dto.BatchResponse
+// @Failure 400 {object} errors.APIError
+// @Failure 500 {object} errors.APIError
+// @Router /api/v1/produtos:batch [post]
+func (h *ProdutoHandler) BatchProdutos(w http.ResponseWriter, r *http.Request) {
+	atomic, err := getBoolQuery(r, "atomic", false)
+	if err != nil {
+		utils.ErrorResponse(w, err)
+		return
+	}
+
+	var request dto.BatchRequest
+	if err := utils.DecodeJSON(r.Body, &request); err != nil {
+		utils.BadRequestResponse(w, "Erro ao decodificar JSON: "+err.Error())
+		return
+	}
+	if len(request.Operations) == 0 {
+		utils.ErrorResponse(w, errors.ErrInvalidInput.WithDetails("nenhuma operação informada"))
+		return
+	}
+	if len(request.Operations) > dto.MaxBatchOperations {
+		utils.ErrorResponse(w, errors.ErrInvalidInput.WithDetailsf("o lote deve ter no máximo %d operações", dto.MaxBatchOperations))
+		return
+	}
+
+	// Decodificar e validar cada operação; as inválidas já recebem o resultado
+	results := make([]service.BatchResult, len(request.Operations))
+	ops := make([]service.BatchOperation, 0, len(request.Operations))
+	opIndex := make([]int, 0, len(request.Operations))
+	for i, item := range request.Operations {
+		op, err := decodeBatchOperation(item)
+		if err != nil {
+			results[i].Err = err
+			continue
+		}
+		ops = append(ops, op)
+		opIndex = append(opIndex, i)
+	}
+
+	switch {
+	case atomic && len(ops) < len(request.Operations):
+		// No modo atômico, uma operação inválida impede a aplicação de todo o lote
+		for _, i := range opIndex {
+			results[i].Err = errors.ErrBatchAborted
+		}
+	case len(ops) > 0:
+		applied, err := h.service.Batch(requestContext(r), ops, atomic)
+		if err != nil {
+			utils.ErrorResponse(w, err)
+			return
+		}
+		for j, res := range applied {
+			results[opIndex[j]] = res
+		}
+	}
+
+	utils.SuccessResponse(w, http.StatusOK, newBatchResponse(request.Operations, results, atomic))
+}
+
+// decodeBatchOperation converte uma operação da requisição, validando os dados
+// com as mesmas regras dos endpoints individuais
+func decodeBatchOperation(item dto.BatchOperationRequest) (service.BatchOperation, *errors.APIError) {
+	op := service.BatchOperation{
+		Op:              item.Op,
+		ID:              item.ID,
+		ExpectedVersion: item.Version,
+	}
+
+	switch item.Op {
+	case dto.BatchOpCreate:
+		var request dto.CreateProdutoRequest
+		if err := decodeBatchData(item.Data, &request); err != nil {
+			return op, err
+		}
+		op.Produto = request.ToModel()
+	case dto.BatchOpUpdate:
+		var request dto.UpdateProdutoRequest
+		if err := decodeBatchData(item.Data, &request); err != nil {
+			return op, err
+		}
+		op.Produto = request.ToModel()
+	case dto.BatchOpPatch:
+		var request dto.PatchProdutoRequest
+		if err := decodeBatchData(item.Data, &request); err != nil {
+			return op, err
+		}
+		op.Updates = request.ToMap()
+	case dto.BatchOpDelete:
+	default:
+		return op, errors.ErrInvalidInput.WithDetailsf("operação '%s' inválida (use create, update, patch ou delete)", item.Op)
+	}
+	return op, nil
+}
+
+// decodeBatchData decodifica e valida o campo data de uma operação
+func decodeBatchData(data json.RawMessage, v interface{}) *errors.APIError {
+	if len(data) == 0 {
+		return errors.ErrInvalidInput.WithDetails("o campo 'data' é obrigatório")
+	}
+	if err := utils.DecodeJSON(bytes.NewReader(data), v); err != nil {
+		return errors.ErrInvalidInput.WithDetails("Erro ao decodificar data: " + err.Error())
+	}
+	if validationErrors := validator.Validate(v); len(validationErrors) > 0 {
+		return errors.ErrValidation.WithDetailsf("Erros de validação: %v", validationErrors)
+	}
+	return nil
+}
+
+// newBatchResponse monta a resposta com o status HTTP equivalente de cada operação
+func newBatchResponse(items []dto.BatchOperationRequest, results []service.BatchResult, atomic bool) dto.BatchResponse {
+	response := dto.BatchResponse{
+		Atomic:  atomic,
+		Results: make([]dto.BatchItemResult, len(items)),
+	}
+
+	for i, item := range items {
+		res := results[i]
+		result := dto.BatchItemResult{Index: i, Op: item.Op, ID: item.ID}
+
+		if res.Err != nil {
+			result.Status = res.Err.Status
+			result.Error = res.Err
+			response.Failed++
+			response.Results[i] = result
+			continue
+		}
+
+		switch item.Op {
+		case dto.BatchOpCreate:
+			result.Status = http.StatusCreated
+		case dto.BatchOpDelete:
+			result.Status = http.StatusNoContent
+		default:
+			result.Status = http.StatusOK
+		}
+		if item.Op != dto.BatchOpDelete {
+			produto := dto.FromModel(res.Produto)
+			result.ID = produto.ID
+			result.Produto = &produto
+		}
+		response.Succeeded++
+		response.Results[i] = result
+	}
+	return response
+}
@@ -0,0 +1,111 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"api-go-arquitetura/internal/dto"
+	"api-go-arquitetura/internal/model"
+)
+
+func TestProdutoHandler_BatchProdutos(t *testing.T) {
+	mockService := NewMockProdutoService()
+	handler := NewProdutoHandler(mockService)
+
+	existing, _ := mockService.Create(context.Background(), model.Produto{Nome: "Notebook", Preco: 3500.00})
+
+	batch := func(query, body string) (*httptest.ResponseRecorder, dto.BatchResponse) {
+		req := httptest.NewRequest("POST", "/api/v1/produtos:batch"+query, strings.NewReader(body))
+		w := httptest.NewRecorder()
+		handler.BatchProdutos(w, req)
+
+		var response dto.BatchResponse
+		if w.Code == http.StatusOK {
+			if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+				t.Fatalf("Erro ao decodificar resposta: %v", err)
+			}
+		}
+		return w, response
+	}
+
+	t.Run("deve retornar o resultado de cada operação", func(t *testing.T) {
+		w, response := batch("", `{"operations": [
+			{"op": "create", "data": {"nome": "Mouse", "preco": 150}},
+			{"op": "patch", "id": 1, "version": 1, "data": {"preco": 3200}},
+			{"op": "create", "data": {"nome": "", "preco": 10}},
+			{"op": "delete", "id": 99}
+		]}`)
+
+		if w.Code != http.StatusOK {
+			t.Fatalf("Status esperado %d, obtido %d", http.StatusOK, w.Code)
+		}
+		if response.Succeeded != 2 || response.Failed != 2 {
+			t.Errorf("Esperado 2 sucessos e 2 falhas, obtido %d e %d", response.Succeeded, response.Failed)
+		}
+
+		expected := []int{http.StatusCreated, http.StatusOK, http.StatusUnprocessableEntity, http.StatusNotFound}
+		for i, status := range expected {
+			if response.Results[i].Status != status {
+				t.Errorf("Item %d: status esperado %d, obtido %d", i, status, response.Results[i].Status)
+			}
+		}
+		if response.Results[1].Produto == nil || response.Results[1].Produto.Preco != 3200 {
+			t.Error("Produto atualizado deveria ser retornado no resultado")
+		}
+		if response.Results[3].Error == nil || response.Results[3].Error.Code != "PRODUTO_NOT_FOUND" {
+			t.Error("Item inexistente deveria retornar PRODUTO_NOT_FOUND")
+		}
+	})
+
+	t.Run("não deve aplicar nenhuma operação no modo atômico com falha", func(t *testing.T) {
+		before := len(mockService.produtos)
+
+		_, response := batch("?atomic=true", `{"operations": [
+			{"op": "create", "data": {"nome": "Teclado", "preco": 200}},
+			{"op": "update", "id": 1, "version": 99, "data": {"nome": "Notebook", "preco": 10}}
+		]}`)
+
+		if response.Succeeded != 0 || response.Failed != 2 {
+			t.Errorf("Esperado 0 sucessos e 2 falhas, obtido %d e %d", response.Succeeded, response.Failed)
+		}
+		if code := response.Results[0].Error; code == nil || code.Code != "BATCH_ABORTED" {
+			t.Error("Operação válida deveria ser abortada")
+		}
+		if code := response.Results[1].Error; code == nil || code.Code != "PRECONDITION_FAILED" {
+			t.Error("Operação com versão desatualizada deveria retornar PRECONDITION_FAILED")
+		}
+		if len(mockService.produtos) != before {
+			t.Error("Nenhum produto deveria ser criado")
+		}
+	})
+
+	t.Run("deve abortar o lote atômico com operação inválida", func(t *testing.T) {
+		_, response := batch("?atomic=true", `{"operations": [
+			{"op": "delete", "id": 1},
+			{"op": "rename", "id": 1}
+		]}`)
+
+		if response.Results[0].Error == nil || response.Results[0].Error.Code != "BATCH_ABORTED" {
+			t.Error("Operação válida deveria ser abortada")
+		}
+		if response.Results[1].Error == nil || response.Results[1].Error.Code != "INVALID_INPUT" {
+			t.Error("Operação desconhecida deveria retornar INVALID_INPUT")
+		}
+		if _, err := mockService.FindByID(context.Background(), existing.ID); err != nil {
+			t.Error("Produto não deveria ser removido")
+		}
+	})
+
+	t.Run("deve rejeitar lote vazio ou parâmetro atomic inválido", func(t *testing.T) {
+		if w, _ := batch("", `{"operations": []}`); w.Code != http.StatusBadRequest {
+			t.Errorf("Status esperado %d, obtido %d", http.StatusBadRequest, w.Code)
+		}
+		if w, _ := batch("?atomic=talvez", `{"operations": [{"op": "delete", "id": 1}]}`); w.Code != http.StatusBadRequest {
+			t.Errorf("Status esperado %d, obtido %d", http.StatusBadRequest, w.Code)
+		}
+	})
+}
@@ -0,0 +1,135 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+
+	"api-go-arquitetura/internal/dto"
+	"api-go-arquitetura/internal/errors"
+	"api-go-arquitetura/internal/utils"
+	"api-go-arquitetura/internal/validator"
+)
+
+// AdjustStock ajusta o estoque do produto
+// @Summary Ajusta o estoque
+// @Description Soma delta ao estoque (negativo para baixa). Responde 409 se o estoque ficaria abaixo da quantidade reservada
+// @Tags estoque
+// @Accept json
+// @Produce json
+// @Param id path int true "ID do produto"
+// @Param request body dto.AdjustStockRequest true "Ajuste de estoque"
+// @Success 200 {object} dto.StockResponse
+// @Failure 404 {object} errors.APIError
+// @Failure 409 {object} errors.APIError
+// @Failure 422 {object} errors.APIError
+// @Router /api/v1/produtos/{id}/stock/adjust [post]
+// POST /api/v1/produtos/{id}/stock/adjust
+func (h *ProdutoHandler) AdjustStock(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		utils.ErrorResponse(w, errors.ErrInvalidID)
+		return
+	}
+
+	var request dto.AdjustStockRequest
+	if err := utils.DecodeJSON(r.Body, &request); err != nil {
+		utils.BadRequestResponse(w, "Erro ao decodificar JSON: "+err.Error())
+		return
+	}
+	if validationErrors := validator.Validate(&request); len(validationErrors) > 0 {
+		utils.ValidationErrorResponse(w, validationErrors)
+		return
+	}
+
+	produto, err := h.service.AdjustStock(r.Context(), id, request.Delta)
+	if err != nil {
+		utils.ErrorResponse(w, err)
+		return
+	}
+
+	w.Header().Set("ETag", formatETag(produto.Version))
+	utils.SuccessResponse(w, http.StatusOK, dto.FromStock(produto))
+}
+
+// ReserveStock reserva uma quantidade do estoque disponível
+// @Summary Reserva estoque
+// @Description Reserva a quantidade até a expiração (ttlSeconds, padrão 15 minutos); reservas expiradas são liberadas automaticamente
+// @Tags estoque
+// @Accept json
+// @Produce json
+// @Param id path int true "ID do produto"
+// @Param Idempotency-Key header string false "Chave para repetir a reserva com segurança"
+// @Param request body dto.ReserveStockRequest true "Reserva"
+// @Success 201 {object} dto.ReservaResponse
+// @Failure 404 {object} errors.APIError
+// @Failure 409 {object} errors.APIError
+// @Failure 422 {object} errors.APIError
+// @Router /api/v1/produtos/{id}/stock/reserve [post]
+// POST /api/v1/produtos/{id}/stock/reserve
+func (h *ProdutoHandler) ReserveStock(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		utils.ErrorResponse(w, errors.ErrInvalidID)
+		return
+	}
+
+	var request dto.ReserveStockRequest
+	if err := utils.DecodeJSON(r.Body, &request); err != nil {
+		utils.BadRequestResponse(w, "Erro ao decodificar JSON: "+err.Error())
+		return
+	}
+	if validationErrors := validator.Validate(&request); len(validationErrors) > 0 {
+		utils.ValidationErrorResponse(w, validationErrors)
+		return
+	}
+
+	reserva, produto, err := h.service.Reserve(r.Context(), id, request.Quantidade, request.ReservationID, request.TTL())
+	if err != nil {
+		utils.ErrorResponse(w, err)
+		return
+	}
+
+	w.Header().Set("ETag", formatETag(produto.Version))
+	utils.SuccessResponse(w, http.StatusCreated, dto.FromReserva(reserva, produto))
+}
+
+// ReleaseStock libera uma reserva
+// @Summary Libera uma reserva de estoque
+// @Tags estoque
+// @Accept json
+// @Produce json
+// @Param id path int true "ID do produto"
+// @Param request body dto.ReleaseStockRequest true "Reserva a liberar"
+// @Success 200 {object} dto.StockResponse
+// @Failure 404 {object} errors.APIError
+// @Failure 422 {object} errors.APIError
+// @Router /api/v1/produtos/{id}/stock/release [post]
+// POST /api/v1/produtos/{id}/stock/release
+func (h *ProdutoHandler) ReleaseStock(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		utils.ErrorResponse(w, errors.ErrInvalidID)
+		return
+	}
+
+	var request dto.ReleaseStockRequest
+	if err := utils.DecodeJSON(r.Body, &request); err != nil {
+		utils.BadRequestResponse(w, "Erro ao decodificar JSON: "+err.Error())
+		return
+	}
+	if validationErrors := validator.Validate(&request); len(validationErrors) > 0 {
+		utils.ValidationErrorResponse(w, validationErrors)
+		return
+	}
+
+	produto, err := h.service.Release(r.Context(), id, request.ReservationID)
+	if err != nil {
+		utils.ErrorResponse(w, err)
+		return
+	}
+
+	w.Header().Set("ETag", formatETag(produto.Version))
+	utils.SuccessResponse(w, http.StatusOK, dto.FromStock(produto))
+}
@@ -0,0 +1,91 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"api-go-arquitetura/internal/dto"
+	"api-go-arquitetura/internal/model"
+
+	"github.com/gorilla/mux"
+)
+
+func TestProdutoHandler_Stock(t *testing.T) {
+	mockService := NewMockProdutoService()
+	produto, _ := mockService.Create(context.Background(), model.Produto{Nome: "Mouse", Preco: 50, Estoque: 5})
+	handler := NewProdutoHandler(mockService)
+
+	router := mux.NewRouter()
+	router.HandleFunc("/api/v1/produtos/{id}/stock/adjust", handler.AdjustStock).Methods("POST")
+	router.HandleFunc("/api/v1/produtos/{id}/stock/reserve", handler.ReserveStock).Methods("POST")
+	router.HandleFunc("/api/v1/produtos/{id}/stock/release", handler.ReleaseStock).Methods("POST")
+
+	do := func(action, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("POST", "/api/v1/produtos/1/stock/"+action, bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("deve ajustar o estoque", func(t *testing.T) {
+		w := do("adjust", `{"delta":3}`)
+		if w.Code != http.StatusOK {
+			t.Fatalf("Status esperado %d, obtido %d: %s", http.StatusOK, w.Code, w.Body.String())
+		}
+		var response dto.StockResponse
+		if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+			t.Fatalf("Erro ao decodificar resposta: %v", err)
+		}
+		if response.Estoque != 8 || response.Disponivel != 8 {
+			t.Errorf("Estoque incorreto: %+v", response)
+		}
+		if w.Header().Get("ETag") != formatETag(produto.Version+1) {
+			t.Errorf("ETag esperado %s, obtido %s", formatETag(produto.Version+1), w.Header().Get("ETag"))
+		}
+	})
+
+	t.Run("deve reservar e liberar", func(t *testing.T) {
+		w := do("reserve", `{"quantidade":6,"reservationId":"pedido-1"}`)
+		if w.Code != http.StatusCreated {
+			t.Fatalf("Status esperado %d, obtido %d: %s", http.StatusCreated, w.Code, w.Body.String())
+		}
+		var reserva dto.ReservaResponse
+		if err := json.NewDecoder(w.Body).Decode(&reserva); err != nil {
+			t.Fatalf("Erro ao decodificar resposta: %v", err)
+		}
+		if reserva.ReservationID != "pedido-1" || reserva.Estoque.Disponivel != 2 {
+			t.Errorf("Reserva incorreta: %+v", reserva)
+		}
+
+		if w := do("adjust", `{"delta":-3}`); w.Code != http.StatusConflict {
+			t.Errorf("Status esperado %d, obtido %d", http.StatusConflict, w.Code)
+		}
+		if w := do("reserve", `{"quantidade":3}`); w.Code != http.StatusConflict {
+			t.Errorf("Status esperado %d, obtido %d", http.StatusConflict, w.Code)
+		}
+
+		if w := do("release", `{"reservationId":"pedido-1"}`); w.Code != http.StatusOK {
+			t.Errorf("Status esperado %d, obtido %d", http.StatusOK, w.Code)
+		}
+		if w := do("release", `{"reservationId":"pedido-1"}`); w.Code != http.StatusNotFound {
+			t.Errorf("Status esperado %d, obtido %d", http.StatusNotFound, w.Code)
+		}
+	})
+
+	t.Run("deve validar a requisição", func(t *testing.T) {
+		for action, body := range map[string]string{
+			"adjust":  `{"delta":0}`,
+			"reserve": `{"quantidade":1,"ttlSeconds":90000}`,
+			"release": `{}`,
+		} {
+			if w := do(action, body); w.Code != http.StatusUnprocessableEntity {
+				t.Errorf("%s: status esperado %d, obtido %d", action, http.StatusUnprocessableEntity, w.Code)
+			}
+		}
+	})
+}
@@ -0,0 +1,232 @@
+package handlers
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"api-go-arquitetura/internal/dto"
+	"api-go-arquitetura/internal/errors"
+	"api-go-arquitetura/internal/logger"
+	"api-go-arquitetura/internal/model"
+	"api-go-arquitetura/internal/utils"
+)
+
+// Formatos aceitos por GET /api/v1/produtos/export
+const (
+	exportFormatCSV    = "csv"
+	exportFormatNDJSON = "ndjson"
+	exportFormatJSON   = "json"
+)
+
+// exportBufferSize é o tamanho do buffer entre o cursor e a resposta
+const exportBufferSize = 32 << 10
+
+// exportContentTypes mapeia cada formato para o Content-Type da resposta
+var exportContentTypes = map[string]string{
+	exportFormatCSV:    "text/csv; charset=utf-8",
+	exportFormatNDJSON: "application/x-ndjson",
+	exportFormatJSON:   "application/json",
+}
+
+// ExportProdutos exporta o catálogo em CSV, NDJSON ou array JSON
+// @Summary Exporta produtos
+// @Description Exporta todos os produtos que correspondem aos filtros, lendo direto do cursor do MongoDB
+// @Description (sem carregar o catálogo em memória). Aceita os mesmos filtros e ordenação da listagem
+// @Tags produtos
+// @Produce text/csv
+// @Produce application/x-ndjson
+// @Produce json
+// @Param format query string false "Formato do arquivo (csv, ndjson, json)" default(csv)
+// @Param nome query string false "Filtro por nome (busca parcial, case-insensitive)"
+// @Param precoMin query number false "Preço mínimo"
+// @Param precoMax query number false "Preço máximo"
+// @Param descricao query string false "Filtro por descrição (busca parcial, case-insensitive)"
+// @Param q query string false "Busca textual em nome e descrição (com stemming em português)"
+// @Param filter query string false "Expressão de filtro campo:operador:valor separada por vírgulas (ex.: preco:gte:100,nome:startswith:Note)"
+// @Param sort query string false "Campo para ordenação (id, nome, preco, descricao, created_at, updated_at, score); com q, o padrão é score" default(id)
+// @Param order query string false "Ordem de ordenação (asc, desc)" default(asc)
+// @Success 200 {file} file
+// @Failure 400 {object} errors.APIError
+// @Failure 500 {object} errors.APIError
+// @Router /api/v1/produtos/export [get]
+// GET /api/v1/produtos/export?format=ndjson&precoMin=1000&sort=preco:desc
+func (h *ProdutoHandler) ExportProdutos(w http.ResponseWriter, r *http.Request) {
+	format := strings.ToLower(r.URL.Query().Get("format"))
+	if format == "" {
+		format = exportFormatCSV
+	}
+	contentType, ok := exportContentTypes[format]
+	if !ok {
+		utils.ErrorResponse(w, errors.ErrInvalidInput.WithDetails("format deve ser csv, ndjson ou json"))
+		return
+	}
+
+	filter := getFilterQuery(r)
+	sort := getSortQuery(r)
+
+	// A exportação pode levar mais que o WriteTimeout do servidor
+	if err := http.NewResponseController(w).SetWriteDeadline(time.Time{}); err != nil {
+		logger.WithField("error", err).Debug("Não foi possível remover o prazo de escrita da exportação")
+	}
+
+	// Os headers só são enviados quando o primeiro bloco sai do buffer; até lá,
+	// um erro (ex.: ordenação inválida, banco indisponível) ainda vira uma resposta JSON
+	sent := &sentWriter{w: w}
+	buf := bufio.NewWriterSize(sent, exportBufferSize)
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Disposition", `attachment; filename="produtos-`+time.Now().UTC().Format("20060102-150405")+"."+format+`"`)
+
+	exporter := newProdutoExporter(format, buf)
+	count := 0
+	err := h.service.Export(r.Context(), filter, sort, func(produto model.Produto) error {
+		count++
+		return exporter.Write(dto.FromModel(produto))
+	})
+	if err == nil {
+		err = exporter.Close()
+	}
+	if err == nil {
+		err = buf.Flush()
+	}
+
+	if err != nil {
+		if !sent.started {
+			w.Header().Del("Content-Disposition")
+			utils.ErrorResponse(w, err)
+			return
+		}
+		// A resposta já foi iniciada: encerrar a conexão para que o cliente
+		// perceba o arquivo incompleto
+		logger.WithFields(map[string]interface{}{
+			"format":   format,
+			"exported": count,
+			"error":    err,
+		}).Error("Exportação de produtos interrompida")
+		panic(http.ErrAbortHandler)
+	}
+
+	logger.WithFields(map[string]interface{}{
+		"format":   format,
+		"exported": count,
+	}).Info("Exportação de produtos concluída")
+}
+
+// sentWriter registra se algum byte já foi enviado ao cliente
+type sentWriter struct {
+	w       io.Writer
+	started bool
+}
+
+func (s *sentWriter) Write(p []byte) (int, error) {
+	s.started = true
+	return s.w.Write(p)
+}
+
+// produtoExporter escreve os produtos no formato do arquivo exportado
+type produtoExporter interface {
+	Write(produto dto.ProdutoResponse) error
+	// Close escreve o final do arquivo (o buffer é descarregado pelo chamador)
+	Close() error
+}
+
+// newProdutoExporter cria o exportador correspondente ao formato
+func newProdutoExporter(format string, w io.Writer) produtoExporter {
+	switch format {
+	case exportFormatNDJSON:
+		return &ndjsonExporter{encoder: json.NewEncoder(w)}
+	case exportFormatJSON:
+		return &jsonArrayExporter{w: w}
+	default:
+		return &csvExporter{writer: csv.NewWriter(w)}
+	}
+}
+
+// csvColumns são as colunas do CSV exportado (compatível com a importação)
+var csvColumns = []string{"id", "nome", "preco", "descricao", "version"}
+
+// csvExporter escreve um produto por linha, com cabeçalho
+type csvExporter struct {
+	writer        *csv.Writer
+	headerWritten bool
+}
+
+func (e *csvExporter) writeHeader() error {
+	if e.headerWritten {
+		return nil
+	}
+	e.headerWritten = true
+	return e.writer.Write(csvColumns)
+}
+
+func (e *csvExporter) Write(produto dto.ProdutoResponse) error {
+	if err := e.writeHeader(); err != nil {
+		return err
+	}
+	return e.writer.Write([]string{
+		strconv.Itoa(produto.ID),
+		produto.Nome,
+		strconv.FormatFloat(produto.Preco, 'f', -1, 64),
+		produto.Descricao,
+		strconv.Itoa(produto.Version),
+	})
+}
+
+func (e *csvExporter) Close() error {
+	// Um catálogo vazio ainda gera o cabeçalho
+	if err := e.writeHeader(); err != nil {
+		return err
+	}
+	e.writer.Flush()
+	return e.writer.Error()
+}
+
+// ndjsonExporter escreve um produto (JSON) por linha
+type ndjsonExporter struct {
+	encoder *json.Encoder
+}
+
+func (e *ndjsonExporter) Write(produto dto.ProdutoResponse) error {
+	return e.encoder.Encode(produto)
+}
+
+func (e *ndjsonExporter) Close() error {
+	return nil
+}
+
+// jsonArrayExporter escreve os produtos como um único array JSON
+type jsonArrayExporter struct {
+	w     io.Writer
+	count int
+}
+
+func (e *jsonArrayExporter) Write(produto dto.ProdutoResponse) error {
+	data, err := json.Marshal(produto)
+	if err != nil {
+		return err
+	}
+	prefix := ","
+	if e.count == 0 {
+		prefix = "["
+	}
+	e.count++
+	if _, err := io.WriteString(e.w, prefix); err != nil {
+		return err
+	}
+	_, err = e.w.Write(data)
+	return err
+}
+
+func (e *jsonArrayExporter) Close() error {
+	end := "]\n"
+	if e.count == 0 {
+		end = "[]\n"
+	}
+	_, err := io.WriteString(e.w, end)
+	return err
+}
@@ -0,0 +1,85 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"api-go-arquitetura/internal/dto"
+	"api-go-arquitetura/internal/model"
+)
+
+func TestProdutoHandler_ExportProdutos(t *testing.T) {
+	mockService := NewMockProdutoService()
+	handler := NewProdutoHandler(mockService)
+
+	_, _ = mockService.Create(context.Background(), model.Produto{Nome: "Notebook", Preco: 3500.5, Descricao: "Tela 15, i7"})
+	_, _ = mockService.Create(context.Background(), model.Produto{Nome: "Mouse", Preco: 150})
+
+	export := func(query string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("GET", "/api/v1/produtos/export"+query, nil)
+		w := httptest.NewRecorder()
+		handler.ExportProdutos(w, req)
+		return w
+	}
+
+	t.Run("deve exportar CSV por padrão", func(t *testing.T) {
+		w := export("")
+
+		if w.Code != http.StatusOK {
+			t.Fatalf("Status esperado %d, obtido %d", http.StatusOK, w.Code)
+		}
+		if ct := w.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/csv") {
+			t.Errorf("Content-Type esperado text/csv, obtido %s", ct)
+		}
+		if cd := w.Header().Get("Content-Disposition"); !strings.HasPrefix(cd, "attachment;") || !strings.Contains(cd, ".csv") {
+			t.Errorf("Content-Disposition inválido: %s", cd)
+		}
+		expected := "id,nome,preco,descricao,version\n1,Notebook,3500.5,\"Tela 15, i7\",1\n2,Mouse,150,,1\n"
+		if w.Body.String() != expected {
+			t.Errorf("CSV esperado %q, obtido %q", expected, w.Body.String())
+		}
+	})
+
+	t.Run("deve exportar NDJSON respeitando o filtro", func(t *testing.T) {
+		w := export("?format=ndjson&nome=mouse")
+
+		lines := strings.Split(strings.TrimSpace(w.Body.String()), "\n")
+		if len(lines) != 1 {
+			t.Fatalf("Esperada 1 linha, obtidas %d", len(lines))
+		}
+		var produto dto.ProdutoResponse
+		if err := json.Unmarshal([]byte(lines[0]), &produto); err != nil || produto.Nome != "Mouse" {
+			t.Errorf("Linha NDJSON inválida: %s", lines[0])
+		}
+	})
+
+	t.Run("deve exportar array JSON válido", func(t *testing.T) {
+		for query, total := range map[string]int{"?format=json": 2, "?format=json&nome=inexistente": 0} {
+			w := export(query)
+
+			var produtos []dto.ProdutoResponse
+			if err := json.Unmarshal(w.Body.Bytes(), &produtos); err != nil {
+				t.Fatalf("Array JSON inválido (%s): %v", query, err)
+			}
+			if len(produtos) != total {
+				t.Errorf("Esperados %d produtos para %s, obtidos %d", total, query, len(produtos))
+			}
+		}
+	})
+
+	t.Run("deve rejeitar formato ou ordenação inválidos antes de iniciar o download", func(t *testing.T) {
+		for _, query := range []string{"?format=xlsx", "?sort=senha"} {
+			w := export(query)
+			if w.Code != http.StatusBadRequest {
+				t.Errorf("Status esperado %d para %s, obtido %d", http.StatusBadRequest, query, w.Code)
+			}
+			if w.Header().Get("Content-Disposition") != "" {
+				t.Errorf("Erro não deve ser enviado como download (%s)", query)
+			}
+		}
+	})
+}
@@ -0,0 +1,73 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"api-go-arquitetura/internal/model"
+
+	"github.com/gorilla/mux"
+)
+
+func TestProdutoHandler_Fields(t *testing.T) {
+	mockService := NewMockProdutoService()
+	handler := NewProdutoHandler(mockService)
+
+	_, _ = mockService.Create(context.Background(), model.Produto{Nome: "Notebook", Preco: 3500, Descricao: "Notebook de alta performance", Tags: []string{"informatica"}})
+
+	t.Run("deve retornar apenas os campos pedidos do produto", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/api/v1/produtos/1?fields=preco,nome", nil)
+		req = mux.SetURLVars(req, map[string]string{"id": "1"})
+		w := httptest.NewRecorder()
+		handler.GetProduto(w, req)
+
+		if w.Code != http.StatusOK {
+			t.Fatalf("Status esperado %d, obtido %d: %s", http.StatusOK, w.Code, w.Body.String())
+		}
+		if body := w.Body.String(); body != `{"id":1,"nome":"Notebook","preco":3500}`+"\n" {
+			t.Errorf("Resposta parcial inesperada: %s", body)
+		}
+		if etag := w.Header().Get("ETag"); etag != `"1-id.nome.preco"` {
+			t.Errorf("ETag da representação parcial esperado, obtido %s", etag)
+		}
+	})
+
+	t.Run("deve retornar apenas os campos pedidos na listagem", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/api/v1/produtos?fields=nome,tags", nil)
+		w := httptest.NewRecorder()
+		handler.GetProdutos(w, req)
+
+		if w.Code != http.StatusOK {
+			t.Fatalf("Status esperado %d, obtido %d: %s", http.StatusOK, w.Code, w.Body.String())
+		}
+		var response struct {
+			Produtos []map[string]interface{} `json:"produtos"`
+		}
+		if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+			t.Fatalf("Erro ao decodificar resposta: %v", err)
+		}
+		if len(response.Produtos) != 1 || len(response.Produtos[0]) != 3 || response.Produtos[0]["nome"] != "Notebook" || response.Produtos[0]["tags"] == nil {
+			t.Errorf("Esperados apenas id, nome e tags, obtido %v", response.Produtos)
+		}
+	})
+
+	t.Run("deve rejeitar campo fora da lista permitida", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/api/v1/produtos?fields=nome,deleted_at", nil)
+		w := httptest.NewRecorder()
+		handler.GetProdutos(w, req)
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("Status esperado %d na listagem, obtido %d", http.StatusBadRequest, w.Code)
+		}
+
+		req = httptest.NewRequest("GET", "/api/v1/produtos/1?fields=senha", nil)
+		req = mux.SetURLVars(req, map[string]string{"id": "1"})
+		w = httptest.NewRecorder()
+		handler.GetProduto(w, req)
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("Status esperado %d no produto, obtido %d", http.StatusBadRequest, w.Code)
+		}
+	})
+}
@@ -0,0 +1,327 @@
+package handlers
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"api-go-arquitetura/internal/dto"
+	"api-go-arquitetura/internal/errors"
+	"api-go-arquitetura/internal/logger"
+	"api-go-arquitetura/internal/service"
+	"api-go-arquitetura/internal/utils"
+	"api-go-arquitetura/internal/validator"
+)
+
+const (
+	// importBatchSize é a quantidade de linhas válidas enviadas ao banco por vez
+	importBatchSize = 500
+	// maxImportBytes limita o tamanho do arquivo importado
+	maxImportBytes = 32 << 20
+)
+
+// ImportProdutos importa produtos a partir de um arquivo CSV ou NDJSON
+// @Summary Importa produtos de CSV ou NDJSON
+// @Description Lê o arquivo linha a linha, valida cada linha com as mesmas regras da criação e insere as válidas em lotes.
+// @Description O CSV deve ter cabeçalho com as colunas nome, preco e descricao (separador , ou ;).
+// @Description Com dryRun=true, apenas valida o arquivo, sem gravar
+// @Tags produtos
+// @Accept text/csv
+// @Accept application/x-ndjson
+// @Produce json
+// @Param format query string false "Formato do arquivo (csv, ndjson); por padrão é obtido do Content-Type"
+// @Param dryRun query bool false "Apenas validar, sem gravar" default(false)
+// @Success 200 {object} dto.ImportReport
+// @Failure 400 {object} errors.APIError
+// @Failure 500 {object} errors.APIError
+// @Router /api/v1/produtos/import [post]
+// POST /api/v1/produtos/import?dryRun=true
+func (h *ProdutoHandler) ImportProdutos(w http.ResponseWriter, r *http.Request) {
+	dryRun, err := getBoolQuery(r, "dryRun", false)
+	if err != nil {
+		utils.ErrorResponse(w, err)
+		return
+	}
+
+	format := importFormat(r)
+	if format == "" {
+		utils.ErrorResponse(w, errors.ErrInvalidInput.WithDetails("formato não suportado: envie Content-Type text/csv ou application/x-ndjson, ou informe format=csv|ndjson"))
+		return
+	}
+
+	body := http.MaxBytesReader(w, r.Body, maxImportBytes)
+	reader, err := newImportReader(format, body)
+	if err != nil {
+		utils.ErrorResponse(w, err)
+		return
+	}
+
+	report := dto.ImportReport{
+		Format: format,
+		DryRun: dryRun,
+		Errors: []dto.ImportRowError{},
+	}
+
+	// Linhas válidas aguardando inserção, com a linha do arquivo de cada uma
+	pending := make([]service.BatchOperation, 0, importBatchSize)
+	pendingRows := make([]int, 0, importBatchSize)
+	flush := func() error {
+		if len(pending) == 0 {
+			return nil
+		}
+		results, err := h.service.Batch(requestContext(r), pending, false)
+		if err != nil {
+			return err
+		}
+		for i, res := range results {
+			if res.Err != nil {
+				report.AddError(pendingRows[i], res.Err)
+				continue
+			}
+			report.Accepted++
+		}
+		pending, pendingRows = pending[:0], pendingRows[:0]
+		return nil
+	}
+
+	for {
+		row, err := reader.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			utils.ErrorResponse(w, importAbortError(errors.ErrInvalidInput.WithDetails("Erro ao ler arquivo: "+err.Error()), report))
+			return
+		}
+
+		report.Total++
+		if row.err != nil {
+			report.AddError(row.line, row.err)
+			continue
+		}
+		if validationErrors := validator.Validate(&row.request); len(validationErrors) > 0 {
+			report.AddError(row.line, errors.ErrValidation.WithDetailsf("Erros de validação: %v", validationErrors))
+			continue
+		}
+		if dryRun {
+			report.Accepted++
+			continue
+		}
+
+		pending = append(pending, service.BatchOperation{Op: dto.BatchOpCreate, Produto: row.request.ToModel()})
+		pendingRows = append(pendingRows, row.line)
+		if len(pending) == importBatchSize {
+			if err := flush(); err != nil {
+				utils.ErrorResponse(w, importAbortError(err, report))
+				return
+			}
+		}
+	}
+	if err := flush(); err != nil {
+		utils.ErrorResponse(w, importAbortError(err, report))
+		return
+	}
+
+	logger.WithFields(map[string]interface{}{
+		"format":   report.Format,
+		"dry_run":  report.DryRun,
+		"total":    report.Total,
+		"accepted": report.Accepted,
+		"rejected": report.Rejected,
+	}).Info("Importação de produtos concluída")
+
+	utils.SuccessResponse(w, http.StatusOK, report)
+}
+
+// importAbortError informa, no erro que interrompeu a importação, quantas linhas já foram gravadas
+func importAbortError(err error, report dto.ImportReport) error {
+	apiErr := errors.AsAPIError(err)
+	if apiErr == nil {
+		apiErr = errors.WrapError(err, errors.ErrInternalServer)
+	}
+	if report.DryRun || report.Accepted == 0 {
+		return apiErr
+	}
+	logger.WithFields(map[string]interface{}{
+		"accepted": report.Accepted,
+		"error":    err,
+	}).Warn("Importação de produtos interrompida")
+	return apiErr.WithDetailsf("%s (importação interrompida após gravar %d linhas)", apiErr.Details, report.Accepted)
+}
+
+// importFormat obtém o formato do parâmetro format ou, na ausência dele, do Content-Type
+func importFormat(r *http.Request) string {
+	if format := strings.ToLower(r.URL.Query().Get("format")); format != "" {
+		if format == dto.ImportFormatCSV || format == dto.ImportFormatNDJSON {
+			return format
+		}
+		return ""
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	switch mediaType {
+	case "text/csv", "application/csv":
+		return dto.ImportFormatCSV
+	case "application/x-ndjson", "application/ndjson", "application/jsonl":
+		return dto.ImportFormatNDJSON
+	}
+	return ""
+}
+
+// importRow é uma linha lida do arquivo importado
+type importRow struct {
+	line    int
+	request dto.CreateProdutoRequest
+	err     *errors.APIError // Erro de conversão da linha (a validação é feita depois)
+}
+
+// importReader lê as linhas do arquivo uma a uma, retornando io.EOF ao final
+// Erros que impedem a leitura do restante do arquivo são retornados como error;
+// erros de uma linha específica ficam em importRow.err
+type importReader interface {
+	Next() (importRow, error)
+}
+
+// newImportReader cria o leitor correspondente ao formato
+func newImportReader(format string, body io.Reader) (importReader, error) {
+	if format == dto.ImportFormatCSV {
+		return newCSVImportReader(body)
+	}
+	return &ndjsonImportReader{reader: bufio.NewReader(body)}, nil
+}
+
+// csvImportReader lê produtos de um CSV com cabeçalho
+type csvImportReader struct {
+	reader  *csv.Reader
+	columns map[string]int
+}
+
+func newCSVImportReader(body io.Reader) (*csvImportReader, error) {
+	buffered := bufio.NewReader(body)
+
+	reader := csv.NewReader(buffered)
+	reader.Comma = detectCSVDelimiter(buffered)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil, errors.ErrInvalidInput.WithDetails("arquivo vazio")
+	}
+	if err != nil {
+		return nil, errors.ErrInvalidInput.WithDetails("Erro ao ler cabeçalho do CSV: " + err.Error())
+	}
+
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		name = strings.TrimPrefix(name, "\ufeff") // BOM de arquivos exportados por planilhas
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	for _, required := range []string{"nome", "preco"} {
+		if _, ok := columns[required]; !ok {
+			return nil, errors.ErrInvalidInput.WithDetailsf("o cabeçalho do CSV deve conter a coluna '%s'", required)
+		}
+	}
+
+	return &csvImportReader{reader: reader, columns: columns}, nil
+}
+
+func (c *csvImportReader) Next() (importRow, error) {
+	record, err := c.reader.Read()
+	if err == io.EOF {
+		return importRow{}, io.EOF
+	}
+	if parseErr, ok := err.(*csv.ParseError); ok {
+		return importRow{
+			line: parseErr.StartLine,
+			err:  errors.ErrInvalidInput.WithDetails(parseErr.Err.Error()),
+		}, nil
+	}
+	if err != nil {
+		return importRow{}, err
+	}
+
+	line, _ := c.reader.FieldPos(0)
+	row := importRow{line: line}
+	row.request.Nome = strings.TrimSpace(c.field(record, "nome"))
+	row.request.Descricao = strings.TrimSpace(c.field(record, "descricao"))
+
+	preco, err := parsePreco(c.field(record, "preco"))
+	if err != nil {
+		row.err = errors.ErrInvalidInput.WithDetailsf("preço '%s' inválido", c.field(record, "preco"))
+		return row, nil
+	}
+	row.request.Preco = preco
+	return row, nil
+}
+
+// field retorna o valor da coluna, ou vazio se a linha não tiver a coluna
+func (c *csvImportReader) field(record []string, column string) string {
+	i, ok := c.columns[column]
+	if !ok || i >= len(record) {
+		return ""
+	}
+	return record[i]
+}
+
+// detectCSVDelimiter usa ';' quando o cabeçalho não contém ',' (padrão das planilhas em pt-BR)
+func detectCSVDelimiter(reader *bufio.Reader) rune {
+	peek, _ := reader.Peek(4096)
+	if i := bytes.IndexByte(peek, '\n'); i >= 0 {
+		peek = peek[:i]
+	}
+	if bytes.IndexByte(peek, ';') >= 0 && bytes.IndexByte(peek, ',') < 0 {
+		return ';'
+	}
+	return ','
+}
+
+// parsePreco aceita tanto "1234.56" quanto o formato brasileiro "1.234,56"
+func parsePreco(value string) (float64, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0, nil // A obrigatoriedade é verificada pelo validator
+	}
+	if strings.Contains(value, ",") {
+		value = strings.ReplaceAll(value, ".", "")
+		value = strings.Replace(value, ",", ".", 1)
+	}
+	return strconv.ParseFloat(value, 64)
+}
+
+// ndjsonImportReader lê um produto (JSON) por linha, ignorando linhas em branco
+type ndjsonImportReader struct {
+	reader *bufio.Reader
+	line   int
+	eof    bool
+}
+
+func (n *ndjsonImportReader) Next() (importRow, error) {
+	for !n.eof {
+		data, err := n.reader.ReadBytes('\n')
+		if err == io.EOF {
+			n.eof = true
+		} else if err != nil {
+			return importRow{}, err
+		}
+		n.line++
+
+		data = bytes.TrimSpace(data)
+		if len(data) == 0 {
+			continue
+		}
+
+		row := importRow{line: n.line}
+		if err := json.Unmarshal(data, &row.request); err != nil {
+			row.err = errors.ErrInvalidInput.WithDetails("Erro ao decodificar JSON: " + err.Error())
+		}
+		return row, nil
+	}
+	return importRow{}, io.EOF
+}
@@ -0,0 +1,98 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"api-go-arquitetura/internal/dto"
+)
+
+func TestProdutoHandler_ImportProdutos(t *testing.T) {
+	importFile := func(handler *ProdutoHandler, query, contentType, body string) (*httptest.ResponseRecorder, dto.ImportReport) {
+		req := httptest.NewRequest("POST", "/api/v1/produtos/import"+query, strings.NewReader(body))
+		req.Header.Set("Content-Type", contentType)
+		w := httptest.NewRecorder()
+		handler.ImportProdutos(w, req)
+
+		var report dto.ImportReport
+		if w.Code == http.StatusOK {
+			if err := json.NewDecoder(w.Body).Decode(&report); err != nil {
+				t.Fatalf("Erro ao decodificar resposta: %v", err)
+			}
+		}
+		return w, report
+	}
+
+	t.Run("deve importar CSV e reportar as linhas inválidas", func(t *testing.T) {
+		mockService := NewMockProdutoService()
+		handler := NewProdutoHandler(mockService)
+
+		csv := "\ufeffNome;Preco;Descricao\n" +
+			"Notebook;3.500,00;Notebook de alta performance\n" +
+			"Mouse;abc;\n" +
+			";10;Sem nome\n" +
+			"\"Teclado; mecânico\";250.5;\n"
+		w, report := importFile(handler, "", "text/csv; charset=utf-8", csv)
+
+		if w.Code != http.StatusOK {
+			t.Fatalf("Status esperado %d, obtido %d: %s", http.StatusOK, w.Code, w.Body.String())
+		}
+		if report.Total != 4 || report.Accepted != 2 || report.Rejected != 2 {
+			t.Errorf("Esperado 4 linhas, 2 aceitas e 2 rejeitadas, obtido %+v", report)
+		}
+		if len(report.Errors) != 2 || report.Errors[0].Row != 3 || report.Errors[1].Row != 4 {
+			t.Errorf("Linhas com erro esperadas 3 e 4, obtido %+v", report.Errors)
+		}
+		if report.Errors[1].Error.Code != "VALIDATION_ERROR" {
+			t.Errorf("Esperado VALIDATION_ERROR, obtido %s", report.Errors[1].Error.Code)
+		}
+		if len(mockService.produtos) != 2 || mockService.produtos[0].Preco != 3500 || mockService.produtos[1].Nome != "Teclado; mecânico" {
+			t.Errorf("Produtos importados incorretamente: %+v", mockService.produtos)
+		}
+	})
+
+	t.Run("deve importar NDJSON ignorando linhas em branco", func(t *testing.T) {
+		mockService := NewMockProdutoService()
+		handler := NewProdutoHandler(mockService)
+
+		ndjson := `{"nome": "Notebook", "preco": 3500}` + "\n\n" +
+			`{"nome": "Mouse", "preco": ` + "\n" +
+			`{"nome": "Monitor", "preco": 800}`
+		_, report := importFile(handler, "", "application/x-ndjson", ndjson)
+
+		if report.Total != 3 || report.Accepted != 2 {
+			t.Errorf("Esperado 3 linhas e 2 aceitas, obtido %+v", report)
+		}
+		if len(report.Errors) != 1 || report.Errors[0].Row != 3 || report.Errors[0].Error.Code != "INVALID_INPUT" {
+			t.Errorf("Esperado INVALID_INPUT na linha 3, obtido %+v", report.Errors)
+		}
+	})
+
+	t.Run("não deve gravar com dryRun", func(t *testing.T) {
+		mockService := NewMockProdutoService()
+		handler := NewProdutoHandler(mockService)
+
+		_, report := importFile(handler, "?dryRun=true&format=csv", "", "nome,preco\nNotebook,3500\nMouse,0\n")
+
+		if !report.DryRun || report.Accepted != 1 || report.Rejected != 1 {
+			t.Errorf("Esperado 1 linha aceita e 1 rejeitada, obtido %+v", report)
+		}
+		if len(mockService.produtos) != 0 {
+			t.Error("Nenhum produto deveria ser gravado com dryRun")
+		}
+	})
+
+	t.Run("deve rejeitar formato ou cabeçalho inválido", func(t *testing.T) {
+		handler := NewProdutoHandler(NewMockProdutoService())
+
+		if w, _ := importFile(handler, "", "application/json", `{}`); w.Code != http.StatusBadRequest {
+			t.Errorf("Status esperado %d, obtido %d", http.StatusBadRequest, w.Code)
+		}
+		if w, _ := importFile(handler, "", "text/csv", "nome,descricao\nNotebook,x\n"); w.Code != http.StatusBadRequest {
+			t.Errorf("Status esperado %d para CSV sem coluna preco, obtido %d", http.StatusBadRequest, w.Code)
+		}
+	})
+}
@@ -0,0 +1,54 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+
+	"api-go-arquitetura/internal/dto"
+	"api-go-arquitetura/internal/errors"
+	"api-go-arquitetura/internal/utils"
+)
+
+// GetPriceHistory lista as alterações de preço do produto
+// @Summary Histórico de preços
+// @Description Retorna as alterações de preço do produto (feitas via PUT ou PATCH), da mais recente para a mais antiga
+// @Tags produtos
+// @Produce json
+// @Param id path int true "ID do produto"
+// @Param page query int false "Número da página (padrão: 1)" default(1)
+// @Param pageSize query int false "Tamanho da página (padrão: 10, máximo: 100)" default(10)
+// @Param from query string false "Início do período (YYYY-MM-DD ou RFC 3339)"
+// @Param to query string false "Fim do período, inclusivo (YYYY-MM-DD inclui o dia inteiro)"
+// @Success 200 {object} dto.PriceHistoryResponse
+// @Failure 400 {object} errors.APIError
+// @Failure 404 {object} errors.APIError
+// @Failure 500 {object} errors.APIError
+// @Router /api/v1/produtos/{id}/price-history [get]
+// GET /api/v1/produtos/{id}/price-history?from=2024-01-01&to=2024-01-31&page=1&pageSize=10
+func (h *ProdutoHandler) GetPriceHistory(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		utils.ErrorResponse(w, errors.ErrInvalidID)
+		return
+	}
+
+	filter, err := dto.ParsePriceHistoryFilter(r.URL.Query().Get("from"), r.URL.Query().Get("to"))
+	if err != nil {
+		utils.ErrorResponse(w, errors.ErrInvalidInput.WithDetails(err.Error()))
+		return
+	}
+	pagination := dto.PaginationRequest{
+		Page:     getIntQuery(r, "page", 1),
+		PageSize: getIntQuery(r, "pageSize", 10),
+	}
+
+	changes, paginationResp, err := h.service.PriceHistory(r.Context(), id, pagination, filter)
+	if err != nil {
+		utils.ErrorResponse(w, err)
+		return
+	}
+
+	utils.SuccessResponse(w, http.StatusOK, dto.ToPriceHistoryResponse(id, changes, paginationResp))
+}
@@ -0,0 +1,73 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"api-go-arquitetura/internal/api/middleware"
+	"api-go-arquitetura/internal/dto"
+	"api-go-arquitetura/internal/model"
+
+	"github.com/gorilla/mux"
+)
+
+func TestProdutoHandler_GetPriceHistory(t *testing.T) {
+	mockService := NewMockProdutoService()
+	_, _ = mockService.Create(context.Background(), model.Produto{Nome: "Notebook", Preco: 3500})
+	handler := NewProdutoHandler(mockService)
+
+	router := mux.NewRouter()
+	router.HandleFunc("/api/v1/produtos/{id}", handler.PatchProduto).Methods("PATCH")
+	router.HandleFunc("/api/v1/produtos/{id}/price-history", handler.GetPriceHistory).Methods("GET")
+	h := middleware.RequestIDMiddleware(router)
+
+	req := httptest.NewRequest("PATCH", "/api/v1/produtos/1", bytes.NewBufferString(`{"preco": 3299.9}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(middleware.RequestIDHeader, "req-123")
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Status esperado %d, obtido %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+
+	t.Run("deve retornar o histórico com o request ID", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, httptest.NewRequest("GET", "/api/v1/produtos/1/price-history?from=2020-01-01", nil))
+		if w.Code != http.StatusOK {
+			t.Fatalf("Status esperado %d, obtido %d: %s", http.StatusOK, w.Code, w.Body.String())
+		}
+		var response dto.PriceHistoryResponse
+		if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+			t.Fatalf("Erro ao decodificar resposta: %v", err)
+		}
+		if len(response.Historico) != 1 {
+			t.Fatalf("Esperada 1 alteração, obtidas %d", len(response.Historico))
+		}
+		change := response.Historico[0]
+		if change.PrecoAnterior != 3500 || change.PrecoNovo != 3299.9 || change.RequestID != "req-123" {
+			t.Errorf("Alteração incorreta: %+v", change)
+		}
+	})
+
+	t.Run("deve validar o período", func(t *testing.T) {
+		for _, query := range []string{"from=ontem", "from=2024-02-01&to=2024-01-01"} {
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, httptest.NewRequest("GET", "/api/v1/produtos/1/price-history?"+query, nil))
+			if w.Code != http.StatusBadRequest {
+				t.Errorf("%s: status esperado %d, obtido %d", query, http.StatusBadRequest, w.Code)
+			}
+		}
+	})
+
+	t.Run("deve retornar 404 para produto inexistente", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, httptest.NewRequest("GET", "/api/v1/produtos/99/price-history", nil))
+		if w.Code != http.StatusNotFound {
+			t.Errorf("Status esperado %d, obtido %d", http.StatusNotFound, w.Code)
+		}
+	})
+}
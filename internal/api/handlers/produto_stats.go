@@ -0,0 +1,86 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"api-go-arquitetura/internal/dto"
+	"api-go-arquitetura/internal/errors"
+	"api-go-arquitetura/internal/model"
+	"api-go-arquitetura/internal/utils"
+)
+
+// GetProdutosStats retorna estatísticas e facetas dos produtos
+// @Summary Estatísticas e facetas de produtos
+// @Description Calcula min/max/média de preço, histograma de preço e contagem por tag e por categoria dos produtos
+// @Description que correspondem aos filtros (os mesmos da listagem). O resultado é armazenado no cache
+// @Tags produtos
+// @Produce json
+// @Param facets query string false "Facetas separadas por vírgula (stats, preco, tags, categoria); padrão: todas"
+// @Param buckets query int false "Número de faixas do histograma de preço (máximo 50)" default(10)
+// @Param nome query string false "Filtro por nome (busca parcial, case-insensitive)"
+// @Param precoMin query number false "Preço mínimo"
+// @Param precoMax query number false "Preço máximo"
+// @Param descricao query string false "Filtro por descrição (busca parcial, case-insensitive)"
+// @Param q query string false "Busca textual em nome e descrição (com stemming em português)"
+// @Param filter query string false "Expressão de filtro campo:operador:valor separada por vírgulas (ex.: preco:gte:100,nome:startswith:Note)"
+// @Success 200 {object} dto.ProdutoStatsResponse
+// @Failure 400 {object} errors.APIError
+// @Failure 500 {object} errors.APIError
+// @Router /api/v1/produtos/stats [get]
+// GET /api/v1/produtos/stats?facets=stats,preco&buckets=5&q=notebook
+func (h *ProdutoHandler) GetProdutosStats(w http.ResponseWriter, r *http.Request) {
+	facets, err := h.service.Facets(r.Context(), getFilterQuery(r), getFacetQuery(r))
+	if err != nil {
+		utils.ErrorResponse(w, err)
+		return
+	}
+
+	response := dto.ProdutoStatsResponse{Facets: facets}
+	body, err := json.Marshal(response)
+	if err != nil {
+		utils.ErrorResponse(w, errors.WrapError(err, errors.ErrInternalServer))
+		return
+	}
+	etag := utils.ContentETag(body)
+	utils.SetCacheValidators(w, etag, time.Time{})
+	if utils.NotModified(w, r, etag, time.Time{}) {
+		return
+	}
+	utils.SuccessResponse(w, http.StatusOK, response)
+}
+
+// getFacetQuery obtém as facetas solicitadas a partir da query string
+func getFacetQuery(r *http.Request) dto.FacetRequest {
+	return dto.FacetRequest{
+		Facets:  dto.ParseFacets(r.URL.Query().Get("facets")),
+		Buckets: getIntQuery(r, "buckets", dto.DefaultPrecoBuckets),
+	}
+}
+
+// listFacets calcula as facetas pedidas na listagem (?facets=); retorna nil
+// quando o parâmetro não foi informado
+func (h *ProdutoHandler) listFacets(r *http.Request, filter dto.FilterRequest) (*model.ProdutoFacets, error) {
+	if r.URL.Query().Get("facets") == "" {
+		return nil, nil
+	}
+	facets, err := h.service.Facets(r.Context(), filter, getFacetQuery(r))
+	if err != nil {
+		return nil, err
+	}
+	return &facets, nil
+}
+
+// facetsETag combina o ETag da página com o conteúdo das facetas: as facetas
+// cobrem todo o resultado filtrado e podem mudar sem que a página mude
+func facetsETag(etag string, facets *model.ProdutoFacets) (string, error) {
+	if facets == nil {
+		return etag, nil
+	}
+	data, err := json.Marshal(facets)
+	if err != nil {
+		return "", err
+	}
+	return utils.ContentETag(append([]byte(etag), data...)), nil
+}
@@ -0,0 +1,73 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"api-go-arquitetura/internal/dto"
+	"api-go-arquitetura/internal/model"
+)
+
+func TestProdutoHandler_GetProdutosStats(t *testing.T) {
+	mockService := NewMockProdutoService()
+	handler := NewProdutoHandler(mockService)
+
+	_, _ = mockService.Create(context.Background(), model.Produto{Nome: "Notebook", Preco: 3500})
+	_, _ = mockService.Create(context.Background(), model.Produto{Nome: "Mouse", Preco: 150})
+
+	t.Run("deve retornar as estatísticas com ETag", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/api/v1/produtos/stats?facets=stats", nil)
+		w := httptest.NewRecorder()
+		handler.GetProdutosStats(w, req)
+
+		if w.Code != http.StatusOK {
+			t.Fatalf("Status esperado %d, obtido %d: %s", http.StatusOK, w.Code, w.Body.String())
+		}
+		var response dto.ProdutoStatsResponse
+		if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+			t.Fatalf("Erro ao decodificar resposta: %v", err)
+		}
+		if stats := response.Facets.Stats; stats == nil || stats.Count != 2 || stats.Min != 150 || stats.Max != 3500 {
+			t.Errorf("Estatísticas incorretas: %+v", stats)
+		}
+
+		etag := w.Header().Get("ETag")
+		req = httptest.NewRequest("GET", "/api/v1/produtos/stats?facets=stats", nil)
+		req.Header.Set("If-None-Match", etag)
+		w = httptest.NewRecorder()
+		handler.GetProdutosStats(w, req)
+		if w.Code != http.StatusNotModified {
+			t.Errorf("Status esperado %d, obtido %d", http.StatusNotModified, w.Code)
+		}
+	})
+
+	t.Run("deve rejeitar faceta inválida", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/api/v1/produtos/stats?facets=cor", nil)
+		w := httptest.NewRecorder()
+		handler.GetProdutosStats(w, req)
+
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("Status esperado %d, obtido %d", http.StatusBadRequest, w.Code)
+		}
+	})
+
+	t.Run("deve incluir as facetas na listagem", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/api/v1/produtos?facets=stats", nil)
+		w := httptest.NewRecorder()
+		handler.GetProdutos(w, req)
+
+		if w.Code != http.StatusOK {
+			t.Fatalf("Status esperado %d, obtido %d: %s", http.StatusOK, w.Code, w.Body.String())
+		}
+		var response dto.PaginatedProdutoListResponse
+		if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+			t.Fatalf("Erro ao decodificar resposta: %v", err)
+		}
+		if len(response.Produtos) != 2 || response.Facets == nil || response.Facets.Stats == nil || response.Facets.Stats.Count != 2 {
+			t.Errorf("Resposta paginada com facetas esperada, obtido %+v", response)
+		}
+	})
+}
@@ -6,22 +6,32 @@ import (
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"strconv"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/gorilla/mux"
+	"go.mongodb.org/mongo-driver/bson"
 
 	"api-go-arquitetura/internal/dto"
 	apiErrors "api-go-arquitetura/internal/errors"
 	"api-go-arquitetura/internal/model"
 	"api-go-arquitetura/internal/service"
+	"api-go-arquitetura/internal/utils"
 )
 
 // MockProdutoService é um mock do ProdutoService para testes
 type MockProdutoService struct {
-	produtos []model.Produto
-	nextID   int
+	produtos     []model.Produto
+	trash        []model.Produto
+	nextID       int
+	priceHistory []model.PriceChange
 }
 
+// Garante em tempo de compilação que o mock implementa a interface
+var _ service.ProdutoService = (*MockProdutoService)(nil)
+
 func NewMockProdutoService() *MockProdutoService {
 	return &MockProdutoService{
 		produtos: make([]model.Produto, 0),
@@ -31,6 +41,7 @@ func NewMockProdutoService() *MockProdutoService {
 
 func (m *MockProdutoService) Create(ctx context.Context, produto model.Produto) (model.Produto, error) {
 	produto.ID = m.nextID
+	produto.BeforeCreate()
 	m.nextID++
 	m.produtos = append(m.produtos, produto)
 	return produto, nil
@@ -40,7 +51,7 @@ func (m *MockProdutoService) FindAll(ctx context.Context) ([]model.Produto, erro
 	return m.produtos, nil
 }
 
-func (m *MockProdutoService) FindByID(ctx context.Context, id int) (model.Produto, error) {
+func (m *MockProdutoService) FindByID(ctx context.Context, id int, fields ...string) (model.Produto, error) {
 	for _, p := range m.produtos {
 		if p.ID == id {
 			return p, nil
@@ -49,26 +60,36 @@ func (m *MockProdutoService) FindByID(ctx context.Context, id int) (model.Produt
 	return model.Produto{}, apiErrors.ErrProdutoNotFound
 }
 
-func (m *MockProdutoService) Update(ctx context.Context, id int, produto model.Produto) (model.Produto, error) {
+func (m *MockProdutoService) Update(ctx context.Context, id int, produto model.Produto, expectedVersion *int) (model.Produto, error) {
 	for i, p := range m.produtos {
 		if p.ID == id {
+			if expectedVersion != nil && p.Version != *expectedVersion {
+				return model.Produto{}, apiErrors.ErrPreconditionFailed
+			}
 			produto.ID = id
+			produto.Version = p.Version + 1
 			m.produtos[i] = produto
+			m.recordPriceChange(ctx, p, produto)
 			return produto, nil
 		}
 	}
 	return model.Produto{}, apiErrors.ErrProdutoNotFound
 }
 
-func (m *MockProdutoService) Patch(ctx context.Context, id int, updates map[string]interface{}) (model.Produto, error) {
+func (m *MockProdutoService) Patch(ctx context.Context, id int, updates map[string]interface{}, expectedVersion *int) (model.Produto, error) {
 	for i, p := range m.produtos {
 		if p.ID == id {
+			if expectedVersion != nil && p.Version != *expectedVersion {
+				return model.Produto{}, apiErrors.ErrPreconditionFailed
+			}
+			p.Version++
 			if nome, ok := updates["nome"].(string); ok {
 				p.Nome = nome
 			}
 			if preco, ok := updates["preco"].(float64); ok {
 				p.Preco = preco
 			}
+			m.recordPriceChange(ctx, m.produtos[i], p)
 			m.produtos[i] = p
 			return p, nil
 		}
@@ -76,9 +97,14 @@ func (m *MockProdutoService) Patch(ctx context.Context, id int, updates map[stri
 	return model.Produto{}, apiErrors.ErrProdutoNotFound
 }
 
-func (m *MockProdutoService) Delete(ctx context.Context, id int) error {
+func (m *MockProdutoService) Delete(ctx context.Context, id int, expectedVersion *int) error {
 	for i, p := range m.produtos {
 		if p.ID == id {
+			if expectedVersion != nil && p.Version != *expectedVersion {
+				return apiErrors.ErrPreconditionFailed
+			}
+			p.SoftDelete()
+			m.trash = append(m.trash, p)
 			m.produtos = append(m.produtos[:i], m.produtos[i+1:]...)
 			return nil
 		}
@@ -86,6 +112,231 @@ func (m *MockProdutoService) Delete(ctx context.Context, id int) error {
 	return apiErrors.ErrProdutoNotFound
 }
 
+func (m *MockProdutoService) Restore(ctx context.Context, id int) (model.Produto, error) {
+	for i, p := range m.trash {
+		if p.ID == id {
+			p.Restore()
+			m.produtos = append(m.produtos, p)
+			m.trash = append(m.trash[:i], m.trash[i+1:]...)
+			return p, nil
+		}
+	}
+	return model.Produto{}, apiErrors.ErrProdutoNotFound
+}
+
+func (m *MockProdutoService) PurgeTrash(ctx context.Context, before time.Time) (int64, error) {
+	var kept []model.Produto
+	var purged int64
+	for _, p := range m.trash {
+		if p.DeletedAt.Before(before) {
+			purged++
+			continue
+		}
+		kept = append(kept, p)
+	}
+	m.trash = kept
+	return purged, nil
+}
+
+func (m *MockProdutoService) FindTrashPaginated(ctx context.Context, pagination dto.PaginationRequest, sort dto.SortRequest) ([]model.Produto, dto.PaginationResponse, error) {
+	pagination.Validate()
+	if err := sort.ValidateTrash(); err != nil {
+		return nil, dto.PaginationResponse{}, apiErrors.ErrInvalidInput.WithDetails(err.Error())
+	}
+	return m.trash, dto.NewPaginationResponse(pagination.Page, pagination.PageSize, len(m.trash)), nil
+}
+
+func (m *MockProdutoService) FindAllPaginated(ctx context.Context, pagination dto.PaginationRequest, filter dto.FilterRequest, sort dto.SortRequest, fields ...string) (service.ProdutoPage, error) {
+	pagination.Validate()
+	body, _ := json.Marshal(m.produtos)
+	return service.ProdutoPage{
+		Produtos:   m.produtos,
+		Pagination: dto.NewPaginationResponse(pagination.Page, pagination.PageSize, len(m.produtos)),
+		ETag:       utils.ContentETag(body),
+	}, nil
+}
+
+func (m *MockProdutoService) FindAllByCursor(ctx context.Context, pagination dto.CursorPaginationRequest, filter dto.FilterRequest, sort dto.SortRequest, fields ...string) (service.ProdutoCursorPage, error) {
+	pagination.Validate()
+	keyset, err := sort.KeysetFilter(pagination.Cursor)
+	if err != nil {
+		return service.ProdutoCursorPage{}, apiErrors.ErrInvalidInput.WithDetails(err.Error())
+	}
+	// O mock ordena apenas por ID
+	after := 0
+	if keyset != nil {
+		after = keyset["id"].(bson.M)["$gt"].(int)
+	}
+	page := service.ProdutoCursorPage{Pagination: dto.CursorPaginationResponse{PageSize: pagination.PageSize}}
+	for _, p := range m.produtos {
+		if p.ID <= after {
+			continue
+		}
+		if len(page.Produtos) == pagination.PageSize {
+			page.Pagination.HasNext = true
+			page.Pagination.NextCursor, _ = sort.EncodeCursor(page.Produtos[len(page.Produtos)-1])
+			break
+		}
+		page.Produtos = append(page.Produtos, p)
+	}
+	return page, nil
+}
+
+func (m *MockProdutoService) Export(ctx context.Context, filter dto.FilterRequest, sort dto.SortRequest, fn func(model.Produto) error) error {
+	if err := sort.Validate(); err != nil {
+		return apiErrors.ErrInvalidInput.WithDetails(err.Error())
+	}
+	for _, p := range m.produtos {
+		if filter.Nome != nil && !strings.Contains(strings.ToLower(p.Nome), strings.ToLower(*filter.Nome)) {
+			continue
+		}
+		if err := fn(p); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m *MockProdutoService) Facets(ctx context.Context, filter dto.FilterRequest, facets dto.FacetRequest) (model.ProdutoFacets, error) {
+	if err := facets.Validate(); err != nil {
+		return model.ProdutoFacets{}, apiErrors.ErrInvalidInput.WithDetails(err.Error())
+	}
+	var result model.ProdutoFacets
+	if facets.Has(dto.FacetStats) {
+		result.Stats = &model.PrecoStats{}
+		for _, p := range m.produtos {
+			if result.Stats.Count == 0 || p.Preco < result.Stats.Min {
+				result.Stats.Min = p.Preco
+			}
+			if p.Preco > result.Stats.Max {
+				result.Stats.Max = p.Preco
+			}
+			result.Stats.Count++
+		}
+	}
+	return result, nil
+}
+
+func (m *MockProdutoService) Batch(ctx context.Context, ops []service.BatchOperation, atomic bool) ([]service.BatchResult, error) {
+	produtos, trash, nextID := append([]model.Produto(nil), m.produtos...), append([]model.Produto(nil), m.trash...), m.nextID
+	results := make([]service.BatchResult, len(ops))
+	failed := false
+	for i, op := range ops {
+		var err error
+		switch op.Op {
+		case dto.BatchOpCreate:
+			results[i].Produto, err = m.Create(ctx, op.Produto)
+		case dto.BatchOpUpdate:
+			results[i].Produto, err = m.Update(ctx, op.ID, op.Produto, op.ExpectedVersion)
+		case dto.BatchOpPatch:
+			results[i].Produto, err = m.Patch(ctx, op.ID, op.Updates, op.ExpectedVersion)
+		case dto.BatchOpDelete:
+			err = m.Delete(ctx, op.ID, op.ExpectedVersion)
+		}
+		if err != nil {
+			results[i].Err = apiErrors.AsAPIError(err)
+			failed = true
+		}
+	}
+	if atomic && failed {
+		m.produtos, m.trash, m.nextID = produtos, trash, nextID
+		for i := range results {
+			if results[i].Err == nil {
+				results[i] = service.BatchResult{Err: apiErrors.ErrBatchAborted}
+			}
+		}
+	}
+	return results, nil
+}
+
+func (m *MockProdutoService) AdjustStock(ctx context.Context, id int, delta int) (model.Produto, error) {
+	for i, p := range m.produtos {
+		if p.ID == id {
+			if p.Estoque+delta < p.Reservado {
+				return model.Produto{}, apiErrors.ErrEstoqueInsuficiente
+			}
+			p.Estoque += delta
+			p.Version++
+			m.produtos[i] = p
+			return p, nil
+		}
+	}
+	return model.Produto{}, apiErrors.ErrProdutoNotFound
+}
+
+func (m *MockProdutoService) Reserve(ctx context.Context, id int, quantidade int, reservaID string, ttl time.Duration) (model.Reserva, model.Produto, error) {
+	for i, p := range m.produtos {
+		if p.ID == id {
+			if p.Disponivel() < quantidade {
+				return model.Reserva{}, model.Produto{}, apiErrors.ErrEstoqueInsuficiente
+			}
+			if reservaID == "" {
+				reservaID = "reserva-" + strconv.Itoa(len(p.Reservas)+1)
+			}
+			reserva := model.Reserva{ID: reservaID, Quantidade: quantidade, ExpiresAt: time.Now().Add(ttl)}
+			p.Reservas = append(p.Reservas, reserva)
+			p.Reservado += quantidade
+			p.Version++
+			m.produtos[i] = p
+			return reserva, p, nil
+		}
+	}
+	return model.Reserva{}, model.Produto{}, apiErrors.ErrProdutoNotFound
+}
+
+func (m *MockProdutoService) Release(ctx context.Context, id int, reservaID string) (model.Produto, error) {
+	for i, p := range m.produtos {
+		if p.ID == id {
+			for j, reserva := range p.Reservas {
+				if reserva.ID == reservaID {
+					p.Reservas = append(p.Reservas[:j:j], p.Reservas[j+1:]...)
+					p.Reservado -= reserva.Quantidade
+					p.Version++
+					m.produtos[i] = p
+					return p, nil
+				}
+			}
+			return model.Produto{}, apiErrors.ErrReservaNotFound
+		}
+	}
+	return model.Produto{}, apiErrors.ErrProdutoNotFound
+}
+
+func (m *MockProdutoService) ReleaseExpired(ctx context.Context, now time.Time) (int, error) {
+	return 0, nil
+}
+
+// recordPriceChange simula o histórico gravado quando o preço muda
+func (m *MockProdutoService) recordPriceChange(ctx context.Context, before, after model.Produto) {
+	if before.Preco != after.Preco {
+		m.priceHistory = append(m.priceHistory, model.PriceChange{
+			ProdutoID:     after.ID,
+			PrecoAnterior: before.Preco,
+			PrecoNovo:     after.Preco,
+			Version:       after.Version,
+			RequestID:     utils.RequestIDFromContext(ctx),
+			ChangedAt:     time.Now(),
+		})
+	}
+}
+
+func (m *MockProdutoService) PriceHistory(ctx context.Context, id int, pagination dto.PaginationRequest, filter dto.PriceHistoryFilter) ([]model.PriceChange, dto.PaginationResponse, error) {
+	if err := filter.Validate(); err != nil {
+		return nil, dto.PaginationResponse{}, apiErrors.ErrInvalidInput.WithDetails(err.Error())
+	}
+	if _, err := m.FindByID(ctx, id); err != nil {
+		return nil, dto.PaginationResponse{}, err
+	}
+	changes := []model.PriceChange{}
+	for i := len(m.priceHistory) - 1; i >= 0; i-- {
+		if m.priceHistory[i].ProdutoID == id {
+			changes = append(changes, m.priceHistory[i])
+		}
+	}
+	pagination.Validate()
+	return changes, dto.NewPaginationResponse(pagination.Page, pagination.PageSize, len(changes)), nil
+}
+
 func TestProdutoHandler_CreateProduto(t *testing.T) {
 	mockService := NewMockProdutoService()
 	handler := NewProdutoHandler(mockService)
@@ -241,3 +492,314 @@ func TestProdutoHandler_GetProdutos(t *testing.T) {
 	})
 }
 
+
+func TestProdutoHandler_RestoreProduto(t *testing.T) {
+	mockService := NewMockProdutoService()
+	handler := NewProdutoHandler(mockService)
+
+	created, _ := mockService.Create(context.Background(), model.Produto{Nome: "Notebook", Preco: 3500.00})
+	_ = mockService.Delete(context.Background(), created.ID, nil)
+
+	router := mux.NewRouter()
+	router.HandleFunc("/api/v1/produtos/trash", handler.GetProdutosTrash).Methods("GET")
+	router.HandleFunc("/api/v1/produtos/{id}/restore", handler.RestoreProduto).Methods("POST")
+
+	t.Run("deve listar produto na lixeira", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/api/v1/produtos/trash?sort=deleted_at&order=desc", nil)
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		if w.Code != http.StatusOK {
+			t.Fatalf("Status esperado %d, obtido %d", http.StatusOK, w.Code)
+		}
+
+		var response dto.PaginatedProdutoListResponse
+		if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+			t.Fatalf("Erro ao decodificar resposta: %v", err)
+		}
+		if len(response.Produtos) != 1 || response.Produtos[0].DeletedAt == nil {
+			t.Errorf("Esperado 1 produto removido na lixeira, obtido %+v", response.Produtos)
+		}
+	})
+
+	t.Run("deve rejeitar campo de ordenação inválido", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/api/v1/produtos/trash?sort=senha", nil)
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("Status esperado %d, obtido %d", http.StatusBadRequest, w.Code)
+		}
+	})
+
+	t.Run("deve restaurar produto removido", func(t *testing.T) {
+		req := httptest.NewRequest("POST", "/api/v1/produtos/1/restore", nil)
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		if w.Code != http.StatusOK {
+			t.Fatalf("Status esperado %d, obtido %d", http.StatusOK, w.Code)
+		}
+		if _, err := mockService.FindByID(context.Background(), created.ID); err != nil {
+			t.Errorf("Produto deveria estar disponível após restauração: %v", err)
+		}
+	})
+
+	t.Run("deve retornar 404 quando produto não está na lixeira", func(t *testing.T) {
+		req := httptest.NewRequest("POST", "/api/v1/produtos/1/restore", nil)
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		if w.Code != http.StatusNotFound {
+			t.Errorf("Status esperado %d, obtido %d", http.StatusNotFound, w.Code)
+		}
+	})
+}
+
+func TestProdutoHandler_IfMatch(t *testing.T) {
+	mockService := NewMockProdutoService()
+	handler := NewProdutoHandler(mockService)
+
+	created, _ := mockService.Create(context.Background(), model.Produto{Nome: "Notebook", Preco: 3500.00})
+
+	router := mux.NewRouter()
+	router.HandleFunc("/api/v1/produtos/{id}", handler.GetProduto).Methods("GET")
+	router.HandleFunc("/api/v1/produtos/{id}", handler.PatchProduto).Methods("PATCH")
+	router.HandleFunc("/api/v1/produtos/{id}", handler.DeleteProduto).Methods("DELETE")
+
+	t.Run("deve retornar a versão no ETag", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/api/v1/produtos/1", nil)
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		if etag := w.Header().Get("ETag"); etag != `"1"` {
+			t.Errorf("ETag esperado %q, obtido %q", `"1"`, etag)
+		}
+	})
+
+	t.Run("deve aplicar PATCH com If-Match atual e retornar novo ETag", func(t *testing.T) {
+		req := httptest.NewRequest("PATCH", "/api/v1/produtos/1", bytes.NewBufferString(`{"preco": 4000}`))
+		req.Header.Set("If-Match", formatETag(created.Version))
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		if w.Code != http.StatusOK {
+			t.Fatalf("Status esperado %d, obtido %d", http.StatusOK, w.Code)
+		}
+		if etag := w.Header().Get("ETag"); etag != `"2"` {
+			t.Errorf("ETag esperado %q, obtido %q", `"2"`, etag)
+		}
+	})
+
+	t.Run("deve responder 412 para If-Match desatualizado", func(t *testing.T) {
+		req := httptest.NewRequest("PATCH", "/api/v1/produtos/1", bytes.NewBufferString(`{"preco": 10}`))
+		req.Header.Set("If-Match", `"1"`)
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		if w.Code != http.StatusPreconditionFailed {
+			t.Errorf("Status esperado %d, obtido %d", http.StatusPreconditionFailed, w.Code)
+		}
+	})
+
+	t.Run("deve responder 400 para If-Match malformado", func(t *testing.T) {
+		for _, value := range []string{`2`, `"2`, `"abc"`} {
+			req := httptest.NewRequest("DELETE", "/api/v1/produtos/1", nil)
+			req.Header.Set("If-Match", value)
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			if w.Code != http.StatusBadRequest {
+				t.Errorf("If-Match %s: status esperado %d, obtido %d", value, http.StatusBadRequest, w.Code)
+			}
+		}
+	})
+
+	t.Run("deve responder 412 para If-Match fraco ou que não corresponde a uma versão", func(t *testing.T) {
+		for _, value := range []string{`W/"2"`, `"1", "2"`, `"2-nome"`} {
+			req := httptest.NewRequest("DELETE", "/api/v1/produtos/1", nil)
+			req.Header.Set("If-Match", value)
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			if w.Code != http.StatusPreconditionFailed {
+				t.Errorf("If-Match %s: status esperado %d, obtido %d", value, http.StatusPreconditionFailed, w.Code)
+			}
+		}
+	})
+
+	t.Run("deve aceitar If-Match 0 para produtos sem versão", func(t *testing.T) {
+		legacy, _ := mockService.Create(context.Background(), model.Produto{Nome: "Mouse", Preco: 150.00})
+		mockService.produtos[len(mockService.produtos)-1].Version = 0
+
+		req := httptest.NewRequest("PATCH", "/api/v1/produtos/"+strconv.Itoa(legacy.ID), bytes.NewBufferString(`{"preco": 200}`))
+		req.Header.Set("If-Match", `"0"`)
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		if w.Code != http.StatusOK {
+			t.Errorf("Status esperado %d, obtido %d", http.StatusOK, w.Code)
+		}
+	})
+
+	t.Run("deve aceitar If-Match curinga", func(t *testing.T) {
+		req := httptest.NewRequest("DELETE", "/api/v1/produtos/1", nil)
+		req.Header.Set("If-Match", "*")
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		if w.Code != http.StatusNoContent {
+			t.Errorf("Status esperado %d, obtido %d", http.StatusNoContent, w.Code)
+		}
+	})
+}
+
+func TestProdutoHandler_ConditionalGet(t *testing.T) {
+	mockService := NewMockProdutoService()
+	handler := NewProdutoHandler(mockService)
+
+	created, _ := mockService.Create(context.Background(), model.Produto{Nome: "Notebook", Preco: 3500.00})
+
+	router := mux.NewRouter()
+	router.HandleFunc("/api/v1/produtos", handler.GetProdutos).Methods("GET")
+	router.HandleFunc("/api/v1/produtos/{id}", handler.GetProduto).Methods("GET")
+
+	get := func(url string, headers map[string]string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("GET", url, nil)
+		for k, v := range headers {
+			req.Header.Set(k, v)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("deve emitir Last-Modified no produto", func(t *testing.T) {
+		w := get("/api/v1/produtos/1", nil)
+		expected := created.UpdatedAt.UTC().Format(http.TimeFormat)
+		if lm := w.Header().Get("Last-Modified"); lm != expected {
+			t.Errorf("Last-Modified esperado %q, obtido %q", expected, lm)
+		}
+	})
+
+	t.Run("deve responder 304 para If-None-Match correspondente", func(t *testing.T) {
+		w := get("/api/v1/produtos/1", map[string]string{"If-None-Match": `"0", "1"`})
+		if w.Code != http.StatusNotModified {
+			t.Errorf("Status esperado %d, obtido %d", http.StatusNotModified, w.Code)
+		}
+		if w.Body.Len() != 0 {
+			t.Error("Resposta 304 não deve conter corpo")
+		}
+	})
+
+	t.Run("deve responder 200 para If-None-Match diferente", func(t *testing.T) {
+		w := get("/api/v1/produtos/1", map[string]string{"If-None-Match": `"99"`})
+		if w.Code != http.StatusOK {
+			t.Errorf("Status esperado %d, obtido %d", http.StatusOK, w.Code)
+		}
+	})
+
+	t.Run("deve responder 304 para If-Modified-Since posterior", func(t *testing.T) {
+		since := created.UpdatedAt.Add(time.Minute).UTC().Format(http.TimeFormat)
+		w := get("/api/v1/produtos/1", map[string]string{"If-Modified-Since": since})
+		if w.Code != http.StatusNotModified {
+			t.Errorf("Status esperado %d, obtido %d", http.StatusNotModified, w.Code)
+		}
+	})
+
+	t.Run("deve responder 200 para If-Modified-Since anterior", func(t *testing.T) {
+		since := created.UpdatedAt.Add(-time.Hour).UTC().Format(http.TimeFormat)
+		w := get("/api/v1/produtos/1", map[string]string{"If-Modified-Since": since})
+		if w.Code != http.StatusOK {
+			t.Errorf("Status esperado %d, obtido %d", http.StatusOK, w.Code)
+		}
+	})
+
+	for _, url := range []string{"/api/v1/produtos", "/api/v1/produtos?page=1&pageSize=10"} {
+		t.Run("deve revalidar a lista "+url, func(t *testing.T) {
+			first := get(url, nil)
+			etag := first.Header().Get("ETag")
+			if etag == "" {
+				t.Fatal("Lista deveria emitir ETag")
+			}
+			if lm := first.Header().Get("Last-Modified"); lm != "" {
+				t.Errorf("Lista não deveria emitir Last-Modified, obtido %q", lm)
+			}
+
+			w := get(url, map[string]string{"If-None-Match": etag})
+			if w.Code != http.StatusNotModified {
+				t.Errorf("Status esperado %d, obtido %d", http.StatusNotModified, w.Code)
+			}
+
+			// Alterar o conteúdo deve gerar um novo ETag
+			_, _ = mockService.Patch(context.Background(), created.ID, map[string]interface{}{"nome": "Notebook " + url}, nil)
+			w = get(url, map[string]string{"If-None-Match": etag})
+			if w.Code != http.StatusOK {
+				t.Errorf("Status esperado %d após alteração, obtido %d", http.StatusOK, w.Code)
+			}
+		})
+	}
+}
+
+func TestProdutoHandler_GetProdutosByCursor(t *testing.T) {
+	mockService := NewMockProdutoService()
+	handler := NewProdutoHandler(mockService)
+	for i := 0; i < 5; i++ {
+		_, _ = mockService.Create(context.Background(), model.Produto{Nome: "Produto", Preco: 10})
+	}
+
+	list := func(query string) (*httptest.ResponseRecorder, dto.CursorProdutoListResponse) {
+		req := httptest.NewRequest("GET", "/api/v1/produtos"+query, nil)
+		w := httptest.NewRecorder()
+		handler.GetProdutos(w, req)
+
+		var response dto.CursorProdutoListResponse
+		if w.Code == http.StatusOK {
+			if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+				t.Fatalf("Erro ao decodificar resposta: %v", err)
+			}
+		}
+		return w, response
+	}
+
+	t.Run("deve percorrer todas as páginas pelo nextCursor", func(t *testing.T) {
+		var ids []int
+		query := "?cursor=&pageSize=2"
+		for pages := 0; pages < 10; pages++ {
+			w, response := list(query)
+			if w.Code != http.StatusOK {
+				t.Fatalf("Status esperado %d, obtido %d", http.StatusOK, w.Code)
+			}
+			for _, p := range response.Produtos {
+				ids = append(ids, p.ID)
+			}
+			if !response.Pagination.HasNext {
+				break
+			}
+			query = "?pageSize=2&cursor=" + response.Pagination.NextCursor
+		}
+
+		if len(ids) != 5 {
+			t.Errorf("Esperados 5 produtos, obtidos %v", ids)
+		}
+	})
+
+	t.Run("deve rejeitar cursor inválido ou combinado com page", func(t *testing.T) {
+		for _, query := range []string{"?cursor=invalido", "?cursor=&page=2"} {
+			if w, _ := list(query); w.Code != http.StatusBadRequest {
+				t.Errorf("Status esperado %d para %s, obtido %d", http.StatusBadRequest, query, w.Code)
+			}
+		}
+	})
+}
@@ -0,0 +1,226 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+
+	"api-go-arquitetura/internal/dto"
+	"api-go-arquitetura/internal/errors"
+	"api-go-arquitetura/internal/service"
+	"api-go-arquitetura/internal/utils"
+	"api-go-arquitetura/internal/validator"
+)
+
+// WebhookHandler gerencia os handlers de webhooks
+type WebhookHandler struct {
+	service service.WebhookService
+}
+
+// NewWebhookHandler cria uma nova instância do WebhookHandler
+func NewWebhookHandler(svc service.WebhookService) *WebhookHandler {
+	return &WebhookHandler{
+		service: svc,
+	}
+}
+
+// GetWebhooks lista todos os webhooks
+// @Summary Lista webhooks
+// @Tags webhooks
+// @Produce json
+// @Success 200 {object} dto.WebhookListResponse
+// @Failure 500 {object} errors.APIError
+// @Router /api/v1/webhooks [get]
+// GET /api/v1/webhooks
+func (h *WebhookHandler) GetWebhooks(w http.ResponseWriter, r *http.Request) {
+	webhooks, err := h.service.FindAll(r.Context())
+	if err != nil {
+		utils.ErrorResponse(w, err)
+		return
+	}
+	utils.SuccessResponse(w, http.StatusOK, dto.ToWebhookListResponse(webhooks))
+}
+
+// GetWebhook obtém um webhook por ID
+// @Summary Obtém um webhook
+// @Tags webhooks
+// @Produce json
+// @Param id path int true "ID do webhook"
+// @Success 200 {object} dto.WebhookResponse
+// @Failure 400 {object} errors.APIError
+// @Failure 404 {object} errors.APIError
+// @Router /api/v1/webhooks/{id} [get]
+// GET /api/v1/webhooks/{id}
+func (h *WebhookHandler) GetWebhook(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		utils.ErrorResponse(w, errors.ErrInvalidID)
+		return
+	}
+
+	webhook, err := h.service.FindByID(r.Context(), id)
+	if err != nil {
+		utils.ErrorResponse(w, err)
+		return
+	}
+	utils.SuccessResponse(w, http.StatusOK, dto.FromWebhook(webhook))
+}
+
+// CreateWebhook cadastra um webhook
+// @Summary Cadastra um webhook
+// @Description O secret usado na assinatura (X-Webhook-Signature) é gerado quando omitido e só é retornado nesta resposta
+// @Tags webhooks
+// @Accept json
+// @Produce json
+// @Param webhook body dto.WebhookRequest true "Dados do webhook"
+// @Success 201 {object} dto.WebhookResponse
+// @Failure 400 {object} errors.APIError
+// @Failure 422 {object} errors.APIError
+// @Router /api/v1/webhooks [post]
+// POST /api/v1/webhooks
+func (h *WebhookHandler) CreateWebhook(w http.ResponseWriter, r *http.Request) {
+	var request dto.WebhookRequest
+	if err := utils.DecodeJSON(r.Body, &request); err != nil {
+		utils.BadRequestResponse(w, "Erro ao decodificar JSON: "+err.Error())
+		return
+	}
+	if validationErrors := validator.Validate(&request); len(validationErrors) > 0 {
+		utils.ValidationErrorResponse(w, validationErrors)
+		return
+	}
+
+	created, err := h.service.Create(r.Context(), request.ToModel())
+	if err != nil {
+		utils.ErrorResponse(w, err)
+		return
+	}
+	response := dto.FromWebhook(created)
+	response.Secret = created.Secret
+	utils.SuccessResponse(w, http.StatusCreated, response)
+}
+
+// UpdateWebhook atualiza um webhook; sem secret, o secret atual é mantido
+// @Summary Atualiza um webhook
+// @Tags webhooks
+// @Accept json
+// @Produce json
+// @Param id path int true "ID do webhook"
+// @Param webhook body dto.WebhookRequest true "Dados do webhook"
+// @Success 200 {object} dto.WebhookResponse
+// @Failure 400 {object} errors.APIError
+// @Failure 404 {object} errors.APIError
+// @Failure 422 {object} errors.APIError
+// @Router /api/v1/webhooks/{id} [put]
+// PUT /api/v1/webhooks/{id}
+func (h *WebhookHandler) UpdateWebhook(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		utils.ErrorResponse(w, errors.ErrInvalidID)
+		return
+	}
+
+	var request dto.WebhookRequest
+	if err := utils.DecodeJSON(r.Body, &request); err != nil {
+		utils.BadRequestResponse(w, "Erro ao decodificar JSON: "+err.Error())
+		return
+	}
+	if validationErrors := validator.Validate(&request); len(validationErrors) > 0 {
+		utils.ValidationErrorResponse(w, validationErrors)
+		return
+	}
+
+	updated, err := h.service.Update(r.Context(), id, request.ToModel())
+	if err != nil {
+		utils.ErrorResponse(w, err)
+		return
+	}
+	utils.SuccessResponse(w, http.StatusOK, dto.FromWebhook(updated))
+}
+
+// DeleteWebhook remove um webhook e o histórico de suas entregas
+// @Summary Remove um webhook
+// @Tags webhooks
+// @Param id path int true "ID do webhook"
+// @Success 204 "Removido"
+// @Failure 400 {object} errors.APIError
+// @Failure 404 {object} errors.APIError
+// @Router /api/v1/webhooks/{id} [delete]
+// DELETE /api/v1/webhooks/{id}
+func (h *WebhookHandler) DeleteWebhook(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		utils.ErrorResponse(w, errors.ErrInvalidID)
+		return
+	}
+
+	if err := h.service.Delete(r.Context(), id); err != nil {
+		utils.ErrorResponse(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// GetDeliveries lista as entregas de um webhook com o histórico das tentativas
+// @Summary Histórico de entregas de um webhook
+// @Tags webhooks
+// @Produce json
+// @Param id path int true "ID do webhook"
+// @Param status query string false "Situação (pending, delivered, dead)"
+// @Param page query int false "Número da página (padrão: 1)" default(1)
+// @Param pageSize query int false "Tamanho da página (padrão: 10, máximo: 100)" default(10)
+// @Success 200 {object} dto.WebhookDeliveryListResponse
+// @Failure 400 {object} errors.APIError
+// @Failure 404 {object} errors.APIError
+// @Router /api/v1/webhooks/{id}/deliveries [get]
+// GET /api/v1/webhooks/{id}/deliveries?status=dead&page=1&pageSize=10
+func (h *WebhookHandler) GetDeliveries(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		utils.ErrorResponse(w, errors.ErrInvalidID)
+		return
+	}
+
+	pagination := dto.PaginationRequest{
+		Page:     getIntQuery(r, "page", 1),
+		PageSize: getIntQuery(r, "pageSize", 10),
+	}
+	deliveries, paginationResponse, err := h.service.Deliveries(r.Context(), id, r.URL.Query().Get("status"), pagination)
+	if err != nil {
+		utils.ErrorResponse(w, err)
+		return
+	}
+	utils.SuccessResponse(w, http.StatusOK, dto.WebhookDeliveryListResponse{
+		WebhookID:  id,
+		Deliveries: deliveries,
+		Pagination: paginationResponse,
+	})
+}
+
+// RedeliverWebhook agenda o reenvio imediato de uma entrega
+// @Summary Reenvia uma entrega
+// @Description Volta a entrega para pendente com um novo ciclo de tentativas, inclusive entregas concluídas ou esgotadas (dead)
+// @Tags webhooks
+// @Produce json
+// @Param id path int true "ID do webhook"
+// @Param deliveryId path string true "ID da entrega"
+// @Success 202 {object} model.WebhookDelivery
+// @Failure 400 {object} errors.APIError
+// @Failure 404 {object} errors.APIError
+// @Router /api/v1/webhooks/{id}/deliveries/{deliveryId}/redeliver [post]
+// POST /api/v1/webhooks/{id}/deliveries/{deliveryId}/redeliver
+func (h *WebhookHandler) RedeliverWebhook(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	id, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		utils.ErrorResponse(w, errors.ErrInvalidID)
+		return
+	}
+
+	delivery, err := h.service.Redeliver(r.Context(), id, vars["deliveryId"])
+	if err != nil {
+		utils.ErrorResponse(w, err)
+		return
+	}
+	utils.SuccessResponse(w, http.StatusAccepted, delivery)
+}
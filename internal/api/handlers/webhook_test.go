@@ -0,0 +1,163 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+
+	"api-go-arquitetura/internal/dto"
+	"api-go-arquitetura/internal/errors"
+	"api-go-arquitetura/internal/model"
+)
+
+// MockWebhookService é um mock do WebhookService para testes
+type MockWebhookService struct {
+	webhooks   []model.Webhook
+	deliveries []model.WebhookDelivery
+}
+
+func (m *MockWebhookService) Create(ctx context.Context, webhook model.Webhook) (model.Webhook, error) {
+	webhook.ID = len(m.webhooks) + 1
+	if webhook.Secret == "" {
+		webhook.Secret = "gerado"
+	}
+	m.webhooks = append(m.webhooks, webhook)
+	return webhook, nil
+}
+
+func (m *MockWebhookService) FindByID(ctx context.Context, id int) (model.Webhook, error) {
+	for _, w := range m.webhooks {
+		if w.ID == id {
+			return w, nil
+		}
+	}
+	return model.Webhook{}, errors.ErrWebhookNotFound
+}
+
+func (m *MockWebhookService) FindAll(ctx context.Context) ([]model.Webhook, error) {
+	return m.webhooks, nil
+}
+
+func (m *MockWebhookService) Update(ctx context.Context, id int, webhook model.Webhook) (model.Webhook, error) {
+	existing, err := m.FindByID(ctx, id)
+	if err != nil {
+		return model.Webhook{}, err
+	}
+	webhook.ID = id
+	webhook.Secret = existing.Secret
+	m.webhooks[id-1] = webhook
+	return webhook, nil
+}
+
+func (m *MockWebhookService) Delete(ctx context.Context, id int) error {
+	_, err := m.FindByID(ctx, id)
+	return err
+}
+
+func (m *MockWebhookService) Deliveries(ctx context.Context, id int, status string, pagination dto.PaginationRequest) ([]model.WebhookDelivery, dto.PaginationResponse, error) {
+	if _, err := m.FindByID(ctx, id); err != nil {
+		return nil, dto.PaginationResponse{}, err
+	}
+	return m.deliveries, dto.NewPaginationResponse(1, 10, len(m.deliveries)), nil
+}
+
+func (m *MockWebhookService) Redeliver(ctx context.Context, id int, deliveryID string) (model.WebhookDelivery, error) {
+	for _, d := range m.deliveries {
+		if d.ID == deliveryID && d.WebhookID == id {
+			d.Status = model.DeliveryPending
+			d.Attempts = 0
+			return d, nil
+		}
+	}
+	return model.WebhookDelivery{}, errors.ErrWebhookDeliveryNotFound
+}
+
+func newWebhookRouter(handler *WebhookHandler) *mux.Router {
+	router := mux.NewRouter()
+	router.HandleFunc("/api/v1/webhooks", handler.GetWebhooks).Methods("GET")
+	router.HandleFunc("/api/v1/webhooks/{id}", handler.GetWebhook).Methods("GET")
+	router.HandleFunc("/api/v1/webhooks", handler.CreateWebhook).Methods("POST")
+	router.HandleFunc("/api/v1/webhooks/{id}", handler.UpdateWebhook).Methods("PUT")
+	router.HandleFunc("/api/v1/webhooks/{id}", handler.DeleteWebhook).Methods("DELETE")
+	router.HandleFunc("/api/v1/webhooks/{id}/deliveries", handler.GetDeliveries).Methods("GET")
+	router.HandleFunc("/api/v1/webhooks/{id}/deliveries/{deliveryId}/redeliver", handler.RedeliverWebhook).Methods("POST")
+	return router
+}
+
+func TestWebhookHandler(t *testing.T) {
+	svc := &MockWebhookService{}
+	router := newWebhookRouter(NewWebhookHandler(svc))
+
+	do := func(method, path, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("deve retornar o secret apenas na criação", func(t *testing.T) {
+		w := do("POST", "/api/v1/webhooks", `{"url":"https://parceiro.com/hook","events":["PrecoAlterado"]}`)
+		if w.Code != http.StatusCreated {
+			t.Fatalf("Status esperado %d, obtido %d: %s", http.StatusCreated, w.Code, w.Body.String())
+		}
+		var created dto.WebhookResponse
+		if err := json.NewDecoder(w.Body).Decode(&created); err != nil {
+			t.Fatalf("Erro ao decodificar resposta: %v", err)
+		}
+		if created.Secret != "gerado" || !created.Active {
+			t.Errorf("Webhook criado incorreto: %+v", created)
+		}
+
+		var found dto.WebhookResponse
+		_ = json.NewDecoder(do("GET", "/api/v1/webhooks/1", "").Body).Decode(&found)
+		if found.ID != 1 || found.Secret != "" {
+			t.Errorf("Secret não deve ser retornado na consulta: %+v", found)
+		}
+	})
+
+	t.Run("deve validar a requisição", func(t *testing.T) {
+		for _, body := range []string{
+			`{"url":"ftp://parceiro.com","events":["*"]}`,
+			`{"url":"https://parceiro.com","events":[]}`,
+			`{"url":"https://parceiro.com","events":["EventoDesconhecido"]}`,
+			`{"url":"https://parceiro.com","events":["*"],"secret":"curto"}`,
+		} {
+			if w := do("POST", "/api/v1/webhooks", body); w.Code != http.StatusUnprocessableEntity {
+				t.Errorf("%s: status esperado %d, obtido %d", body, http.StatusUnprocessableEntity, w.Code)
+			}
+		}
+		if w := do("PUT", "/api/v1/webhooks/99", `{"url":"https://parceiro.com","events":["*"]}`); w.Code != http.StatusNotFound {
+			t.Errorf("Status esperado %d, obtido %d", http.StatusNotFound, w.Code)
+		}
+	})
+
+	t.Run("deve listar e reenviar entregas", func(t *testing.T) {
+		svc.deliveries = []model.WebhookDelivery{{ID: "evt-1", WebhookID: 1, Status: model.DeliveryDead, Attempts: 8}}
+
+		var list dto.WebhookDeliveryListResponse
+		_ = json.NewDecoder(do("GET", "/api/v1/webhooks/1/deliveries?status=dead", "").Body).Decode(&list)
+		if list.WebhookID != 1 || len(list.Deliveries) != 1 || list.Pagination.TotalItems != 1 {
+			t.Errorf("Lista de entregas incorreta: %+v", list)
+		}
+
+		w := do("POST", "/api/v1/webhooks/1/deliveries/evt-1/redeliver", "")
+		if w.Code != http.StatusAccepted {
+			t.Fatalf("Status esperado %d, obtido %d: %s", http.StatusAccepted, w.Code, w.Body.String())
+		}
+		var delivery model.WebhookDelivery
+		_ = json.NewDecoder(w.Body).Decode(&delivery)
+		if delivery.Status != model.DeliveryPending || delivery.Attempts != 0 {
+			t.Errorf("Entrega deveria voltar para pendente: %+v", delivery)
+		}
+
+		if w := do("POST", "/api/v1/webhooks/1/deliveries/nao-existe/redeliver", ""); w.Code != http.StatusNotFound {
+			t.Errorf("Status esperado %d, obtido %d", http.StatusNotFound, w.Code)
+		}
+	})
+}
@@ -0,0 +1,35 @@
+package middleware
+
+import "net/http"
+
+// ActorHeader é o header HTTP que identifica o autor da requisição
+// A API não faz autenticação: o header só identifica o autor quando preenchido
+// por um gateway que autentica o cliente (ver SetTrustActorHeader)
+const ActorHeader = "X-Actor"
+
+// trustActorHeader indica se X-Actor vem de um gateway confiável
+var trustActorHeader bool
+
+// SetTrustActorHeader configura se X-Actor é preenchido por um gateway que
+// autentica o cliente e descarta o valor enviado por ele
+func SetTrustActorHeader(trust bool) {
+	trustActorHeader = trust
+}
+
+// GetActor retorna o autor verificado da requisição: o header X-Actor, apenas
+// quando ele vem de um gateway confiável (vazio caso contrário)
+func GetActor(r *http.Request) string {
+	if !trustActorHeader {
+		return ""
+	}
+	return r.Header.Get(ActorHeader)
+}
+
+// GetClaimedActor retorna o autor informado pelo próprio cliente em X-Actor,
+// quando o header não é confiável (vazio caso contrário)
+func GetClaimedActor(r *http.Request) string {
+	if trustActorHeader {
+		return ""
+	}
+	return r.Header.Get(ActorHeader)
+}
@@ -21,7 +21,7 @@ func CORSMiddleware(next http.Handler) http.Handler {
 		if corsConfig == nil {
 			w.Header().Set("Access-Control-Allow-Origin", "*")
 			w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, PATCH, DELETE, OPTIONS")
-			w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization")
+			w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization, If-Match, If-None-Match, If-Modified-Since, Idempotency-Key")
 		} else {
 			// Configurar origem
 			origin := r.Header.Get("Origin")
@@ -39,7 +39,7 @@ func CORSMiddleware(next http.Handler) http.Handler {
 			if len(corsConfig.CORSAllowedHeaders) > 0 {
 				w.Header().Set("Access-Control-Allow-Headers", strings.Join(corsConfig.CORSAllowedHeaders, ", "))
 			} else {
-				w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization")
+				w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization, If-Match, If-None-Match, If-Modified-Since, Idempotency-Key")
 			}
 
 			// Configurar credenciais
@@ -48,6 +48,9 @@ func CORSMiddleware(next http.Handler) http.Handler {
 			}
 		}
 
+		// Permitir que clientes leiam o ETag para enviar If-Match
+		w.Header().Set("Access-Control-Expose-Headers", "ETag, Last-Modified, "+IdempotentReplayedHeader+", "+RequestIDHeader)
+
 		// Responder a requisições OPTIONS
 		if r.Method == http.MethodOptions {
 			w.WriteHeader(http.StatusNoContent)
@@ -0,0 +1,272 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"api-go-arquitetura/internal/cache"
+	"api-go-arquitetura/internal/errors"
+	"api-go-arquitetura/internal/logger"
+	"api-go-arquitetura/internal/utils"
+)
+
+const (
+	// IdempotencyKeyHeader é o header HTTP usado pelo cliente para identificar uma operação
+	IdempotencyKeyHeader = "Idempotency-Key"
+	// IdempotentReplayedHeader indica que a resposta foi reproduzida a partir de uma requisição anterior
+	IdempotentReplayedHeader = "Idempotent-Replayed"
+
+	// maxIdempotencyKeyLength limita o tamanho da chave enviada pelo cliente
+	maxIdempotencyKeyLength = 255
+	// maxIdempotentBodyBytes limita o corpo lido (e guardado na memória) para
+	// calcular a impressão digital, antes de qualquer limite do handler
+	maxIdempotentBodyBytes = 8 << 20
+)
+
+var (
+	// idempotencyLockTTL é o tempo que a reserva de uma chave sobrevive sem ser
+	// renovada: enquanto a requisição é processada, ela é renovada a cada
+	// idempotencyLockRefresh, de modo que uma operação longa (ex.: um lote atômico)
+	// não perde a reserva; se a instância cair, a chave é liberada após o TTL
+	idempotencyLockTTL     = time.Minute
+	idempotencyLockRefresh = idempotencyLockTTL / 3
+)
+
+// Estados de um registro de idempotência
+const (
+	idempotencyInProgress = "in_progress"
+	idempotencyCompleted  = "completed"
+)
+
+var (
+	idempotencyStore cache.Cache
+	idempotencyTTL   = 24 * time.Hour
+)
+
+// SetIdempotencyConfig configura o armazenamento e o TTL das respostas idempotentes
+func SetIdempotencyConfig(store cache.Cache, ttl time.Duration) {
+	idempotencyStore = store
+	if ttl > 0 {
+		idempotencyTTL = ttl
+	}
+}
+
+// idempotencyRecord é o registro armazenado no cache para cada chave
+type idempotencyRecord struct {
+	State       string      `json:"state"`
+	Fingerprint string      `json:"fingerprint"`
+	StatusCode  int         `json:"status_code,omitempty"`
+	Header      http.Header `json:"header,omitempty"`
+	Body        []byte      `json:"body,omitempty"`
+}
+
+// IdempotencyMiddleware garante que requisições repetidas com o mesmo header
+// Idempotency-Key sejam executadas uma única vez:
+// - a primeira resposta (status, headers e corpo) é armazenada e reproduzida nas repetições
+// - a mesma chave com um corpo ou query diferente é rejeitada com 422
+// - repetições enquanto a primeira ainda está em processamento recebem 409
+// Respostas 5xx não são armazenadas, permitindo que o cliente tente novamente.
+func IdempotencyMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key := r.Header.Get(IdempotencyKeyHeader)
+		if key == "" || idempotencyStore == nil {
+			next.ServeHTTP(w, r)
+			return
+		}
+		if len(key) > maxIdempotencyKeyLength {
+			utils.BadRequestResponse(w, "Idempotency-Key deve ter no máximo "+strconv.Itoa(maxIdempotencyKeyLength)+" caracteres")
+			return
+		}
+
+		// Ler o corpo para calcular a impressão digital e restaurá-lo para o handler
+		body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxIdempotentBodyBytes))
+		if err != nil {
+			if _, ok := err.(*http.MaxBytesError); ok {
+				utils.ErrorResponse(w, errors.ErrPayloadTooLarge.WithDetailsf("máximo de %d bytes", maxIdempotentBodyBytes))
+				return
+			}
+			utils.BadRequestResponse(w, "Erro ao ler corpo da requisição: "+err.Error())
+			return
+		}
+		r.Body = io.NopCloser(bytes.NewReader(body))
+		fingerprint := requestFingerprint(r, body)
+
+		ctx := r.Context()
+		cacheKey := cache.NewKeyGenerator("idempotency").Generate(r.Method, r.URL.Path, key)
+
+		// Reservar a chave de forma atômica
+		lock, _ := cache.Encode(idempotencyRecord{State: idempotencyInProgress, Fingerprint: fingerprint})
+		acquired, err := idempotencyStore.SetNX(ctx, cacheKey, lock, idempotencyLockTTL)
+		if err == cache.ErrCacheFull {
+			// Sem espaço para o registro, a requisição não é executada: descartar
+			// outro registro permitiria repetir a operação correspondente
+			w.Header().Set("Retry-After", "60")
+			utils.ErrorResponse(w, errors.ErrIdempotencyStoreFull)
+			return
+		}
+		if err != nil {
+			// Falhar de forma aberta: sem cache, a requisição segue sem garantia de idempotência
+			logger.WithField("error", err).Warn("Erro ao acessar armazenamento de idempotência")
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		if !acquired {
+			replayIdempotentResponse(ctx, w, cacheKey, fingerprint)
+			return
+		}
+
+		// Primeira requisição com esta chave: executar e armazenar a resposta
+		rec := newCaptureResponseWriter(w)
+		completed := false
+		stopRefresh := refreshIdempotencyLock(cacheKey, lock)
+		defer func() {
+			stopRefresh()
+			// Em caso de panic ou erro 5xx, liberar a chave para uma nova tentativa
+			if !completed {
+				_ = idempotencyStore.Delete(context.Background(), cacheKey)
+			}
+		}()
+
+		next.ServeHTTP(rec, r)
+		// A renovação termina antes de gravar a resposta, que substitui a reserva
+		stopRefresh()
+
+		if rec.statusCode >= http.StatusInternalServerError {
+			return
+		}
+
+		record := idempotencyRecord{
+			State:       idempotencyCompleted,
+			Fingerprint: fingerprint,
+			StatusCode:  rec.statusCode,
+			Header:      rec.Header().Clone(),
+			Body:        rec.body.Bytes(),
+		}
+		// O header de request ID pertence a cada requisição e não deve ser reproduzido
+		record.Header.Del(RequestIDHeader)
+
+		data, err := cache.Encode(record)
+		if err == nil {
+			err = idempotencyStore.Set(context.Background(), cacheKey, data, idempotencyTTL)
+		}
+		if err != nil {
+			logger.WithField("error", err).Warn("Erro ao armazenar resposta idempotente")
+			return
+		}
+		completed = true
+	})
+}
+
+// refreshIdempotencyLock renova a reserva da chave a cada idempotencyLockRefresh
+// até que a função retornada seja chamada (pode ser chamada mais de uma vez)
+func refreshIdempotencyLock(cacheKey string, lock []byte) (stop func()) {
+	done := make(chan struct{})
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		ticker := time.NewTicker(idempotencyLockRefresh)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				if err := idempotencyStore.Set(context.Background(), cacheKey, lock, idempotencyLockTTL); err != nil {
+					logger.WithField("error", err).Warn("Erro ao renovar reserva de idempotência")
+				}
+			}
+		}
+	}()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			close(done)
+			<-stopped
+		})
+	}
+}
+
+// replayIdempotentResponse responde a uma requisição repetida com base no registro existente
+func replayIdempotentResponse(ctx context.Context, w http.ResponseWriter, cacheKey, fingerprint string) {
+	data, err := idempotencyStore.Get(ctx, cacheKey)
+	if err != nil {
+		// O registro expirou ou foi liberado entre o SetNX e o Get
+		utils.ErrorResponse(w, errors.ErrIdempotencyInProgress)
+		return
+	}
+
+	var record idempotencyRecord
+	if err := cache.Decode(data, &record); err != nil {
+		utils.ErrorResponse(w, errors.WrapError(err, errors.ErrInternalServer))
+		return
+	}
+
+	if record.Fingerprint != fingerprint {
+		utils.ErrorResponse(w, errors.ErrIdempotencyKeyReused)
+		return
+	}
+
+	if record.State != idempotencyCompleted {
+		w.Header().Set("Retry-After", "1")
+		utils.ErrorResponse(w, errors.ErrIdempotencyInProgress)
+		return
+	}
+
+	// Substituir (e não acrescentar) para não duplicar headers já definidos por outros middlewares
+	for name, values := range record.Header {
+		w.Header()[name] = values
+	}
+	w.Header().Set(IdempotentReplayedHeader, "true")
+	w.WriteHeader(record.StatusCode)
+	_, _ = w.Write(record.Body)
+}
+
+// requestFingerprint identifica o conteúdo da requisição (método, rota, query e corpo)
+// A query é normalizada (parâmetros em ordem alfabética): parâmetros como atomic
+// mudam o resultado da operação e não podem reaproveitar a mesma chave
+func requestFingerprint(r *http.Request, body []byte) string {
+	h := sha256.New()
+	h.Write([]byte(r.Method))
+	h.Write([]byte{0})
+	h.Write([]byte(r.URL.Path))
+	h.Write([]byte{0})
+	h.Write([]byte(r.URL.Query().Encode()))
+	h.Write([]byte{0})
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// captureResponseWriter repassa a resposta ao cliente e guarda uma cópia do status e do corpo
+type captureResponseWriter struct {
+	http.ResponseWriter
+	statusCode int
+	body       bytes.Buffer
+}
+
+func newCaptureResponseWriter(w http.ResponseWriter) *captureResponseWriter {
+	return &captureResponseWriter{ResponseWriter: w, statusCode: http.StatusOK}
+}
+
+func (rw *captureResponseWriter) WriteHeader(code int) {
+	rw.statusCode = code
+	rw.ResponseWriter.WriteHeader(code)
+}
+
+func (rw *captureResponseWriter) Write(b []byte) (int, error) {
+	rw.body.Write(b)
+	return rw.ResponseWriter.Write(b)
+}
+
+// Unwrap expõe o writer original para http.ResponseController
+func (rw *captureResponseWriter) Unwrap() http.ResponseWriter {
+	return rw.ResponseWriter
+}
@@ -0,0 +1,266 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"api-go-arquitetura/internal/cache"
+)
+
+// newIdempotentHandler cria um handler que conta execuções e responde 201
+func newIdempotentHandler(calls *int32, delay time.Duration) http.Handler {
+	return IdempotencyMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(calls, 1)
+		time.Sleep(delay)
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Location", "/api/v1/produtos/1")
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{"id":` + strconv.Itoa(int(n)) + `}`))
+	}))
+}
+
+func postWithKey(h http.Handler, key, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest("POST", "/api/v1/produtos", strings.NewReader(body))
+	if key != "" {
+		req.Header.Set(IdempotencyKeyHeader, key)
+	}
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+	return w
+}
+
+func TestIdempotencyMiddleware(t *testing.T) {
+	SetIdempotencyConfig(cache.NewMemoryCache(), time.Hour)
+	defer SetIdempotencyConfig(nil, 0)
+
+	t.Run("deve reproduzir a primeira resposta para a mesma chave", func(t *testing.T) {
+		var calls int32
+		h := newIdempotentHandler(&calls, 0)
+
+		first := postWithKey(h, "chave-1", `{"nome":"Notebook"}`)
+		second := postWithKey(h, "chave-1", `{"nome":"Notebook"}`)
+
+		if calls != 1 {
+			t.Errorf("Handler deveria executar 1 vez, executou %d", calls)
+		}
+		if second.Code != http.StatusCreated || second.Body.String() != first.Body.String() {
+			t.Errorf("Resposta reproduzida difere da original: %d %s", second.Code, second.Body.String())
+		}
+		if second.Header().Get("Location") != "/api/v1/produtos/1" {
+			t.Error("Headers da resposta original deveriam ser reproduzidos")
+		}
+		if second.Header().Get(IdempotentReplayedHeader) != "true" {
+			t.Error("Resposta reproduzida deveria ter o header Idempotent-Replayed")
+		}
+	})
+
+	t.Run("deve rejeitar chave reutilizada com corpo diferente", func(t *testing.T) {
+		var calls int32
+		h := newIdempotentHandler(&calls, 0)
+
+		postWithKey(h, "chave-2", `{"nome":"Notebook"}`)
+		w := postWithKey(h, "chave-2", `{"nome":"Mouse"}`)
+
+		if w.Code != http.StatusUnprocessableEntity {
+			t.Errorf("Status esperado %d, obtido %d", http.StatusUnprocessableEntity, w.Code)
+		}
+		if calls != 1 {
+			t.Errorf("Handler deveria executar 1 vez, executou %d", calls)
+		}
+	})
+
+	t.Run("deve rejeitar chave reutilizada com query diferente", func(t *testing.T) {
+		var calls int32
+		h := newIdempotentHandler(&calls, 0)
+		batch := func(query string) *httptest.ResponseRecorder {
+			req := httptest.NewRequest("POST", "/api/v1/produtos:batch"+query, strings.NewReader(`{"operations":[]}`))
+			req.Header.Set(IdempotencyKeyHeader, "chave-lote")
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, req)
+			return w
+		}
+
+		batch("?atomic=false&dry=1")
+		if w := batch("?atomic=true&dry=1"); w.Code != http.StatusUnprocessableEntity {
+			t.Errorf("Status esperado %d, obtido %d", http.StatusUnprocessableEntity, w.Code)
+		}
+		// A ordem dos parâmetros não muda a impressão digital
+		if w := batch("?dry=1&atomic=false"); w.Header().Get(IdempotentReplayedHeader) != "true" {
+			t.Errorf("A mesma query em outra ordem deveria reproduzir a resposta: %d", w.Code)
+		}
+		if calls != 1 {
+			t.Errorf("Handler deveria executar 1 vez, executou %d", calls)
+		}
+	})
+
+	t.Run("deve executar normalmente sem o header", func(t *testing.T) {
+		var calls int32
+		h := newIdempotentHandler(&calls, 0)
+
+		postWithKey(h, "", `{}`)
+		postWithKey(h, "", `{}`)
+
+		if calls != 2 {
+			t.Errorf("Handler deveria executar 2 vezes, executou %d", calls)
+		}
+	})
+
+	t.Run("deve executar uma única vez com duplicatas concorrentes", func(t *testing.T) {
+		var calls int32
+		h := newIdempotentHandler(&calls, 50*time.Millisecond)
+
+		const total = 50
+		var wg sync.WaitGroup
+		codes := make(chan int, total)
+		for i := 0; i < total; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				codes <- postWithKey(h, "chave-3", `{"nome":"Notebook"}`).Code
+			}()
+		}
+		wg.Wait()
+		close(codes)
+
+		if calls != 1 {
+			t.Errorf("Handler deveria executar 1 vez, executou %d", calls)
+		}
+		for code := range codes {
+			if code != http.StatusCreated && code != http.StatusConflict {
+				t.Errorf("Status inesperado para duplicata concorrente: %d", code)
+			}
+		}
+
+		// Após a conclusão, a repetição recebe a resposta original
+		if w := postWithKey(h, "chave-3", `{"nome":"Notebook"}`); w.Code != http.StatusCreated {
+			t.Errorf("Status esperado %d, obtido %d", http.StatusCreated, w.Code)
+		}
+	})
+
+	t.Run("não deve armazenar respostas 5xx", func(t *testing.T) {
+		var calls int32
+		h := IdempotencyMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if atomic.AddInt32(&calls, 1) == 1 {
+				w.WriteHeader(http.StatusInternalServerError)
+				return
+			}
+			w.WriteHeader(http.StatusCreated)
+		}))
+
+		postWithKey(h, "chave-4", `{}`)
+		w := postWithKey(h, "chave-4", `{}`)
+
+		if calls != 2 || w.Code != http.StatusCreated {
+			t.Errorf("Nova tentativa após 5xx deveria executar o handler (execuções: %d, status: %d)", calls, w.Code)
+		}
+	})
+}
+
+func TestIdempotencyMiddleware_LongRequest(t *testing.T) {
+	SetIdempotencyConfig(cache.NewMemoryCache(), time.Hour)
+	defer SetIdempotencyConfig(nil, 0)
+
+	t.Run("deve rejeitar corpo acima do limite com 413", func(t *testing.T) {
+		var calls int32
+		h := newIdempotentHandler(&calls, 0)
+
+		w := postWithKey(h, "chave-grande", strings.Repeat("x", maxIdempotentBodyBytes+1))
+		if w.Code != http.StatusRequestEntityTooLarge {
+			t.Errorf("Status esperado %d, obtido %d", http.StatusRequestEntityTooLarge, w.Code)
+		}
+		if calls != 0 {
+			t.Errorf("Handler não deveria executar, executou %d", calls)
+		}
+	})
+
+	t.Run("deve renovar a reserva enquanto a requisição é processada", func(t *testing.T) {
+		ttl, refresh := idempotencyLockTTL, idempotencyLockRefresh
+		idempotencyLockTTL, idempotencyLockRefresh = 60*time.Millisecond, 20*time.Millisecond
+		defer func() { idempotencyLockTTL, idempotencyLockRefresh = ttl, refresh }()
+
+		var calls int32
+		h := newIdempotentHandler(&calls, 300*time.Millisecond)
+
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			postWithKey(h, "chave-longa", `{"nome":"Lote"}`)
+		}()
+
+		// Bem depois do TTL da reserva, a repetição ainda encontra a requisição em processamento
+		time.Sleep(150 * time.Millisecond)
+		if w := postWithKey(h, "chave-longa", `{"nome":"Lote"}`); w.Code != http.StatusConflict {
+			t.Errorf("Status esperado %d, obtido %d", http.StatusConflict, w.Code)
+		}
+		<-done
+
+		if w := postWithKey(h, "chave-longa", `{"nome":"Lote"}`); w.Header().Get(IdempotentReplayedHeader) != "true" {
+			t.Errorf("A resposta armazenada deveria ser reproduzida: %d", w.Code)
+		}
+		if calls != 1 {
+			t.Errorf("Handler deveria executar 1 vez, executou %d", calls)
+		}
+	})
+}
+
+func TestIdempotencyMiddleware_ReplaySurvivesCachePressure(t *testing.T) {
+	ctx := context.Background()
+	appCache := cache.NewMemoryCacheWithOptions(cache.MemoryCacheOptions{MaxEntries: 3})
+	store := cache.NewMemoryCacheWithOptions(cache.MemoryCacheOptions{Name: "idempotency", MaxEntries: 20, NoEviction: true})
+	defer store.Close()
+	SetIdempotencyConfig(store, time.Hour)
+	defer SetIdempotencyConfig(nil, 0)
+
+	var calls int32
+	h := newIdempotentHandler(&calls, 0)
+	first := postWithKey(h, "chave-antiga", `{"nome":"Notebook"}`)
+
+	// Mais entradas e chaves que o limite do cache da aplicação
+	for i := 0; i < 10; i++ {
+		_ = appCache.Set(ctx, "produto:"+strconv.Itoa(i), []byte("x"), time.Hour)
+		postWithKey(h, "chave-"+strconv.Itoa(i), `{"nome":"Mouse"}`)
+	}
+	if ok, _ := appCache.Exists(ctx, "produto:0"); ok {
+		t.Fatal("O cache da aplicação deveria ter descartado as entradas antigas")
+	}
+
+	replay := postWithKey(h, "chave-antiga", `{"nome":"Notebook"}`)
+	if replay.Header().Get(IdempotentReplayedHeader) != "true" || replay.Body.String() != first.Body.String() {
+		t.Errorf("A resposta armazenada deveria ser reproduzida: %d %s", replay.Code, replay.Body.String())
+	}
+	if calls != 11 {
+		t.Errorf("Handler deveria executar 11 vezes, executou %d", calls)
+	}
+}
+
+func TestIdempotencyMiddleware_StoreFull(t *testing.T) {
+	store := cache.NewMemoryCacheWithOptions(cache.MemoryCacheOptions{Name: "idempotency", MaxEntries: 2, NoEviction: true})
+	SetIdempotencyConfig(store, time.Hour)
+	defer SetIdempotencyConfig(nil, 0)
+
+	var calls int32
+	h := newIdempotentHandler(&calls, 0)
+	first := postWithKey(h, "chave-1", `{"nome":"Notebook"}`)
+	postWithKey(h, "chave-2", `{"nome":"Mouse"}`)
+
+	// Sem espaço, a nova chave é recusada em vez de descartar um registro existente
+	w := postWithKey(h, "chave-3", `{"nome":"Teclado"}`)
+	if w.Code != http.StatusServiceUnavailable || w.Header().Get("Retry-After") == "" {
+		t.Errorf("Esperado 503 com Retry-After, obtido %d", w.Code)
+	}
+	if calls != 2 {
+		t.Errorf("Handler deveria executar 2 vezes, executou %d", calls)
+	}
+
+	replay := postWithKey(h, "chave-1", `{"nome":"Notebook"}`)
+	if replay.Header().Get(IdempotentReplayedHeader) != "true" || replay.Body.String() != first.Body.String() {
+		t.Errorf("A resposta armazenada deveria ser reproduzida: %d %s", replay.Code, replay.Body.String())
+	}
+}
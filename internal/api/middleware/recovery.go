@@ -11,6 +11,11 @@ func RecoveryMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		defer func() {
 			if rec := recover(); rec != nil {
+				// http.ErrAbortHandler interrompe uma resposta já iniciada (ex.: streaming);
+				// deve chegar ao servidor para que a conexão seja encerrada sem log de erro
+				if rec == http.ErrAbortHandler {
+					panic(rec)
+				}
 				logger.WithFields(map[string]interface{}{
 					"path":   r.URL.Path,
 					"method": r.Method,
@@ -1,29 +1,74 @@
 package api
 
 import (
+	"net/http"
+
 	"api-go-arquitetura/internal/api/handlers"
+	"api-go-arquitetura/internal/api/middleware"
 
 	"github.com/gorilla/mux"
 )
 
 // NewRouter monta e retorna o router com as rotas registradas pelos handlers
-func NewRouter(produtoHandler *handlers.ProdutoHandler, healthCheckHandler *handlers.HealthCheckHandler) *mux.Router {
+func NewRouter(produtoHandler *handlers.ProdutoHandler, healthCheckHandler *handlers.HealthCheckHandler, adminHandler *handlers.AdminHandler, categoriaHandler *handlers.CategoriaHandler, auditHandler *handlers.AuditHandler, webhookHandler *handlers.WebhookHandler) *mux.Router {
 	router := mux.NewRouter()
 
 	// Rotas versionadas para produtos (v1)
 	v1 := router.PathPrefix("/api/v1").Subrouter()
 	v1.HandleFunc("/produtos", produtoHandler.GetProdutos).Methods("GET")
+	// Lixeira e exportação registradas antes de /produtos/{id} para não serem capturadas como ID
+	v1.HandleFunc("/produtos/trash", produtoHandler.GetProdutosTrash).Methods("GET")
+	v1.HandleFunc("/produtos/export", produtoHandler.ExportProdutos).Methods("GET")
+	v1.HandleFunc("/produtos/stats", produtoHandler.GetProdutosStats).Methods("GET")
 	v1.HandleFunc("/produtos/{id}", produtoHandler.GetProduto).Methods("GET")
-	v1.HandleFunc("/produtos", produtoHandler.CreateProduto).Methods("POST")
+	v1.Handle("/produtos", middleware.IdempotencyMiddleware(http.HandlerFunc(produtoHandler.CreateProduto))).Methods("POST")
+	v1.HandleFunc("/produtos/import", produtoHandler.ImportProdutos).Methods("POST")
+	v1.Handle("/produtos:batch", middleware.IdempotencyMiddleware(http.HandlerFunc(produtoHandler.BatchProdutos))).Methods("POST")
 	v1.HandleFunc("/produtos/{id}", produtoHandler.UpdateProduto).Methods("PUT")
 	v1.HandleFunc("/produtos/{id}", produtoHandler.PatchProduto).Methods("PATCH")
 	v1.HandleFunc("/produtos/{id}", produtoHandler.DeleteProduto).Methods("DELETE")
+	v1.HandleFunc("/produtos/{id}/restore", produtoHandler.RestoreProduto).Methods("POST")
+	v1.Handle("/produtos/{id}/stock/adjust", middleware.IdempotencyMiddleware(http.HandlerFunc(produtoHandler.AdjustStock))).Methods("POST")
+	v1.Handle("/produtos/{id}/stock/reserve", middleware.IdempotencyMiddleware(http.HandlerFunc(produtoHandler.ReserveStock))).Methods("POST")
+	v1.HandleFunc("/produtos/{id}/stock/release", produtoHandler.ReleaseStock).Methods("POST")
+	v1.HandleFunc("/produtos/{id}/price-history", produtoHandler.GetPriceHistory).Methods("GET")
+
+	// Rotas administrativas
+	if adminHandler != nil {
+		v1.HandleFunc("/admin/produtos/purge", adminHandler.PurgeTrash).Methods("POST")
+	}
+
+	// Rotas de categorias (/categorias/tree antes de /categorias/{id})
+	if categoriaHandler != nil {
+		v1.HandleFunc("/categorias", categoriaHandler.GetCategorias).Methods("GET")
+		v1.HandleFunc("/categorias/tree", categoriaHandler.GetCategoriasTree).Methods("GET")
+		v1.HandleFunc("/categorias/{id}", categoriaHandler.GetCategoria).Methods("GET")
+		v1.Handle("/categorias", middleware.IdempotencyMiddleware(http.HandlerFunc(categoriaHandler.CreateCategoria))).Methods("POST")
+		v1.HandleFunc("/categorias/{id}", categoriaHandler.UpdateCategoria).Methods("PUT")
+		v1.HandleFunc("/categorias/{id}", categoriaHandler.DeleteCategoria).Methods("DELETE")
+	}
+
+	// Log de auditoria
+	if auditHandler != nil {
+		v1.HandleFunc("/audit", auditHandler.GetAuditLog).Methods("GET")
+	}
+
+	// Webhooks e histórico de entregas
+	if webhookHandler != nil {
+		v1.HandleFunc("/webhooks", webhookHandler.GetWebhooks).Methods("GET")
+		v1.HandleFunc("/webhooks/{id}", webhookHandler.GetWebhook).Methods("GET")
+		v1.Handle("/webhooks", middleware.IdempotencyMiddleware(http.HandlerFunc(webhookHandler.CreateWebhook))).Methods("POST")
+		v1.HandleFunc("/webhooks/{id}", webhookHandler.UpdateWebhook).Methods("PUT")
+		v1.HandleFunc("/webhooks/{id}", webhookHandler.DeleteWebhook).Methods("DELETE")
+		v1.HandleFunc("/webhooks/{id}/deliveries", webhookHandler.GetDeliveries).Methods("GET")
+		v1.HandleFunc("/webhooks/{id}/deliveries/{deliveryId}/redeliver", webhookHandler.RedeliverWebhook).Methods("POST")
+	}
 
 	// Manter compatibilidade com rotas antigas (redirecionar para v1)
 	// Isso permite uma transição suave para o versionamento
 	router.HandleFunc("/api/produtos", produtoHandler.GetProdutos).Methods("GET")
 	router.HandleFunc("/api/produtos/{id}", produtoHandler.GetProduto).Methods("GET")
-	router.HandleFunc("/api/produtos", produtoHandler.CreateProduto).Methods("POST")
+	router.Handle("/api/produtos", middleware.IdempotencyMiddleware(http.HandlerFunc(produtoHandler.CreateProduto))).Methods("POST")
 	router.HandleFunc("/api/produtos/{id}", produtoHandler.UpdateProduto).Methods("PUT")
 	router.HandleFunc("/api/produtos/{id}", produtoHandler.PatchProduto).Methods("PATCH")
 	router.HandleFunc("/api/produtos/{id}", produtoHandler.DeleteProduto).Methods("DELETE")
@@ -0,0 +1,114 @@
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"time"
+)
+
+// Entidades auditadas
+const (
+	EntityProduto = "produto"
+)
+
+// Ações registradas no log de auditoria
+const (
+	ActionCreate  = "create"
+	ActionUpdate  = "update"
+	ActionPatch   = "patch"
+	ActionDelete  = "delete"
+	ActionRestore = "restore"
+)
+
+// AnonymousActor identifica alterações feitas sem ator informado
+const AnonymousActor = "anonymous"
+
+// Change é a alteração de um campo: o valor antes e depois (nil = ausente)
+type Change struct {
+	Before interface{} `json:"before" bson:"before"`
+	After  interface{} `json:"after" bson:"after"`
+}
+
+// Entry é um registro do log de auditoria
+type Entry struct {
+	ID           string            `json:"id" bson:"_id"`
+	Entity       string            `json:"entity" bson:"entity"`
+	EntityID     int               `json:"entityId" bson:"entity_id"`
+	Action       string            `json:"action" bson:"action"`
+	Actor        string            `json:"actor" bson:"actor"`                                    // Autor verificado (anonymous sem gateway confiável)
+	ClaimedActor string            `json:"claimedActor,omitempty" bson:"claimed_actor,omitempty"` // X-Actor informado pelo cliente, sem verificação
+	RequestID    string            `json:"requestId,omitempty" bson:"request_id,omitempty"`
+	Changes      map[string]Change `json:"changes" bson:"changes"` // Chave: campo (nome JSON)
+	Timestamp    time.Time         `json:"timestamp" bson:"timestamp"`
+}
+
+// Query filtra os registros do log de auditoria
+// Campos vazios (ou EntityID = 0) não restringem a busca
+type Query struct {
+	Entity   string
+	EntityID int
+	Action   string
+	Actor    string
+	Skip     int64
+	Limit    int64 // 0 = sem limite
+}
+
+// Sink grava e consulta os registros do log de auditoria
+type Sink interface {
+	Record(ctx context.Context, entry Entry) error
+	// Find retorna os registros da consulta, do mais recente para o mais antigo
+	Find(ctx context.Context, query Query) ([]Entry, error)
+	Count(ctx context.Context, query Query) (int64, error)
+}
+
+// Diff compara before e after campo a campo, pelos nomes JSON
+// nil (inclusive ponteiro nil) representa a entidade inexistente: todos os campos
+// do outro lado aparecem como alterados. Os campos em ignore não são comparados
+func Diff(before, after interface{}, ignore ...string) (map[string]Change, error) {
+	b, err := toMap(before)
+	if err != nil {
+		return nil, err
+	}
+	a, err := toMap(after)
+	if err != nil {
+		return nil, err
+	}
+
+	ignored := make(map[string]bool, len(ignore))
+	for _, field := range ignore {
+		ignored[field] = true
+	}
+
+	changes := make(map[string]Change)
+	for field, value := range b {
+		if !ignored[field] && !reflect.DeepEqual(value, a[field]) {
+			changes[field] = Change{Before: value, After: a[field]}
+		}
+	}
+	for field, value := range a {
+		if _, seen := b[field]; !seen && !ignored[field] {
+			changes[field] = Change{Before: nil, After: value}
+		}
+	}
+	return changes, nil
+}
+
+// toMap converte v para um mapa pelos nomes JSON
+func toMap(v interface{}) (map[string]interface{}, error) {
+	if v == nil {
+		return map[string]interface{}{}, nil
+	}
+	if rv := reflect.ValueOf(v); rv.Kind() == reflect.Ptr && rv.IsNil() {
+		return map[string]interface{}{}, nil
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	m := make(map[string]interface{})
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
@@ -0,0 +1,71 @@
+package audit
+
+import (
+	"context"
+	"sync"
+)
+
+// MemorySink mantém os registros de auditoria em memória (testes e desenvolvimento)
+type MemorySink struct {
+	mu      sync.RWMutex
+	entries []Entry
+}
+
+// Garante em tempo de compilação que MemorySink implementa Sink
+var _ Sink = (*MemorySink)(nil)
+
+// NewMemorySink cria um novo MemorySink
+func NewMemorySink() *MemorySink {
+	return &MemorySink{}
+}
+
+// Record armazena o registro
+func (s *MemorySink) Record(ctx context.Context, entry Entry) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.entries = append(s.entries, entry)
+	return nil
+}
+
+// Find retorna os registros da consulta, do mais recente para o mais antigo
+func (s *MemorySink) Find(ctx context.Context, query Query) ([]Entry, error) {
+	matched := s.match(query)
+	if query.Skip >= int64(len(matched)) {
+		return []Entry{}, nil
+	}
+	matched = matched[query.Skip:]
+	if query.Limit > 0 && query.Limit < int64(len(matched)) {
+		matched = matched[:query.Limit]
+	}
+	return matched, nil
+}
+
+// Count conta os registros da consulta
+func (s *MemorySink) Count(ctx context.Context, query Query) (int64, error) {
+	return int64(len(s.match(query))), nil
+}
+
+// Entries retorna todos os registros, na ordem em que foram gravados
+func (s *MemorySink) Entries() []Entry {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return append([]Entry(nil), s.entries...)
+}
+
+// match retorna os registros da consulta, do mais recente para o mais antigo
+func (s *MemorySink) match(query Query) []Entry {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	matched := []Entry{}
+	for i := len(s.entries) - 1; i >= 0; i-- {
+		e := s.entries[i]
+		if (query.Entity == "" || e.Entity == query.Entity) &&
+			(query.EntityID == 0 || e.EntityID == query.EntityID) &&
+			(query.Action == "" || e.Action == query.Action) &&
+			(query.Actor == "" || e.Actor == query.Actor) {
+			matched = append(matched, e)
+		}
+	}
+	return matched
+}
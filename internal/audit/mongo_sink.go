@@ -0,0 +1,79 @@
+package audit
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Collection é a coleção do log de auditoria
+const Collection = "audit_log"
+
+// MongoSink grava os registros de auditoria no MongoDB
+type MongoSink struct {
+	collection *mongo.Collection
+}
+
+// Garante em tempo de compilação que MongoSink implementa Sink
+var _ Sink = (*MongoSink)(nil)
+
+// NewMongoSink cria um novo MongoSink na coleção audit_log do banco
+func NewMongoSink(db *mongo.Database) *MongoSink {
+	// Os valores das alterações são lidos como mapas (e não bson.D), para que
+	// a resposta JSON tenha o mesmo formato do registro gravado
+	opts := options.Collection().SetBSONOptions(&options.BSONOptions{DefaultDocumentM: true})
+	return &MongoSink{collection: db.Collection(Collection, opts)}
+}
+
+// Record grava o registro
+func (s *MongoSink) Record(ctx context.Context, entry Entry) error {
+	_, err := s.collection.InsertOne(ctx, entry)
+	return err
+}
+
+// Find retorna os registros da consulta, do mais recente para o mais antigo
+func (s *MongoSink) Find(ctx context.Context, query Query) ([]Entry, error) {
+	opts := options.Find().
+		SetSkip(query.Skip).
+		SetSort(bson.D{{Key: "timestamp", Value: -1}, {Key: "_id", Value: -1}})
+	if query.Limit > 0 {
+		opts.SetLimit(query.Limit)
+	}
+
+	cursor, err := s.collection.Find(ctx, queryFilter(query), opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	entries := []Entry{}
+	if err := cursor.All(ctx, &entries); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// Count conta os registros da consulta
+func (s *MongoSink) Count(ctx context.Context, query Query) (int64, error) {
+	return s.collection.CountDocuments(ctx, queryFilter(query))
+}
+
+// queryFilter converte a consulta para filtro MongoDB
+func queryFilter(query Query) bson.M {
+	filter := bson.M{}
+	if query.Entity != "" {
+		filter["entity"] = query.Entity
+	}
+	if query.EntityID != 0 {
+		filter["entity_id"] = query.EntityID
+	}
+	if query.Action != "" {
+		filter["action"] = query.Action
+	}
+	if query.Actor != "" {
+		filter["actor"] = query.Actor
+	}
+	return filter
+}
@@ -3,6 +3,7 @@ package cache
 import (
 	"context"
 	"fmt"
+	"strconv"
 	"time"
 )
 
@@ -12,12 +13,34 @@ type Cache interface {
 	Get(ctx context.Context, key string) ([]byte, error)
 	// Set armazena um valor no cache com TTL
 	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+	// SetNX armazena um valor apenas se a chave não existir, retornando se foi armazenado
+	SetNX(ctx context.Context, key string, value []byte, ttl time.Duration) (bool, error)
 	// Delete remove um valor do cache
 	Delete(ctx context.Context, key string) error
 	// Clear limpa todo o cache
 	Clear(ctx context.Context) error
 	// Exists verifica se uma chave existe no cache
 	Exists(ctx context.Context, key string) (bool, error)
+	// Generation retorna a geração atual do namespace (0 se nunca foi invalidado)
+	Generation(ctx context.Context, namespace string) (int64, error)
+	// InvalidateNamespace incrementa a geração do namespace: as chaves montadas
+	// com a geração anterior (NamespacedKey) deixam de ser lidas e expiram pelo TTL
+	InvalidateNamespace(ctx context.Context, namespace string) error
+	// Close libera os recursos do cache (goroutines, conexões)
+	Close() error
+}
+
+// ProdutoListNamespace agrupa as listas e facetas de produtos em cache
+// Toda alteração de produto invalida o namespace inteiro
+const ProdutoListNamespace = "produto:list"
+
+// NamespacedKey acrescenta à chave a geração atual do namespace
+func NamespacedKey(ctx context.Context, cache Cache, namespace, key string) (string, error) {
+	gen, err := cache.Generation(ctx, namespace)
+	if err != nil {
+		return "", err
+	}
+	return key + ":gen:" + strconv.FormatInt(gen, 10), nil
 }
 
 // KeyGenerator gera chaves de cache de forma consistente
@@ -49,31 +72,7 @@ func GenerateProdutoKey(id int) string {
 	return ProdutoKeyGenerator.Generate("id", fmt.Sprintf("%d", id))
 }
 
-// GenerateProdutosListKey gera uma chave de cache para lista de produtos
-func GenerateProdutosListKey(page, pageSize int, filters map[string]interface{}) string {
-	key := ProdutoKeyGenerator.Generate("list")
-	if page > 0 {
-		key += ":page:" + fmt.Sprintf("%d", page)
-	}
-	if pageSize > 0 {
-		key += ":size:" + fmt.Sprintf("%d", pageSize)
-	}
-	// Adicionar filtros à chave se existirem
-	if filters != nil && len(filters) > 0 {
-		// Simplificado: em produção, seria melhor usar hash dos filtros
-		for k, v := range filters {
-			key += ":" + k + ":" + fmt.Sprintf("%v", v)
-		}
-	}
-	return key
-}
-
-// InvalidateListCache invalida todas as listas em cache
-// Em produção, seria melhor usar padrões de chave ou tags
+// InvalidateListCache invalida todas as listas (e facetas) de produtos em cache
 func InvalidateListCache(ctx context.Context, cache Cache) error {
-	// Por enquanto, não implementamos limpeza completa
-	// O cache será invalidado naturalmente pelo TTL
-	// Em produção, usaríamos Redis com padrões de chave ou tags
-	return nil
+	return cache.InvalidateNamespace(ctx, ProdutoListNamespace)
 }
-
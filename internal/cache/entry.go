@@ -0,0 +1,38 @@
+package cache
+
+import (
+	"encoding/binary"
+	"errors"
+	"time"
+)
+
+// ErrInvalidEntry é retornado quando o valor armazenado não é um envelope válido
+// (ex.: gravado por uma versão anterior da aplicação)
+var ErrInvalidEntry = errors.New("invalid cache entry")
+
+const (
+	// entryVersion identifica o formato do envelope
+	entryVersion byte = 1
+	// entryHeaderSize é o tamanho do cabeçalho: versão + validade (Unix, nanossegundos)
+	entryHeaderSize = 1 + 8
+)
+
+// WrapEntry envolve o valor com o instante até o qual ele é considerado atual
+// O valor pode continuar armazenado depois disso (stale-while-revalidate): o TTL
+// da chave no cache é maior que a validade do envelope
+func WrapEntry(data []byte, freshUntil time.Time) []byte {
+	entry := make([]byte, entryHeaderSize+len(data))
+	entry[0] = entryVersion
+	binary.BigEndian.PutUint64(entry[1:entryHeaderSize], uint64(freshUntil.UnixNano()))
+	copy(entry[entryHeaderSize:], data)
+	return entry
+}
+
+// UnwrapEntry retorna o valor e a validade de um envelope criado por WrapEntry
+func UnwrapEntry(entry []byte) ([]byte, time.Time, error) {
+	if len(entry) < entryHeaderSize || entry[0] != entryVersion {
+		return nil, time.Time{}, ErrInvalidEntry
+	}
+	freshUntil := time.Unix(0, int64(binary.BigEndian.Uint64(entry[1:entryHeaderSize])))
+	return entry[entryHeaderSize:], freshUntil, nil
+}
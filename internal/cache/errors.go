@@ -7,5 +7,10 @@ var (
 	ErrCacheMiss = errors.New("cache miss")
 	// ErrCacheConnection é retornado quando há erro de conexão com o cache
 	ErrCacheConnection = errors.New("cache connection error")
+	// ErrValueTooLarge é retornado quando o valor excede o limite de bytes do cache em memória
+	ErrValueTooLarge = errors.New("cache value too large")
+	// ErrCacheFull é retornado por um cache em memória sem remoção (NoEviction)
+	// quando um novo valor não cabe nos limites
+	ErrCacheFull = errors.New("cache full")
 )
 
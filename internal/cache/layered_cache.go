@@ -0,0 +1,318 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"api-go-arquitetura/internal/logger"
+	"api-go-arquitetura/internal/metrics"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+// InvalidationChannel é o canal do Redis em que as instâncias publicam as invalidações do L1
+const InvalidationChannel = "cache:invalidate"
+
+// Tipos de mensagem de invalidação
+const (
+	invalidateKey        = "key"        // Uma chave foi alterada ou removida
+	invalidateGeneration = "generation" // A geração de um namespace foi incrementada
+	invalidateAll        = "clear"      // O L1 inteiro deve ser descartado
+)
+
+// invalidation é a mensagem publicada em InvalidationChannel
+type invalidation struct {
+	Origin string `json:"origin"` // Instância que publicou (ignora as próprias mensagens)
+	Type   string `json:"type"`
+	Key    string `json:"key,omitempty"`
+}
+
+// LayeredCacheOptions configura o cache em duas camadas
+type LayeredCacheOptions struct {
+	Addr     string
+	Password string
+	DB       int
+	Local    MemoryCacheOptions // Limites do L1
+	LocalTTL time.Duration      // TTL máximo de uma entrada no L1
+}
+
+// layeredCache implementa Cache com um cache em memória (L1) na frente do Redis (L2)
+// Remoções e invalidações de namespace são publicadas em InvalidationChannel, para
+// que as demais instâncias descartem a chave do seu L1; Set apenas preenche o cache
+// e não é publicado. Como o pub/sub do Redis não garante a entrega, LocalTTL limita
+// por quanto tempo um L1 (ou uma geração guardada localmente) pode divergir
+type layeredCache struct {
+	local      Cache
+	remote     *redisCache
+	pubsub     *redis.PubSub
+	instanceID string
+	localTTL   time.Duration
+
+	// invalidations é incrementado a cada invalidação recebida: uma leitura do L2
+	// iniciada antes de uma invalidação não é copiada para o L1
+	invalidations atomic.Uint64
+
+	mu          sync.Mutex
+	generations map[string]cachedGeneration // Gerações lidas do Redis, por até LocalTTL
+
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+// cachedGeneration é uma geração lida do Redis e o momento da leitura
+type cachedGeneration struct {
+	gen       int64
+	fetchedAt time.Time
+}
+
+// NewLayeredCache cria um cache em duas camadas: memória local (L1) e Redis (L2)
+// Retorna erro se o Redis não estiver acessível ou a inscrição no canal falhar
+func NewLayeredCache(opts LayeredCacheOptions) (Cache, error) {
+	client := redis.NewClient(&redis.Options{
+		Addr:     opts.Addr,
+		Password: opts.Password,
+		DB:       opts.DB,
+	})
+
+	// Verificar conexão e inscrição no canal de invalidação
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := client.Ping(ctx).Err(); err != nil {
+		client.Close()
+		return nil, err
+	}
+	pubsub := client.Subscribe(ctx, InvalidationChannel)
+	if _, err := pubsub.Receive(ctx); err != nil {
+		pubsub.Close()
+		client.Close()
+		return nil, err
+	}
+
+	c := &layeredCache{
+		local:       NewMemoryCacheWithOptions(opts.Local),
+		remote:      &redisCache{client: client},
+		pubsub:      pubsub,
+		instanceID:  uuid.NewString(),
+		localTTL:    opts.LocalTTL,
+		generations: make(map[string]cachedGeneration),
+		done:        make(chan struct{}),
+	}
+	go c.listen()
+	return c, nil
+}
+
+// Get recupera um valor do L1 ou, na falta, do Redis (copiando-o para o L1)
+func (c *layeredCache) Get(ctx context.Context, key string) ([]byte, error) {
+	if value, err := c.local.Get(ctx, key); err == nil {
+		metrics.RecordCacheLayerRead("l1")
+		return value, nil
+	}
+
+	seen := c.invalidations.Load()
+	value, err := c.remote.Get(ctx, key)
+	if err != nil {
+		if err == ErrCacheMiss {
+			metrics.RecordCacheLayerRead("miss")
+		}
+		return nil, err
+	}
+	metrics.RecordCacheLayerRead("l2")
+
+	if c.invalidations.Load() == seen {
+		_ = c.local.Set(ctx, key, value, c.localTTL)
+	}
+	return value, nil
+}
+
+// Set armazena o valor no Redis e no L1
+// Set preenche o cache após uma leitura e não é publicado: as mutações invalidam
+// as demais instâncias com Delete ou InvalidateNamespace
+func (c *layeredCache) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	if err := c.remote.Set(ctx, key, value, ttl); err != nil {
+		return err
+	}
+	_ = c.local.Set(ctx, key, value, minDuration(ttl, c.localTTL))
+	return nil
+}
+
+// SetNX armazena o valor no Redis apenas se a chave não existir
+// A chave não é copiada para o L1: SetNX é usado como trava entre as instâncias
+func (c *layeredCache) SetNX(ctx context.Context, key string, value []byte, ttl time.Duration) (bool, error) {
+	ok, err := c.remote.SetNX(ctx, key, value, ttl)
+	if err != nil || !ok {
+		return ok, err
+	}
+	_ = c.local.Delete(ctx, key)
+	return true, nil
+}
+
+// Delete remove a chave do Redis, do L1 e do L1 das demais instâncias
+func (c *layeredCache) Delete(ctx context.Context, key string) error {
+	if err := c.remote.Delete(ctx, key); err != nil {
+		return err
+	}
+	_ = c.local.Delete(ctx, key)
+	return c.publish(ctx, invalidateKey, key)
+}
+
+// Clear limpa o L1 de todas as instâncias (o Redis não é limpo, como em redisCache)
+func (c *layeredCache) Clear(ctx context.Context) error {
+	c.clearLocal()
+	return c.publish(ctx, invalidateAll, "")
+}
+
+// Exists verifica se uma chave existe no L1 ou no Redis
+func (c *layeredCache) Exists(ctx context.Context, key string) (bool, error) {
+	if ok, _ := c.local.Exists(ctx, key); ok {
+		return true, nil
+	}
+	return c.remote.Exists(ctx, key)
+}
+
+// Generation retorna a geração do namespace, lida novamente do Redis após uma
+// invalidação ou quando a leitura anterior tem mais de LocalTTL
+func (c *layeredCache) Generation(ctx context.Context, namespace string) (int64, error) {
+	c.mu.Lock()
+	cached, ok := c.generations[namespace]
+	c.mu.Unlock()
+	if ok && time.Since(cached.fetchedAt) < c.localTTL {
+		return cached.gen, nil
+	}
+
+	seen := c.invalidations.Load()
+	gen, err := c.remote.Generation(ctx, namespace)
+	if err != nil {
+		return 0, err
+	}
+
+	c.mu.Lock()
+	if c.invalidations.Load() == seen {
+		c.generations[namespace] = cachedGeneration{gen: gen, fetchedAt: time.Now()}
+	}
+	c.mu.Unlock()
+	return gen, nil
+}
+
+// InvalidateNamespace incrementa a geração do namespace no Redis e em todas as instâncias
+func (c *layeredCache) InvalidateNamespace(ctx context.Context, namespace string) error {
+	if err := c.remote.InvalidateNamespace(ctx, namespace); err != nil {
+		return err
+	}
+	c.forgetGeneration(namespace)
+	return c.publish(ctx, invalidateGeneration, namespace)
+}
+
+// Close encerra a inscrição no canal, o L1 e a conexão com o Redis
+func (c *layeredCache) Close() error {
+	var err error
+	c.closeOnce.Do(func() {
+		close(c.done)
+		err = c.pubsub.Close()
+		_ = c.local.Close()
+		if closeErr := c.remote.Close(); err == nil {
+			err = closeErr
+		}
+	})
+	return err
+}
+
+// publish envia a invalidação às demais instâncias
+func (c *layeredCache) publish(ctx context.Context, kind, key string) error {
+	payload, err := json.Marshal(invalidation{Origin: c.instanceID, Type: kind, Key: key})
+	if err != nil {
+		return err
+	}
+	if err := c.remote.client.Publish(ctx, InvalidationChannel, payload).Err(); err != nil {
+		metrics.RecordCacheInvalidation("published", "error")
+		return fmt.Errorf("erro ao publicar invalidação do cache: %w", err)
+	}
+	metrics.RecordCacheInvalidation("published", "success")
+	return nil
+}
+
+// listen aplica ao L1 as invalidações publicadas pelas demais instâncias, até Close
+// Após uma reconexão, mensagens podem ter sido perdidas: o L1 inteiro é descartado
+func (c *layeredCache) listen() {
+	ctx := context.Background()
+	subscribed := true
+	for {
+		msg, err := c.pubsub.Receive(ctx)
+		if err != nil {
+			select {
+			case <-c.done:
+				return
+			default:
+			}
+			logger.WithField("error", err).Warn("Erro ao receber invalidações do cache, reconectando")
+			subscribed = false
+			time.Sleep(time.Second)
+			continue
+		}
+
+		switch msg := msg.(type) {
+		case *redis.Subscription:
+			if !subscribed {
+				c.clearLocal()
+				subscribed = true
+			}
+		case *redis.Message:
+			c.apply(msg.Payload)
+		}
+	}
+}
+
+// apply aplica uma mensagem de invalidação ao L1
+func (c *layeredCache) apply(payload string) {
+	var msg invalidation
+	if err := json.Unmarshal([]byte(payload), &msg); err != nil {
+		metrics.RecordCacheInvalidation("received", "error")
+		logger.WithField("error", err).Warn("Mensagem de invalidação do cache inválida")
+		return
+	}
+	if msg.Origin == c.instanceID {
+		return
+	}
+	metrics.RecordCacheInvalidation("received", "success")
+
+	switch msg.Type {
+	case invalidateKey:
+		c.invalidations.Add(1)
+		_ = c.local.Delete(context.Background(), msg.Key)
+	case invalidateGeneration:
+		c.forgetGeneration(msg.Key)
+	case invalidateAll:
+		c.clearLocal()
+	}
+}
+
+// forgetGeneration descarta a geração do namespace guardada localmente
+func (c *layeredCache) forgetGeneration(namespace string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.invalidations.Add(1)
+	delete(c.generations, namespace)
+}
+
+// clearLocal descarta o L1 e as gerações guardadas localmente
+func (c *layeredCache) clearLocal() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.invalidations.Add(1)
+	c.generations = make(map[string]cachedGeneration)
+	_ = c.local.Clear(context.Background())
+}
+
+func minDuration(a, b time.Duration) time.Duration {
+	if a < b {
+		return a
+	}
+	return b
+}
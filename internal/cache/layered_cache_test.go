@@ -0,0 +1,201 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+)
+
+// newTestLayeredCaches cria duas instâncias do cache em duas camadas sobre o mesmo Redis
+func newTestLayeredCaches(t *testing.T) (*miniredis.Miniredis, *layeredCache, *layeredCache) {
+	t.Helper()
+	return newTestLayeredCachesWithTTL(t, time.Minute)
+}
+
+// newTestLayeredCachesWithTTL é newTestLayeredCaches com o LocalTTL informado
+func newTestLayeredCachesWithTTL(t *testing.T, localTTL time.Duration) (*miniredis.Miniredis, *layeredCache, *layeredCache) {
+	t.Helper()
+	server := miniredis.RunT(t)
+
+	newCache := func() *layeredCache {
+		c, err := NewLayeredCache(LayeredCacheOptions{
+			Addr:     server.Addr(),
+			Local:    MemoryCacheOptions{MaxEntries: 100},
+			LocalTTL: localTTL,
+		})
+		if err != nil {
+			t.Fatalf("Erro ao criar cache: %v", err)
+		}
+		t.Cleanup(func() { c.Close() })
+		return c.(*layeredCache)
+	}
+	return server, newCache(), newCache()
+}
+
+// eventually aguarda a condição por até um segundo
+func eventually(t *testing.T, msg string, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatal(msg)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func inLocal(c *layeredCache, key string) bool {
+	ok, _ := c.local.Exists(context.Background(), key)
+	return ok
+}
+
+func TestLayeredCache_ReadThrough(t *testing.T) {
+	ctx := context.Background()
+	server, a, b := newTestLayeredCaches(t)
+
+	if err := a.Set(ctx, "produto:1", []byte("v1"), time.Minute); err != nil {
+		t.Fatalf("Erro inesperado: %v", err)
+	}
+	if !server.Exists("produto:1") {
+		t.Fatal("O valor deveria ser gravado no Redis")
+	}
+
+	// A leitura em b vem do Redis e é copiada para o L1
+	value, err := b.Get(ctx, "produto:1")
+	if err != nil || string(value) != "v1" {
+		t.Fatalf("Esperado v1, obtido %q (%v)", value, err)
+	}
+	if !inLocal(b, "produto:1") {
+		t.Fatal("A chave lida do Redis deveria ser copiada para o L1")
+	}
+
+	// Com a chave no L1, a leitura não depende do Redis
+	server.Del("produto:1")
+	if value, err := b.Get(ctx, "produto:1"); err != nil || string(value) != "v1" {
+		t.Errorf("Esperado v1 do L1, obtido %q (%v)", value, err)
+	}
+
+	if _, err := b.Get(ctx, "inexistente"); err != ErrCacheMiss {
+		t.Errorf("Esperado ErrCacheMiss, obtido %v", err)
+	}
+}
+
+func TestLayeredCache_CrossInstanceInvalidation(t *testing.T) {
+	ctx := context.Background()
+	_, a, b := newTestLayeredCaches(t)
+
+	_ = a.Set(ctx, "produto:1", []byte("v1"), time.Minute)
+	_, _ = b.Get(ctx, "produto:1")
+
+	t.Run("Set apenas preenche o cache e não descarta o L1 das demais", func(t *testing.T) {
+		_ = a.Set(ctx, "produto:1", []byte("v2"), time.Minute)
+		_ = a.Set(ctx, "sentinela", []byte("v"), time.Minute)
+		_ = a.Delete(ctx, "sentinela")
+		// A remoção publicada depois do Set já foi aplicada em b
+		eventually(t, "b deveria receber a remoção", func() bool {
+			_, err := b.Get(ctx, "sentinela")
+			return err == ErrCacheMiss
+		})
+		if value, _ := b.Get(ctx, "produto:1"); string(value) != "v1" {
+			t.Errorf("Esperado v1 do L1 de b, obtido %q", value)
+		}
+	})
+
+	t.Run("Delete em uma instância remove a chave do L1 das demais", func(t *testing.T) {
+		if !inLocal(b, "produto:1") {
+			t.Fatal("A chave deveria estar no L1 de b")
+		}
+		if err := a.Delete(ctx, "produto:1"); err != nil {
+			t.Fatalf("Erro inesperado: %v", err)
+		}
+		eventually(t, "A chave deveria ser removida do L1 de b", func() bool { return !inLocal(b, "produto:1") })
+		if _, err := b.Get(ctx, "produto:1"); err != ErrCacheMiss {
+			t.Errorf("Esperado ErrCacheMiss, obtido %v", err)
+		}
+	})
+
+	t.Run("Clear descarta o L1 das demais instâncias", func(t *testing.T) {
+		_ = a.Set(ctx, "produto:2", []byte("v"), time.Minute)
+		if _, _ = b.Get(ctx, "produto:2"); !inLocal(b, "produto:2") {
+			t.Fatal("A chave deveria estar no L1 de b")
+		}
+		_ = a.Clear(ctx)
+		eventually(t, "O L1 de b deveria ser descartado", func() bool { return !inLocal(b, "produto:2") })
+	})
+}
+
+func TestLayeredCache_Generation(t *testing.T) {
+	ctx := context.Background()
+	server, a, b := newTestLayeredCaches(t)
+
+	if gen, err := b.Generation(ctx, ProdutoListNamespace); err != nil || gen != 0 {
+		t.Fatalf("Geração esperada 0, obtida %d (%v)", gen, err)
+	}
+
+	// A geração fica guardada localmente até ser invalidada ou expirar
+	server.Set(generationKeyPrefix+ProdutoListNamespace, "10")
+	if gen, _ := b.Generation(ctx, ProdutoListNamespace); gen != 0 {
+		t.Errorf("Geração guardada localmente esperada (0), obtida %d", gen)
+	}
+
+	if err := a.InvalidateNamespace(ctx, ProdutoListNamespace); err != nil {
+		t.Fatalf("Erro inesperado: %v", err)
+	}
+	if gen, _ := a.Generation(ctx, ProdutoListNamespace); gen != 11 {
+		t.Errorf("Geração esperada 11 em a, obtida %d", gen)
+	}
+	eventually(t, "b deveria ler a nova geração", func() bool {
+		gen, _ := b.Generation(ctx, ProdutoListNamespace)
+		return gen == 11
+	})
+}
+
+func TestLayeredCache_GenerationExpires(t *testing.T) {
+	ctx := context.Background()
+	localTTL := 50 * time.Millisecond
+	server, _, b := newTestLayeredCachesWithTTL(t, localTTL)
+
+	if gen, err := b.Generation(ctx, ProdutoListNamespace); err != nil || gen != 0 {
+		t.Fatalf("Geração esperada 0, obtida %d (%v)", gen, err)
+	}
+
+	// Geração incrementada sem mensagem de invalidação (mensagem perdida)
+	server.Set(generationKeyPrefix+ProdutoListNamespace, "3")
+	start := time.Now()
+	eventually(t, "b deveria ler a nova geração após LocalTTL", func() bool {
+		gen, _ := b.Generation(ctx, ProdutoListNamespace)
+		return gen == 3
+	})
+	if elapsed := time.Since(start); elapsed > localTTL+100*time.Millisecond {
+		t.Errorf("A geração deveria convergir em até LocalTTL, levou %v", elapsed)
+	}
+}
+
+func TestLayeredCache_SetNX(t *testing.T) {
+	ctx := context.Background()
+	_, a, b := newTestLayeredCaches(t)
+
+	ok, err := a.SetNX(ctx, "trava", []byte("a"), time.Minute)
+	if err != nil || !ok {
+		t.Fatalf("A primeira instância deveria obter a trava: %v, %v", ok, err)
+	}
+	if ok, _ := b.SetNX(ctx, "trava", []byte("b"), time.Minute); ok {
+		t.Error("A trava não deveria ser obtida por outra instância")
+	}
+	if inLocal(a, "trava") {
+		t.Error("A trava não deveria ser copiada para o L1")
+	}
+}
+
+func TestLayeredCache_Close(t *testing.T) {
+	_, a, _ := newTestLayeredCaches(t)
+
+	if err := a.Close(); err != nil {
+		t.Fatalf("Erro inesperado: %v", err)
+	}
+	if err := a.Close(); err != nil {
+		t.Errorf("Close deveria poder ser chamado mais de uma vez: %v", err)
+	}
+}
@@ -0,0 +1,119 @@
+package cache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"api-go-arquitetura/internal/dto"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// ListQuery descreve uma consulta da listagem paginada de produtos
+// É convertida pela chave de cache para a forma canônica (ver canonical)
+type ListQuery struct {
+	Pagination dto.PaginationRequest
+	Filter     map[string]interface{} // Filtro MongoDB (FilterRequest.ToMongoFilter)
+	Sort       bson.D                 // Ordenação MongoDB; a ordem das chaves é significativa
+	Fields     []string               // Campos da projeção (vazio = documento completo)
+}
+
+// FacetsQuery descreve uma consulta das facetas de produtos
+type FacetsQuery struct {
+	Facets  []string
+	Buckets int
+	Filter  map[string]interface{}
+}
+
+// GenerateProdutosListKey gera a chave de cache de uma página de produtos
+// Consultas equivalentes (mesmo filtro em qualquer ordem de montagem, mesmos
+// campos em qualquer ordem, paginação com os mesmos valores padrão) geram a
+// mesma chave; qualquer diferença de página, filtro, ordenação ou campos gera
+// outra chave
+func GenerateProdutosListKey(q ListQuery) string {
+	return ProdutoKeyGenerator.Generate("list", hashKey(q.canonical()))
+}
+
+// GenerateProdutosFacetsKey gera a chave de cache das facetas de uma busca
+func GenerateProdutosFacetsKey(q FacetsQuery) string {
+	return ProdutoKeyGenerator.Generate("list", "facets", hashKey(q.canonical()))
+}
+
+// canonicalListQuery é a forma canônica de ListQuery
+type canonicalListQuery struct {
+	Page     int                    `json:"page"`
+	PageSize int                    `json:"pageSize"`
+	Filter   map[string]interface{} `json:"filter"`
+	Sort     bson.D                 `json:"sort"`
+	Fields   []string               `json:"fields"`
+}
+
+// canonical normaliza a consulta: paginação com os valores padrão, filtro vazio
+// igual a nenhum filtro e campos ordenados e sem repetições
+func (q ListQuery) canonical() canonicalListQuery {
+	pagination := q.Pagination
+	pagination.Validate()
+	return canonicalListQuery{
+		Page:     pagination.Page,
+		PageSize: pagination.PageSize,
+		Filter:   canonicalFilter(q.Filter),
+		Sort:     q.Sort,
+		Fields:   canonicalStrings(q.Fields),
+	}
+}
+
+// canonicalFacetsQuery é a forma canônica de FacetsQuery
+type canonicalFacetsQuery struct {
+	Facets  []string               `json:"facets"`
+	Buckets int                    `json:"buckets"`
+	Filter  map[string]interface{} `json:"filter"`
+}
+
+func (q FacetsQuery) canonical() canonicalFacetsQuery {
+	return canonicalFacetsQuery{
+		Facets:  canonicalStrings(q.Facets),
+		Buckets: q.Buckets,
+		Filter:  canonicalFilter(q.Filter),
+	}
+}
+
+// canonicalFilter trata filtro vazio como nenhum filtro
+func canonicalFilter(filter map[string]interface{}) map[string]interface{} {
+	if len(filter) == 0 {
+		return nil
+	}
+	return filter
+}
+
+// canonicalStrings retorna uma cópia ordenada e sem repetições (nil se vazia)
+func canonicalStrings(values []string) []string {
+	if len(values) == 0 {
+		return nil
+	}
+	seen := make(map[string]bool, len(values))
+	result := make([]string, 0, len(values))
+	for _, v := range values {
+		if !seen[v] {
+			seen[v] = true
+			result = append(result, v)
+		}
+	}
+	sort.Strings(result)
+	return result
+}
+
+// hashKey retorna o SHA-256 (hex) da forma canônica serializada
+// encoding/json ordena as chaves dos mapas em todos os níveis, o que torna a
+// serialização determinística; bson.D mantém a ordem, que é significativa
+func hashKey(v interface{}) string {
+	data, err := json.Marshal(v)
+	if err != nil {
+		// Valores sem representação JSON (ex.: NaN): fmt também ordena as chaves dos mapas
+		data = []byte(fmt.Sprintf("%#v", v))
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,228 @@
+package cache
+
+import (
+	"encoding/json"
+	"math/rand"
+	"reflect"
+	"strings"
+	"testing"
+	"testing/quick"
+
+	"api-go-arquitetura/internal/dto"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var (
+	testFilterFields = []string{"nome", "descricao", "preco", "tags", "categoria_id", "estoque", "deleted_at"}
+	testSortFields   = []string{"id", "nome", "preco", "created_at"}
+	testFields       = []string{"id", "nome", "preco", "descricao", "tags", "categoriaId", "estoque"}
+)
+
+// randomQuery é uma ListQuery aleatória (já normalizada) para os testes de propriedade
+type randomQuery struct {
+	ListQuery
+}
+
+// Generate implementa quick.Generator
+func (randomQuery) Generate(r *rand.Rand, size int) reflect.Value {
+	q := ListQuery{
+		Pagination: dto.PaginationRequest{Page: 1 + r.Intn(20), PageSize: 1 + r.Intn(100)},
+		Filter:     randomFilter(r),
+		Fields:     randomSubset(r, testFields),
+	}
+	order := 1
+	if r.Intn(2) == 0 {
+		order = -1
+	}
+	q.Sort = bson.D{{Key: testSortFields[r.Intn(len(testSortFields))], Value: order}}
+	return reflect.ValueOf(randomQuery{q})
+}
+
+// randomFilter monta um filtro com valores dos tipos gerados por ToMongoFilter
+func randomFilter(r *rand.Rand) map[string]interface{} {
+	filter := make(map[string]interface{})
+	for _, field := range randomSubset(r, testFilterFields) {
+		switch r.Intn(4) {
+		case 0:
+			filter[field] = map[string]interface{}{"$regex": randomWord(r), "$options": "i"}
+		case 1:
+			filter[field] = map[string]interface{}{"$gte": float64(r.Intn(1000)), "$lte": float64(1000 + r.Intn(1000))}
+		case 2:
+			filter[field] = primitive.Regex{Pattern: randomWord(r), Options: "i"}
+		default:
+			filter[field] = r.Intn(50)
+		}
+	}
+	if r.Intn(3) == 0 {
+		filter["$and"] = []interface{}{
+			map[string]interface{}{"estoque": map[string]interface{}{"$gt": r.Intn(10)}},
+			map[string]interface{}{"tags": map[string]interface{}{"$in": randomSubset(r, []string{"a", "b", "c"})}},
+		}
+	}
+	return filter
+}
+
+func randomSubset(r *rand.Rand, values []string) []string {
+	var subset []string
+	for _, v := range values {
+		if r.Intn(2) == 0 {
+			subset = append(subset, v)
+		}
+	}
+	return subset
+}
+
+func randomWord(r *rand.Rand) string {
+	const letters = "abcdefgh"
+	b := make([]byte, 1+r.Intn(6))
+	for i := range b {
+		b[i] = letters[r.Intn(len(letters))]
+	}
+	return string(b)
+}
+
+// equivalent retorna uma consulta equivalente a q montada de outra forma: mapas
+// recriados em outra ordem de inserção, campos embaralhados e repetidos
+func equivalent(r *rand.Rand, q ListQuery) ListQuery {
+	fields := append([]string{}, q.Fields...)
+	if len(fields) > 0 {
+		fields = append(fields, fields[r.Intn(len(fields))])
+	}
+	r.Shuffle(len(fields), func(i, j int) { fields[i], fields[j] = fields[j], fields[i] })
+
+	return ListQuery{
+		Pagination: q.Pagination,
+		Filter:     rebuild(r, q.Filter).(map[string]interface{}),
+		Sort:       append(bson.D{}, q.Sort...),
+		Fields:     fields,
+	}
+}
+
+// rebuild copia o valor recriando os mapas com as chaves em ordem aleatória
+func rebuild(r *rand.Rand, v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		r.Shuffle(len(keys), func(i, j int) { keys[i], keys[j] = keys[j], keys[i] })
+		m := make(map[string]interface{}, len(v))
+		for _, k := range keys {
+			m[k] = rebuild(r, v[k])
+		}
+		return m
+	case []interface{}:
+		s := make([]interface{}, len(v))
+		for i := range v {
+			s[i] = rebuild(r, v[i])
+		}
+		return s
+	}
+	return v
+}
+
+func TestGenerateProdutosListKey_EqualQueriesEqualKeys(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	property := func(q randomQuery) bool {
+		return GenerateProdutosListKey(q.ListQuery) == GenerateProdutosListKey(equivalent(r, q.ListQuery))
+	}
+	if err := quick.Check(property, &quick.Config{MaxCount: 2000}); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestGenerateProdutosListKey_DifferentQueriesDifferentKeys(t *testing.T) {
+	// Cada alteração muda a consulta; a chave também deve mudar
+	mutations := map[string]func(q *ListQuery){
+		"página": func(q *ListQuery) { q.Pagination.Page++ },
+		"tamanho da página": func(q *ListQuery) {
+			q.Pagination.PageSize = q.Pagination.PageSize%100 + 1
+		},
+		"ordem": func(q *ListQuery) {
+			q.Sort = bson.D{{Key: q.Sort[0].Key, Value: -q.Sort[0].Value.(int)}}
+		},
+		"campo de ordenação": func(q *ListQuery) {
+			q.Sort = append(bson.D{{Key: "estoque", Value: 1}}, q.Sort...)
+		},
+		"filtro": func(q *ListQuery) {
+			filter := make(map[string]interface{}, len(q.Filter)+1)
+			for k, v := range q.Filter {
+				filter[k] = v
+			}
+			filter["version"] = 7
+			q.Filter = filter
+		},
+		"campos": func(q *ListQuery) { q.Fields = append(append([]string{}, q.Fields...), "version") },
+	}
+
+	for name, mutate := range mutations {
+		mutate := mutate
+		property := func(q randomQuery) bool {
+			changed := q.ListQuery
+			mutate(&changed)
+			return GenerateProdutosListKey(q.ListQuery) != GenerateProdutosListKey(changed)
+		}
+		if err := quick.Check(property, &quick.Config{MaxCount: 500}); err != nil {
+			t.Errorf("%s: %v", name, err)
+		}
+	}
+}
+
+func TestGenerateProdutosListKey_NoCollisions(t *testing.T) {
+	// Consultas com formas canônicas diferentes nunca compartilham a chave
+	r := rand.New(rand.NewSource(2))
+	canonicalByKey := make(map[string]string)
+	for i := 0; i < 5000; i++ {
+		q := randomQuery{}.Generate(r, 0).Interface().(randomQuery).ListQuery
+		canonical := hashKeyInput(t, q.canonical())
+		key := GenerateProdutosListKey(q)
+		if existing, ok := canonicalByKey[key]; ok && existing != canonical {
+			t.Fatalf("Colisão na chave %s:\n%s\n%s", key, existing, canonical)
+		}
+		canonicalByKey[key] = canonical
+	}
+}
+
+// hashKeyInput retorna a serialização usada por hashKey
+func hashKeyInput(t *testing.T, v interface{}) string {
+	t.Helper()
+	data, err := json.Marshal(v)
+	if err != nil {
+		t.Fatalf("Erro ao serializar: %v", err)
+	}
+	return string(data)
+}
+
+func TestGenerateProdutosListKey_Normalization(t *testing.T) {
+	base := ListQuery{Filter: map[string]interface{}{}, Sort: bson.D{{Key: "id", Value: 1}}}
+	defaults := ListQuery{
+		Pagination: dto.PaginationRequest{Page: 1, PageSize: 10},
+		Sort:       bson.D{{Key: "id", Value: 1}},
+	}
+	if GenerateProdutosListKey(base) != GenerateProdutosListKey(defaults) {
+		t.Error("Paginação e filtro vazios deveriam gerar a mesma chave dos valores padrão")
+	}
+
+	key := GenerateProdutosListKey(defaults)
+	if !strings.HasPrefix(key, ProdutoListNamespace+":") || len(key) != len(ProdutoListNamespace)+1+64 {
+		t.Errorf("Chave fora do formato %s:<sha256>: %s", ProdutoListNamespace, key)
+	}
+}
+
+func TestGenerateProdutosFacetsKey(t *testing.T) {
+	filter := map[string]interface{}{"preco": map[string]interface{}{"$gte": 10.0}, "tags": "a"}
+	a := GenerateProdutosFacetsKey(FacetsQuery{Facets: []string{"tags", "preco"}, Buckets: 5, Filter: filter})
+	b := GenerateProdutosFacetsKey(FacetsQuery{Facets: []string{"preco", "tags"}, Buckets: 5, Filter: rebuild(rand.New(rand.NewSource(3)), filter).(map[string]interface{})})
+	if a != b {
+		t.Errorf("Facetas equivalentes deveriam gerar a mesma chave: %s != %s", a, b)
+	}
+	if c := GenerateProdutosFacetsKey(FacetsQuery{Facets: []string{"preco", "tags"}, Buckets: 6, Filter: filter}); c == a {
+		t.Error("Número de faixas diferente deveria gerar outra chave")
+	}
+	if GenerateProdutosListKey(ListQuery{Filter: filter}) == a {
+		t.Error("Listas e facetas não devem compartilhar chaves")
+	}
+}
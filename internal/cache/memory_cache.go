@@ -1,53 +1,141 @@
 package cache
 
 import (
+	"container/list"
 	"context"
 	"sync"
 	"time"
+
+	"api-go-arquitetura/internal/metrics"
+)
+
+// EvictionPolicy define como o cache em memória escolhe as entradas a remover
+type EvictionPolicy string
+
+const (
+	// EvictionLRU remove a entrada usada há mais tempo
+	EvictionLRU EvictionPolicy = "lru"
+	// EvictionTinyLFU também remove pela ordem LRU, mas só admite uma chave nova
+	// se ela for mais frequente que a entrada que seria removida
+	EvictionTinyLFU EvictionPolicy = "tinylfu"
+)
+
+// Motivos de remoção registrados na métrica de evictions
+const (
+	evictCapacity = "capacity" // Limite de entradas ou de bytes atingido
+	evictExpired  = "expired"  // TTL vencido
+	evictRejected = "rejected" // Valor não admitido (TinyLFU) ou maior que MaxBytes
 )
 
-// memoryCache implementa Cache usando memória local
+// MemoryCacheOptions configura os limites do cache em memória
+type MemoryCacheOptions struct {
+	Name            string         // Nome do cache no label "cache" das métricas (padrão: default)
+	MaxEntries      int            // Máximo de entradas (0 = sem limite)
+	MaxBytes        int64          // Máximo de bytes de chaves e valores (0 = sem limite)
+	Policy          EvictionPolicy // Política de remoção (padrão: lru)
+	CleanupInterval time.Duration  // Intervalo da remoção dos itens expirados (0 = apenas na leitura)
+	// NoEviction recusa com ErrCacheFull os valores que não cabem nos limites, em vez
+	// de remover outras entradas: para dados que não podem ser descartados antes do TTL
+	NoEviction bool
+}
+
+// DefaultMemoryCacheOptions retorna os limites padrão do cache em memória
+func DefaultMemoryCacheOptions() MemoryCacheOptions {
+	return MemoryCacheOptions{
+		MaxEntries:      10000,
+		MaxBytes:        64 << 20,
+		Policy:          EvictionLRU,
+		CleanupInterval: time.Minute,
+	}
+}
+
+// memoryCache implementa Cache usando memória local, limitado em entradas e bytes
 type memoryCache struct {
-	mu    sync.RWMutex
-	items map[string]*cacheItem
+	mu          sync.Mutex
+	opts        MemoryCacheOptions
+	items       map[string]*list.Element // Elementos de lru, por chave
+	lru         *list.List               // *cacheItem, do mais para o menos recente
+	bytes       int64
+	sketch      *frequencySketch // nil: sem admissão TinyLFU
+	generations map[string]int64 // Geração de cada namespace (não é apagada por Clear)
+
+	hits   int64
+	misses int64
+
+	stop      chan struct{}
+	closeOnce sync.Once
 }
 
 type cacheItem struct {
+	key        string
 	value      []byte
 	expiration time.Time
 }
 
-// NewMemoryCache cria uma nova instância de cache em memória
+// size é o quanto o item conta para MaxBytes
+func (i *cacheItem) size() int64 {
+	return int64(len(i.key) + len(i.value))
+}
+
+// NewMemoryCache cria uma nova instância de cache em memória com os limites padrão
 func NewMemoryCache() Cache {
+	return NewMemoryCacheWithOptions(DefaultMemoryCacheOptions())
+}
+
+// NewMemoryCacheWithOptions cria uma nova instância de cache em memória com os
+// limites informados
+// Com CleanupInterval, uma goroutine remove os itens expirados até Close
+func NewMemoryCacheWithOptions(opts MemoryCacheOptions) Cache {
+	if opts.Policy == "" {
+		opts.Policy = EvictionLRU
+	}
+	if opts.Name == "" {
+		opts.Name = "default"
+	}
 	c := &memoryCache{
-		items: make(map[string]*cacheItem),
+		opts:        opts,
+		items:       make(map[string]*list.Element),
+		lru:         list.New(),
+		generations: make(map[string]int64),
+		stop:        make(chan struct{}),
+	}
+	if opts.Policy == EvictionTinyLFU {
+		c.sketch = newFrequencySketch(opts.MaxEntries)
+	}
+	if opts.CleanupInterval > 0 {
+		go c.cleanup(opts.CleanupInterval)
 	}
-	// Iniciar goroutine para limpar itens expirados
-	go c.cleanup()
 	return c
 }
 
 // Get recupera um valor do cache
 func (c *memoryCache) Get(ctx context.Context, key string) ([]byte, error) {
-	c.mu.RLock()
-	defer c.mu.RUnlock()
+	c.mu.Lock()
+	defer c.mu.Unlock()
 
-	item, exists := c.items[key]
+	if c.sketch != nil {
+		c.sketch.increment(key)
+	}
+
+	elem, exists := c.items[key]
 	if !exists {
+		c.recordAccess(false)
 		return nil, ErrCacheMiss
 	}
 
 	// Verificar se o item expirou
+	item := elem.Value.(*cacheItem)
 	if time.Now().After(item.expiration) {
-		// Remover item expirado (em modo de escrita)
-		c.mu.RUnlock()
-		c.mu.Lock()
-		delete(c.items, key)
-		c.mu.Unlock()
-		c.mu.RLock()
+		c.remove(elem)
+		metrics.RecordCacheEviction(c.opts.Name, evictExpired, 1)
+		c.updateSize()
+		c.recordAccess(false)
 		return nil, ErrCacheMiss
 	}
 
+	c.lru.MoveToFront(elem)
+	c.recordAccess(true)
+
 	// Retornar cópia do valor para evitar race conditions
 	result := make([]byte, len(item.value))
 	copy(result, item.value)
@@ -55,16 +143,31 @@ func (c *memoryCache) Get(ctx context.Context, key string) ([]byte, error) {
 }
 
 // Set armazena um valor no cache com TTL
+// Com TinyLFU, um valor não admitido é descartado sem erro
 func (c *memoryCache) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
-	expiration := time.Now().Add(ttl)
-	c.items[key] = &cacheItem{
-		value:      value,
-		expiration: expiration,
+	if c.sketch != nil {
+		c.sketch.increment(key)
 	}
-	return nil
+	_, err := c.store(key, value, ttl, true)
+	return err
+}
+
+// SetNX armazena um valor apenas se a chave não existir (ou estiver expirada)
+// A admissão do TinyLFU não se aplica: a chave é usada como trava
+func (c *memoryCache) SetNX(ctx context.Context, key string, value []byte, ttl time.Duration) (bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if elem, exists := c.items[key]; exists && time.Now().Before(elem.Value.(*cacheItem).expiration) {
+		return false, nil
+	}
+	if c.sketch != nil {
+		c.sketch.increment(key)
+	}
+	return c.store(key, value, ttl, false)
 }
 
 // Delete remove um valor do cache
@@ -72,7 +175,10 @@ func (c *memoryCache) Delete(ctx context.Context, key string) error {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
-	delete(c.items, key)
+	if elem, exists := c.items[key]; exists {
+		c.remove(elem)
+		c.updateSize()
+	}
 	return nil
 }
 
@@ -81,42 +187,181 @@ func (c *memoryCache) Clear(ctx context.Context) error {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
-	c.items = make(map[string]*cacheItem)
+	c.items = make(map[string]*list.Element)
+	c.lru.Init()
+	c.bytes = 0
+	c.updateSize()
 	return nil
 }
 
 // Exists verifica se uma chave existe no cache
+// Não altera a ordem de uso da chave
 func (c *memoryCache) Exists(ctx context.Context, key string) (bool, error) {
-	c.mu.RLock()
-	defer c.mu.RUnlock()
+	c.mu.Lock()
+	defer c.mu.Unlock()
 
-	item, exists := c.items[key]
+	elem, exists := c.items[key]
 	if !exists {
 		return false, nil
 	}
 
 	// Verificar se expirou
-	if time.Now().After(item.expiration) {
+	if time.Now().After(elem.Value.(*cacheItem).expiration) {
 		return false, nil
 	}
 
 	return true, nil
 }
 
-// cleanup remove itens expirados periodicamente
-func (c *memoryCache) cleanup() {
-	ticker := time.NewTicker(1 * time.Minute)
-	defer ticker.Stop()
+// Generation retorna a geração atual do namespace
+func (c *memoryCache) Generation(ctx context.Context, namespace string) (int64, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.generations[namespace], nil
+}
+
+// InvalidateNamespace incrementa a geração do namespace
+func (c *memoryCache) InvalidateNamespace(ctx context.Context, namespace string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.generations[namespace]++
+	return nil
+}
+
+// Close encerra a remoção periódica dos itens expirados
+// O cache continua utilizável; pode ser chamado mais de uma vez
+func (c *memoryCache) Close() error {
+	c.closeOnce.Do(func() { close(c.stop) })
+	return nil
+}
+
+// store insere ou substitui o item, removendo os menos recentes até caber nos limites
+// Com admission, a chave nova passa pela admissão do TinyLFU (se habilitado)
+// Retorna false se o item não foi armazenado
+// Deve ser chamado com o lock adquirido
+func (c *memoryCache) store(key string, value []byte, ttl time.Duration, admission bool) (bool, error) {
+	item := &cacheItem{key: key, value: value, expiration: time.Now().Add(ttl)}
+	defer c.updateSize()
+
+	elem, replacing := c.items[key]
+	if replacing {
+		c.remove(elem)
+	}
+	if c.opts.MaxBytes > 0 && item.size() > c.opts.MaxBytes {
+		metrics.RecordCacheEviction(c.opts.Name, evictRejected, 1)
+		return false, ErrValueTooLarge
+	}
 
-	for range ticker.C {
-		c.mu.Lock()
-		now := time.Now()
-		for key, item := range c.items {
-			if now.After(item.expiration) {
-				delete(c.items, key)
+	// Sem remoção, os itens expirados são os únicos que podem abrir espaço
+	if c.opts.NoEviction && c.overLimit(item.size()) {
+		if expired := c.dropExpired(time.Now()); expired > 0 {
+			metrics.RecordCacheEviction(c.opts.Name, evictExpired, expired)
+		}
+		if c.overLimit(item.size()) {
+			if replacing {
+				c.items[key] = c.lru.PushFront(elem.Value)
+				c.bytes += elem.Value.(*cacheItem).size()
 			}
+			metrics.RecordCacheEviction(c.opts.Name, evictRejected, 1)
+			return false, ErrCacheFull
+		}
+	}
+
+	// Com TinyLFU, a chave nova só entra se for mais frequente que a primeira que sairia
+	if admission && c.sketch != nil && !replacing && c.overLimit(item.size()) {
+		if victim := c.lru.Back(); victim != nil && !c.sketch.admit(key, victim.Value.(*cacheItem).key) {
+			metrics.RecordCacheEviction(c.opts.Name, evictRejected, 1)
+			return false, nil
+		}
+	}
+
+	evicted := 0
+	for c.overLimit(item.size()) {
+		c.remove(c.lru.Back())
+		evicted++
+	}
+	if evicted > 0 {
+		metrics.RecordCacheEviction(c.opts.Name, evictCapacity, evicted)
+	}
+
+	c.items[key] = c.lru.PushFront(item)
+	c.bytes += item.size()
+	return true, nil
+}
+
+// overLimit indica se um item com o tamanho informado excederia os limites
+func (c *memoryCache) overLimit(size int64) bool {
+	if c.lru.Len() == 0 {
+		return false
+	}
+	if c.opts.MaxEntries > 0 && c.lru.Len()+1 > c.opts.MaxEntries {
+		return true
+	}
+	return c.opts.MaxBytes > 0 && c.bytes+size > c.opts.MaxBytes
+}
+
+// remove retira o elemento do cache
+// Deve ser chamado com o lock adquirido
+func (c *memoryCache) remove(elem *list.Element) {
+	item := c.lru.Remove(elem).(*cacheItem)
+	delete(c.items, item.key)
+	c.bytes -= item.size()
+}
+
+// updateSize atualiza as métricas de tamanho do cache
+func (c *memoryCache) updateSize() {
+	metrics.SetCacheMemorySize(c.opts.Name, c.lru.Len(), c.bytes)
+}
+
+// recordAccess contabiliza a leitura e atualiza a taxa de acerto
+func (c *memoryCache) recordAccess(hit bool) {
+	if hit {
+		c.hits++
+	} else {
+		c.misses++
+	}
+	metrics.SetCacheMemoryHitRatio(c.opts.Name, float64(c.hits) / float64(c.hits+c.misses))
+}
+
+// cleanup remove itens expirados periodicamente, até Close
+func (c *memoryCache) cleanup(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-c.stop:
+			return
+		case <-ticker.C:
+			c.removeExpired()
 		}
-		c.mu.Unlock()
 	}
 }
 
+// removeExpired remove todos os itens expirados
+func (c *memoryCache) removeExpired() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if expired := c.dropExpired(time.Now()); expired > 0 {
+		metrics.RecordCacheEviction(c.opts.Name, evictExpired, expired)
+		c.updateSize()
+	}
+}
+
+// dropExpired remove os itens expirados e retorna quantos foram removidos
+// Deve ser chamado com o lock adquirido
+func (c *memoryCache) dropExpired(now time.Time) int {
+	expired := 0
+	for elem := c.lru.Back(); elem != nil; {
+		prev := elem.Prev()
+		if now.After(elem.Value.(*cacheItem).expiration) {
+			c.remove(elem)
+			expired++
+		}
+		elem = prev
+	}
+	return expired
+}
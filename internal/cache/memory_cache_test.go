@@ -0,0 +1,237 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"api-go-arquitetura/internal/metrics"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestMemoryCache_MaxEntriesEvictsLeastRecentlyUsed(t *testing.T) {
+	ctx := context.Background()
+	c := NewMemoryCacheWithOptions(MemoryCacheOptions{MaxEntries: 3})
+	defer c.Close()
+
+	for _, key := range []string{"a", "b", "c"} {
+		_ = c.Set(ctx, key, []byte(key), time.Minute)
+	}
+	// "a" passa a ser a mais recente; "b" é a próxima a sair
+	if _, err := c.Get(ctx, "a"); err != nil {
+		t.Fatalf("Erro inesperado: %v", err)
+	}
+	_ = c.Set(ctx, "d", []byte("d"), time.Minute)
+
+	if _, err := c.Get(ctx, "b"); err != ErrCacheMiss {
+		t.Errorf("Esperado ErrCacheMiss para a chave menos recente, obtido %v", err)
+	}
+	for _, key := range []string{"a", "c", "d"} {
+		if _, err := c.Get(ctx, key); err != nil {
+			t.Errorf("Chave %s deveria permanecer no cache: %v", key, err)
+		}
+	}
+}
+
+func TestMemoryCache_MaxBytes(t *testing.T) {
+	ctx := context.Background()
+	c := NewMemoryCacheWithOptions(MemoryCacheOptions{MaxBytes: 100}).(*memoryCache)
+	defer c.Close()
+
+	value := make([]byte, 30)
+	for i := 0; i < 5; i++ {
+		if err := c.Set(ctx, fmt.Sprintf("k%d", i), value, time.Minute); err != nil {
+			t.Fatalf("Erro inesperado: %v", err)
+		}
+	}
+	if c.bytes > 100 || c.lru.Len() != 3 {
+		t.Errorf("Esperadas 3 entradas em até 100 bytes, obtidas %d em %d bytes", c.lru.Len(), c.bytes)
+	}
+
+	t.Run("deve recusar valor maior que o limite e remover o anterior", func(t *testing.T) {
+		if err := c.Set(ctx, "k4", make([]byte, 200), time.Minute); err != ErrValueTooLarge {
+			t.Errorf("Esperado ErrValueTooLarge, obtido %v", err)
+		}
+		if _, err := c.Get(ctx, "k4"); err != ErrCacheMiss {
+			t.Errorf("O valor anterior não deveria permanecer no cache: %v", err)
+		}
+	})
+
+	t.Run("deve contabilizar a substituição de uma chave", func(t *testing.T) {
+		before := c.bytes
+		_ = c.Set(ctx, "k3", make([]byte, 10), time.Minute)
+		if c.bytes != before-20 {
+			t.Errorf("Esperados %d bytes, obtidos %d", before-20, c.bytes)
+		}
+	})
+}
+
+func TestMemoryCache_Expiration(t *testing.T) {
+	ctx := context.Background()
+	c := NewMemoryCacheWithOptions(MemoryCacheOptions{CleanupInterval: 10 * time.Millisecond}).(*memoryCache)
+	defer c.Close()
+
+	_ = c.Set(ctx, "curta", []byte("x"), 5*time.Millisecond)
+	_ = c.Set(ctx, "longa", []byte("x"), time.Minute)
+
+	deadline := time.Now().Add(time.Second)
+	for {
+		c.mu.Lock()
+		entries := c.lru.Len()
+		c.mu.Unlock()
+		if entries == 1 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("A limpeza deveria remover a chave expirada, restam %d entradas", entries)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	if ok, _ := c.Exists(ctx, "longa"); !ok {
+		t.Error("A chave não expirada deveria permanecer no cache")
+	}
+}
+
+func TestMemoryCache_Close(t *testing.T) {
+	ctx := context.Background()
+	c := NewMemoryCacheWithOptions(MemoryCacheOptions{CleanupInterval: time.Millisecond})
+
+	if err := c.Close(); err != nil {
+		t.Fatalf("Erro inesperado: %v", err)
+	}
+	if err := c.Close(); err != nil {
+		t.Fatalf("Close deveria poder ser chamado mais de uma vez: %v", err)
+	}
+
+	// O cache continua utilizável após Close
+	_ = c.Set(ctx, "a", []byte("a"), time.Minute)
+	if _, err := c.Get(ctx, "a"); err != nil {
+		t.Errorf("Erro inesperado após Close: %v", err)
+	}
+}
+
+func TestMemoryCache_TinyLFUAdmission(t *testing.T) {
+	ctx := context.Background()
+	c := NewMemoryCacheWithOptions(MemoryCacheOptions{MaxEntries: 2, Policy: EvictionTinyLFU})
+	defer c.Close()
+
+	// Chaves frequentes ocupam o cache
+	for i := 0; i < 5; i++ {
+		_ = c.Set(ctx, "quente1", []byte("1"), time.Minute)
+		_ = c.Set(ctx, "quente2", []byte("2"), time.Minute)
+		_, _ = c.Get(ctx, "quente1")
+		_, _ = c.Get(ctx, "quente2")
+	}
+
+	// Uma varredura de chaves lidas uma única vez não deve expulsá-las
+	for i := 0; i < 100; i++ {
+		_ = c.Set(ctx, fmt.Sprintf("varredura%d", i), []byte("x"), time.Minute)
+	}
+	for _, key := range []string{"quente1", "quente2"} {
+		if _, err := c.Get(ctx, key); err != nil {
+			t.Errorf("Chave frequente %s deveria permanecer no cache: %v", key, err)
+		}
+	}
+
+	t.Run("SetNX ignora a admissão", func(t *testing.T) {
+		ok, err := c.SetNX(ctx, "trava", []byte("x"), time.Minute)
+		if err != nil || !ok {
+			t.Fatalf("SetNX deveria armazenar a trava, obtido %v, %v", ok, err)
+		}
+		if exists, _ := c.Exists(ctx, "trava"); !exists {
+			t.Error("A trava deveria estar no cache")
+		}
+	})
+}
+
+func TestMemoryCache_GenerationSurvivesClear(t *testing.T) {
+	ctx := context.Background()
+	c := NewMemoryCache()
+	defer c.Close()
+
+	_ = c.InvalidateNamespace(ctx, ProdutoListNamespace)
+	_ = c.Set(ctx, "a", []byte("a"), time.Minute)
+	_ = c.Clear(ctx)
+
+	if gen, _ := c.Generation(ctx, ProdutoListNamespace); gen != 1 {
+		t.Errorf("Geração esperada 1 após Clear, obtida %d", gen)
+	}
+	if _, err := c.Get(ctx, "a"); err != ErrCacheMiss {
+		t.Errorf("Esperado ErrCacheMiss após Clear, obtido %v", err)
+	}
+}
+
+func TestMemoryCache_NoEviction(t *testing.T) {
+	ctx := context.Background()
+	c := NewMemoryCacheWithOptions(MemoryCacheOptions{MaxEntries: 2, NoEviction: true})
+	defer c.Close()
+
+	_ = c.Set(ctx, "a", []byte("a"), time.Minute)
+	_ = c.Set(ctx, "b", []byte("b"), 10*time.Millisecond)
+
+	if ok, err := c.SetNX(ctx, "c", []byte("c"), time.Minute); ok || err != ErrCacheFull {
+		t.Fatalf("Esperado ErrCacheFull, obtido %v, %v", ok, err)
+	}
+	if err := c.Set(ctx, "a", []byte("a2"), time.Minute); err != nil {
+		t.Errorf("Substituir uma chave existente deveria caber: %v", err)
+	}
+	if ok, _ := c.Exists(ctx, "a"); !ok {
+		t.Error("Nenhuma entrada deveria ser removida para abrir espaço")
+	}
+
+	// Entradas expiradas liberam espaço
+	time.Sleep(20 * time.Millisecond)
+	if ok, err := c.SetNX(ctx, "c", []byte("c"), time.Minute); !ok || err != nil {
+		t.Errorf("A entrada expirada deveria abrir espaço: %v, %v", ok, err)
+	}
+}
+
+func TestMemoryCache_NoEvictionKeepsReplacedValue(t *testing.T) {
+	ctx := context.Background()
+	c := NewMemoryCacheWithOptions(MemoryCacheOptions{MaxBytes: 10, NoEviction: true})
+	defer c.Close()
+
+	_ = c.Set(ctx, "a", []byte("1234"), time.Minute)
+	_ = c.Set(ctx, "b", []byte("1"), time.Minute)
+	if err := c.Set(ctx, "a", []byte("12345678"), time.Minute); err != ErrCacheFull {
+		t.Fatalf("Esperado ErrCacheFull, obtido %v", err)
+	}
+	if value, err := c.Get(ctx, "a"); err != nil || string(value) != "1234" {
+		t.Errorf("O valor anterior deveria ser mantido, obtido %q (%v)", value, err)
+	}
+}
+
+func TestMemoryCache_MetricsPerCache(t *testing.T) {
+	ctx := context.Background()
+	a := NewMemoryCacheWithOptions(MemoryCacheOptions{Name: "teste-a", MaxEntries: 1})
+	b := NewMemoryCacheWithOptions(MemoryCacheOptions{Name: "teste-b"})
+	defer a.Close()
+	defer b.Close()
+
+	_ = a.Set(ctx, "k1", []byte("v"), time.Minute)
+	_ = a.Set(ctx, "k2", []byte("v"), time.Minute) // Remove k1 por capacidade
+	for i := 0; i < 3; i++ {
+		_ = b.Set(ctx, fmt.Sprintf("k%d", i), []byte("v"), time.Minute)
+	}
+	_, _ = a.Get(ctx, "k1")
+	_, _ = b.Get(ctx, "k1")
+
+	// Cada cache tem as suas próprias séries: uma escrita em b não altera as de a
+	if entries := testutil.ToFloat64(metrics.CacheMemoryEntries.WithLabelValues("teste-a")); entries != 1 {
+		t.Errorf("Entradas de teste-a esperadas 1, obtidas %v", entries)
+	}
+	if entries := testutil.ToFloat64(metrics.CacheMemoryEntries.WithLabelValues("teste-b")); entries != 3 {
+		t.Errorf("Entradas de teste-b esperadas 3, obtidas %v", entries)
+	}
+	if ratio := testutil.ToFloat64(metrics.CacheMemoryHitRatio.WithLabelValues("teste-a")); ratio != 0 {
+		t.Errorf("Taxa de acerto de teste-a esperada 0, obtida %v", ratio)
+	}
+	if ratio := testutil.ToFloat64(metrics.CacheMemoryHitRatio.WithLabelValues("teste-b")); ratio != 1 {
+		t.Errorf("Taxa de acerto de teste-b esperada 1, obtida %v", ratio)
+	}
+	if evicted := testutil.ToFloat64(metrics.CacheMemoryEvictions.WithLabelValues("teste-b", evictCapacity)); evicted != 0 {
+		t.Errorf("teste-b não deveria ter remoções por capacidade, obtidas %v", evicted)
+	}
+}
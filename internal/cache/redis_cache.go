@@ -7,6 +7,9 @@ import (
 	"github.com/redis/go-redis/v9"
 )
 
+// generationKeyPrefix prefixa as chaves com a geração de cada namespace
+const generationKeyPrefix = "cache:gen:"
+
 // redisCache implementa Cache usando Redis
 type redisCache struct {
 	client *redis.Client
@@ -48,6 +51,11 @@ func (c *redisCache) Set(ctx context.Context, key string, value []byte, ttl time
 	return c.client.Set(ctx, key, value, ttl).Err()
 }
 
+// SetNX armazena um valor apenas se a chave não existir
+func (c *redisCache) SetNX(ctx context.Context, key string, value []byte, ttl time.Duration) (bool, error) {
+	return c.client.SetNX(ctx, key, value, ttl).Result()
+}
+
 // Delete remove um valor do cache
 func (c *redisCache) Delete(ctx context.Context, key string) error {
 	return c.client.Del(ctx, key).Err()
@@ -70,3 +78,23 @@ func (c *redisCache) Exists(ctx context.Context, key string) (bool, error) {
 	return count > 0, nil
 }
 
+
+// Generation retorna a geração atual do namespace
+// A geração é compartilhada por todas as instâncias que usam o mesmo Redis
+func (c *redisCache) Generation(ctx context.Context, namespace string) (int64, error) {
+	gen, err := c.client.Get(ctx, generationKeyPrefix+namespace).Int64()
+	if err == redis.Nil {
+		return 0, nil
+	}
+	return gen, err
+}
+
+// InvalidateNamespace incrementa atomicamente a geração do namespace
+func (c *redisCache) InvalidateNamespace(ctx context.Context, namespace string) error {
+	return c.client.Incr(ctx, generationKeyPrefix+namespace).Err()
+}
+
+// Close fecha a conexão com o Redis
+func (c *redisCache) Close() error {
+	return c.client.Close()
+}
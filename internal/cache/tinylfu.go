@@ -0,0 +1,89 @@
+package cache
+
+import "hash/fnv"
+
+const (
+	// sketchDepth é o número de linhas do count-min sketch
+	sketchDepth = 4
+	// sketchMaxCount é o valor máximo de cada contador
+	sketchMaxCount = 15
+	// sketchMinWidth é a largura mínima de cada linha
+	sketchMinWidth = 1024
+)
+
+// frequencySketch estima a frequência de acesso das chaves (count-min sketch)
+// Os contadores são reduzidos à metade periodicamente, para que chaves que
+// deixaram de ser usadas percam prioridade
+type frequencySketch struct {
+	rows       [sketchDepth][]uint8
+	mask       uint64
+	additions  int
+	resetAfter int
+}
+
+// newFrequencySketch cria um sketch dimensionado para o número de entradas do cache
+func newFrequencySketch(entries int) *frequencySketch {
+	width := sketchMinWidth
+	for width < entries {
+		width <<= 1
+	}
+	s := &frequencySketch{
+		mask:       uint64(width - 1),
+		resetAfter: 10 * width,
+	}
+	for i := range s.rows {
+		s.rows[i] = make([]uint8, width)
+	}
+	return s
+}
+
+// increment registra um acesso à chave
+func (s *frequencySketch) increment(key string) {
+	h1, h2 := sketchHashes(key)
+	for i := range s.rows {
+		idx := (h1 + uint64(i)*h2) & s.mask
+		if s.rows[i][idx] < sketchMaxCount {
+			s.rows[i][idx]++
+		}
+	}
+
+	s.additions++
+	if s.additions >= s.resetAfter {
+		s.reset()
+	}
+}
+
+// estimate retorna a frequência estimada da chave
+func (s *frequencySketch) estimate(key string) uint8 {
+	h1, h2 := sketchHashes(key)
+	min := uint8(sketchMaxCount)
+	for i := range s.rows {
+		if v := s.rows[i][(h1+uint64(i)*h2)&s.mask]; v < min {
+			min = v
+		}
+	}
+	return min
+}
+
+// admit indica se a chave candidata deve substituir a vítima
+func (s *frequencySketch) admit(candidate, victim string) bool {
+	return s.estimate(candidate) > s.estimate(victim)
+}
+
+// reset reduz todos os contadores à metade
+func (s *frequencySketch) reset() {
+	for i := range s.rows {
+		for j := range s.rows[i] {
+			s.rows[i][j] >>= 1
+		}
+	}
+	s.additions /= 2
+}
+
+// sketchHashes retorna os dois hashes usados para derivar o índice de cada linha
+func sketchHashes(key string) (uint64, uint64) {
+	h := fnv.New64a()
+	h.Write([]byte(key))
+	sum := h.Sum64()
+	return sum, sum>>32 | 1
+}
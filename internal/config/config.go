@@ -25,16 +25,51 @@ type Config struct {
 	MaxPoolSize  uint64
 	MinPoolSize  uint64
 	
+	// IDs
+	IDBlockSize int // Quantidade de IDs reservados por vez no contador (1 = sem reserva em bloco)
+	
+	// Lixeira (soft delete)
+	TrashRetention time.Duration // Tempo que um produto removido permanece na lixeira antes da remoção definitiva
+	PurgeInterval  time.Duration // Intervalo entre execuções da limpeza (0 = desabilitado)
+	
+	// Estoque
+	ReservationSweepInterval time.Duration // Intervalo entre as liberações de reservas expiradas (0 = desabilitado)
+	
+	// Eventos de domínio (outbox)
+	OutboxEnabled       bool          // Grava eventos no outbox na transação de cada alteração (requer replica set)
+	OutboxPublisher     string        // "log" ou "http"
+	OutboxWebhookURL    string        // URL que recebe os eventos quando OutboxPublisher = "http"
+	OutboxRelayInterval time.Duration // Intervalo entre as publicações dos eventos pendentes (0 = desabilitado)
+	
+	// Webhooks
+	WebhookDeliveryInterval  time.Duration // Intervalo entre os envios das entregas pendentes (0 = desabilitado)
+	WebhookMaxAttempts       int           // Tentativas de uma entrega antes de ir para dead
+	WebhookRetryInitialDelay time.Duration // Espera após a primeira falha (dobra a cada falha)
+	WebhookRetryMaxDelay     time.Duration // Espera máxima entre tentativas
+	
 	// Observability
 	LokiURL string
 	LokiJob string
 	
 	// Cache
-	CacheType      string        // "memory" ou "redis"
-	CacheTTL       time.Duration // TTL padrão do cache
-	RedisAddr      string        // Endereço do Redis (ex: "localhost:6379")
-	RedisPassword  string        // Senha do Redis
-	RedisDB        int           // Database do Redis
+	CacheType           string        // "memory", "redis" ou "layered" (memória local na frente do Redis)
+	CacheTTL            time.Duration // TTL padrão do cache
+	CacheStaleTTL       time.Duration // Tempo após o TTL em que a entrada vencida ainda é servida durante a recarga (0 = desabilitado)
+	CacheMaxEntries     int           // Máximo de entradas do cache em memória (0 = sem limite)
+	CacheMaxBytes       int64         // Máximo de bytes do cache em memória (0 = sem limite)
+	CacheEvictionPolicy string        // Política de remoção do cache em memória: "lru" ou "tinylfu"
+	CacheLocalTTL       time.Duration // TTL máximo de uma entrada no cache local quando CacheType = "layered"
+	RedisAddr           string        // Endereço do Redis (ex: "localhost:6379")
+	RedisPassword       string        // Senha do Redis
+	RedisDB             int           // Database do Redis
+	
+	// Idempotência
+	IdempotencyTTL        time.Duration // Tempo de retenção das respostas associadas a um Idempotency-Key
+	IdempotencyMaxEntries int           // Máximo de registros de idempotência em memória (sem Redis)
+	IdempotencyMaxBytes   int64         // Máximo de bytes dos registros de idempotência em memória (sem Redis)
+	
+	// Auditoria
+	TrustActorHeader bool // X-Actor é preenchido por um gateway que autentica o cliente (autor verificado)
 	
 	// CORS
 	CORSAllowedOrigins []string // Origens permitidas (vazio = todas)
@@ -69,21 +104,56 @@ func Load() Config {
 		MaxPoolSize: getUint64Env("MONGO_MAX_POOL_SIZE", 100),
 		MinPoolSize: getUint64Env("MONGO_MIN_POOL_SIZE", 10),
 		
+		// IDs
+		IDBlockSize: getIntEnv("ID_BLOCK_SIZE", 1),
+		
+		// Lixeira (soft delete)
+		TrashRetention: getDurationEnv("TRASH_RETENTION", 30*24*time.Hour),
+		PurgeInterval:  getDurationEnv("PURGE_INTERVAL", time.Hour),
+		
+		// Estoque
+		ReservationSweepInterval: getDurationEnv("RESERVATION_SWEEP_INTERVAL", time.Minute),
+		
+		// Eventos de domínio (outbox)
+		OutboxEnabled:       getBoolEnv("OUTBOX_ENABLED", false),
+		OutboxPublisher:     getEnv("OUTBOX_PUBLISHER", "log"),
+		OutboxWebhookURL:    getEnv("OUTBOX_WEBHOOK_URL", ""),
+		OutboxRelayInterval: getDurationEnv("OUTBOX_RELAY_INTERVAL", time.Second),
+		
+		// Webhooks
+		WebhookDeliveryInterval:  getDurationEnv("WEBHOOK_DELIVERY_INTERVAL", time.Second),
+		WebhookMaxAttempts:       getIntEnv("WEBHOOK_MAX_ATTEMPTS", 8),
+		WebhookRetryInitialDelay: getDurationEnv("WEBHOOK_RETRY_INITIAL_DELAY", 30*time.Second),
+		WebhookRetryMaxDelay:     getDurationEnv("WEBHOOK_RETRY_MAX_DELAY", time.Hour),
+		
 		// Observability
 		LokiURL: getEnv("LOKI_URL", ""),
 		LokiJob: getEnv("LOKI_JOB", "ARQUITETURA"),
 		
 		// Cache
-		CacheType:     getEnv("CACHE_TYPE", "memory"), // memory ou redis
-		CacheTTL:      getDurationEnv("CACHE_TTL", 5*time.Minute),
-		RedisAddr:     getEnv("REDIS_ADDR", "localhost:6379"),
-		RedisPassword: getEnv("REDIS_PASSWORD", ""),
-		RedisDB:        getIntEnv("REDIS_DB", 0),
+		CacheType:           getEnv("CACHE_TYPE", "memory"), // memory, redis ou layered
+		CacheTTL:            getDurationEnv("CACHE_TTL", 5*time.Minute),
+		CacheStaleTTL:       getDurationEnv("CACHE_STALE_TTL", 0),
+		CacheMaxEntries:     getIntEnv("CACHE_MAX_ENTRIES", 10000),
+		CacheMaxBytes:       int64(getIntEnv("CACHE_MAX_BYTES", 64<<20)),
+		CacheEvictionPolicy: getEnv("CACHE_EVICTION_POLICY", "lru"),
+		CacheLocalTTL:       getDurationEnv("CACHE_LOCAL_TTL", 30*time.Second),
+		RedisAddr:           getEnv("REDIS_ADDR", "localhost:6379"),
+		RedisPassword:       getEnv("REDIS_PASSWORD", ""),
+		RedisDB:             getIntEnv("REDIS_DB", 0),
+		
+		// Idempotência
+		IdempotencyTTL:        getDurationEnv("IDEMPOTENCY_TTL", 24*time.Hour),
+		IdempotencyMaxEntries: getIntEnv("IDEMPOTENCY_MAX_ENTRIES", 100000),
+		IdempotencyMaxBytes:   int64(getIntEnv("IDEMPOTENCY_MAX_BYTES", 256<<20)),
+		
+		// Auditoria
+		TrustActorHeader: getBoolEnv("TRUST_ACTOR_HEADER", false),
 		
 		// CORS
 		CORSAllowedOrigins: getStringSliceEnv("CORS_ALLOWED_ORIGINS", []string{"*"}),
 		CORSAllowedMethods: getStringSliceEnv("CORS_ALLOWED_METHODS", []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"}),
-		CORSAllowedHeaders: getStringSliceEnv("CORS_ALLOWED_HEADERS", []string{"Content-Type", "Authorization"}),
+		CORSAllowedHeaders: getStringSliceEnv("CORS_ALLOWED_HEADERS", []string{"Content-Type", "Authorization", "If-Match", "If-None-Match", "If-Modified-Since", "Idempotency-Key"}),
 		CORSCredentials:    getBoolEnv("CORS_CREDENTIALS", false),
 	}
 }
@@ -102,6 +172,51 @@ func (c *Config) Validate() error {
 	if c.ConnectTimeout <= 0 {
 		return fmt.Errorf("MONGO_CONNECT_TIMEOUT deve ser maior que zero")
 	}
+	if c.TrashRetention < 0 {
+		return fmt.Errorf("TRASH_RETENTION não pode ser negativo")
+	}
+	if c.PurgeInterval < 0 {
+		return fmt.Errorf("PURGE_INTERVAL não pode ser negativo")
+	}
+	if c.ReservationSweepInterval < 0 {
+		return fmt.Errorf("RESERVATION_SWEEP_INTERVAL não pode ser negativo")
+	}
+	if c.OutboxPublisher != "log" && c.OutboxPublisher != "http" {
+		return fmt.Errorf("OUTBOX_PUBLISHER deve ser \"log\" ou \"http\"")
+	}
+	if c.OutboxEnabled && c.OutboxPublisher == "http" && c.OutboxWebhookURL == "" {
+		return fmt.Errorf("OUTBOX_WEBHOOK_URL é obrigatória quando OUTBOX_PUBLISHER=http")
+	}
+	if c.OutboxRelayInterval < 0 {
+		return fmt.Errorf("OUTBOX_RELAY_INTERVAL não pode ser negativo")
+	}
+	if c.WebhookDeliveryInterval < 0 {
+		return fmt.Errorf("WEBHOOK_DELIVERY_INTERVAL não pode ser negativo")
+	}
+	if c.WebhookMaxAttempts < 1 {
+		return fmt.Errorf("WEBHOOK_MAX_ATTEMPTS deve ser maior que zero")
+	}
+	if c.WebhookRetryInitialDelay < 0 || c.WebhookRetryMaxDelay < c.WebhookRetryInitialDelay {
+		return fmt.Errorf("WEBHOOK_RETRY_INITIAL_DELAY não pode ser negativo nem maior que WEBHOOK_RETRY_MAX_DELAY")
+	}
+	if c.CacheType != "memory" && c.CacheType != "redis" && c.CacheType != "layered" {
+		return fmt.Errorf("CACHE_TYPE deve ser \"memory\", \"redis\" ou \"layered\"")
+	}
+	if c.CacheType == "layered" && c.CacheLocalTTL <= 0 {
+		return fmt.Errorf("CACHE_LOCAL_TTL deve ser maior que zero")
+	}
+	if c.CacheStaleTTL < 0 {
+		return fmt.Errorf("CACHE_STALE_TTL não pode ser negativo")
+	}
+	if c.CacheMaxEntries < 0 || c.CacheMaxBytes < 0 {
+		return fmt.Errorf("CACHE_MAX_ENTRIES e CACHE_MAX_BYTES não podem ser negativos")
+	}
+	if c.CacheEvictionPolicy != "lru" && c.CacheEvictionPolicy != "tinylfu" {
+		return fmt.Errorf("CACHE_EVICTION_POLICY deve ser \"lru\" ou \"tinylfu\"")
+	}
+	if c.IDBlockSize < 1 {
+		return fmt.Errorf("ID_BLOCK_SIZE deve ser maior que zero")
+	}
 	return nil
 }
 
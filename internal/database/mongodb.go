@@ -147,8 +147,42 @@ func CreateIndexes(ctx context.Context, client *mongo.Client, database, collecti
 		Options: options.Index().SetName("idx_preco"),
 	}
 
+	// Índice para a lixeira (soft delete)
+	deletedAtIndex := mongo.IndexModel{
+		Keys: bson.D{{Key: "deleted_at", Value: -1}},
+		Options: options.Index().SetName("idx_deleted_at"),
+	}
+
+	// Índice multikey para filtros e facetas por tag
+	tagsIndex := mongo.IndexModel{
+		Keys: bson.D{{Key: "tags", Value: 1}},
+		Options: options.Index().SetName("idx_tags"),
+	}
+
+	// Índice para o filtro por categoria
+	categoriaIndex := mongo.IndexModel{
+		Keys: bson.D{{Key: "categoria_id", Value: 1}},
+		Options: options.Index().SetName("idx_categoria_id"),
+	}
+
+	// Índice para a liberação das reservas de estoque expiradas
+	reservasIndex := mongo.IndexModel{
+		Keys: bson.D{{Key: "reservas.expires_at", Value: 1}},
+		Options: options.Index().SetName("idx_reservas_expires_at").SetSparse(true),
+	}
+
+	// Índice de texto para a busca (q): nome pesa mais que descrição na relevância
+	// e o idioma padrão português habilita stemming e stop words
+	textIndex := mongo.IndexModel{
+		Keys: bson.D{{Key: "nome", Value: "text"}, {Key: "descricao", Value: "text"}},
+		Options: options.Index().
+			SetName("idx_text").
+			SetWeights(bson.D{{Key: "nome", Value: 10}, {Key: "descricao", Value: 2}}).
+			SetDefaultLanguage("portuguese"),
+	}
+
 	// Criar todos os índices
-	indexes := []mongo.IndexModel{idIndex, nomeIndex, descricaoIndex, precoIndex}
+	indexes := []mongo.IndexModel{idIndex, nomeIndex, descricaoIndex, precoIndex, deletedAtIndex, tagsIndex, categoriaIndex, reservasIndex, textIndex}
 	_, err := col.Indexes().CreateMany(ctx, indexes)
 	if err != nil {
 		return fmt.Errorf("erro ao criar índices: %w", err)
@@ -163,6 +197,111 @@ func CreateIndexes(ctx context.Context, client *mongo.Client, database, collecti
 	return nil
 }
 
+// CreateCategoriaIndexes cria os índices da coleção de categorias
+func CreateCategoriaIndexes(ctx context.Context, client *mongo.Client, database, collection string) error {
+	col := client.Database(database).Collection(collection)
+
+	indexes := []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "id", Value: 1}},
+			Options: options.Index().SetUnique(true).SetName("idx_id"),
+		},
+		{
+			// O path (slugs da raiz até a categoria) não pode se repetir: o mesmo slug
+			// é aceito sob pais diferentes (ex.: "roupas/infantil" e "calcados/infantil")
+			// Também atende à ordenação de FindAll
+			Keys:    bson.D{{Key: "path", Value: 1}},
+			Options: options.Index().SetUnique(true).SetName("idx_path"),
+		},
+		{
+			// Índice multikey usado para buscar os descendentes de uma categoria
+			Keys:    bson.D{{Key: "ancestors", Value: 1}},
+			Options: options.Index().SetName("idx_ancestors"),
+		},
+		{
+			Keys:    bson.D{{Key: "parent_id", Value: 1}},
+			Options: options.Index().SetName("idx_parent_id"),
+		},
+	}
+	// Versões anteriores exigiam slug único em toda a árvore
+	if err := dropIndexIfExists(ctx, col, "idx_slug"); err != nil {
+		return fmt.Errorf("erro ao remover índice idx_slug de categorias: %w", err)
+	}
+	if _, err := col.Indexes().CreateMany(ctx, indexes); err != nil {
+		return fmt.Errorf("erro ao criar índices de categorias: %w", err)
+	}
+
+	logger.WithFields(map[string]interface{}{
+		"database":   database,
+		"collection": collection,
+		"indexes":    len(indexes),
+	}).Info("Índices criados com sucesso")
+
+	return nil
+}
+
+// dropIndexIfExists remove o índice pelo nome, ignorando coleção ou índice inexistentes
+func dropIndexIfExists(ctx context.Context, col *mongo.Collection, name string) error {
+	_, err := col.Indexes().DropOne(ctx, name)
+	var cmdErr mongo.CommandError
+	if errors.As(err, &cmdErr) && (cmdErr.Name == "IndexNotFound" || cmdErr.Name == "NamespaceNotFound") {
+		return nil
+	}
+	return err
+}
+
+// CreatePriceHistoryIndexes cria os índices da coleção do histórico de preços
+func CreatePriceHistoryIndexes(ctx context.Context, client *mongo.Client, database, collection string) error {
+	col := client.Database(database).Collection(collection)
+
+	indexes := []mongo.IndexModel{
+		{
+			// Histórico de um produto, do mais recente para o mais antigo, com filtro por período
+			Keys:    bson.D{{Key: "produto_id", Value: 1}, {Key: "changed_at", Value: -1}},
+			Options: options.Index().SetName("idx_produto_changed_at"),
+		},
+	}
+	if _, err := col.Indexes().CreateMany(ctx, indexes); err != nil {
+		return fmt.Errorf("erro ao criar índices do histórico de preços: %w", err)
+	}
+
+	logger.WithFields(map[string]interface{}{
+		"database":   database,
+		"collection": collection,
+		"indexes":    len(indexes),
+	}).Info("Índices criados com sucesso")
+
+	return nil
+}
+
+// CreateAuditIndexes cria os índices da coleção do log de auditoria
+func CreateAuditIndexes(ctx context.Context, client *mongo.Client, database, collection string) error {
+	col := client.Database(database).Collection(collection)
+
+	indexes := []mongo.IndexModel{
+		{
+			// Registros de uma entidade, do mais recente para o mais antigo
+			Keys:    bson.D{{Key: "entity", Value: 1}, {Key: "entity_id", Value: 1}, {Key: "timestamp", Value: -1}},
+			Options: options.Index().SetName("idx_entity_timestamp"),
+		},
+		{
+			Keys:    bson.D{{Key: "actor", Value: 1}, {Key: "timestamp", Value: -1}},
+			Options: options.Index().SetName("idx_actor_timestamp"),
+		},
+	}
+	if _, err := col.Indexes().CreateMany(ctx, indexes); err != nil {
+		return fmt.Errorf("erro ao criar índices do log de auditoria: %w", err)
+	}
+
+	logger.WithFields(map[string]interface{}{
+		"database":   database,
+		"collection": collection,
+		"indexes":    len(indexes),
+	}).Info("Índices criados com sucesso")
+
+	return nil
+}
+
 // HealthCheck verifica a saúde da conexão com o MongoDB
 func HealthCheck(ctx context.Context, client *mongo.Client) error {
 	if client == nil {
@@ -210,4 +349,90 @@ func GetCollection(client *mongo.Client, dbName, collectionName string) (*mongo.
 	}
 
 	return db.Collection(collectionName), nil
-}
\ No newline at end of file
+}
+
+// CreateOutboxIndexes cria os índices da coleção do outbox de eventos
+func CreateOutboxIndexes(ctx context.Context, client *mongo.Client, database, collection string) error {
+	col := client.Database(database).Collection(collection)
+
+	indexes := []mongo.IndexModel{
+		{
+			// Eventos pendentes disponíveis para publicação, dos mais antigos para os mais recentes
+			Keys:    bson.D{{Key: "status", Value: 1}, {Key: "next_attempt_at", Value: 1}, {Key: "occurred_at", Value: 1}},
+			Options: options.Index().SetName("idx_status_next_attempt"),
+		},
+		{
+			// Eventos publicados são removidos após 7 dias (pendentes não têm published_at)
+			Keys:    bson.D{{Key: "published_at", Value: 1}},
+			Options: options.Index().SetName("idx_published_at_ttl").SetExpireAfterSeconds(7 * 24 * 60 * 60),
+		},
+	}
+	if _, err := col.Indexes().CreateMany(ctx, indexes); err != nil {
+		return fmt.Errorf("erro ao criar índices do outbox: %w", err)
+	}
+
+	logger.WithFields(map[string]interface{}{
+		"database":   database,
+		"collection": collection,
+		"indexes":    len(indexes),
+	}).Info("Índices criados com sucesso")
+
+	return nil
+}
+
+// CreateWebhookIndexes cria os índices da coleção de webhooks
+func CreateWebhookIndexes(ctx context.Context, client *mongo.Client, database, collection string) error {
+	col := client.Database(database).Collection(collection)
+
+	indexes := []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "id", Value: 1}},
+			Options: options.Index().SetName("idx_id_unique").SetUnique(true),
+		},
+		{
+			// Webhooks ativos inscritos em um tipo de evento
+			Keys:    bson.D{{Key: "active", Value: 1}, {Key: "events", Value: 1}},
+			Options: options.Index().SetName("idx_active_events"),
+		},
+	}
+	if _, err := col.Indexes().CreateMany(ctx, indexes); err != nil {
+		return fmt.Errorf("erro ao criar índices de webhooks: %w", err)
+	}
+
+	logger.WithFields(map[string]interface{}{
+		"database":   database,
+		"collection": collection,
+		"indexes":    len(indexes),
+	}).Info("Índices criados com sucesso")
+
+	return nil
+}
+
+// CreateWebhookDeliveryIndexes cria os índices da coleção de entregas de webhooks
+func CreateWebhookDeliveryIndexes(ctx context.Context, client *mongo.Client, database, collection string) error {
+	col := client.Database(database).Collection(collection)
+
+	indexes := []mongo.IndexModel{
+		{
+			// Entregas pendentes disponíveis para envio
+			Keys:    bson.D{{Key: "status", Value: 1}, {Key: "next_attempt_at", Value: 1}},
+			Options: options.Index().SetName("idx_status_next_attempt"),
+		},
+		{
+			// Histórico de entregas de um webhook, mais recentes primeiro
+			Keys:    bson.D{{Key: "webhook_id", Value: 1}, {Key: "created_at", Value: -1}},
+			Options: options.Index().SetName("idx_webhook_created_at"),
+		},
+	}
+	if _, err := col.Indexes().CreateMany(ctx, indexes); err != nil {
+		return fmt.Errorf("erro ao criar índices de entregas de webhooks: %w", err)
+	}
+
+	logger.WithFields(map[string]interface{}{
+		"database":   database,
+		"collection": collection,
+		"indexes":    len(indexes),
+	}).Info("Índices criados com sucesso")
+
+	return nil
+}
@@ -2,6 +2,7 @@ package database
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
 
@@ -28,12 +29,37 @@ func DefaultRetryOptions() RetryOptions {
 	}
 }
 
+// Delay retorna o tempo de espera antes da tentativa seguinte à tentativa
+// attempt (a partir de 1), com o mesmo backoff exponencial usado por Retry
+func (o RetryOptions) Delay(attempt int) time.Duration {
+	delay := o.InitialDelay
+	for i := 1; i < attempt && delay < o.MaxDelay; i++ {
+		delay = time.Duration(float64(delay) * o.Multiplier)
+	}
+	if delay > o.MaxDelay {
+		delay = o.MaxDelay
+	}
+	return delay
+}
+
+// Retryable é implementado por erros que informam se a operação pode ser
+// repetida (ex.: falhas transitórias na publicação de eventos)
+type Retryable interface {
+	Retryable() bool
+}
+
 // RetryableError verifica se um erro é retryable
 func RetryableError(err error) bool {
 	if err == nil {
 		return false
 	}
 
+	// Erros que informam se podem ser repetidos
+	var retryable Retryable
+	if errors.As(err, &retryable) {
+		return retryable.Retryable()
+	}
+
 	// Erros de rede são retryable
 	if mongo.IsNetworkError(err) {
 		return true
@@ -55,7 +81,6 @@ func RetryableError(err error) bool {
 // Retry executa uma função com retry logic
 func Retry(ctx context.Context, fn func() error, opts RetryOptions) error {
 	var lastErr error
-	delay := opts.InitialDelay
 
 	for attempt := 1; attempt <= opts.MaxAttempts; attempt++ {
 		err := fn()
@@ -76,6 +101,7 @@ func Retry(ctx context.Context, fn func() error, opts RetryOptions) error {
 		}
 
 		// Log da tentativa
+		delay := opts.Delay(attempt)
 		logger.WithFields(map[string]interface{}{
 			"attempt": attempt,
 			"max_attempts": opts.MaxAttempts,
@@ -89,12 +115,6 @@ func Retry(ctx context.Context, fn func() error, opts RetryOptions) error {
 			return ctx.Err()
 		case <-time.After(delay):
 		}
-
-		// Calcular próximo delay (backoff exponencial)
-		delay = time.Duration(float64(delay) * opts.Multiplier)
-		if delay > opts.MaxDelay {
-			delay = opts.MaxDelay
-		}
 	}
 
 	return fmt.Errorf("max retry attempts (%d) reached: %w", opts.MaxAttempts, lastErr)
@@ -104,7 +124,6 @@ func Retry(ctx context.Context, fn func() error, opts RetryOptions) error {
 func RetryWithResult[T any](ctx context.Context, fn func() (T, error), opts RetryOptions) (T, error) {
 	var zero T
 	var lastErr error
-	delay := opts.InitialDelay
 
 	for attempt := 1; attempt <= opts.MaxAttempts; attempt++ {
 		result, err := fn()
@@ -125,6 +144,7 @@ func RetryWithResult[T any](ctx context.Context, fn func() (T, error), opts Retr
 		}
 
 		// Log da tentativa
+		delay := opts.Delay(attempt)
 		logger.WithFields(map[string]interface{}{
 			"attempt": attempt,
 			"max_attempts": opts.MaxAttempts,
@@ -138,12 +158,6 @@ func RetryWithResult[T any](ctx context.Context, fn func() (T, error), opts Retr
 			return zero, ctx.Err()
 		case <-time.After(delay):
 		}
-
-		// Calcular próximo delay (backoff exponencial)
-		delay = time.Duration(float64(delay) * opts.Multiplier)
-		if delay > opts.MaxDelay {
-			delay = opts.MaxDelay
-		}
 	}
 
 	return zero, fmt.Errorf("max retry attempts (%d) reached: %w", opts.MaxAttempts, lastErr)
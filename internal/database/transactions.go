@@ -4,6 +4,7 @@ import (
 	"context"
 	"time"
 
+	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
 )
 
@@ -29,6 +30,17 @@ func StartTransaction(ctx context.Context, client *mongo.Client) (*TransactionSe
 	}, cancel, nil
 }
 
+// SupportsTransactions indica se o servidor aceita transações: apenas membros de
+// replica set e roteadores de sharded cluster (mongos) aceitam
+func SupportsTransactions(ctx context.Context, client *mongo.Client) (bool, error) {
+	var hello bson.M
+	if err := client.Database("admin").RunCommand(ctx, bson.D{{Key: "hello", Value: 1}}).Decode(&hello); err != nil {
+		return false, err
+	}
+	_, replicaSet := hello["setName"]
+	return replicaSet || hello["msg"] == "isdbgrid", nil
+}
+
 // WithTransaction executa uma função dentro de uma transação
 func (ts *TransactionSession) WithTransaction(fn func(mongo.SessionContext) error) error {
 	return mongo.WithSession(ts.ctx, ts.session, func(sc mongo.SessionContext) error {
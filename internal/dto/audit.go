@@ -0,0 +1,10 @@
+package dto
+
+import "api-go-arquitetura/internal/audit"
+
+// AuditLogResponse representa uma página do log de auditoria
+// @Description Registros de auditoria paginados, do mais recente para o mais antigo
+type AuditLogResponse struct {
+	Entries    []audit.Entry      `json:"entries"`
+	Pagination PaginationResponse `json:"pagination"`
+}
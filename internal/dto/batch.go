@@ -0,0 +1,54 @@
+package dto
+
+import (
+	"encoding/json"
+
+	"api-go-arquitetura/internal/errors"
+)
+
+// Operações aceitas por POST /api/v1/produtos:batch
+const (
+	BatchOpCreate = "create"
+	BatchOpUpdate = "update"
+	BatchOpPatch  = "patch"
+	BatchOpDelete = "delete"
+)
+
+// MaxBatchOperations limita a quantidade de operações por requisição
+const MaxBatchOperations = 1000
+
+// BatchOperationRequest representa uma operação do lote
+// @Description Operação do lote: data segue o formato de criação (create),
+// @Description atualização completa (update) ou parcial (patch); delete não usa data
+type BatchOperationRequest struct {
+	Op      string          `json:"op" example:"create"`
+	ID      int             `json:"id,omitempty" example:"1"`
+	Version *int            `json:"version,omitempty" example:"3"` // Versão esperada, equivalente ao If-Match (0 é válido)
+	Data    json.RawMessage `json:"data,omitempty" swaggertype:"object"`
+}
+
+// BatchRequest representa o corpo de POST /api/v1/produtos:batch
+// @Description Lista de operações aplicadas em lote
+type BatchRequest struct {
+	Operations []BatchOperationRequest `json:"operations"`
+}
+
+// BatchItemResult representa o resultado de uma operação do lote
+// @Description Resultado de uma operação do lote, na mesma ordem da requisição
+type BatchItemResult struct {
+	Index   int              `json:"index" example:"0"`
+	Op      string           `json:"op" example:"create"`
+	ID      int              `json:"id,omitempty" example:"1"`
+	Status  int              `json:"status" example:"201"` // Status HTTP equivalente à operação individual
+	Produto *ProdutoResponse `json:"produto,omitempty"`
+	Error   *errors.APIError `json:"error,omitempty"`
+}
+
+// BatchResponse representa a resposta de POST /api/v1/produtos:batch
+// @Description Resultado por item das operações em lote
+type BatchResponse struct {
+	Atomic    bool              `json:"atomic" example:"false"`
+	Succeeded int               `json:"succeeded" example:"2"`
+	Failed    int               `json:"failed" example:"0"`
+	Results   []BatchItemResult `json:"results"`
+}
@@ -0,0 +1,115 @@
+package dto
+
+import (
+	"time"
+
+	"api-go-arquitetura/internal/model"
+)
+
+// CreateCategoriaRequest representa a requisição para criar uma categoria
+// @Description Dados para criação de uma categoria
+type CreateCategoriaRequest struct {
+	Nome     string `json:"nome" validate:"required,min=1,max=100" example:"Notebooks"`
+	Slug     string `json:"slug,omitempty" validate:"omitempty,max=100" example:"notebooks"` // Gerado a partir do nome quando omitido
+	ParentID int    `json:"parentId,omitempty" validate:"omitempty,gt=0" example:"1"`        // Omitido = categoria raiz
+}
+
+// UpdateCategoriaRequest representa a requisição para atualizar uma categoria
+// Alterar parentId move a categoria (e suas subcategorias) na árvore
+// @Description Dados para atualização de uma categoria
+type UpdateCategoriaRequest struct {
+	Nome     string `json:"nome" validate:"required,min=1,max=100" example:"Notebooks"`
+	Slug     string `json:"slug,omitempty" validate:"omitempty,max=100" example:"notebooks"`
+	ParentID int    `json:"parentId,omitempty" validate:"omitempty,gt=0" example:"1"`
+}
+
+// CategoriaResponse representa a resposta de uma categoria
+// @Description Resposta com dados da categoria
+type CategoriaResponse struct {
+	ID        int       `json:"id" example:"3"`
+	Nome      string    `json:"nome" example:"Notebooks"`
+	Slug      string    `json:"slug" example:"notebooks"`
+	ParentID  int       `json:"parentId,omitempty" example:"1"`
+	Ancestors []int     `json:"ancestors" example:"1"`
+	Path      string    `json:"path" example:"eletronicos/notebooks"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// CategoriaListResponse representa a lista de categorias
+type CategoriaListResponse struct {
+	Categorias []CategoriaResponse `json:"categorias"`
+	Total      int                 `json:"total"`
+}
+
+// CategoriaTreeNode representa uma categoria com suas subcategorias
+type CategoriaTreeNode struct {
+	ID            int                  `json:"id"`
+	Nome          string               `json:"nome"`
+	Slug          string               `json:"slug"`
+	Path          string               `json:"path"`
+	Subcategorias []*CategoriaTreeNode `json:"subcategorias"`
+}
+
+// ToModel converte CreateCategoriaRequest para model.Categoria
+func (r *CreateCategoriaRequest) ToModel() model.Categoria {
+	return model.Categoria{Nome: r.Nome, Slug: r.Slug, ParentID: r.ParentID}
+}
+
+// ToModel converte UpdateCategoriaRequest para model.Categoria
+func (r *UpdateCategoriaRequest) ToModel() model.Categoria {
+	return model.Categoria{Nome: r.Nome, Slug: r.Slug, ParentID: r.ParentID}
+}
+
+// FromCategoria converte model.Categoria para CategoriaResponse
+func FromCategoria(c model.Categoria) CategoriaResponse {
+	ancestors := c.Ancestors
+	if ancestors == nil {
+		ancestors = []int{}
+	}
+	return CategoriaResponse{
+		ID:        c.ID,
+		Nome:      c.Nome,
+		Slug:      c.Slug,
+		ParentID:  c.ParentID,
+		Ancestors: ancestors,
+		Path:      c.Path,
+		CreatedAt: c.CreatedAt,
+		UpdatedAt: c.UpdatedAt,
+	}
+}
+
+// ToCategoriaListResponse converte uma lista de categorias para CategoriaListResponse
+func ToCategoriaListResponse(categorias []model.Categoria) CategoriaListResponse {
+	response := CategoriaListResponse{
+		Categorias: make([]CategoriaResponse, len(categorias)),
+		Total:      len(categorias),
+	}
+	for i, c := range categorias {
+		response.Categorias[i] = FromCategoria(c)
+	}
+	return response
+}
+
+// ToCategoriaTree monta a árvore de categorias a partir da lista ordenada por path
+// (pais antes dos filhos); categorias cujo pai não está na lista viram raízes
+func ToCategoriaTree(categorias []model.Categoria) []*CategoriaTreeNode {
+	roots := []*CategoriaTreeNode{}
+	nodes := make(map[int]*CategoriaTreeNode, len(categorias))
+	for _, c := range categorias {
+		node := &CategoriaTreeNode{
+			ID:            c.ID,
+			Nome:          c.Nome,
+			Slug:          c.Slug,
+			Path:          c.Path,
+			Subcategorias: []*CategoriaTreeNode{},
+		}
+		nodes[c.ID] = node
+		if parent, ok := nodes[c.ParentID]; ok && !c.IsRoot() {
+			parent.Subcategorias = append(parent.Subcategorias, node)
+			continue
+		}
+		roots = append(roots, node)
+	}
+	return roots
+}
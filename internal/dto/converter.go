@@ -8,6 +8,9 @@ func (r *CreateProdutoRequest) ToModel() model.Produto {
 		Nome:      r.Nome,
 		Preco:     r.Preco,
 		Descricao: r.Descricao,
+		Tags:      r.Tags,
+		CategoriaID: r.CategoriaID,
+		Estoque:     r.Estoque,
 	}
 }
 
@@ -17,6 +20,8 @@ func (r *UpdateProdutoRequest) ToModel() model.Produto {
 		Nome:      r.Nome,
 		Preco:     r.Preco,
 		Descricao: r.Descricao,
+		Tags:      r.Tags,
+		CategoriaID: r.CategoriaID,
 	}
 }
 
@@ -33,6 +38,12 @@ func (r *PatchProdutoRequest) ToMap() map[string]interface{} {
 	if r.Descricao != nil {
 		updates["descricao"] = *r.Descricao
 	}
+	if r.Tags != nil {
+		updates["tags"] = r.Tags
+	}
+	if r.CategoriaID != nil {
+		updates["categoria_id"] = *r.CategoriaID
+	}
 	
 	return updates
 }
@@ -44,6 +55,12 @@ func FromModel(p model.Produto) ProdutoResponse {
 		Nome:      p.Nome,
 		Preco:     p.Preco,
 		Descricao: p.Descricao,
+		Tags:      p.Tags,
+		CategoriaID: p.CategoriaID,
+		Estoque:     p.Estoque,
+		Reservado:   p.Reservado,
+		DeletedAt: p.DeletedAt,
+		Version:   p.Version,
 	}
 }
 
@@ -0,0 +1,173 @@
+package dto
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"api-go-arquitetura/internal/model"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// CursorPaginationRequest representa os parâmetros da paginação por cursor (keyset)
+type CursorPaginationRequest struct {
+	Cursor   string `json:"cursor"`   // Cursor opaco retornado em nextCursor (vazio na primeira página)
+	PageSize int    `json:"pageSize"` // Tamanho da página
+}
+
+// Validate aplica os mesmos limites da paginação por página
+func (p *CursorPaginationRequest) Validate() {
+	if p.PageSize < 1 {
+		p.PageSize = 10 // Valor padrão
+	}
+	if p.PageSize > 100 {
+		p.PageSize = 100 // Limite máximo
+	}
+}
+
+// CursorPaginationResponse representa os metadados da paginação por cursor
+type CursorPaginationResponse struct {
+	PageSize   int    `json:"pageSize"`             // Tamanho da página
+	NextCursor string `json:"nextCursor,omitempty"` // Cursor da próxima página
+	HasNext    bool   `json:"hasNext"`              // Tem próxima página
+}
+
+// CursorProdutoListResponse representa uma resposta paginada por cursor
+type CursorProdutoListResponse struct {
+	Produtos   []ProdutoResponse        `json:"produtos"`
+	Pagination CursorPaginationResponse `json:"pagination"`
+	Facets     *model.ProdutoFacets     `json:"facets,omitempty"` // Presente quando solicitado via facets=
+}
+
+// pageCursor é o conteúdo do cursor: a chave de ordenação do último item da página
+// O ID desempata produtos com o mesmo valor no campo ordenado
+type pageCursor struct {
+	Field string          `json:"f"`
+	Order string          `json:"o"`
+	Value json.RawMessage `json:"v,omitempty"`
+	ID    int             `json:"id"`
+}
+
+// keysetField retorna o campo e a ordem efetivos (sem campo, a ordenação é por ID)
+func (s *SortRequest) keysetField() (string, string) {
+	if s.Field == "" {
+		return "id", "asc"
+	}
+	if s.Order == "desc" {
+		return s.Field, "desc"
+	}
+	return s.Field, "asc"
+}
+
+// ToMongoKeysetSort converte a ordenação incluindo o ID como desempate,
+// o que torna a ordem total e estável entre páginas
+func (s *SortRequest) ToMongoKeysetSort() bson.D {
+	field, order := s.keysetField()
+	direction := 1
+	if order == "desc" {
+		direction = -1
+	}
+	if field == "id" {
+		return bson.D{{Key: "id", Value: direction}}
+	}
+	return bson.D{{Key: field, Value: direction}, {Key: "id", Value: direction}}
+}
+
+// EncodeCursor gera o cursor que aponta para a posição logo após o produto informado
+func (s *SortRequest) EncodeCursor(p model.Produto) (string, error) {
+	field, order := s.keysetField()
+	cursor := pageCursor{Field: field, Order: order, ID: p.ID}
+
+	if field != "id" {
+		value, err := json.Marshal(keysetValue(p, field))
+		if err != nil {
+			return "", err
+		}
+		cursor.Value = value
+	}
+
+	data, err := json.Marshal(cursor)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+// KeysetFilter decodifica o cursor e retorna o filtro MongoDB que seleciona os
+// produtos posteriores a ele na ordenação atual. Cursor vazio retorna nil
+func (s *SortRequest) KeysetFilter(encoded string) (bson.M, error) {
+	field, order := s.keysetField()
+	if field == ScoreSortField {
+		return nil, fmt.Errorf("a paginação por cursor não suporta ordenação por score")
+	}
+	if encoded == "" {
+		return nil, nil
+	}
+
+	data, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("cursor inválido")
+	}
+	var cursor pageCursor
+	if err := json.Unmarshal(data, &cursor); err != nil {
+		return nil, fmt.Errorf("cursor inválido")
+	}
+
+	if cursor.Field != field || cursor.Order != order {
+		return nil, fmt.Errorf("cursor gerado para outra ordenação (%s:%s); use a mesma ordenação da primeira página", cursor.Field, cursor.Order)
+	}
+
+	op := "$gt"
+	if order == "desc" {
+		op = "$lt"
+	}
+	if field == "id" {
+		return bson.M{"id": bson.M{op: cursor.ID}}, nil
+	}
+
+	value, err := decodeKeysetValue(field, cursor.Value)
+	if err != nil {
+		return nil, fmt.Errorf("cursor inválido")
+	}
+	return bson.M{"$or": bson.A{
+		bson.M{field: bson.M{op: value}},
+		bson.M{field: value, "id": bson.M{op: cursor.ID}},
+	}}, nil
+}
+
+// keysetValue retorna o valor do campo de ordenação do produto
+func keysetValue(p model.Produto, field string) interface{} {
+	switch field {
+	case "nome":
+		return p.Nome
+	case "preco":
+		return p.Preco
+	case "descricao":
+		return p.Descricao
+	case "created_at":
+		return p.CreatedAt
+	case "updated_at":
+		return p.UpdatedAt
+	}
+	return p.ID
+}
+
+// decodeKeysetValue restaura o tipo original do valor armazenado no cursor
+func decodeKeysetValue(field string, raw json.RawMessage) (interface{}, error) {
+	switch field {
+	case "preco":
+		var v float64
+		err := json.Unmarshal(raw, &v)
+		return v, err
+	case "created_at", "updated_at":
+		var v time.Time
+		err := json.Unmarshal(raw, &v)
+		return v, err
+	default:
+		var v string
+		err := json.Unmarshal(raw, &v)
+		return v, err
+	}
+}
@@ -0,0 +1,121 @@
+package dto
+
+import (
+	"encoding/base64"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"api-go-arquitetura/internal/model"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestSortRequest_CursorRoundTrip(t *testing.T) {
+	createdAt := time.Date(2024, 5, 10, 14, 30, 0, 0, time.UTC)
+	produto := model.Produto{ID: 42, Nome: "Notebook", Preco: 3500.50, CreatedAt: createdAt}
+
+	tests := []struct {
+		name     string
+		sort     SortRequest
+		expected bson.M
+	}{
+		{
+			name:     "sem ordenação usa o ID",
+			sort:     SortRequest{},
+			expected: bson.M{"id": bson.M{"$gt": 42}},
+		},
+		{
+			name:     "ID decrescente",
+			sort:     SortRequest{Field: "id", Order: "desc"},
+			expected: bson.M{"id": bson.M{"$lt": 42}},
+		},
+		{
+			name: "preço crescente desempata pelo ID",
+			sort: SortRequest{Field: "preco", Order: "asc"},
+			expected: bson.M{"$or": bson.A{
+				bson.M{"preco": bson.M{"$gt": 3500.50}},
+				bson.M{"preco": 3500.50, "id": bson.M{"$gt": 42}},
+			}},
+		},
+		{
+			name: "nome decrescente",
+			sort: SortRequest{Field: "nome", Order: "desc"},
+			expected: bson.M{"$or": bson.A{
+				bson.M{"nome": bson.M{"$lt": "Notebook"}},
+				bson.M{"nome": "Notebook", "id": bson.M{"$lt": 42}},
+			}},
+		},
+		{
+			name: "data de criação",
+			sort: SortRequest{Field: "created_at"},
+			expected: bson.M{"$or": bson.A{
+				bson.M{"created_at": bson.M{"$gt": createdAt}},
+				bson.M{"created_at": createdAt, "id": bson.M{"$gt": 42}},
+			}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			encoded, err := tt.sort.EncodeCursor(produto)
+			if err != nil {
+				t.Fatalf("Erro inesperado: %v", err)
+			}
+			filter, err := tt.sort.KeysetFilter(encoded)
+			if err != nil {
+				t.Fatalf("Erro inesperado: %v", err)
+			}
+			if !reflect.DeepEqual(filter, tt.expected) {
+				t.Errorf("Filtro esperado %v, obtido %v", tt.expected, filter)
+			}
+		})
+	}
+
+	t.Run("cursor vazio não filtra", func(t *testing.T) {
+		sort := SortRequest{Field: "preco"}
+		if filter, err := sort.KeysetFilter(""); err != nil || filter != nil {
+			t.Errorf("Esperado filtro nil, obtido %v (%v)", filter, err)
+		}
+	})
+}
+
+func TestSortRequest_KeysetFilterRejectsInvalidCursor(t *testing.T) {
+	sort := SortRequest{Field: "preco", Order: "asc"}
+	valid, err := sort.EncodeCursor(model.Produto{ID: 7, Preco: 99.90})
+	if err != nil {
+		t.Fatalf("Erro inesperado: %v", err)
+	}
+	encode := func(raw string) string { return base64.RawURLEncoding.EncodeToString([]byte(raw)) }
+
+	tests := []struct {
+		name    string
+		sort    SortRequest
+		cursor  string
+		message string
+	}{
+		{"não é base64", sort, "não-é-base64!", "cursor inválido"},
+		{"base64 padrão com preenchimento", sort, base64.StdEncoding.EncodeToString([]byte(`{"f":"preco"}`)), "cursor inválido"},
+		{"truncado", sort, valid[:len(valid)/2], "cursor inválido"},
+		{"JSON inválido", sort, encode(`{"f":"preco","o":"asc","v":`), "cursor inválido"},
+		{"valor adulterado com outro tipo", sort, encode(`{"f":"preco","o":"asc","v":"abc","id":7}`), "cursor inválido"},
+		{"valor ausente", sort, encode(`{"f":"preco","o":"asc","id":7}`), "cursor inválido"},
+		{"outro campo de ordenação", SortRequest{Field: "nome", Order: "asc"}, valid, "outra ordenação"},
+		{"outra direção", SortRequest{Field: "preco", Order: "desc"}, valid, "outra ordenação"},
+		{"ordenação padrão", SortRequest{}, valid, "outra ordenação"},
+		{"ordenação por score", SortRequest{Field: ScoreSortField}, valid, "score"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filter, err := tt.sort.KeysetFilter(tt.cursor)
+			if err == nil {
+				t.Fatalf("Esperado erro, obtido filtro %v", filter)
+			}
+			if !strings.Contains(err.Error(), tt.message) {
+				t.Errorf("Erro deveria conter %q, obtido %q", tt.message, err.Error())
+			}
+		})
+	}
+}
@@ -0,0 +1,74 @@
+package dto
+
+import (
+	"time"
+
+	"api-go-arquitetura/internal/model"
+)
+
+// AdjustStockRequest representa um ajuste de estoque (entrada ou baixa)
+// @Description Ajuste de estoque: delta positivo para entrada, negativo para baixa
+type AdjustStockRequest struct {
+	Delta int `json:"delta" validate:"required" example:"-2"`
+}
+
+// ReserveStockRequest representa a reserva de uma quantidade do estoque
+// @Description Reserva de estoque com validade
+type ReserveStockRequest struct {
+	Quantidade    int    `json:"quantidade" validate:"required,gt=0" example:"2"`
+	ReservationID string `json:"reservationId,omitempty" validate:"omitempty,max=100" example:"pedido-1234"` // Gerado quando omitido
+	TTLSeconds    int    `json:"ttlSeconds,omitempty" validate:"omitempty,gt=0,lte=86400" example:"900"`     // Padrão: 15 minutos
+}
+
+// ReleaseStockRequest representa a liberação de uma reserva
+// @Description Liberação de uma reserva de estoque
+type ReleaseStockRequest struct {
+	ReservationID string `json:"reservationId" validate:"required,max=100" example:"pedido-1234"`
+}
+
+// StockResponse representa a situação do estoque de um produto
+// @Description Estoque, quantidade reservada e disponível
+type StockResponse struct {
+	ProdutoID  int `json:"produtoId" example:"1"`
+	Estoque    int `json:"estoque" example:"10"`
+	Reservado  int `json:"reservado" example:"2"`
+	Disponivel int `json:"disponivel" example:"8"`
+	Version    int `json:"version" example:"4"`
+}
+
+// ReservaResponse representa uma reserva criada
+// @Description Reserva de estoque e a situação do estoque após a reserva
+type ReservaResponse struct {
+	ReservationID string        `json:"reservationId" example:"pedido-1234"`
+	ProdutoID     int           `json:"produtoId" example:"1"`
+	Quantidade    int           `json:"quantidade" example:"2"`
+	ExpiresAt     time.Time     `json:"expiresAt"`
+	Estoque       StockResponse `json:"estoque"`
+}
+
+// TTL retorna a validade pedida para a reserva (0 = padrão do serviço)
+func (r *ReserveStockRequest) TTL() time.Duration {
+	return time.Duration(r.TTLSeconds) * time.Second
+}
+
+// FromStock converte o estoque de model.Produto para StockResponse
+func FromStock(p model.Produto) StockResponse {
+	return StockResponse{
+		ProdutoID:  p.ID,
+		Estoque:    p.Estoque,
+		Reservado:  p.Reservado,
+		Disponivel: p.Disponivel(),
+		Version:    p.Version,
+	}
+}
+
+// FromReserva converte a reserva e o produto para ReservaResponse
+func FromReserva(reserva model.Reserva, p model.Produto) ReservaResponse {
+	return ReservaResponse{
+		ReservationID: reserva.ID,
+		ProdutoID:     p.ID,
+		Quantidade:    reserva.Quantidade,
+		ExpiresAt:     reserva.ExpiresAt,
+		Estoque:       FromStock(p),
+	}
+}
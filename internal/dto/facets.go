@@ -0,0 +1,83 @@
+package dto
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"api-go-arquitetura/internal/model"
+)
+
+// Facetas disponíveis em GET /api/v1/produtos/stats e no parâmetro facets da listagem
+const (
+	FacetStats     = "stats"     // min, max, média e total de preco
+	FacetPreco     = "preco"     // histograma de preço
+	FacetTags      = "tags"      // contagem de produtos por tag
+	FacetCategoria = "categoria" // contagem de produtos por categoria
+)
+
+// Limites do histograma de preço
+const (
+	DefaultPrecoBuckets = 10
+	MaxPrecoBuckets     = 50
+)
+
+// facetNames são as facetas disponíveis, na ordem da documentação
+var facetNames = []string{FacetStats, FacetPreco, FacetTags, FacetCategoria}
+
+// FacetRequest representa as facetas solicitadas
+type FacetRequest struct {
+	Facets  []string `json:"facets"`  // Facetas a calcular (vazio = todas)
+	Buckets int      `json:"buckets"` // Número de faixas do histograma de preço
+}
+
+// ParseFacets extrai a lista de facetas do parâmetro (ex.: "stats,preco")
+func ParseFacets(param string) []string {
+	var facets []string
+	for _, facet := range strings.Split(param, ",") {
+		if facet = strings.ToLower(strings.TrimSpace(facet)); facet != "" {
+			facets = append(facets, facet)
+		}
+	}
+	return facets
+}
+
+// Validate valida as facetas e normaliza a requisição (ordenada e sem repetições),
+// o que mantém a chave de cache estável
+func (f *FacetRequest) Validate() error {
+	if len(f.Facets) == 0 {
+		f.Facets = append([]string{}, facetNames...)
+	}
+
+	seen := make(map[string]bool, len(f.Facets))
+	facets := make([]string, 0, len(f.Facets))
+	for _, facet := range f.Facets {
+		if !containsString(facetNames, facet) {
+			return fmt.Errorf("faceta inválida: %s. Facetas permitidas: %s", facet, strings.Join(facetNames, ", "))
+		}
+		if !seen[facet] {
+			seen[facet] = true
+			facets = append(facets, facet)
+		}
+	}
+	sort.Strings(facets)
+	f.Facets = facets
+
+	if f.Buckets < 1 {
+		f.Buckets = DefaultPrecoBuckets
+	}
+	if f.Buckets > MaxPrecoBuckets {
+		f.Buckets = MaxPrecoBuckets
+	}
+	return nil
+}
+
+// Has verifica se a faceta foi solicitada
+func (f *FacetRequest) Has(facet string) bool {
+	return containsString(f.Facets, facet)
+}
+
+// ProdutoStatsResponse representa a resposta de GET /api/v1/produtos/stats
+type ProdutoStatsResponse struct {
+	Facets model.ProdutoFacets `json:"facets"`
+}
@@ -0,0 +1,107 @@
+package dto
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// produtoFields são os campos de ProdutoResponse que podem ser selecionados
+// com ?fields=, na ordem em que aparecem na resposta
+var produtoFields = []string{"id", "nome", "preco", "descricao", "tags", "categoriaId", "estoque", "reservado", "version"}
+
+// ParseFields valida a lista de campos (ex.: "nome,preco") contra os campos
+// permitidos e a normaliza: ordem da resposta, sem repetições e sempre com o id
+// Retorna nil quando nenhum campo foi informado (resposta completa)
+func ParseFields(param string) ([]string, error) {
+	if strings.TrimSpace(param) == "" {
+		return nil, nil
+	}
+
+	selected := map[string]bool{"id": true}
+	for _, field := range strings.Split(param, ",") {
+		field = strings.TrimSpace(field)
+		if field == "" {
+			continue
+		}
+		if !containsString(produtoFields, field) {
+			return nil, fmt.Errorf("campo inválido em fields: %s. Campos permitidos: %s", field, strings.Join(produtoFields, ", "))
+		}
+		selected[field] = true
+	}
+
+	fields := make([]string, 0, len(selected))
+	for _, field := range produtoFields {
+		if selected[field] {
+			fields = append(fields, field)
+		}
+	}
+	return fields, nil
+}
+
+// projectionFieldNames mapeia os campos da resposta com nome diferente no documento
+var projectionFieldNames = map[string]string{"categoriaId": "categoria_id"}
+
+// ProjectionFields converte os campos da resposta para os campos do documento (projeção)
+func ProjectionFields(fields []string) []string {
+	if len(fields) == 0 {
+		return nil
+	}
+	projection := make([]string, len(fields))
+	for i, field := range fields {
+		projection[i] = field
+		if name, ok := projectionFieldNames[field]; ok {
+			projection[i] = name
+		}
+	}
+	return projection
+}
+
+// WithFields restringe a serialização JSON do produto aos campos informados
+// (nil mantém todos os campos)
+func (p ProdutoResponse) WithFields(fields []string) ProdutoResponse {
+	p.fields = fields
+	return p
+}
+
+// SelectFields aplica WithFields a uma lista de produtos
+func SelectFields(produtos []ProdutoResponse, fields []string) []ProdutoResponse {
+	if len(fields) == 0 {
+		return produtos
+	}
+	for i := range produtos {
+		produtos[i] = produtos[i].WithFields(fields)
+	}
+	return produtos
+}
+
+// MarshalJSON serializa o produto; com WithFields, apenas os campos selecionados
+func (p ProdutoResponse) MarshalJSON() ([]byte, error) {
+	type produtoResponse ProdutoResponse // sem MarshalJSON, evita recursão
+	data, err := json.Marshal(produtoResponse(p))
+	if err != nil || len(p.fields) == 0 {
+		return data, err
+	}
+
+	var all map[string]json.RawMessage
+	if err := json.Unmarshal(data, &all); err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for _, field := range p.fields {
+		value, ok := all[field]
+		if !ok {
+			continue // Campos omitidos (omitempty) continuam omitidos
+		}
+		if buf.Len() > 1 {
+			buf.WriteByte(',')
+		}
+		buf.WriteString(`"` + field + `":`)
+		buf.Write(value)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
@@ -1,31 +1,78 @@
 package dto
 
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
 // FilterRequest representa os filtros de busca
 type FilterRequest struct {
 	Nome      *string  `json:"nome,omitempty"`      // Busca por nome (contém)
 	PrecoMin  *float64 `json:"precoMin,omitempty"`  // Preço mínimo
 	PrecoMax  *float64 `json:"precoMax,omitempty"`  // Preço máximo
 	Descricao *string  `json:"descricao,omitempty"` // Busca por descrição (contém)
+	Q         *string  `json:"q,omitempty"`         // Busca textual em nome e descrição (índice de texto)
+	Filter    *string  `json:"filter,omitempty"`    // Expressão de filtro (campo:operador:valor,...)
+	Categoria *int     `json:"categoria,omitempty"` // ID da categoria
+
+	// Subcategorias inclui os produtos das categorias abaixo de Categoria
+	Subcategorias bool `json:"subcategorias,omitempty"`
+
+	// Condições da expressão de filtro, preenchidas por Validate
+	Conditions []FilterCondition `json:"-"`
+	// Categoria e seus descendentes, preenchidos pelo serviço quando Subcategorias = true
+	CategoriaIDs []int `json:"-"`
+}
+
+// Validate interpreta a expressão de filtro, rejeitando campos e operadores desconhecidos
+func (f *FilterRequest) Validate() error {
+	if f.Categoria != nil && *f.Categoria <= 0 {
+		return fmt.Errorf("categoria deve ser um ID positivo")
+	}
+	if f.Filter == nil {
+		f.Conditions = nil
+		return nil
+	}
+	conditions, err := ParseFilterExpression(*f.Filter)
+	if err != nil {
+		return err
+	}
+	f.Conditions = conditions
+	return nil
+}
+
+// HasText indica se o filtro contém busca textual
+func (f *FilterRequest) HasText() bool {
+	return f.Q != nil && strings.TrimSpace(*f.Q) != ""
 }
 
 // ToMongoFilter converte FilterRequest para filtro MongoDB
+// Nome e descrição são buscas literais: o valor é escapado antes de virar $regex
 func (f *FilterRequest) ToMongoFilter() map[string]interface{} {
 	filter := make(map[string]interface{})
 
 	if f.Nome != nil && *f.Nome != "" {
 		filter["nome"] = map[string]interface{}{
-			"$regex":   *f.Nome,
+			"$regex":   regexp.QuoteMeta(*f.Nome),
 			"$options": "i", // Case insensitive
 		}
 	}
 
 	if f.Descricao != nil && *f.Descricao != "" {
 		filter["descricao"] = map[string]interface{}{
-			"$regex":   *f.Descricao,
+			"$regex":   regexp.QuoteMeta(*f.Descricao),
 			"$options": "i", // Case insensitive
 		}
 	}
 
+	// Busca textual: usa o índice idx_text (stemming em português)
+	if f.HasText() {
+		filter["$text"] = map[string]interface{}{
+			"$search": strings.TrimSpace(*f.Q),
+		}
+	}
+
 	// Filtro de preço
 	precoFilter := make(map[string]interface{})
 	if f.PrecoMin != nil {
@@ -38,6 +85,22 @@ func (f *FilterRequest) ToMongoFilter() map[string]interface{} {
 		filter["preco"] = precoFilter
 	}
 
+	// Filtro por categoria (com as subcategorias, quando resolvidas pelo serviço)
+	if len(f.CategoriaIDs) > 0 {
+		filter["categoria_id"] = map[string]interface{}{"$in": f.CategoriaIDs}
+	} else if f.Categoria != nil {
+		filter["categoria_id"] = *f.Categoria
+	}
+
+	// Condições da expressão de filtro (combinadas com os filtros acima)
+	if len(f.Conditions) > 0 {
+		conditions := make([]interface{}, 0, len(f.Conditions))
+		for _, condition := range f.Conditions {
+			conditions = append(conditions, condition.ToMongoFilter())
+		}
+		filter["$and"] = conditions
+	}
+
 	return filter
 }
 
@@ -46,6 +109,9 @@ func (f *FilterRequest) IsEmpty() bool {
 	return (f.Nome == nil || *f.Nome == "") &&
 		(f.PrecoMin == nil) &&
 		(f.PrecoMax == nil) &&
-		(f.Descricao == nil || *f.Descricao == "")
+		(f.Descricao == nil || *f.Descricao == "") &&
+		!f.HasText() &&
+		(f.Categoria == nil) &&
+		(f.Filter == nil || strings.TrimSpace(*f.Filter) == "")
 }
 
@@ -0,0 +1,280 @@
+package dto
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// Operadores da expressão de filtro (?filter=campo:operador:valor,...)
+const (
+	FilterOpEq         = "eq"
+	FilterOpNe         = "ne"
+	FilterOpGt         = "gt"
+	FilterOpGte        = "gte"
+	FilterOpLt         = "lt"
+	FilterOpLte        = "lte"
+	FilterOpBetween    = "between"
+	FilterOpIn         = "in"
+	FilterOpContains   = "contains"
+	FilterOpStartsWith = "startswith"
+	FilterOpEndsWith   = "endswith"
+)
+
+// MaxFilterConditions limita o número de condições de uma expressão de filtro
+const MaxFilterConditions = 20
+
+// filterFieldKind é o tipo do valor de um campo filtrável
+type filterFieldKind int
+
+const (
+	filterKindString filterFieldKind = iota
+	filterKindInt
+	filterKindFloat
+	filterKindTime
+)
+
+// filterField descreve um campo filtrável e os operadores que ele aceita
+type filterField struct {
+	kind      filterFieldKind
+	operators []string
+}
+
+var (
+	stringFilterOperators = []string{FilterOpEq, FilterOpNe, FilterOpIn, FilterOpContains, FilterOpStartsWith, FilterOpEndsWith}
+	numberFilterOperators = []string{FilterOpEq, FilterOpNe, FilterOpGt, FilterOpGte, FilterOpLt, FilterOpLte, FilterOpBetween, FilterOpIn}
+	timeFilterOperators   = []string{FilterOpEq, FilterOpGt, FilterOpGte, FilterOpLt, FilterOpLte, FilterOpBetween}
+	// Em campos de lista, eq/ne/in verificam se algum elemento corresponde
+	listFilterOperators = []string{FilterOpEq, FilterOpNe, FilterOpIn}
+)
+
+// filterFields são os campos aceitos na expressão de filtro
+var filterFields = map[string]filterField{
+	"id":           {kind: filterKindInt, operators: numberFilterOperators},
+	"nome":         {kind: filterKindString, operators: stringFilterOperators},
+	"descricao":    {kind: filterKindString, operators: stringFilterOperators},
+	"preco":        {kind: filterKindFloat, operators: numberFilterOperators},
+	"version":      {kind: filterKindInt, operators: numberFilterOperators},
+	"tags":         {kind: filterKindString, operators: listFilterOperators},
+	"categoria_id": {kind: filterKindInt, operators: numberFilterOperators},
+	"created_at":   {kind: filterKindTime, operators: timeFilterOperators},
+	"updated_at":   {kind: filterKindTime, operators: timeFilterOperators},
+}
+
+// FilterCondition é uma condição da expressão de filtro, já validada
+type FilterCondition struct {
+	Field    string
+	Operator string
+	Values   []interface{}
+	// dateOnly indica, para cada valor, se ele era uma data sem horário: nesse
+	// caso a condição se refere ao dia inteiro (ver ToMongoFilter)
+	dateOnly []bool
+}
+
+// ParseFilterExpression interpreta uma expressão no formato
+// campo:operador:valor[,campo:operador:valor...]
+// Os operadores between e in recebem os valores separados por "|"
+// (ex.: preco:between:100|500). Use "\" para escapar "," e "|" nos valores
+func ParseFilterExpression(expression string) ([]FilterCondition, error) {
+	if strings.TrimSpace(expression) == "" {
+		return nil, nil
+	}
+
+	parts := splitUnescaped(expression, ',')
+	if len(parts) > MaxFilterConditions {
+		return nil, fmt.Errorf("a expressão de filtro aceita no máximo %d condições", MaxFilterConditions)
+	}
+
+	conditions := make([]FilterCondition, 0, len(parts))
+	for _, part := range parts {
+		condition, err := parseFilterCondition(part)
+		if err != nil {
+			return nil, err
+		}
+		conditions = append(conditions, condition)
+	}
+	return conditions, nil
+}
+
+// parseFilterCondition interpreta e valida uma única condição
+func parseFilterCondition(expression string) (FilterCondition, error) {
+	// O valor pode conter ":" (ex.: horários), por isso são no máximo 3 partes
+	parts := strings.SplitN(strings.TrimSpace(expression), ":", 3)
+	if len(parts) != 3 {
+		return FilterCondition{}, fmt.Errorf("condição de filtro inválida: %q (formato campo:operador:valor)", expression)
+	}
+	name, operator, raw := strings.TrimSpace(parts[0]), strings.ToLower(strings.TrimSpace(parts[1])), parts[2]
+
+	field, ok := filterFields[name]
+	if !ok {
+		return FilterCondition{}, fmt.Errorf("campo de filtro inválido: %s. Campos permitidos: %s", name, strings.Join(filterFieldNames(), ", "))
+	}
+	if !containsString(field.operators, operator) {
+		return FilterCondition{}, fmt.Errorf("operador inválido para %s: %s. Operadores permitidos: %s", name, operator, strings.Join(field.operators, ", "))
+	}
+
+	rawValues := []string{raw}
+	if operator == FilterOpBetween || operator == FilterOpIn {
+		rawValues = splitUnescaped(raw, '|')
+	}
+	if operator == FilterOpBetween && len(rawValues) != 2 {
+		return FilterCondition{}, fmt.Errorf("o operador between exige dois valores (ex.: %s:between:min|max)", name)
+	}
+
+	values := make([]interface{}, 0, len(rawValues))
+	dateOnly := make([]bool, 0, len(rawValues))
+	for _, rawValue := range rawValues {
+		value, isDateOnly, err := parseFilterValue(field.kind, unescapeFilterValue(rawValue))
+		if err != nil {
+			return FilterCondition{}, fmt.Errorf("valor inválido para %s: %v", name, err)
+		}
+		values = append(values, value)
+		dateOnly = append(dateOnly, isDateOnly)
+	}
+
+	return FilterCondition{Field: name, Operator: operator, Values: values, dateOnly: dateOnly}, nil
+}
+
+// parseFilterValue converte o valor para o tipo do campo
+// dateOnly indica uma data sem horário (apenas em campos de data)
+func parseFilterValue(kind filterFieldKind, raw string) (value interface{}, dateOnly bool, err error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return nil, false, fmt.Errorf("valor vazio")
+	}
+
+	switch kind {
+	case filterKindInt:
+		v, err := strconv.Atoi(raw)
+		if err != nil {
+			return nil, false, fmt.Errorf("%q não é um número inteiro", raw)
+		}
+		return v, false, nil
+	case filterKindFloat:
+		v, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return nil, false, fmt.Errorf("%q não é um número", raw)
+		}
+		return v, false, nil
+	case filterKindTime:
+		v, dateOnly, err := parseDate(raw)
+		if err != nil {
+			return nil, false, err
+		}
+		return v, dateOnly, nil
+	}
+	return raw, false, nil
+}
+
+// parseDate interpreta uma data em RFC 3339 ou YYYY-MM-DD
+// dateOnly indica que o valor não tinha horário
+func parseDate(raw string) (t time.Time, dateOnly bool, err error) {
+	if t, err := time.Parse(time.RFC3339, raw); err == nil {
+		return t, false, nil
+	}
+	t, err = time.Parse("2006-01-02", raw)
+	if err != nil {
+		return time.Time{}, false, fmt.Errorf("%q não é uma data (use YYYY-MM-DD ou RFC 3339)", raw)
+	}
+	return t, true, nil
+}
+
+// ToMongoFilter converte a condição para o filtro MongoDB
+// Os operadores de texto escapam o valor: ele nunca é interpretado como regex
+// Uma data sem horário se refere ao dia inteiro: eq cobre [dia, dia+1), gt e
+// lte comparam com o início do dia seguinte e o limite superior de between
+// inclui o dia informado
+func (c FilterCondition) ToMongoFilter() bson.M {
+	switch c.Operator {
+	case FilterOpEq:
+		if next, ok := c.nextDay(0); ok {
+			return bson.M{c.Field: bson.M{"$gte": c.Values[0], "$lt": next}}
+		}
+		return bson.M{c.Field: c.Values[0]}
+	case FilterOpGt:
+		if next, ok := c.nextDay(0); ok {
+			return bson.M{c.Field: bson.M{"$gte": next}}
+		}
+	case FilterOpLte:
+		if next, ok := c.nextDay(0); ok {
+			return bson.M{c.Field: bson.M{"$lt": next}}
+		}
+	case FilterOpBetween:
+		if next, ok := c.nextDay(1); ok {
+			return bson.M{c.Field: bson.M{"$gte": c.Values[0], "$lt": next}}
+		}
+		return bson.M{c.Field: bson.M{"$gte": c.Values[0], "$lte": c.Values[1]}}
+	case FilterOpIn:
+		return bson.M{c.Field: bson.M{"$in": c.Values}}
+	case FilterOpContains, FilterOpStartsWith, FilterOpEndsWith:
+		pattern := regexp.QuoteMeta(c.Values[0].(string))
+		switch c.Operator {
+		case FilterOpStartsWith:
+			pattern = "^" + pattern
+		case FilterOpEndsWith:
+			pattern = pattern + "$"
+		}
+		return bson.M{c.Field: bson.M{"$regex": pattern, "$options": "i"}}
+	}
+	// ne, gt, gte, lt, lte têm o mesmo nome no MongoDB
+	return bson.M{c.Field: bson.M{"$" + c.Operator: c.Values[0]}}
+}
+
+// nextDay retorna o início do dia seguinte ao valor i, quando ele é uma data sem horário
+func (c FilterCondition) nextDay(i int) (time.Time, bool) {
+	if i >= len(c.dateOnly) || !c.dateOnly[i] {
+		return time.Time{}, false
+	}
+	return c.Values[i].(time.Time).AddDate(0, 0, 1), true
+}
+
+// splitUnescaped divide s em sep, ignorando separadores precedidos por "\"
+// (os escapes são mantidos para o próximo nível de divisão)
+func splitUnescaped(s string, sep byte) []string {
+	var parts []string
+	start := 0
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case sep:
+			parts = append(parts, s[start:i])
+			start = i + 1
+		}
+	}
+	return append(parts, s[start:])
+}
+
+// unescapeFilterValue remove os escapes ("\," vira ",")
+func unescapeFilterValue(s string) string {
+	if !strings.Contains(s, `\`) {
+		return s
+	}
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\\' && i+1 < len(s) {
+			i++
+		}
+		b.WriteByte(s[i])
+	}
+	return b.String()
+}
+
+// filterFieldNames retorna os campos filtráveis na ordem da documentação
+func filterFieldNames() []string {
+	return []string{"id", "nome", "descricao", "preco", "version", "tags", "categoria_id", "created_at", "updated_at"}
+}
+
+// containsString verifica se value está em values
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,156 @@
+package dto
+
+import (
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestParseFilterExpression_Operators(t *testing.T) {
+	date := time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC)
+	nextDay := time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	noon := time.Date(2024, 1, 31, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		expression string
+		expected   bson.M
+	}{
+		{"preco:eq:10", bson.M{"preco": 10.0}},
+		{"preco:ne:10", bson.M{"preco": bson.M{"$ne": 10.0}}},
+		{"preco:gt:10.5", bson.M{"preco": bson.M{"$gt": 10.5}}},
+		{"id:gte:3", bson.M{"id": bson.M{"$gte": 3}}},
+		{"version:lt:2", bson.M{"version": bson.M{"$lt": 2}}},
+		{"categoria_id:lte:7", bson.M{"categoria_id": bson.M{"$lte": 7}}},
+		{"preco:between:100|500", bson.M{"preco": bson.M{"$gte": 100.0, "$lte": 500.0}}},
+		{"id:in:1|2|3", bson.M{"id": bson.M{"$in": []interface{}{1, 2, 3}}}},
+		{"nome:eq:Notebook", bson.M{"nome": "Notebook"}},
+		{"tags:in:promo|novo", bson.M{"tags": bson.M{"$in": []interface{}{"promo", "novo"}}}},
+		{"nome:contains:book", bson.M{"nome": bson.M{"$regex": "book", "$options": "i"}}},
+		{"nome:startswith:Note", bson.M{"nome": bson.M{"$regex": "^Note", "$options": "i"}}},
+		{"descricao:endswith:fim", bson.M{"descricao": bson.M{"$regex": "fim$", "$options": "i"}}},
+		{"created_at:gte:2024-01-31", bson.M{"created_at": bson.M{"$gte": date}}},
+		{"updated_at:lt:2024-01-31T00:00:00Z", bson.M{"updated_at": bson.M{"$lt": date}}},
+		// Datas sem horário se referem ao dia inteiro
+		{"created_at:eq:2024-01-31", bson.M{"created_at": bson.M{"$gte": date, "$lt": nextDay}}},
+		{"created_at:gt:2024-01-31", bson.M{"created_at": bson.M{"$gte": nextDay}}},
+		{"created_at:lt:2024-01-31", bson.M{"created_at": bson.M{"$lt": date}}},
+		{"created_at:lte:2024-01-31", bson.M{"created_at": bson.M{"$lt": nextDay}}},
+		{"created_at:between:2024-01-01|2024-01-31", bson.M{"created_at": bson.M{"$gte": start, "$lt": nextDay}}},
+		{"created_at:between:2024-01-01|2024-01-31T12:00:00Z", bson.M{"created_at": bson.M{"$gte": start, "$lte": noon}}},
+		{"updated_at:lte:2024-01-31T12:00:00Z", bson.M{"updated_at": bson.M{"$lte": noon}}},
+		{"updated_at:eq:2024-01-31T12:00:00Z", bson.M{"updated_at": noon}},
+		{" preco : GTE : 10 ", bson.M{"preco": bson.M{"$gte": 10.0}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expression, func(t *testing.T) {
+			conditions, err := ParseFilterExpression(tt.expression)
+			if err != nil {
+				t.Fatalf("Erro inesperado: %v", err)
+			}
+			if len(conditions) != 1 {
+				t.Fatalf("Esperada 1 condição, obtidas %d", len(conditions))
+			}
+			if filter := conditions[0].ToMongoFilter(); !reflect.DeepEqual(filter, tt.expected) {
+				t.Errorf("Filtro esperado %v, obtido %v", tt.expected, filter)
+			}
+		})
+	}
+
+	t.Run("várias condições e expressão vazia", func(t *testing.T) {
+		conditions, err := ParseFilterExpression("preco:gte:100,nome:startswith:Note")
+		if err != nil || len(conditions) != 2 {
+			t.Fatalf("Esperadas 2 condições, obtidas %v (%v)", conditions, err)
+		}
+		if conditions, err := ParseFilterExpression("  "); err != nil || conditions != nil {
+			t.Errorf("Expressão vazia deveria retornar nil, obtido %v (%v)", conditions, err)
+		}
+	})
+}
+
+func TestParseFilterExpression_Malformed(t *testing.T) {
+	tooMany := strings.TrimSuffix(strings.Repeat("id:gt:1,", MaxFilterConditions+1), ",")
+
+	tests := []struct {
+		name       string
+		expression string
+		message    string
+	}{
+		{"sem operador", "preco", "formato campo:operador:valor"},
+		{"sem valor", "preco:gt", "formato campo:operador:valor"},
+		{"condição vazia", "preco:gt:1,", "formato campo:operador:valor"},
+		{"campo desconhecido", "estoque:gt:1", "campo de filtro inválido"},
+		{"operador desconhecido", "preco:like:1", "operador inválido"},
+		{"operador não aceito pelo campo", "nome:gt:a", "operador inválido"},
+		{"operador de texto em número", "preco:contains:1", "operador inválido"},
+		{"valor vazio", "nome:eq: ", "valor vazio"},
+		{"número inválido", "preco:gt:abc", "não é um número"},
+		{"inteiro inválido", "id:eq:1.5", "não é um número inteiro"},
+		{"data inválida", "created_at:gt:31/01/2024", "não é uma data"},
+		{"between com um valor", "preco:between:100", "dois valores"},
+		{"between com três valores", "preco:between:1|2|3", "dois valores"},
+		{"in com valor vazio", "id:in:1||3", "valor vazio"},
+		{"condições demais", tooMany, "no máximo"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			conditions, err := ParseFilterExpression(tt.expression)
+			if err == nil {
+				t.Fatalf("Esperado erro, obtidas condições %v", conditions)
+			}
+			if !strings.Contains(err.Error(), tt.message) {
+				t.Errorf("Erro deveria conter %q, obtido %q", tt.message, err.Error())
+			}
+		})
+	}
+}
+
+func TestParseFilterExpression_RegexMetacharacters(t *testing.T) {
+	tests := []struct {
+		name       string
+		expression string
+		value      string // Valor que deve ser encontrado literalmente
+		pattern    string
+	}{
+		{"ponto e asterisco", "nome:contains:a.*b", "xa.*by", `a\.\*b`},
+		{"âncoras e grupos", "nome:startswith:^(x)$", "^(x)$ fim", `^\^\(x\)\$`},
+		{"colchetes e barra", `nome:endswith:[0-9]\\d`, `item [0-9]\d`, `\[0-9\]\\d$`},
+		{"interrogação e mais", "descricao:contains:c++?", "linguagem c++?", `c\+\+\?`},
+		{"vírgula e barra vertical escapadas", `nome:contains:a\,b\|c`, "a,b|c", `a,b\|c`},
+		{"dois-pontos no valor", "nome:contains:10:30", "às 10:30", `10:30`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			conditions, err := ParseFilterExpression(tt.expression)
+			if err != nil {
+				t.Fatalf("Erro inesperado: %v", err)
+			}
+			filter := conditions[0].ToMongoFilter()
+			regex := filter[conditions[0].Field].(bson.M)["$regex"].(string)
+			if regex != tt.pattern {
+				t.Errorf("Regex esperada %q, obtida %q", tt.pattern, regex)
+			}
+			// O valor escapado só corresponde ao texto literal
+			if !regexp.MustCompile(regex).MatchString(tt.value) {
+				t.Errorf("Regex %q deveria corresponder a %q", regex, tt.value)
+			}
+		})
+	}
+
+	t.Run("in mantém os valores literais", func(t *testing.T) {
+		conditions, err := ParseFilterExpression(`tags:in:a.*|b\|c`)
+		if err != nil {
+			t.Fatalf("Erro inesperado: %v", err)
+		}
+		if expected := []interface{}{"a.*", "b|c"}; !reflect.DeepEqual(conditions[0].Values, expected) {
+			t.Errorf("Valores esperados %v, obtidos %v", expected, conditions[0].Values)
+		}
+	})
+}
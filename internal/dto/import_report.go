@@ -0,0 +1,42 @@
+package dto
+
+import "api-go-arquitetura/internal/errors"
+
+// Formatos aceitos por POST /api/v1/produtos/import
+const (
+	ImportFormatCSV    = "csv"
+	ImportFormatNDJSON = "ndjson"
+)
+
+// MaxImportErrors limita a quantidade de erros detalhados no relatório
+// (os totais continuam considerando todas as linhas)
+const MaxImportErrors = 1000
+
+// ImportRowError representa o erro de uma linha da importação
+// @Description Erro de uma linha do arquivo importado
+type ImportRowError struct {
+	Row   int              `json:"row" example:"3"` // Linha do arquivo (no CSV, o cabeçalho é a linha 1)
+	Error *errors.APIError `json:"error"`
+}
+
+// ImportReport representa o relatório de POST /api/v1/produtos/import
+// @Description Relatório da importação de produtos
+type ImportReport struct {
+	Format          string           `json:"format" example:"csv"`
+	DryRun          bool             `json:"dryRun" example:"false"`
+	Total           int              `json:"total" example:"120"`    // Linhas de dados lidas
+	Accepted        int              `json:"accepted" example:"118"` // Linhas válidas (inseridas, se dryRun=false)
+	Rejected        int              `json:"rejected" example:"2"`
+	Errors          []ImportRowError `json:"errors"`
+	ErrorsTruncated bool             `json:"errorsTruncated,omitempty"`
+}
+
+// AddError registra o erro de uma linha respeitando o limite de erros detalhados
+func (r *ImportReport) AddError(row int, err *errors.APIError) {
+	r.Rejected++
+	if len(r.Errors) >= MaxImportErrors {
+		r.ErrorsTruncated = true
+		return
+	}
+	r.Errors = append(r.Errors, ImportRowError{Row: row, Error: err})
+}
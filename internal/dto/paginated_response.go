@@ -1,9 +1,12 @@
 package dto
 
+import "api-go-arquitetura/internal/model"
+
 // PaginatedProdutoListResponse representa uma resposta paginada de produtos
 type PaginatedProdutoListResponse struct {
 	Produtos   []ProdutoResponse  `json:"produtos"`
 	Pagination PaginationResponse `json:"pagination"`
+	Facets     *model.ProdutoFacets `json:"facets,omitempty"` // Presente quando solicitado via facets=
 }
 
 // ToPaginatedResponse converte lista de produtos com paginação
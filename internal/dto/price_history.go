@@ -0,0 +1,99 @@
+package dto
+
+import (
+	"fmt"
+	"time"
+
+	"api-go-arquitetura/internal/model"
+)
+
+// PriceHistoryFilter restringe o histórico de preços a um período (inclusivo)
+type PriceHistoryFilter struct {
+	From *time.Time `json:"from,omitempty"`
+	To   *time.Time `json:"to,omitempty"`
+}
+
+// ParsePriceHistoryFilter interpreta from e to (YYYY-MM-DD ou RFC 3339; vazio = sem limite)
+// Uma data sem horário em to inclui o dia inteiro
+func ParsePriceHistoryFilter(from, to string) (PriceHistoryFilter, error) {
+	var filter PriceHistoryFilter
+	if from != "" {
+		t, _, err := parseDate(from)
+		if err != nil {
+			return PriceHistoryFilter{}, fmt.Errorf("from: %w", err)
+		}
+		filter.From = &t
+	}
+	if to != "" {
+		t, dateOnly, err := parseDate(to)
+		if err != nil {
+			return PriceHistoryFilter{}, fmt.Errorf("to: %w", err)
+		}
+		if dateOnly {
+			t = t.Add(24*time.Hour - time.Millisecond)
+		}
+		filter.To = &t
+	}
+	return filter, nil
+}
+
+// Validate rejeita períodos em que from é posterior a to
+func (f PriceHistoryFilter) Validate() error {
+	if f.From != nil && f.To != nil && f.From.After(*f.To) {
+		return fmt.Errorf("from deve ser anterior a to")
+	}
+	return nil
+}
+
+// ToMongoFilter converte o período para filtro MongoDB sobre changed_at
+func (f PriceHistoryFilter) ToMongoFilter() map[string]interface{} {
+	filter := make(map[string]interface{})
+	changedAt := make(map[string]interface{})
+	if f.From != nil {
+		changedAt["$gte"] = *f.From
+	}
+	if f.To != nil {
+		changedAt["$lte"] = *f.To
+	}
+	if len(changedAt) > 0 {
+		filter["changed_at"] = changedAt
+	}
+	return filter
+}
+
+// PriceChangeResponse representa uma alteração de preço
+// @Description Alteração de preço de um produto
+type PriceChangeResponse struct {
+	PrecoAnterior float64   `json:"precoAnterior" example:"3500.00"`
+	PrecoNovo     float64   `json:"precoNovo" example:"3299.90"`
+	Version       int       `json:"version" example:"5"` // Versão do produto após a alteração
+	RequestID     string    `json:"requestId,omitempty" example:"550e8400-e29b-41d4-a716-446655440000"`
+	ChangedAt     time.Time `json:"changedAt"`
+}
+
+// PriceHistoryResponse representa uma página do histórico de preços
+// @Description Histórico de preços paginado, da alteração mais recente para a mais antiga
+type PriceHistoryResponse struct {
+	ProdutoID  int                   `json:"produtoId" example:"1"`
+	Historico  []PriceChangeResponse `json:"historico"`
+	Pagination PaginationResponse    `json:"pagination"`
+}
+
+// ToPriceHistoryResponse converte as alterações de preço com paginação
+func ToPriceHistoryResponse(produtoID int, changes []model.PriceChange, pagination PaginationResponse) PriceHistoryResponse {
+	historico := make([]PriceChangeResponse, len(changes))
+	for i, c := range changes {
+		historico[i] = PriceChangeResponse{
+			PrecoAnterior: c.PrecoAnterior,
+			PrecoNovo:     c.PrecoNovo,
+			Version:       c.Version,
+			RequestID:     c.RequestID,
+			ChangedAt:     c.ChangedAt,
+		}
+	}
+	return PriceHistoryResponse{
+		ProdutoID:  produtoID,
+		Historico:  historico,
+		Pagination: pagination,
+	}
+}
@@ -1,12 +1,22 @@
 package dto
 
+import "time"
+
 // ProdutoResponse representa a resposta de um produto
 // @Description Resposta com dados do produto
 type ProdutoResponse struct {
-	ID        int     `json:"id" example:"1"`
-	Nome      string  `json:"nome" example:"Notebook"`
-	Preco     float64 `json:"preco" example:"3500.00"`
-	Descricao string  `json:"descricao" example:"Notebook de alta performance"`
+	ID          int        `json:"id" example:"1"`
+	Nome        string     `json:"nome" example:"Notebook"`
+	Preco       float64    `json:"preco" example:"3500.00"`
+	Descricao   string     `json:"descricao" example:"Notebook de alta performance"`
+	Tags        []string   `json:"tags,omitempty" example:"informatica,promocao"`
+	CategoriaID int        `json:"categoriaId,omitempty" example:"3"`
+	Estoque     int        `json:"estoque" example:"10"`
+	Reservado   int        `json:"reservado" example:"2"`
+	DeletedAt   *time.Time `json:"deleted_at,omitempty"` // Preenchido apenas para produtos na lixeira
+	Version     int        `json:"version" example:"1"`
+
+	fields []string // Campos selecionados via WithFields (vazio = todos)
 }
 
 // ProdutoListResponse representa uma lista de produtos
@@ -15,4 +25,3 @@ type ProdutoListResponse struct {
 	Produtos []ProdutoResponse `json:"produtos"`
 	Total    int               `json:"total"`
 }
-
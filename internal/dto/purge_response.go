@@ -0,0 +1,11 @@
+package dto
+
+import "time"
+
+// PurgeResponse representa o resultado de uma limpeza da lixeira
+// @Description Resultado da remoção definitiva de produtos da lixeira
+type PurgeResponse struct {
+	Purged    int64     `json:"purged" example:"12"`
+	Retention string    `json:"retention" example:"720h0m0s"`
+	Cutoff    time.Time `json:"cutoff"`
+}
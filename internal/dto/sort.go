@@ -10,27 +10,62 @@ import (
 // SortRequest representa os parâmetros de ordenação
 type SortRequest struct {
 	Field string `json:"field" query:"sort" example:"preco"` // Campo para ordenar (ex: "preco", "nome", "created_at")
-	Order string `json:"order" query:"order" example:"asc"`   // Ordem: "asc" ou "desc" (padrão: "asc")
+	Order string `json:"order" query:"order" example:"asc"`  // Ordem: "asc" ou "desc" (padrão: "asc")
 }
 
+// ScoreSortField ordena pela relevância da busca textual (exige o parâmetro q)
+const ScoreSortField = "score"
+
+// sortFields são os campos do documento permitidos para ordenação
+var sortFields = []string{"id", "nome", "preco", "descricao", "created_at", "updated_at"}
+
+// listSortFields são os campos permitidos para ordenação da listagem de produtos
+var listSortFields = append(append([]string{}, sortFields...), ScoreSortField)
+
+// trashSortFields são os campos permitidos para ordenação da lixeira
+var trashSortFields = append(append([]string{}, sortFields...), "deleted_at")
+
 // Validate valida os parâmetros de ordenação
 func (s *SortRequest) Validate() error {
+	return s.validate(listSortFields)
+}
+
+// ValidateForFilter valida a ordenação considerando o filtro: score só é
+// permitido com busca textual e, sem ordenação explícita, a busca textual
+// ordena por relevância
+func (s *SortRequest) ValidateForFilter(filter FilterRequest) error {
+	if err := s.Validate(); err != nil {
+		return err
+	}
+	if s.Field == ScoreSortField && !filter.HasText() {
+		return fmt.Errorf("ordenação por score exige o parâmetro q")
+	}
+	if s.Field == "" && filter.HasText() {
+		s.Field = ScoreSortField
+	}
+	return nil
+}
+
+// ValidateTrash valida os parâmetros de ordenação da lixeira (aceita também deleted_at)
+func (s *SortRequest) ValidateTrash() error {
+	return s.validate(trashSortFields)
+}
+
+// validate valida o campo de ordenação contra a lista de campos permitidos
+func (s *SortRequest) validate(allowedFields []string) error {
 	if s.Field == "" {
 		return nil // Sem ordenação
 	}
 
-	// Campos permitidos para ordenação
-	allowedFields := map[string]bool{
-		"id":         true,
-		"nome":       true,
-		"preco":      true,
-		"descricao":  true,
-		"created_at": true,
-		"updated_at": true,
+	allowed := false
+	for _, field := range allowedFields {
+		if field == s.Field {
+			allowed = true
+			break
+		}
 	}
-
-	if !allowedFields[s.Field] {
-		return fmt.Errorf("campo de ordenação inválido: %s. Campos permitidos: id, nome, preco, descricao, created_at, updated_at", s.Field)
+	if !allowed {
+		return fmt.Errorf("campo de ordenação inválido: %s. Campos permitidos: %s", s.Field, strings.Join(allowedFields, ", "))
 	}
 
 	// Normalizar ordem
@@ -49,6 +84,11 @@ func (s *SortRequest) ToMongoSort() bson.D {
 		return bson.D{{Key: "id", Value: 1}}
 	}
 
+	// A relevância é sempre decrescente; o ID desempata resultados com o mesmo score
+	if s.Field == ScoreSortField {
+		return bson.D{{Key: ScoreSortField, Value: bson.M{"$meta": "textScore"}}, {Key: "id", Value: 1}}
+	}
+
 	order := 1 // asc
 	if s.Order == "desc" {
 		order = -1
@@ -81,4 +121,3 @@ func GetSortFromQuery(sortParam, orderParam string) SortRequest {
 
 	return sort
 }
-
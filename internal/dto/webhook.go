@@ -0,0 +1,76 @@
+package dto
+
+import (
+	"time"
+
+	"api-go-arquitetura/internal/model"
+)
+
+// WebhookRequest representa a requisição para criar ou atualizar um webhook
+// @Description Dados do webhook. events aceita os tipos de evento de domínio ou "*" (todos)
+type WebhookRequest struct {
+	URL    string   `json:"url" validate:"required,http_url,max=2048" example:"https://parceiro.com/webhooks/catalogo"`
+	Events []string `json:"events" validate:"required,min=1,dive,oneof=ProdutoCriado ProdutoAtualizado ProdutoRemovido ProdutoRestaurado PrecoAlterado *" example:"ProdutoCriado,PrecoAlterado"`
+	Secret string   `json:"secret,omitempty" validate:"omitempty,min=16,max=256"` // Gerado quando omitido na criação; mantido quando omitido na atualização
+	Active *bool    `json:"active,omitempty" example:"true"`                      // Padrão: true
+}
+
+// WebhookResponse representa a resposta de um webhook
+// O secret só é retornado na criação
+// @Description Resposta com dados do webhook
+type WebhookResponse struct {
+	ID        int       `json:"id" example:"1"`
+	URL       string    `json:"url" example:"https://parceiro.com/webhooks/catalogo"`
+	Events    []string  `json:"events" example:"ProdutoCriado,PrecoAlterado"`
+	Active    bool      `json:"active" example:"true"`
+	Secret    string    `json:"secret,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// WebhookListResponse representa a lista de webhooks
+type WebhookListResponse struct {
+	Webhooks []WebhookResponse `json:"webhooks"`
+	Total    int               `json:"total"`
+}
+
+// WebhookDeliveryListResponse representa uma página das entregas de um webhook
+// @Description Entregas do webhook, mais recentes primeiro, com o histórico das tentativas
+type WebhookDeliveryListResponse struct {
+	WebhookID  int                     `json:"webhookId"`
+	Deliveries []model.WebhookDelivery `json:"deliveries"`
+	Pagination PaginationResponse      `json:"pagination"`
+}
+
+// ToModel converte WebhookRequest para model.Webhook
+func (r *WebhookRequest) ToModel() model.Webhook {
+	active := true
+	if r.Active != nil {
+		active = *r.Active
+	}
+	return model.Webhook{URL: r.URL, Events: r.Events, Secret: r.Secret, Active: active}
+}
+
+// FromWebhook converte model.Webhook para WebhookResponse (sem o secret)
+func FromWebhook(w model.Webhook) WebhookResponse {
+	return WebhookResponse{
+		ID:        w.ID,
+		URL:       w.URL,
+		Events:    w.Events,
+		Active:    w.Active,
+		CreatedAt: w.CreatedAt,
+		UpdatedAt: w.UpdatedAt,
+	}
+}
+
+// ToWebhookListResponse converte uma lista de webhooks para WebhookListResponse
+func ToWebhookListResponse(webhooks []model.Webhook) WebhookListResponse {
+	response := WebhookListResponse{
+		Webhooks: make([]WebhookResponse, len(webhooks)),
+		Total:    len(webhooks),
+	}
+	for i, w := range webhooks {
+		response.Webhooks[i] = FromWebhook(w)
+	}
+	return response
+}
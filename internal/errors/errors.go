@@ -64,6 +64,102 @@ var (
 		Status:  http.StatusNotFound,
 	}
 
+	ErrCategoriaNotFound = &APIError{
+		Code:    "CATEGORIA_NOT_FOUND",
+		Message: "Categoria não encontrada",
+		Status:  http.StatusNotFound,
+	}
+
+	ErrWebhookNotFound = &APIError{
+		Code:    "WEBHOOK_NOT_FOUND",
+		Message: "Webhook não encontrado",
+		Status:  http.StatusNotFound,
+	}
+
+	ErrWebhookDeliveryNotFound = &APIError{
+		Code:    "WEBHOOK_DELIVERY_NOT_FOUND",
+		Message: "Entrega não encontrada para o webhook",
+		Status:  http.StatusNotFound,
+	}
+
+	// Erros de categorias (409)
+	ErrCategoriaSlugConflict = &APIError{
+		Code:    "CATEGORIA_SLUG_CONFLICT",
+		Message: "Já existe uma categoria com o mesmo slug sob a mesma categoria pai",
+		Status:  http.StatusConflict,
+	}
+
+	ErrCategoriaEmUso = &APIError{
+		Code:    "CATEGORIA_EM_USO",
+		Message: "A categoria possui subcategorias ou produtos associados",
+		Status:  http.StatusConflict,
+	}
+
+	ErrCategoriaConflict = &APIError{
+		Code:    "CATEGORIA_CONFLICT",
+		Message: "A categoria foi alterada por outra requisição; tente novamente",
+		Status:  http.StatusConflict,
+	}
+
+	// Erros de estoque
+	ErrEstoqueInsuficiente = &APIError{
+		Code:    "ESTOQUE_INSUFICIENTE",
+		Message: "Estoque disponível insuficiente para a operação",
+		Status:  http.StatusConflict,
+	}
+
+	ErrReservaNotFound = &APIError{
+		Code:    "RESERVA_NOT_FOUND",
+		Message: "Reserva não encontrada ou já liberada",
+		Status:  http.StatusNotFound,
+	}
+
+	ErrReservaDuplicada = &APIError{
+		Code:    "RESERVA_DUPLICADA",
+		Message: "Já existe uma reserva com o mesmo ID para o produto",
+		Status:  http.StatusConflict,
+	}
+
+	// Erros de idempotência
+	ErrIdempotencyKeyReused = &APIError{
+		Code:    "IDEMPOTENCY_KEY_REUSED",
+		Message: "Idempotency-Key já utilizada com um corpo de requisição diferente",
+		Status:  http.StatusUnprocessableEntity,
+	}
+
+	ErrIdempotencyInProgress = &APIError{
+		Code:    "IDEMPOTENCY_IN_PROGRESS",
+		Message: "Uma requisição com a mesma Idempotency-Key ainda está em processamento",
+		Status:  http.StatusConflict,
+	}
+
+	ErrIdempotencyStoreFull = &APIError{
+		Code:    "IDEMPOTENCY_STORE_FULL",
+		Message: "Limite de registros de idempotência atingido, tente novamente mais tarde",
+		Status:  http.StatusServiceUnavailable,
+	}
+
+	// Erros de concorrência (412)
+	ErrPreconditionFailed = &APIError{
+		Code:    "PRECONDITION_FAILED",
+		Message: "A versão do produto foi alterada por outra requisição",
+		Status:  http.StatusPreconditionFailed,
+	}
+
+	// Erros de tamanho da requisição (413)
+	ErrPayloadTooLarge = &APIError{
+		Code:    "PAYLOAD_TOO_LARGE",
+		Message: "Corpo da requisição excede o tamanho máximo permitido",
+		Status:  http.StatusRequestEntityTooLarge,
+	}
+
+	// Erros de operações em lote (424)
+	ErrBatchAborted = &APIError{
+		Code:    "BATCH_ABORTED",
+		Message: "Operação não aplicada porque outra operação do lote atômico falhou",
+		Status:  http.StatusFailedDependency,
+	}
+
 	// Erros de servidor (500)
 	ErrInternalServer = &APIError{
 		Code:    "INTERNAL_SERVER_ERROR",
@@ -90,6 +186,18 @@ var (
 		Status:  http.StatusUnprocessableEntity,
 	}
 
+	ErrCategoriaInvalida = &APIError{
+		Code:    "CATEGORIA_INVALIDA",
+		Message: "Categoria informada não existe",
+		Status:  http.StatusUnprocessableEntity,
+	}
+
+	ErrCategoriaHierarquiaInvalida = &APIError{
+		Code:    "CATEGORIA_HIERARQUIA_INVALIDA",
+		Message: "Uma categoria não pode ser movida para baixo dela mesma",
+		Status:  http.StatusUnprocessableEntity,
+	}
+
 	ErrPrecoInvalido = &APIError{
 		Code:    "PRECO_INVALIDO",
 		Message: "Preço não pode ser negativo",
@@ -0,0 +1,61 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"api-go-arquitetura/internal/utils"
+
+	"github.com/google/uuid"
+)
+
+// Tipos de evento de domínio
+const (
+	ProdutoCriado     = "ProdutoCriado"
+	ProdutoAtualizado = "ProdutoAtualizado"
+	ProdutoRemovido   = "ProdutoRemovido"
+	ProdutoRestaurado = "ProdutoRestaurado"
+	PrecoAlterado     = "PrecoAlterado"
+)
+
+// AggregateProduto identifica os eventos de produto
+const AggregateProduto = "produto"
+
+// Event é um evento de domínio
+// Version é a versão do agregado após a alteração: como a entrega é "pelo menos
+// uma vez" e sem ordem garantida, consumidores devem usar ID para descartar
+// duplicatas e Version para descartar eventos antigos
+type Event struct {
+	ID            string                 `json:"id" bson:"_id"`
+	Type          string                 `json:"type" bson:"type"`
+	AggregateType string                 `json:"aggregateType" bson:"aggregate_type"`
+	AggregateID   int                    `json:"aggregateId" bson:"aggregate_id"`
+	Version       int                    `json:"version" bson:"version"`
+	Payload       map[string]interface{} `json:"payload" bson:"payload"`
+	RequestID     string                 `json:"requestId,omitempty" bson:"request_id,omitempty"`
+	OccurredAt    time.Time              `json:"occurredAt" bson:"occurred_at"`
+}
+
+// New cria um evento com o payload convertido para um mapa pelos nomes JSON
+// O request ID é lido do contexto
+func New(ctx context.Context, eventType, aggregateType string, aggregateID, version int, payload interface{}) (Event, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return Event{}, err
+	}
+	m := make(map[string]interface{})
+	if err := json.Unmarshal(data, &m); err != nil {
+		return Event{}, err
+	}
+	return Event{
+		ID:            uuid.New().String(),
+		Type:          eventType,
+		AggregateType: aggregateType,
+		AggregateID:   aggregateID,
+		Version:       version,
+		Payload:       m,
+		RequestID:     utils.RequestIDFromContext(ctx),
+		OccurredAt:    time.Now(),
+	}, nil
+}
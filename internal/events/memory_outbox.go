@@ -0,0 +1,103 @@
+package events
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+)
+
+// MemoryOutbox mantém as mensagens do outbox em memória (testes e desenvolvimento)
+type MemoryOutbox struct {
+	mu       sync.Mutex
+	messages map[string]*Message
+}
+
+// Garante em tempo de compilação que MemoryOutbox implementa Outbox
+var _ Outbox = (*MemoryOutbox)(nil)
+
+// NewMemoryOutbox cria um novo MemoryOutbox
+func NewMemoryOutbox() *MemoryOutbox {
+	return &MemoryOutbox{messages: make(map[string]*Message)}
+}
+
+// Add grava os eventos como pendentes
+func (o *MemoryOutbox) Add(ctx context.Context, events ...Event) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	for _, event := range events {
+		msg := NewMessage(event)
+		o.messages[event.ID] = &msg
+	}
+	return nil
+}
+
+// Claim reserva até limit mensagens pendentes
+func (o *MemoryOutbox) Claim(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]Message, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	claimed := []Message{}
+	for _, msg := range o.sorted() {
+		if len(claimed) >= limit {
+			break
+		}
+		if msg.Status != StatusPending || msg.NextAttemptAt.After(now) {
+			continue
+		}
+		msg.NextAttemptAt = now.Add(lease)
+		claimed = append(claimed, *msg)
+	}
+	return claimed, nil
+}
+
+// MarkPublished marca a mensagem como publicada
+func (o *MemoryOutbox) MarkPublished(ctx context.Context, id string, at time.Time) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if msg, ok := o.messages[id]; ok {
+		msg.Status = StatusPublished
+		msg.PublishedAt = &at
+		msg.LastError = ""
+		msg.Attempts++
+	}
+	return nil
+}
+
+// MarkFailed registra uma tentativa sem sucesso e agenda a próxima para next
+func (o *MemoryOutbox) MarkFailed(ctx context.Context, id string, lastErr string, next time.Time) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if msg, ok := o.messages[id]; ok && msg.Status == StatusPending {
+		msg.LastError = lastErr
+		msg.NextAttemptAt = next
+		msg.Attempts++
+	}
+	return nil
+}
+
+// Messages retorna todas as mensagens, das mais antigas para as mais recentes
+func (o *MemoryOutbox) Messages() []Message {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	messages := make([]Message, 0, len(o.messages))
+	for _, msg := range o.sorted() {
+		messages = append(messages, *msg)
+	}
+	return messages
+}
+
+// sorted retorna as mensagens ordenadas por OccurredAt e ID (requer o lock)
+func (o *MemoryOutbox) sorted() []*Message {
+	messages := make([]*Message, 0, len(o.messages))
+	for _, msg := range o.messages {
+		messages = append(messages, msg)
+	}
+	sort.Slice(messages, func(i, j int) bool {
+		if !messages[i].OccurredAt.Equal(messages[j].OccurredAt) {
+			return messages[i].OccurredAt.Before(messages[j].OccurredAt)
+		}
+		return messages[i].ID < messages[j].ID
+	})
+	return messages
+}
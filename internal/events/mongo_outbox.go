@@ -0,0 +1,89 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// MongoOutbox grava as mensagens do outbox no MongoDB
+// Add pode ser chamado com o contexto de uma transação (mongo.SessionContext),
+// gravando os eventos na mesma transação da alteração que os originou
+type MongoOutbox struct {
+	collection *mongo.Collection
+}
+
+// Garante em tempo de compilação que MongoOutbox implementa Outbox
+var _ Outbox = (*MongoOutbox)(nil)
+
+// NewMongoOutbox cria um novo MongoOutbox na coleção outbox do banco
+func NewMongoOutbox(db *mongo.Database) *MongoOutbox {
+	// Os payloads são lidos como mapas (e não bson.D), para que os publishers
+	// os serializem no mesmo formato em que foram gravados
+	opts := options.Collection().SetBSONOptions(&options.BSONOptions{DefaultDocumentM: true})
+	return &MongoOutbox{collection: db.Collection(CollectionOutbox, opts)}
+}
+
+// Add grava os eventos como pendentes
+func (o *MongoOutbox) Add(ctx context.Context, events ...Event) error {
+	if len(events) == 0 {
+		return nil
+	}
+	docs := make([]interface{}, len(events))
+	for i, event := range events {
+		docs[i] = NewMessage(event)
+	}
+	_, err := o.collection.InsertMany(ctx, docs)
+	return err
+}
+
+// Claim reserva até limit mensagens pendentes, uma a uma, com FindOneAndUpdate
+func (o *MongoOutbox) Claim(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]Message, error) {
+	filter := bson.M{
+		"status":          StatusPending,
+		"next_attempt_at": bson.M{"$lte": now},
+	}
+	update := bson.M{"$set": bson.M{"next_attempt_at": now.Add(lease)}}
+	opts := options.FindOneAndUpdate().
+		SetSort(bson.D{{Key: "occurred_at", Value: 1}, {Key: "_id", Value: 1}}).
+		SetReturnDocument(options.After)
+
+	messages := []Message{}
+	for len(messages) < limit {
+		var msg Message
+		err := o.collection.FindOneAndUpdate(ctx, filter, update, opts).Decode(&msg)
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			break
+		}
+		if err != nil {
+			return messages, err
+		}
+		messages = append(messages, msg)
+	}
+	return messages, nil
+}
+
+// MarkPublished marca a mensagem como publicada
+func (o *MongoOutbox) MarkPublished(ctx context.Context, id string, at time.Time) error {
+	update := bson.M{
+		"$set":   bson.M{"status": StatusPublished, "published_at": at},
+		"$unset": bson.M{"last_error": ""},
+		"$inc":   bson.M{"attempts": 1},
+	}
+	_, err := o.collection.UpdateOne(ctx, bson.M{"_id": id}, update)
+	return err
+}
+
+// MarkFailed registra uma tentativa sem sucesso e agenda a próxima para next
+func (o *MongoOutbox) MarkFailed(ctx context.Context, id string, lastErr string, next time.Time) error {
+	update := bson.M{
+		"$set": bson.M{"last_error": lastErr, "next_attempt_at": next},
+		"$inc": bson.M{"attempts": 1},
+	}
+	_, err := o.collection.UpdateOne(ctx, bson.M{"_id": id, "status": StatusPending}, update)
+	return err
+}
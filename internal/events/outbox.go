@@ -0,0 +1,48 @@
+package events
+
+import (
+	"context"
+	"time"
+)
+
+// CollectionOutbox é a coleção do outbox, no mesmo banco dos produtos
+const CollectionOutbox = "outbox"
+
+// Situações de uma mensagem do outbox
+const (
+	StatusPending   = "pending"
+	StatusPublished = "published"
+)
+
+// Message é um evento gravado no outbox junto com o estado da sua entrega
+type Message struct {
+	Event         `bson:",inline"`
+	Status        string     `bson:"status"`
+	Attempts      int        `bson:"attempts"`
+	LastError     string     `bson:"last_error,omitempty"`
+	NextAttemptAt time.Time  `bson:"next_attempt_at"`
+	PublishedAt   *time.Time `bson:"published_at,omitempty"`
+}
+
+// NewMessage cria a mensagem pendente do evento, disponível para publicação imediata
+func NewMessage(event Event) Message {
+	return Message{
+		Event:         event,
+		Status:        StatusPending,
+		NextAttemptAt: event.OccurredAt,
+	}
+}
+
+// Outbox armazena os eventos até que sejam publicados
+type Outbox interface {
+	// Add grava os eventos como pendentes
+	Add(ctx context.Context, events ...Event) error
+	// Claim reserva até limit mensagens pendentes com NextAttemptAt <= now, das
+	// mais antigas para as mais recentes, adiando NextAttemptAt para now+lease:
+	// outra instância do relay só as recebe novamente se a reserva expirar
+	Claim(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]Message, error)
+	// MarkPublished marca a mensagem como publicada
+	MarkPublished(ctx context.Context, id string, at time.Time) error
+	// MarkFailed registra uma tentativa sem sucesso e agenda a próxima para next
+	MarkFailed(ctx context.Context, id string, lastErr string, next time.Time) error
+}
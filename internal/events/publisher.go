@@ -0,0 +1,170 @@
+package events
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"api-go-arquitetura/internal/logger"
+)
+
+// Publisher entrega os eventos aos consumidores
+// Erros transitórios devem ser retornados como TransientError, para que o relay
+// repita a publicação imediatamente (database.Retry); os demais só são repetidos
+// na próxima tentativa agendada
+type Publisher interface {
+	Publish(ctx context.Context, event Event) error
+}
+
+// TransientError indica uma falha transitória na publicação (ex.: rede, HTTP 5xx)
+type TransientError struct {
+	Err error
+}
+
+func (e *TransientError) Error() string { return e.Err.Error() }
+
+func (e *TransientError) Unwrap() error { return e.Err }
+
+// Retryable indica que a publicação pode ser repetida (ver database.Retryable)
+func (e *TransientError) Retryable() bool { return true }
+
+// Transient marca err como falha transitória
+func Transient(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &TransientError{Err: err}
+}
+
+// LogPublisher apenas registra os eventos no log (desenvolvimento)
+type LogPublisher struct{}
+
+// NewLogPublisher cria um novo LogPublisher
+func NewLogPublisher() *LogPublisher {
+	return &LogPublisher{}
+}
+
+// Publish registra o evento no log
+func (p *LogPublisher) Publish(ctx context.Context, event Event) error {
+	logger.WithFields(map[string]interface{}{
+		"event_id":     event.ID,
+		"event_type":   event.Type,
+		"aggregate":    event.AggregateType,
+		"aggregate_id": event.AggregateID,
+		"version":      event.Version,
+		"request_id":   event.RequestID,
+	}).Info("Evento publicado")
+	return nil
+}
+
+// DefaultPublishTimeout é o timeout padrão de uma publicação
+const DefaultPublishTimeout = 10 * time.Second
+
+// HTTPPublisher publica os eventos como JSON via POST em uma URL (webhook)
+type HTTPPublisher struct {
+	url    string
+	client *http.Client
+}
+
+// NewHTTPPublisher cria um novo HTTPPublisher
+// timeout <= 0 usa DefaultPublishTimeout
+func NewHTTPPublisher(url string, timeout time.Duration) *HTTPPublisher {
+	if timeout <= 0 {
+		timeout = DefaultPublishTimeout
+	}
+	return &HTTPPublisher{url: url, client: &http.Client{Timeout: timeout}}
+}
+
+// Publish envia o evento. Respostas 2xx confirmam a entrega; falhas de rede,
+// 429 e 5xx são transitórias
+func (p *HTTPPublisher) Publish(ctx context.Context, event Event) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Event-ID", event.ID)
+	req.Header.Set("X-Event-Type", event.Type)
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return Transient(err)
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return nil
+	}
+	err = fmt.Errorf("webhook respondeu com status %d", resp.StatusCode)
+	if resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500 {
+		return Transient(err)
+	}
+	return err
+}
+
+// Handler processa um evento publicado no Bus
+type Handler func(ctx context.Context, event Event) error
+
+// Bus entrega os eventos, no próprio processo, aos handlers inscritos (testes)
+type Bus struct {
+	mu       sync.RWMutex
+	handlers map[string][]Handler
+}
+
+// NewBus cria um novo Bus
+func NewBus() *Bus {
+	return &Bus{handlers: make(map[string][]Handler)}
+}
+
+// Subscribe inscreve o handler nos eventos do tipo informado ("" = todos)
+func (b *Bus) Subscribe(eventType string, handler Handler) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.handlers[eventType] = append(b.handlers[eventType], handler)
+}
+
+// Publish chama, em ordem, os handlers do tipo do evento e os inscritos em todos
+// Retorna o primeiro erro, sem chamar os handlers seguintes
+func (b *Bus) Publish(ctx context.Context, event Event) error {
+	b.mu.RLock()
+	handlers := append(append([]Handler(nil), b.handlers[event.Type]...), b.handlers[""]...)
+	b.mu.RUnlock()
+
+	for _, handler := range handlers {
+		if err := handler(ctx, event); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// MultiPublisher publica cada evento em todos os publishers, em ordem
+type MultiPublisher struct {
+	publishers []Publisher
+}
+
+// NewMultiPublisher cria um novo MultiPublisher
+func NewMultiPublisher(publishers ...Publisher) *MultiPublisher {
+	return &MultiPublisher{publishers: publishers}
+}
+
+// Publish publica o evento em todos os publishers e retorna o primeiro erro
+// Como o evento é publicado novamente em todos eles, os publishers devem tolerar duplicatas
+func (p *MultiPublisher) Publish(ctx context.Context, event Event) error {
+	for _, publisher := range p.publishers {
+		if err := publisher.Publish(ctx, event); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -84,6 +84,79 @@ var (
 		[]string{"operation"},
 	)
 
+	// CacheCoalesced é um contador para leituras que aguardaram a carga de outra
+	// requisição com a mesma chave em vez de consultar o banco
+	CacheCoalesced = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "cache_coalesced_total",
+			Help: "Total de leituras atendidas pela carga em andamento de outra requisição",
+		},
+		[]string{"operation"},
+	)
+
+	// CacheStaleServed é um contador para entradas vencidas servidas durante a recarga
+	CacheStaleServed = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "cache_stale_served_total",
+			Help: "Total de entradas vencidas servidas enquanto são recarregadas em background",
+		},
+		[]string{"operation"},
+	)
+
+	// CacheMemoryEntries é um gauge para o número de entradas de cada cache em memória
+	CacheMemoryEntries = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "cache_memory_entries",
+			Help: "Número de entradas do cache em memória",
+		},
+		[]string{"cache"}, // cache: nome do cache (app, l1, idempotency)
+	)
+
+	// CacheMemoryBytes é um gauge para o tamanho (chaves e valores) de cada cache em memória
+	CacheMemoryBytes = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "cache_memory_bytes",
+			Help: "Bytes de chaves e valores armazenados no cache em memória",
+		},
+		[]string{"cache"},
+	)
+
+	// CacheMemoryEvictions é um contador para entradas removidas de cada cache em memória
+	CacheMemoryEvictions = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "cache_memory_evictions_total",
+			Help: "Total de entradas removidas (ou não admitidas) no cache em memória",
+		},
+		[]string{"cache", "reason"}, // reason: capacity, expired, rejected
+	)
+
+	// CacheMemoryHitRatio é um gauge para a taxa de acerto de cada cache em memória
+	CacheMemoryHitRatio = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "cache_memory_hit_ratio",
+			Help: "Fração das leituras do cache em memória que encontraram a chave",
+		},
+		[]string{"cache"},
+	)
+
+	// CacheLayerReads é um contador para leituras do cache em duas camadas, pela camada que respondeu
+	CacheLayerReads = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "cache_layer_reads_total",
+			Help: "Total de leituras do cache em duas camadas, pela camada que encontrou a chave",
+		},
+		[]string{"layer"}, // layer: l1, l2, miss
+	)
+
+	// CacheInvalidations é um contador para mensagens de invalidação do cache local entre instâncias
+	CacheInvalidations = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "cache_invalidations_total",
+			Help: "Total de mensagens de invalidação do cache local publicadas e recebidas",
+		},
+		[]string{"direction", "status"}, // direction: published, received, status: success, error
+	)
+
 	// DatabaseConnections é um gauge para conexões de banco de dados
 	DatabaseConnections = promauto.NewGaugeVec(
 		prometheus.GaugeOpts{
@@ -92,6 +165,67 @@ var (
 		},
 		[]string{"state"}, // state: active, idle, total
 	)
+
+	// ProdutosPurged é um contador para produtos removidos permanentemente da lixeira
+	ProdutosPurged = promauto.NewCounter(
+		prometheus.CounterOpts{
+			Name: "produtos_purged_total",
+			Help: "Total de produtos removidos permanentemente da lixeira",
+		},
+	)
+
+	// PurgeRuns é um contador para execuções da limpeza da lixeira
+	PurgeRuns = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "produtos_purge_runs_total",
+			Help: "Total de execuções da limpeza da lixeira",
+		},
+		[]string{"trigger", "status"}, // trigger: scheduled, manual, status: success, error
+	)
+
+	// ReservasExpiradas é um contador para reservas de estoque liberadas por expiração
+	ReservasExpiradas = promauto.NewCounter(
+		prometheus.CounterOpts{
+			Name: "produtos_reservas_expiradas_total",
+			Help: "Total de reservas de estoque liberadas por expiração",
+		},
+	)
+
+	// ReservationSweepRuns é um contador para execuções da liberação de reservas expiradas
+	ReservationSweepRuns = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "produtos_reservation_sweep_runs_total",
+			Help: "Total de execuções da liberação de reservas expiradas",
+		},
+		[]string{"status"}, // status: success, error
+	)
+
+	// AuditEntries é um contador para registros gravados no log de auditoria
+	AuditEntries = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "audit_entries_total",
+			Help: "Total de registros gravados no log de auditoria",
+		},
+		[]string{"entity", "action", "status"}, // status: success, error
+	)
+
+	// OutboxPublications é um contador para tentativas de publicação de eventos do outbox
+	OutboxPublications = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "outbox_publications_total",
+			Help: "Total de tentativas de publicação de eventos do outbox",
+		},
+		[]string{"type", "status"}, // status: published, failed
+	)
+
+	// WebhookDeliveries é um contador para tentativas de entrega de webhooks
+	WebhookDeliveries = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "webhook_deliveries_total",
+			Help: "Total de tentativas de entrega de webhooks, pela situação resultante",
+		},
+		[]string{"status"}, // status: delivered, pending (reagendada), dead
+	)
 )
 
 // RecordHTTPRequest registra uma requisição HTTP
@@ -136,6 +270,69 @@ func RecordCacheError(operation string, duration time.Duration) {
 	RecordCacheOperation(operation, "error", duration)
 }
 
+// RecordCacheCoalesced registra uma leitura coalescida com a carga de outra requisição
+func RecordCacheCoalesced(operation string) {
+	CacheCoalesced.WithLabelValues(operation).Inc()
+}
+
+// SetCacheMemorySize registra o número de entradas e de bytes do cache em memória
+func SetCacheMemorySize(cache string, entries int, bytes int64) {
+	CacheMemoryEntries.WithLabelValues(cache).Set(float64(entries))
+	CacheMemoryBytes.WithLabelValues(cache).Set(float64(bytes))
+}
+
+// RecordCacheEviction registra entradas removidas do cache em memória
+func RecordCacheEviction(cache, reason string, count int) {
+	CacheMemoryEvictions.WithLabelValues(cache, reason).Add(float64(count))
+}
+
+// SetCacheMemoryHitRatio registra a taxa de acerto do cache em memória
+func SetCacheMemoryHitRatio(cache string, ratio float64) {
+	CacheMemoryHitRatio.WithLabelValues(cache).Set(ratio)
+}
+
+// RecordCacheLayerRead registra a camada que respondeu a uma leitura do cache em duas camadas
+func RecordCacheLayerRead(layer string) {
+	CacheLayerReads.WithLabelValues(layer).Inc()
+}
+
+// RecordCacheInvalidation registra uma mensagem de invalidação publicada ou recebida
+func RecordCacheInvalidation(direction, status string) {
+	CacheInvalidations.WithLabelValues(direction, status).Inc()
+}
+
+// RecordCacheStale registra uma entrada vencida servida durante a recarga
+func RecordCacheStale(operation string) {
+	CacheStaleServed.WithLabelValues(operation).Inc()
+}
+
+// RecordReservationSweep registra uma execução da liberação de reservas expiradas
+func RecordReservationSweep(status string, released int) {
+	ReservationSweepRuns.WithLabelValues(status).Inc()
+	ReservasExpiradas.Add(float64(released))
+}
+
+// RecordAuditEntry registra a gravação de um registro de auditoria
+func RecordAuditEntry(entity, action, status string) {
+	AuditEntries.WithLabelValues(entity, action, status).Inc()
+}
+
+// RecordOutboxPublication registra uma tentativa de publicação de evento do outbox
+func RecordOutboxPublication(eventType, status string) {
+	OutboxPublications.WithLabelValues(eventType, status).Inc()
+}
+
+// RecordWebhookDelivery registra uma tentativa de entrega de webhook
+func RecordWebhookDelivery(status string) {
+	WebhookDeliveries.WithLabelValues(status).Inc()
+}
+
+// RecordPurge registra uma execução da limpeza da lixeira
+func RecordPurge(trigger, status string, purged int64) {
+	PurgeRuns.WithLabelValues(trigger, status).Inc()
+	ProdutosPurged.Add(float64(purged))
+}
+
 // SetDatabaseConnections atualiza o número de conexões de banco de dados
 func SetDatabaseConnections(state string, count float64) {
 	DatabaseConnections.WithLabelValues(state).Set(count)
@@ -0,0 +1,60 @@
+package model
+
+import "time"
+
+// Categoria agrupa produtos em uma hierarquia (árvore)
+// A posição na árvore é materializada em Ancestors e Path, o que permite
+// buscar descendentes com uma única consulta indexada
+type Categoria struct {
+	ID        int       `json:"id" bson:"id"`
+	Nome      string    `json:"nome" bson:"nome"`
+	Slug      string    `json:"slug" bson:"slug"`                              // Identificador legível, único entre as irmãs
+	ParentID  int       `json:"parentId,omitempty" bson:"parent_id,omitempty"` // 0 = categoria raiz
+	Ancestors []int     `json:"ancestors" bson:"ancestors"`                    // IDs da raiz até o pai
+	Path      string    `json:"path" bson:"path"`                              // Slugs da raiz até a categoria (ex.: "eletronicos/notebooks")
+	CreatedAt time.Time `json:"created_at" bson:"created_at"`
+	UpdatedAt time.Time `json:"updated_at" bson:"updated_at"`
+}
+
+// IsRoot verifica se a categoria não possui pai
+func (c *Categoria) IsRoot() bool {
+	return c.ParentID == 0
+}
+
+// PlaceUnder posiciona a categoria sob o pai informado (nil = raiz),
+// recalculando Ancestors e Path
+func (c *Categoria) PlaceUnder(parent *Categoria) {
+	if parent == nil {
+		c.ParentID = 0
+		c.Ancestors = []int{}
+		c.Path = c.Slug
+		return
+	}
+	c.ParentID = parent.ID
+	c.Ancestors = append(append([]int{}, parent.Ancestors...), parent.ID)
+	c.Path = parent.Path + "/" + c.Slug
+}
+
+// IsDescendantOf verifica se a categoria está abaixo (em qualquer nível) da categoria id
+func (c *Categoria) IsDescendantOf(id int) bool {
+	for _, ancestor := range c.Ancestors {
+		if ancestor == id {
+			return true
+		}
+	}
+	return false
+}
+
+// BeforeCreate inicializa os timestamps antes de criar
+func (c *Categoria) BeforeCreate() {
+	now := time.Now()
+	if c.CreatedAt.IsZero() {
+		c.CreatedAt = now
+	}
+	c.UpdatedAt = now
+}
+
+// BeforeUpdate atualiza o timestamp de atualização
+func (c *Categoria) BeforeUpdate() {
+	c.UpdatedAt = time.Now()
+}
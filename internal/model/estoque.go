@@ -0,0 +1,39 @@
+package model
+
+import "time"
+
+// Reserva representa uma quantidade do estoque separada para um pedido até ExpiresAt
+type Reserva struct {
+	ID         string    `json:"id" bson:"id"`
+	Quantidade int       `json:"quantidade" bson:"quantidade"`
+	ExpiresAt  time.Time `json:"expires_at" bson:"expires_at"`
+	CreatedAt  time.Time `json:"created_at" bson:"created_at"`
+}
+
+// IsExpired verifica se a reserva expirou no instante informado
+func (r Reserva) IsExpired(now time.Time) bool {
+	return !r.ExpiresAt.After(now)
+}
+
+// Disponivel retorna a quantidade que ainda pode ser reservada
+func (p *Produto) Disponivel() int {
+	return p.Estoque - p.Reservado
+}
+
+// FindReserva busca uma reserva ativa do produto pelo ID
+func (p *Produto) FindReserva(id string) (Reserva, bool) {
+	for _, r := range p.Reservas {
+		if r.ID == id {
+			return r, true
+		}
+	}
+	return Reserva{}, false
+}
+
+// KeepStock copia o estoque e as reservas de outro produto
+// O estoque só é alterado pelas operações de estoque, nunca pela substituição do produto
+func (p *Produto) KeepStock(from Produto) {
+	p.Estoque = from.Estoque
+	p.Reservado = from.Reservado
+	p.Reservas = from.Reservas
+}
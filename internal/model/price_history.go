@@ -0,0 +1,13 @@
+package model
+
+import "time"
+
+// PriceChange representa uma alteração de preço de um produto
+type PriceChange struct {
+	ProdutoID     int       `json:"produtoId" bson:"produto_id"`
+	PrecoAnterior float64   `json:"precoAnterior" bson:"preco_anterior"`
+	PrecoNovo     float64   `json:"precoNovo" bson:"preco_novo"`
+	Version       int       `json:"version" bson:"version"` // Versão do produto após a alteração
+	RequestID     string    `json:"requestId,omitempty" bson:"request_id,omitempty"`
+	ChangedAt     time.Time `json:"changedAt" bson:"changed_at"`
+}
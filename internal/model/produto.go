@@ -5,13 +5,19 @@ import "time"
 // Produto representa a entidade de domínio de um produto
 // Esta é a entidade interna usada para persistência e lógica de negócio
 type Produto struct {
-	ID        int        `json:"id" bson:"id"`
-	Nome      string     `json:"nome" bson:"nome"`
-	Preco     float64    `json:"preco" bson:"preco"`
-	Descricao string     `json:"descricao" bson:"descricao"`
-	CreatedAt time.Time  `json:"created_at" bson:"created_at"`
-	UpdatedAt time.Time  `json:"updated_at" bson:"updated_at"`
-	DeletedAt *time.Time `json:"deleted_at,omitempty" bson:"deleted_at,omitempty"` // Soft delete
+	ID          int        `json:"id" bson:"id"`
+	Nome        string     `json:"nome" bson:"nome"`
+	Preco       float64    `json:"preco" bson:"preco"`
+	Descricao   string     `json:"descricao" bson:"descricao"`
+	Tags        []string   `json:"tags,omitempty" bson:"tags,omitempty"`
+	CategoriaID int        `json:"categoriaId,omitempty" bson:"categoria_id,omitempty"` // 0 = sem categoria
+	Estoque     int        `json:"estoque" bson:"estoque"`                              // Quantidade em estoque
+	Reservado   int        `json:"reservado" bson:"reservado"`                          // Soma das reservas ativas
+	Reservas    []Reserva  `json:"reservas,omitempty" bson:"reservas,omitempty"`        // Reservas ativas (até expirarem ou serem liberadas)
+	CreatedAt   time.Time  `json:"created_at" bson:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at" bson:"updated_at"`
+	DeletedAt   *time.Time `json:"deleted_at,omitempty" bson:"deleted_at,omitempty"` // Soft delete
+	Version     int        `json:"version" bson:"version"`                           // Controle de concorrência otimista
 }
 
 // IsDeleted verifica se o produto foi deletado (soft delete)
@@ -41,6 +47,9 @@ func (p *Produto) BeforeCreate() {
 	if p.UpdatedAt.IsZero() {
 		p.UpdatedAt = now
 	}
+	if p.Version == 0 {
+		p.Version = 1
+	}
 }
 
 // BeforeUpdate atualiza o timestamp de atualização
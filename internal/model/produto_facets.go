@@ -0,0 +1,40 @@
+package model
+
+// PrecoStats resume os preços dos produtos que correspondem ao filtro
+type PrecoStats struct {
+	Count int64   `json:"count" bson:"count"`
+	Min   float64 `json:"min" bson:"min"`
+	Max   float64 `json:"max" bson:"max"`
+	Avg   float64 `json:"avg" bson:"avg"`
+}
+
+// PrecoBucket é uma faixa do histograma de preços
+type PrecoBucket struct {
+	Min   float64 `json:"min"`
+	Max   float64 `json:"max"`
+	Count int64   `json:"count"`
+}
+
+// FacetCount é a contagem de produtos para um valor (ex.: uma tag)
+type FacetCount struct {
+	Value string `json:"value"`
+	Count int64  `json:"count"`
+}
+
+// CategoriaFacetCount é a contagem de produtos de uma categoria
+// Nome e Path ficam vazios se a categoria não for encontrada
+type CategoriaFacetCount struct {
+	CategoriaID int    `json:"categoriaId"`
+	Nome        string `json:"nome,omitempty"`
+	Path        string `json:"path,omitempty"`
+	Count       int64  `json:"count"`
+}
+
+// ProdutoFacets agrupa as facetas calculadas sobre o resultado de uma busca
+// Apenas as facetas solicitadas são preenchidas
+type ProdutoFacets struct {
+	Stats     *PrecoStats           `json:"stats,omitempty"`
+	Preco     []PrecoBucket         `json:"preco,omitempty"`
+	Tags      []FacetCount          `json:"tags,omitempty"`
+	Categoria []CategoriaFacetCount `json:"categoria,omitempty"`
+}
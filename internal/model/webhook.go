@@ -0,0 +1,73 @@
+package model
+
+import (
+	"time"
+
+	"api-go-arquitetura/internal/events"
+)
+
+// WebhookAllEvents inscreve o webhook em todos os tipos de evento
+const WebhookAllEvents = "*"
+
+// Webhook é a inscrição de um parceiro para receber eventos de domínio via POST
+type Webhook struct {
+	ID        int       `json:"id" bson:"id"`
+	URL       string    `json:"url" bson:"url"`
+	Events    []string  `json:"events" bson:"events"` // Tipos de evento ("*" = todos)
+	Secret    string    `json:"-" bson:"secret"`      // Chave da assinatura HMAC-SHA256
+	Active    bool      `json:"active" bson:"active"`
+	CreatedAt time.Time `json:"created_at" bson:"created_at"`
+	UpdatedAt time.Time `json:"updated_at" bson:"updated_at"`
+}
+
+// Accepts verifica se o webhook está inscrito no tipo de evento
+func (w *Webhook) Accepts(eventType string) bool {
+	for _, e := range w.Events {
+		if e == eventType || e == WebhookAllEvents {
+			return true
+		}
+	}
+	return false
+}
+
+// BeforeCreate inicializa os timestamps antes de criar
+func (w *Webhook) BeforeCreate() {
+	now := time.Now()
+	if w.CreatedAt.IsZero() {
+		w.CreatedAt = now
+	}
+	w.UpdatedAt = now
+}
+
+// BeforeUpdate atualiza o timestamp de atualização
+func (w *Webhook) BeforeUpdate() {
+	w.UpdatedAt = time.Now()
+}
+
+// Situações de uma entrega de webhook
+const (
+	DeliveryPending   = "pending"
+	DeliveryDelivered = "delivered"
+	DeliveryDead      = "dead" // Tentativas esgotadas; só é reenviada manualmente
+)
+
+// WebhookAttempt é uma tentativa de entrega
+type WebhookAttempt struct {
+	At         time.Time `json:"at" bson:"at"`
+	StatusCode int       `json:"statusCode,omitempty" bson:"status_code,omitempty"` // 0 = sem resposta
+	Error      string    `json:"error,omitempty" bson:"error,omitempty"`
+	DurationMs int64     `json:"durationMs" bson:"duration_ms"`
+}
+
+// WebhookDelivery é a entrega de um evento a um webhook, com o histórico das tentativas
+type WebhookDelivery struct {
+	ID            string           `json:"id" bson:"_id"` // Derivado do evento e do webhook: um evento publicado novamente não gera outra entrega
+	WebhookID     int              `json:"webhookId" bson:"webhook_id"`
+	Event         events.Event     `json:"event" bson:"event"`
+	Status        string           `json:"status" bson:"status"`
+	Attempts      int              `json:"attempts" bson:"attempts"` // Tentativas desde a criação ou o último reenvio manual
+	NextAttemptAt time.Time        `json:"nextAttemptAt" bson:"next_attempt_at"`
+	History       []WebhookAttempt `json:"history" bson:"history"`
+	CreatedAt     time.Time        `json:"created_at" bson:"created_at"`
+	DeliveredAt   *time.Time       `json:"delivered_at,omitempty" bson:"delivered_at,omitempty"`
+}
@@ -0,0 +1,284 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+
+	"api-go-arquitetura/internal/database"
+	"api-go-arquitetura/internal/logger"
+	"api-go-arquitetura/internal/model"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// CategoriaCounterName é o nome do contador usado para gerar IDs de categorias
+const CategoriaCounterName = "categorias"
+
+// ErrSlugConflict é retornado quando já existe uma categoria com o mesmo slug sob
+// o mesmo pai (mesmo path)
+var ErrSlugConflict = errors.New("slug conflict")
+
+// ErrCategoriaConflict é retornado quando a categoria (ou o novo pai) foi alterada
+// por outra requisição entre a leitura e a escrita
+var ErrCategoriaConflict = errors.New("categoria conflict")
+
+// CategoriaRepository define a interface para operações de categoria no repositório
+type CategoriaRepository interface {
+	Create(ctx context.Context, categoria model.Categoria) (model.Categoria, error)
+	FindByID(ctx context.Context, id int) (model.Categoria, error)
+	// FindAll retorna todas as categorias ordenadas por path (pais antes dos filhos)
+	FindAll(ctx context.Context) ([]model.Categoria, error)
+	// Update substitui a categoria e, se ela mudou de posição ou de slug,
+	// atualiza Ancestors e Path de todos os descendentes
+	Update(ctx context.Context, categoria model.Categoria) (model.Categoria, error)
+	Delete(ctx context.Context, id int) error
+	// FindDescendantIDs retorna os IDs de todas as categorias abaixo de id
+	FindDescendantIDs(ctx context.Context, id int) ([]int, error)
+	CountChildren(ctx context.Context, id int) (int64, error)
+}
+
+// mongoCategoriaRepository implementa CategoriaRepository usando MongoDB
+type mongoCategoriaRepository struct {
+	Collection *mongo.Collection
+	ids        IDAllocator
+	txSupport  atomic.Int32 // txUnknown, txSupported ou txUnsupported
+}
+
+// NewCategoriaRepository cria uma nova instância do CategoriaRepository
+// Os IDs são gerados pela coleção "counters" do mesmo banco de dados
+func NewCategoriaRepository(col *mongo.Collection) CategoriaRepository {
+	store := NewMongoCounterStore(col.Database().Collection("counters"))
+	return NewCategoriaRepositoryWithAllocator(col, NewSequentialAllocator(store, CategoriaCounterName))
+}
+
+// NewCategoriaRepositoryWithAllocator cria uma nova instância do CategoriaRepository com alocador de IDs customizado
+func NewCategoriaRepositoryWithAllocator(col *mongo.Collection, ids IDAllocator) CategoriaRepository {
+	return &mongoCategoriaRepository{Collection: col, ids: ids}
+}
+
+func (r *mongoCategoriaRepository) Create(ctx context.Context, categoria model.Categoria) (model.Categoria, error) {
+	id, err := r.ids.NextID(ctx)
+	if err != nil {
+		return model.Categoria{}, err
+	}
+	categoria.ID = id
+	categoria.BeforeCreate()
+
+	err = database.Retry(ctx, func() error {
+		_, err := r.Collection.InsertOne(ctx, categoria)
+		return err
+	}, database.DefaultRetryOptions())
+	if mongo.IsDuplicateKeyError(err) {
+		return model.Categoria{}, ErrSlugConflict
+	}
+	if err != nil {
+		return model.Categoria{}, err
+	}
+	return categoria, nil
+}
+
+func (r *mongoCategoriaRepository) FindByID(ctx context.Context, id int) (model.Categoria, error) {
+	var categoria model.Categoria
+	err := r.Collection.FindOne(ctx, bson.M{"id": id}).Decode(&categoria)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return model.Categoria{}, errors.New("not found")
+		}
+		return model.Categoria{}, err
+	}
+	return categoria, nil
+}
+
+func (r *mongoCategoriaRepository) FindAll(ctx context.Context) ([]model.Categoria, error) {
+	cursor, err := r.Collection.Find(ctx, bson.M{}, options.Find().SetSort(bson.D{{Key: "path", Value: 1}}))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	categorias := []model.Categoria{}
+	if err := cursor.All(ctx, &categorias); err != nil {
+		return nil, err
+	}
+	return categorias, nil
+}
+
+func (r *mongoCategoriaRepository) Update(ctx context.Context, categoria model.Categoria) (model.Categoria, error) {
+	existing, err := r.FindByID(ctx, categoria.ID)
+	if err != nil {
+		return model.Categoria{}, err
+	}
+	categoria.CreatedAt = existing.CreatedAt
+	categoria.BeforeUpdate()
+	moved := existing.Path != categoria.Path
+
+	err = r.write(ctx, moved, func(ctx context.Context) error {
+		if moved {
+			if err := r.touchParent(ctx, categoria); err != nil {
+				return err
+			}
+		}
+		// A substituição só é aplicada se a categoria não mudou desde a leitura:
+		// os descendentes são recalculados a partir de existing
+		res, err := r.Collection.ReplaceOne(ctx, bson.M{"id": categoria.ID, "updated_at": existing.UpdatedAt}, categoria)
+		if err != nil {
+			return err
+		}
+		if res.MatchedCount == 0 {
+			return ErrCategoriaConflict
+		}
+		if !moved {
+			return nil
+		}
+		return r.moveDescendants(ctx, existing, categoria)
+	})
+	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return model.Categoria{}, ErrSlugConflict
+		}
+		if isTransientTransactionError(err) {
+			return model.Categoria{}, ErrCategoriaConflict
+		}
+		return model.Categoria{}, err
+	}
+	return categoria, nil
+}
+
+// write aplica a escrita de uma categoria. Quando ela muda de posição, a
+// substituição e a atualização dos descendentes são gravadas em uma transação:
+// ou todas, ou nenhuma. Sem replica set, são gravadas em sequência, sem transação
+func (r *mongoCategoriaRepository) write(ctx context.Context, moved bool, fn func(ctx context.Context) error) error {
+	if !moved || !r.supportsTransactions(ctx) {
+		return fn(ctx)
+	}
+
+	tx, cancel, err := database.StartTransaction(ctx, r.Collection.Database().Client())
+	if err != nil {
+		return err
+	}
+	defer cancel()
+	defer tx.End()
+
+	return tx.WithTransaction(func(sc mongo.SessionContext) error {
+		return fn(sc)
+	})
+}
+
+// supportsTransactions indica se o servidor aceita transações, consultando-o
+// apenas na primeira vez. Se a consulta falhar, assume que sim: o erro é
+// reportado pela própria transação
+func (r *mongoCategoriaRepository) supportsTransactions(ctx context.Context) bool {
+	switch r.txSupport.Load() {
+	case txSupported:
+		return true
+	case txUnsupported:
+		return false
+	}
+
+	ok, err := database.SupportsTransactions(ctx, r.Collection.Database().Client())
+	if err != nil {
+		return true
+	}
+	if ok {
+		r.txSupport.Store(txSupported)
+	} else {
+		r.txSupport.Store(txUnsupported)
+		logger.Warn("MongoDB sem suporte a transações (requer replica set): categorias movidas terão os descendentes atualizados fora da transação")
+	}
+	return ok
+}
+
+// touchParent confirma que o novo pai continua na posição usada para calcular
+// Ancestors e Path da categoria, e atualiza seu updated_at. A escrita no pai faz
+// dois movimentos cruzados (a para baixo de b e b para baixo de a) conflitarem:
+// sem ela, cada transação veria a árvore anterior à outra e ambas criariam um ciclo
+func (r *mongoCategoriaRepository) touchParent(ctx context.Context, categoria model.Categoria) error {
+	if categoria.ParentID == 0 {
+		return nil
+	}
+	res, err := r.Collection.UpdateOne(ctx,
+		bson.M{
+			"id":        categoria.ParentID,
+			"ancestors": categoria.Ancestors[:len(categoria.Ancestors)-1],
+		},
+		bson.M{"$set": bson.M{"updated_at": categoria.UpdatedAt}},
+	)
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return ErrCategoriaConflict
+	}
+	return nil
+}
+
+// isTransientTransactionError indica se a transação foi abortada por conflito de
+// escrita com outra transação concorrente
+func isTransientTransactionError(err error) bool {
+	var labeled mongo.LabeledError
+	return errors.As(err, &labeled) && labeled.HasErrorLabel("TransientTransactionError")
+}
+
+// moveDescendants reescreve Ancestors e Path dos descendentes após a categoria
+// mudar de posição (ou de slug): o prefixo antigo é trocado pelo novo
+func (r *mongoCategoriaRepository) moveDescendants(ctx context.Context, before, after model.Categoria) error {
+	cursor, err := r.Collection.Find(ctx, bson.M{"ancestors": after.ID})
+	if err != nil {
+		return err
+	}
+	var descendants []model.Categoria
+	if err := cursor.All(ctx, &descendants); err != nil {
+		return err
+	}
+	if len(descendants) == 0 {
+		return nil
+	}
+
+	prefix := append(append([]int{}, after.Ancestors...), after.ID)
+	models := make([]mongo.WriteModel, 0, len(descendants))
+	for _, d := range descendants {
+		ancestors := append(append([]int{}, prefix...), d.Ancestors[len(before.Ancestors)+1:]...)
+		path := after.Path + d.Path[len(before.Path):]
+		models = append(models, mongo.NewUpdateOneModel().
+			SetFilter(bson.M{"id": d.ID}).
+			SetUpdate(bson.M{"$set": bson.M{"ancestors": ancestors, "path": path}}))
+	}
+	_, err = r.Collection.BulkWrite(ctx, models, options.BulkWrite().SetOrdered(false))
+	return err
+}
+
+func (r *mongoCategoriaRepository) Delete(ctx context.Context, id int) error {
+	res, err := r.Collection.DeleteOne(ctx, bson.M{"id": id})
+	if err != nil {
+		return err
+	}
+	if res.DeletedCount == 0 {
+		return errors.New("not found")
+	}
+	return nil
+}
+
+func (r *mongoCategoriaRepository) FindDescendantIDs(ctx context.Context, id int) ([]int, error) {
+	opts := options.Find().SetProjection(bson.M{"id": 1})
+	cursor, err := r.Collection.Find(ctx, bson.M{"ancestors": id}, opts)
+	if err != nil {
+		return nil, err
+	}
+	var categorias []model.Categoria
+	if err := cursor.All(ctx, &categorias); err != nil {
+		return nil, err
+	}
+
+	ids := make([]int, len(categorias))
+	for i, c := range categorias {
+		ids[i] = c.ID
+	}
+	return ids, nil
+}
+
+func (r *mongoCategoriaRepository) CountChildren(ctx context.Context, id int) (int64, error) {
+	return r.Collection.CountDocuments(ctx, bson.M{"parent_id": id})
+}
@@ -0,0 +1,119 @@
+package repository
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"api-go-arquitetura/internal/model"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// TestCategoriaRepository_Interface verifica se mongoCategoriaRepository implementa a interface
+func TestCategoriaRepository_Interface(t *testing.T) {
+	var _ CategoriaRepository = (*mongoCategoriaRepository)(nil)
+}
+
+// TestCategoriaRepository_Move verifica que mover uma categoria atualiza
+// Ancestors e Path de todos os descendentes
+func TestCategoriaRepository_Move(t *testing.T) {
+	col := newIntegrationCollection(t).Database().Collection("categorias")
+	ctx := context.Background()
+	_, err := col.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "path", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	})
+	if err != nil {
+		t.Fatalf("Erro ao criar índice: %v", err)
+	}
+	repo := NewCategoriaRepositoryWithAllocator(col, NewSequentialAllocator(NewMemoryCounterStore(), CategoriaCounterName))
+
+	create := func(slug string, parent *model.Categoria) model.Categoria {
+		t.Helper()
+		categoria := model.Categoria{Nome: slug, Slug: slug}
+		categoria.PlaceUnder(parent)
+		created, err := repo.Create(ctx, categoria)
+		if err != nil {
+			t.Fatalf("Erro ao criar %s: %v", slug, err)
+		}
+		return created
+	}
+
+	a := create("a", nil)
+	b := create("b", &a)
+	c := create("c", &b)
+	d := create("d", nil)
+
+	if _, err := repo.Create(ctx, model.Categoria{Nome: "a", Slug: "a", Path: "a"}); err != ErrSlugConflict {
+		t.Errorf("ErrSlugConflict esperado, obtido %v", err)
+	}
+	// O mesmo slug é aceito sob outro pai
+	e := create("e", nil)
+	create("c", &e)
+
+	// Mover b (e c) de a para d
+	b.PlaceUnder(&d)
+	if _, err := repo.Update(ctx, b); err != nil {
+		t.Fatalf("Erro ao mover categoria: %v", err)
+	}
+
+	moved, err := repo.FindByID(ctx, c.ID)
+	if err != nil {
+		t.Fatalf("Erro ao buscar categoria: %v", err)
+	}
+	if moved.Path != "d/b/c" || !reflect.DeepEqual(moved.Ancestors, []int{d.ID, b.ID}) {
+		t.Errorf("Descendente não acompanhou a mudança: path=%s ancestors=%v", moved.Path, moved.Ancestors)
+	}
+
+	ids, err := repo.FindDescendantIDs(ctx, d.ID)
+	if err != nil {
+		t.Fatalf("Erro ao buscar descendentes: %v", err)
+	}
+	if len(ids) != 2 {
+		t.Errorf("2 descendentes esperados em d, obtidos %v", ids)
+	}
+	if ids, _ := repo.FindDescendantIDs(ctx, a.ID); len(ids) != 0 {
+		t.Errorf("Nenhum descendente esperado em a, obtidos %v", ids)
+	}
+}
+
+// TestCategoriaRepository_UpdateConflict verifica que a escrita é recusada quando
+// a categoria ou o novo pai mudaram desde a leitura feita pelo serviço
+func TestCategoriaRepository_UpdateConflict(t *testing.T) {
+	col := newIntegrationCollection(t).Database().Collection("categorias")
+	ctx := context.Background()
+	repo := NewCategoriaRepositoryWithAllocator(col, NewSequentialAllocator(NewMemoryCounterStore(), CategoriaCounterName))
+
+	a, err := repo.Create(ctx, model.Categoria{Nome: "a", Slug: "a", Path: "a", Ancestors: []int{}})
+	if err != nil {
+		t.Fatalf("Erro ao criar a: %v", err)
+	}
+	b, err := repo.Create(ctx, model.Categoria{Nome: "b", Slug: "b", Path: "b", Ancestors: []int{}})
+	if err != nil {
+		t.Fatalf("Erro ao criar b: %v", err)
+	}
+
+	// Movimentos cruzados calculados a partir da mesma leitura: a para baixo de b
+	// e b para baixo de a. Apenas o primeiro pode ser aplicado
+	aUnderB, bUnderA := a, b
+	aUnderB.PlaceUnder(&b)
+	bUnderA.PlaceUnder(&a)
+
+	if _, err := repo.Update(ctx, aUnderB); err != nil {
+		t.Fatalf("Erro ao mover a: %v", err)
+	}
+	if _, err := repo.Update(ctx, bUnderA); err != ErrCategoriaConflict {
+		t.Errorf("ErrCategoriaConflict esperado, obtido %v", err)
+	}
+
+	stored, err := repo.FindByID(ctx, b.ID)
+	if err != nil {
+		t.Fatalf("Erro ao buscar categoria: %v", err)
+	}
+	if stored.ParentID != 0 || stored.Path != "b" {
+		t.Errorf("b não deveria ter sido movida: parent=%d path=%s", stored.ParentID, stored.Path)
+	}
+}
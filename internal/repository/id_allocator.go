@@ -0,0 +1,185 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"api-go-arquitetura/internal/model"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// ProdutoCounterName é o nome do contador usado para gerar IDs de produtos
+const ProdutoCounterName = "produtos"
+
+// ErrInvalidBlockSize é retornado quando o tamanho de bloco de IDs é inválido
+var ErrInvalidBlockSize = errors.New("tamanho de bloco de IDs deve ser maior que zero")
+
+// IDAllocator fornece IDs únicos para novas entidades
+type IDAllocator interface {
+	// NextID retorna o próximo ID disponível
+	NextID(ctx context.Context) (int, error)
+}
+
+// CounterStore armazena contadores nomeados que podem ser incrementados atomicamente
+type CounterStore interface {
+	// Increment soma delta ao contador e retorna o novo valor
+	Increment(ctx context.Context, name string, delta int) (int, error)
+	// EnsureAtLeast garante que o contador seja no mínimo value
+	EnsureAtLeast(ctx context.Context, name string, value int) error
+}
+
+// counterDocument representa um documento da coleção counters
+type counterDocument struct {
+	Name string `bson:"_id"`
+	Seq  int    `bson:"seq"`
+}
+
+// mongoCounterStore implementa CounterStore usando uma coleção do MongoDB
+type mongoCounterStore struct {
+	Collection *mongo.Collection
+}
+
+// NewMongoCounterStore cria um CounterStore baseado na coleção informada
+func NewMongoCounterStore(col *mongo.Collection) CounterStore {
+	return &mongoCounterStore{Collection: col}
+}
+
+// Increment usa FindOneAndUpdate com $inc, que é atômico no servidor
+func (s *mongoCounterStore) Increment(ctx context.Context, name string, delta int) (int, error) {
+	filter := bson.M{"_id": name}
+	update := bson.M{"$inc": bson.M{"seq": delta}}
+	opts := options.FindOneAndUpdate().
+		SetUpsert(true).
+		SetReturnDocument(options.After)
+
+	var doc counterDocument
+	if err := s.Collection.FindOneAndUpdate(ctx, filter, update, opts).Decode(&doc); err != nil {
+		return 0, err
+	}
+	return doc.Seq, nil
+}
+
+// EnsureAtLeast usa $max para nunca diminuir o valor do contador
+func (s *mongoCounterStore) EnsureAtLeast(ctx context.Context, name string, value int) error {
+	filter := bson.M{"_id": name}
+	update := bson.M{"$max": bson.M{"seq": value}}
+	_, err := s.Collection.UpdateOne(ctx, filter, update, options.Update().SetUpsert(true))
+	return err
+}
+
+// memoryCounterStore implementa CounterStore em memória (útil para testes)
+type memoryCounterStore struct {
+	mu       sync.Mutex
+	counters map[string]int
+}
+
+// NewMemoryCounterStore cria um CounterStore em memória
+func NewMemoryCounterStore() CounterStore {
+	return &memoryCounterStore{counters: make(map[string]int)}
+}
+
+// Increment soma delta ao contador e retorna o novo valor
+func (s *memoryCounterStore) Increment(ctx context.Context, name string, delta int) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.counters[name] += delta
+	return s.counters[name], nil
+}
+
+// EnsureAtLeast garante que o contador seja no mínimo value
+func (s *memoryCounterStore) EnsureAtLeast(ctx context.Context, name string, value int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.counters[name] < value {
+		s.counters[name] = value
+	}
+	return nil
+}
+
+// sequentialAllocator reserva um ID por chamada no CounterStore
+type sequentialAllocator struct {
+	store CounterStore
+	name  string
+}
+
+// NewSequentialAllocator cria um IDAllocator que incrementa o contador a cada ID
+func NewSequentialAllocator(store CounterStore, name string) IDAllocator {
+	return &sequentialAllocator{store: store, name: name}
+}
+
+// NextID retorna o próximo ID disponível
+func (a *sequentialAllocator) NextID(ctx context.Context) (int, error) {
+	return a.store.Increment(ctx, a.name, 1)
+}
+
+// blockAllocator reserva blocos de IDs de uma vez e os distribui localmente,
+// reduzindo o número de round-trips ao banco em inserções de alto volume.
+// IDs de um bloco não utilizados antes do encerramento do processo são descartados.
+type blockAllocator struct {
+	store     CounterStore
+	name      string
+	blockSize int
+
+	mu   sync.Mutex
+	next int // próximo ID a ser entregue
+	last int // último ID do bloco atual
+}
+
+// NewBlockAllocator cria um IDAllocator que reserva blockSize IDs por vez
+func NewBlockAllocator(store CounterStore, name string, blockSize int) (IDAllocator, error) {
+	if blockSize <= 0 {
+		return nil, ErrInvalidBlockSize
+	}
+	return &blockAllocator{store: store, name: name, blockSize: blockSize}, nil
+}
+
+// NextID retorna o próximo ID do bloco, reservando um novo bloco quando necessário
+func (a *blockAllocator) NextID(ctx context.Context) (int, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.next == 0 || a.next > a.last {
+		last, err := a.store.Increment(ctx, a.name, a.blockSize)
+		if err != nil {
+			return 0, err
+		}
+		a.next = last - a.blockSize + 1
+		a.last = last
+	}
+
+	id := a.next
+	a.next++
+	return id, nil
+}
+
+// NewIDAllocator cria o alocador adequado para o tamanho de bloco informado
+// (blockSize <= 1 usa um ID por chamada)
+func NewIDAllocator(store CounterStore, name string, blockSize int) IDAllocator {
+	if blockSize <= 1 {
+		return NewSequentialAllocator(store, name)
+	}
+	alloc, _ := NewBlockAllocator(store, name, blockSize)
+	return alloc
+}
+
+// SyncCounterWithCollection ajusta o contador para o maior ID já existente na coleção,
+// evitando colisões com documentos criados antes da adoção do contador
+func SyncCounterWithCollection(ctx context.Context, store CounterStore, name string, col *mongo.Collection) error {
+	opts := options.FindOne().SetSort(bson.D{{Key: "id", Value: -1}})
+	var p model.Produto
+	err := col.FindOne(ctx, bson.M{}, opts).Decode(&p)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil
+		}
+		return fmt.Errorf("erro ao buscar maior ID: %w", err)
+	}
+	return store.EnsureAtLeast(ctx, name, p.ID)
+}
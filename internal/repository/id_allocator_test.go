@@ -0,0 +1,118 @@
+package repository
+
+import (
+	"context"
+	"sync"
+	"testing"
+)
+
+// allocateConcurrently chama NextID em paralelo e retorna os IDs obtidos
+func allocateConcurrently(t *testing.T, alloc IDAllocator, total int) []int {
+	t.Helper()
+	ctx := context.Background()
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	ids := make([]int, 0, total)
+	for i := 0; i < total; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			id, err := alloc.NextID(ctx)
+			if err != nil {
+				t.Errorf("Erro inesperado: %v", err)
+				return
+			}
+			mu.Lock()
+			ids = append(ids, id)
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+	return ids
+}
+
+// assertUniqueRange verifica que os IDs são únicos e cobrem exatamente 1..total
+func assertUniqueRange(t *testing.T, ids []int, total int) {
+	t.Helper()
+	seen := make(map[int]bool, len(ids))
+	for _, id := range ids {
+		if seen[id] {
+			t.Errorf("ID %d atribuído mais de uma vez", id)
+		}
+		seen[id] = true
+	}
+	for id := 1; id <= total; id++ {
+		if !seen[id] {
+			t.Errorf("ID %d não foi atribuído", id)
+		}
+	}
+}
+
+func TestSequentialAllocator_Concurrent(t *testing.T) {
+	alloc := NewSequentialAllocator(NewMemoryCounterStore(), ProdutoCounterName)
+	ids := allocateConcurrently(t, alloc, 500)
+	assertUniqueRange(t, ids, 500)
+}
+
+func TestBlockAllocator_Concurrent(t *testing.T) {
+	alloc, err := NewBlockAllocator(NewMemoryCounterStore(), ProdutoCounterName, 20)
+	if err != nil {
+		t.Fatalf("Erro inesperado: %v", err)
+	}
+	ids := allocateConcurrently(t, alloc, 500)
+	assertUniqueRange(t, ids, 500)
+}
+
+func TestBlockAllocator_MultipleInstances(t *testing.T) {
+	// Duas réplicas compartilhando o mesmo contador nunca devem gerar IDs repetidos
+	store := NewMemoryCounterStore()
+	a, _ := NewBlockAllocator(store, ProdutoCounterName, 10)
+	b, _ := NewBlockAllocator(store, ProdutoCounterName, 10)
+
+	var wg sync.WaitGroup
+	var idsA, idsB []int
+	wg.Add(2)
+	go func() { defer wg.Done(); idsA = allocateConcurrently(t, a, 200) }()
+	go func() { defer wg.Done(); idsB = allocateConcurrently(t, b, 200) }()
+	wg.Wait()
+
+	seen := make(map[int]bool)
+	for _, id := range append(idsA, idsB...) {
+		if seen[id] {
+			t.Errorf("ID %d atribuído mais de uma vez", id)
+		}
+		seen[id] = true
+	}
+}
+
+func TestBlockAllocator_InvalidSize(t *testing.T) {
+	if _, err := NewBlockAllocator(NewMemoryCounterStore(), ProdutoCounterName, 0); err != ErrInvalidBlockSize {
+		t.Errorf("Erro esperado %v, obtido %v", ErrInvalidBlockSize, err)
+	}
+}
+
+func TestCounterStore_EnsureAtLeast(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryCounterStore()
+
+	t.Run("deve continuar a partir do maior ID existente", func(t *testing.T) {
+		if err := store.EnsureAtLeast(ctx, ProdutoCounterName, 41); err != nil {
+			t.Fatalf("Erro inesperado: %v", err)
+		}
+		id, _ := NewSequentialAllocator(store, ProdutoCounterName).NextID(ctx)
+		if id != 42 {
+			t.Errorf("ID esperado 42, obtido %d", id)
+		}
+	})
+
+	t.Run("não deve diminuir o contador", func(t *testing.T) {
+		if err := store.EnsureAtLeast(ctx, ProdutoCounterName, 5); err != nil {
+			t.Fatalf("Erro inesperado: %v", err)
+		}
+		id, _ := NewSequentialAllocator(store, ProdutoCounterName).NextID(ctx)
+		if id != 43 {
+			t.Errorf("ID esperado 43, obtido %d", id)
+		}
+	})
+}
@@ -2,22 +2,61 @@ package repository
 
 import (
 	"context"
+	"errors"
+	"time"
 
 	"api-go-arquitetura/internal/model"
 
 	"go.mongodb.org/mongo-driver/bson"
 )
 
+// ErrVersionConflict é retornado quando a versão informada não corresponde à versão armazenada
+var ErrVersionConflict = errors.New("version conflict")
+
+// ProdutoChange é o produto antes e depois de uma escrita, como lidos pela própria
+// escrita: Before é exatamente a versão substituída (a escrita é condicionada a ela)
+type ProdutoChange struct {
+	Before model.Produto
+	After  model.Produto
+}
+
 // ProdutoRepository define a interface para operações de produto no repositório
 type ProdutoRepository interface {
 	Create(ctx context.Context, produto model.Produto) (model.Produto, error)
 	FindAll(ctx context.Context) ([]model.Produto, error)
-	FindByID(ctx context.Context, id int) (model.Produto, error)
-	Update(ctx context.Context, id int, produto model.Produto) (model.Produto, error)
-	Patch(ctx context.Context, id int, updates map[string]interface{}) (model.Produto, error)
-	Delete(ctx context.Context, id int) error
+	// fields restringe os campos lidos do banco (projeção); vazio lê o documento inteiro
+	FindByID(ctx context.Context, id int, fields ...string) (model.Produto, error)
+	// expectedVersion != nil habilita o controle de concorrência otimista (0 é a
+	// versão dos documentos criados antes do campo version); nil aplica a escrita
+	// sobre a versão atual, qualquer que seja
+	Update(ctx context.Context, id int, produto model.Produto, expectedVersion *int) (ProdutoChange, error)
+	Patch(ctx context.Context, id int, updates map[string]interface{}, expectedVersion *int) (ProdutoChange, error)
+	Delete(ctx context.Context, id int, expectedVersion *int) (ProdutoChange, error)
 	// Novos métodos para paginação e filtros
-	FindAllPaginated(ctx context.Context, skip, limit int64, filter map[string]interface{}, sort bson.D) ([]model.Produto, error)
+	FindAllPaginated(ctx context.Context, skip, limit int64, filter map[string]interface{}, sort bson.D, fields ...string) ([]model.Produto, error)
 	Count(ctx context.Context, filter map[string]interface{}) (int64, error)
+	// Métodos para a lixeira (produtos com soft delete)
+	Restore(ctx context.Context, id int) (ProdutoChange, error)
+	FindDeletedByID(ctx context.Context, id int) (model.Produto, error)
+	FindDeletedPaginated(ctx context.Context, skip, limit int64, sort bson.D) ([]model.Produto, error)
+	CountDeleted(ctx context.Context, filter map[string]interface{}) (int64, error)
+	PurgeDeleted(ctx context.Context, before time.Time) (int64, error)
+	// Stream percorre os produtos que correspondem ao filtro direto do cursor, sem
+	// carregar o resultado em memória; um erro retornado por fn interrompe a leitura
+	Stream(ctx context.Context, filter map[string]interface{}, sort bson.D, fn func(model.Produto) error) error
+	// BulkWrite aplica várias operações em uma única ida ao banco
+	// Com atomic = true, todas as operações são aplicadas em uma transação (tudo ou nada)
+	BulkWrite(ctx context.Context, ops []BulkOperation, atomic bool) ([]BulkResult, error)
+	// Facets calcula estatísticas e contagens dos produtos que correspondem ao filtro
+	Facets(ctx context.Context, filter map[string]interface{}, opts FacetOptions) (model.ProdutoFacets, error)
+	// Estoque: atualizações condicionais ($inc) que nunca deixam o estoque disponível negativo
+	AdjustStock(ctx context.Context, id int, delta int) (model.Produto, error)
+	Reserve(ctx context.Context, id int, reserva model.Reserva) (model.Produto, error)
+	Release(ctx context.Context, id int, reservaID string) (model.Produto, error)
+	FindExpiredReservations(ctx context.Context, before time.Time, limit int) ([]ReservaRef, error)
+	// Histórico de preços: gravado por Update e Patch quando o preço muda
+	// filter restringe o período (changed_at); a ordem é da alteração mais recente para a mais antiga
+	FindPriceHistory(ctx context.Context, produtoID int, filter map[string]interface{}, skip, limit int64) ([]model.PriceChange, error)
+	CountPriceHistory(ctx context.Context, produtoID int, filter map[string]interface{}) (int64, error)
 }
 
@@ -0,0 +1,372 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"api-go-arquitetura/internal/database"
+	"api-go-arquitetura/internal/model"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// BulkOperationType identifica o tipo de uma operação em lote
+type BulkOperationType string
+
+// Tipos de operação suportados por BulkWrite
+const (
+	BulkInsert  BulkOperationType = "insert"
+	BulkReplace BulkOperationType = "replace"
+	BulkPatch   BulkOperationType = "patch"
+	BulkDelete  BulkOperationType = "delete"
+)
+
+// ErrBulkAborted indica que a operação não foi aplicada porque outra operação
+// do mesmo lote atômico falhou
+var ErrBulkAborted = errors.New("bulk aborted")
+
+// errBulkRollback força o rollback da transação quando alguma operação do lote falha
+var errBulkRollback = errors.New("bulk rollback")
+
+// BulkOperation descreve uma operação de escrita em lote
+// Produto é usado por BulkInsert e BulkReplace, Updates por BulkPatch
+// ExpectedVersion != nil habilita o controle de concorrência otimista
+type BulkOperation struct {
+	Type            BulkOperationType
+	ID              int
+	Produto         model.Produto
+	Updates         map[string]interface{}
+	ExpectedVersion *int
+}
+
+// BulkResult é o resultado de uma operação em lote, na mesma posição da operação
+// Before é o produto sobre o qual a operação foi aplicada (vazio em inserções)
+// Err é nil quando a operação foi aplicada
+type BulkResult struct {
+	Produto model.Produto
+	Before  model.Produto
+	Err     error
+}
+
+// BulkWrite aplica as operações usando o BulkWrite do MongoDB
+// Sem atomic, cada operação é independente e as falhas são reportadas por item
+// Com atomic, as operações são aplicadas em uma transação; se alguma falhar,
+// nenhuma é aplicada e as demais recebem ErrBulkAborted (requer replica set)
+// O erro retornado indica uma falha do lote inteiro (ex.: conexão com o banco)
+func (r *mongoProdutoRepository) BulkWrite(ctx context.Context, ops []BulkOperation, atomic bool) ([]BulkResult, error) {
+	// Copiar as operações para não alterar o slice do chamador
+	ops = append([]BulkOperation(nil), ops...)
+
+	// Os IDs são alocados fora da transação, como em Create: um rollback deixa
+	// lacunas na sequência, mas nunca reutiliza um ID
+	for i := range ops {
+		if ops[i].Type != BulkInsert {
+			continue
+		}
+		id, err := r.ids.NextID(ctx)
+		if err != nil {
+			return nil, err
+		}
+		ops[i].Produto.ID = id
+	}
+
+	if !atomic {
+		if r.outbox == nil {
+			existing, err := r.findExisting(ctx, ops)
+			if err != nil {
+				return nil, err
+			}
+			if !bulkChangesPrice(ops, existing) {
+				return r.bulkWrite(ctx, ops, false)
+			}
+			if !r.supportsTransactions(ctx) {
+				// Sem replica set, o histórico de preços é gravado após o lote
+				results, err := r.bulkWrite(ctx, ops, false)
+				if err != nil {
+					return nil, err
+				}
+				r.recordBulkPriceChanges(ctx, ops, results, existing)
+				return results, nil
+			}
+		}
+		// Com o outbox ou alterações de preço, cada operação é aplicada em sua
+		// própria transação junto com os seus eventos e o histórico de preços,
+		// mantendo as operações independentes entre si
+		results := make([]BulkResult, len(ops))
+		for i := range ops {
+			res, err := r.bulkWriteTx(ctx, ops[i:i+1])
+			if err != nil {
+				return nil, err
+			}
+			results[i] = res[0]
+		}
+		return results, nil
+	}
+	return r.bulkWriteTx(ctx, ops)
+}
+
+// bulkWriteTx aplica as operações em uma transação; se alguma falhar, nenhuma é
+// aplicada e as demais recebem ErrBulkAborted. As alterações de preço e, com o
+// outbox habilitado, os eventos das operações são gravados na mesma transação
+func (r *mongoProdutoRepository) bulkWriteTx(ctx context.Context, ops []BulkOperation) ([]BulkResult, error) {
+	tx, cancel, err := database.StartTransaction(ctx, r.Collection.Database().Client())
+	if err != nil {
+		return nil, err
+	}
+	defer cancel()
+	defer tx.End()
+
+	var results []BulkResult
+	err = tx.WithTransaction(func(sc mongo.SessionContext) error {
+		before, err := r.findExisting(sc, ops)
+		if err != nil {
+			return err
+		}
+		results, err = r.bulkWrite(sc, ops, true)
+		if err != nil {
+			return err
+		}
+		if abortBulkResults(results) {
+			return errBulkRollback
+		}
+		return r.recordBulkChanges(sc, ops, results, before)
+	})
+	if err != nil && err != errBulkRollback {
+		return nil, err
+	}
+	return results, nil
+}
+
+// bulkWrite monta e executa os modelos de escrita. As versões atuais são lidas
+// antes da escrita para validar expectedVersion e preservar created_at; cada
+// escrita é condicionada à versão lida, de modo que alterações concorrentes
+// resultam em ErrVersionConflict
+func (r *mongoProdutoRepository) bulkWrite(ctx context.Context, ops []BulkOperation, ordered bool) ([]BulkResult, error) {
+	results := make([]BulkResult, len(ops))
+
+	existing, err := r.findExisting(ctx, ops)
+	if err != nil {
+		return nil, err
+	}
+
+	models := make([]mongo.WriteModel, 0, len(ops))
+	opIndex := make([]int, 0, len(ops)) // posição da operação de cada modelo
+	now := time.Now()
+
+	for i, op := range ops {
+		if op.Type == BulkInsert {
+			produto := op.Produto
+			produto.BeforeCreate()
+			results[i].Produto = produto
+			models = append(models, mongo.NewInsertOneModel().SetDocument(produto))
+			opIndex = append(opIndex, i)
+			continue
+		}
+
+		current, ok := existing[op.ID]
+		if !ok {
+			results[i].Err = errors.New("not found")
+			continue
+		}
+		if op.ExpectedVersion != nil && current.Version != *op.ExpectedVersion {
+			results[i].Err = ErrVersionConflict
+			continue
+		}
+
+		filter := bson.M{
+			"id":         op.ID,
+			"deleted_at": bson.M{"$exists": false},
+			"version":    versionFilter(current.Version),
+		}
+		// A escrita é condicionada à versão lida: current é o produto alterado
+		results[i].Before = current
+
+		switch op.Type {
+		case BulkReplace:
+			produto := op.Produto
+			produto.ID = op.ID
+			produto.BeforeUpdate()
+			produto.CreatedAt = current.CreatedAt // Preservar CreatedAt
+			produto.KeepStock(current)            // Preservar estoque e reservas
+			produto.Version = current.Version + 1
+			results[i].Produto = produto
+			models = append(models, mongo.NewReplaceOneModel().SetFilter(filter).SetReplacement(produto))
+		case BulkPatch:
+			updates := make(bson.M, len(op.Updates)+1)
+			for k, v := range op.Updates {
+				updates[k] = v
+			}
+			updates["updated_at"] = now
+			update := bson.M{"$set": updates, "$inc": bson.M{"version": 1}}
+			models = append(models, mongo.NewUpdateOneModel().SetFilter(filter).SetUpdate(update))
+		case BulkDelete:
+			deleted := current
+			deleted.DeletedAt = &now
+			deleted.UpdatedAt = now
+			deleted.Version = current.Version + 1
+			results[i].Produto = deleted
+			update := bson.M{
+				"$set": bson.M{"deleted_at": now, "updated_at": now},
+				"$inc": bson.M{"version": 1},
+			}
+			models = append(models, mongo.NewUpdateOneModel().SetFilter(filter).SetUpdate(update))
+		default:
+			results[i].Err = errors.New("invalid bulk operation: " + string(op.Type))
+			continue
+		}
+		opIndex = append(opIndex, i)
+	}
+
+	// No modo atômico não há por que escrever se alguma operação já falhou
+	if len(models) == 0 || (ordered && hasBulkFailure(results)) {
+		return results, nil
+	}
+
+	// Sem retry: uma nova tentativa após falha de rede poderia reaplicar parte do lote
+	opts := options.BulkWrite().SetOrdered(ordered)
+	res, err := r.Collection.BulkWrite(ctx, models, opts)
+
+	failed := make(map[int]bool)
+	if err != nil {
+		var bwe mongo.BulkWriteException
+		if !errors.As(err, &bwe) || len(bwe.WriteErrors) == 0 {
+			return nil, err
+		}
+		for _, we := range bwe.WriteErrors {
+			i := opIndex[we.Index]
+			results[i].Err = errors.New(we.Message)
+			failed[i] = true
+		}
+		if ordered {
+			// Em um lote ordenado, os modelos após a falha não foram executados
+			first := bwe.WriteErrors[0].Index
+			for _, i := range opIndex[first+1:] {
+				results[i].Err = ErrBulkAborted
+				failed[i] = true
+			}
+		}
+	}
+
+	// Quando algum filtro de versão não encontrou documento, ou quando há patches
+	// (cujo documento final não é conhecido), reler os produtos afetados
+	applied := 0
+	if res != nil {
+		applied = int(res.InsertedCount + res.MatchedCount)
+	}
+	verify := applied != len(models)-len(failed)
+	needsReload := verify
+	for _, op := range ops {
+		if op.Type == BulkPatch {
+			needsReload = true
+		}
+	}
+	if !needsReload {
+		return results, nil
+	}
+
+	reloaded, err := r.findByIDs(ctx, ops, bson.M{})
+	if err != nil {
+		return nil, err
+	}
+	for _, i := range opIndex {
+		op := ops[i]
+		if failed[i] || op.Type == BulkInsert {
+			continue
+		}
+		doc, ok := reloaded[op.ID]
+		expectedVersion := existing[op.ID].Version + 1
+		if verify && (!ok || doc.Version != expectedVersion) {
+			results[i].Err = ErrVersionConflict
+			continue
+		}
+		if op.Type == BulkPatch {
+			results[i].Produto = doc
+		}
+	}
+	return results, nil
+}
+
+// findExisting retorna, indexados por ID, os produtos não removidos referenciados pelas operações
+func (r *mongoProdutoRepository) findExisting(ctx context.Context, ops []BulkOperation) (map[int]model.Produto, error) {
+	return r.findByIDs(ctx, ops, bson.M{"deleted_at": bson.M{"$exists": false}})
+}
+
+// findByIDs busca os produtos referenciados pelas operações (exceto inserções)
+func (r *mongoProdutoRepository) findByIDs(ctx context.Context, ops []BulkOperation, filter bson.M) (map[int]model.Produto, error) {
+	ids := make([]int, 0, len(ops))
+	for _, op := range ops {
+		if op.Type != BulkInsert {
+			ids = append(ids, op.ID)
+		}
+	}
+	found := make(map[int]model.Produto, len(ids))
+	if len(ids) == 0 {
+		return found, nil
+	}
+
+	filter["id"] = bson.M{"$in": ids}
+	cursor, err := r.Collection.Find(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var produtos []model.Produto
+	if err = cursor.All(ctx, &produtos); err != nil {
+		return nil, err
+	}
+	for _, p := range produtos {
+		found[p.ID] = p
+	}
+	return found, nil
+}
+
+// bulkChangesPrice indica se alguma operação altera o preço de um produto existente
+func bulkChangesPrice(ops []BulkOperation, existing map[int]model.Produto) bool {
+	for _, op := range ops {
+		current, ok := existing[op.ID]
+		if !ok {
+			continue
+		}
+		switch op.Type {
+		case BulkReplace:
+			if op.Produto.Preco != current.Preco {
+				return true
+			}
+		case BulkPatch:
+			if preco, ok := op.Updates["preco"]; ok {
+				if v, isFloat := preco.(float64); !isFloat || v != current.Preco {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// hasBulkFailure indica se alguma operação do lote falhou
+func hasBulkFailure(results []BulkResult) bool {
+	for _, res := range results {
+		if res.Err != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// abortBulkResults marca como abortadas as operações bem-sucedidas de um lote
+// atômico em que alguma operação falhou. Retorna true se o lote deve ser desfeito
+func abortBulkResults(results []BulkResult) bool {
+	if !hasBulkFailure(results) {
+		return false
+	}
+	for i := range results {
+		if results[i].Err == nil {
+			results[i] = BulkResult{Err: ErrBulkAborted}
+		}
+	}
+	return true
+}
@@ -0,0 +1,160 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"api-go-arquitetura/internal/model"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+var (
+	// ErrInsufficientStock é retornado quando a operação deixaria o estoque
+	// disponível (estoque - reservado) negativo
+	ErrInsufficientStock = errors.New("insufficient stock")
+	// ErrReservationNotFound é retornado quando a reserva não existe (ou já foi liberada)
+	ErrReservationNotFound = errors.New("reservation not found")
+	// ErrReservationExists é retornado ao reservar com um ID de reserva já usado no produto
+	ErrReservationExists = errors.New("reservation exists")
+)
+
+// ReservaRef identifica uma reserva de um produto
+type ReservaRef struct {
+	ProdutoID int
+	ReservaID string
+}
+
+// stockField retorna o campo numérico tratando documentos sem o campo como 0
+func stockField(name string) bson.M {
+	return bson.M{"$ifNull": bson.A{"$" + name, 0}}
+}
+
+// AdjustStock soma delta ao estoque (negativo para baixa). A condição e o $inc
+// são aplicados na mesma escrita: o estoque nunca fica abaixo do reservado,
+// mesmo com ajustes concorrentes
+func (r *mongoProdutoRepository) AdjustStock(ctx context.Context, id int, delta int) (model.Produto, error) {
+	filter := bson.M{
+		"id":         id,
+		"deleted_at": bson.M{"$exists": false},
+		"$expr": bson.M{"$gte": bson.A{
+			bson.M{"$add": bson.A{stockField("estoque"), delta}},
+			stockField("reservado"),
+		}},
+	}
+	update := bson.M{
+		"$inc": bson.M{"estoque": delta, "version": 1},
+		"$set": bson.M{"updated_at": time.Now()},
+	}
+	return r.updateStock(ctx, id, "", filter, update)
+}
+
+// Reserve separa reserva.Quantidade do estoque disponível
+// Falha com ErrInsufficientStock se não houver quantidade disponível
+func (r *mongoProdutoRepository) Reserve(ctx context.Context, id int, reserva model.Reserva) (model.Produto, error) {
+	filter := bson.M{
+		"id":          id,
+		"deleted_at":  bson.M{"$exists": false},
+		"reservas.id": bson.M{"$ne": reserva.ID},
+		"$expr": bson.M{"$gte": bson.A{
+			bson.M{"$subtract": bson.A{stockField("estoque"), stockField("reservado")}},
+			reserva.Quantidade,
+		}},
+	}
+	update := bson.M{
+		"$inc":  bson.M{"reservado": reserva.Quantidade, "version": 1},
+		"$push": bson.M{"reservas": reserva},
+		"$set":  bson.M{"updated_at": time.Now()},
+	}
+	return r.updateStock(ctx, id, reserva.ID, filter, update)
+}
+
+// Release libera a reserva, devolvendo a quantidade ao estoque disponível
+// Produtos na lixeira também têm as reservas liberadas
+func (r *mongoProdutoRepository) Release(ctx context.Context, id int, reservaID string) (model.Produto, error) {
+	var produto model.Produto
+	err := r.Collection.FindOne(ctx, bson.M{"id": id}).Decode(&produto)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return model.Produto{}, errors.New("not found")
+		}
+		return model.Produto{}, err
+	}
+	reserva, ok := produto.FindReserva(reservaID)
+	if !ok {
+		return model.Produto{}, ErrReservationNotFound
+	}
+
+	// A quantidade da reserva não muda, então remover o item e decrementar o
+	// reservado na mesma escrita mantém os dois consistentes
+	filter := bson.M{"id": id, "reservas": bson.M{"$elemMatch": bson.M{"id": reservaID}}}
+	update := bson.M{
+		"$inc":  bson.M{"reservado": -reserva.Quantidade, "version": 1},
+		"$pull": bson.M{"reservas": bson.M{"id": reservaID}},
+		"$set":  bson.M{"updated_at": time.Now()},
+	}
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+	var updated model.Produto
+	if err := r.Collection.FindOneAndUpdate(ctx, filter, update, opts).Decode(&updated); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return model.Produto{}, ErrReservationNotFound // Liberada por outra requisição
+		}
+		return model.Produto{}, err
+	}
+	return updated, nil
+}
+
+// FindExpiredReservations retorna até limit reservas expiradas em before
+func (r *mongoProdutoRepository) FindExpiredReservations(ctx context.Context, before time.Time, limit int) ([]ReservaRef, error) {
+	opts := options.Find().
+		SetProjection(bson.M{"id": 1, "reservas": 1}).
+		SetLimit(int64(limit))
+	cursor, err := r.Collection.Find(ctx, bson.M{"reservas.expires_at": bson.M{"$lte": before}}, opts)
+	if err != nil {
+		return nil, err
+	}
+	var produtos []model.Produto
+	if err := cursor.All(ctx, &produtos); err != nil {
+		return nil, err
+	}
+
+	var refs []ReservaRef
+	for _, p := range produtos {
+		for _, reserva := range p.Reservas {
+			if reserva.IsExpired(before) {
+				refs = append(refs, ReservaRef{ProdutoID: p.ID, ReservaID: reserva.ID})
+			}
+		}
+	}
+	return refs, nil
+}
+
+// updateStock aplica a atualização condicional de estoque e, se nenhum documento
+// corresponder, identifica o motivo
+func (r *mongoProdutoRepository) updateStock(ctx context.Context, id int, reservaID string, filter, update bson.M) (model.Produto, error) {
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+	var updated model.Produto
+	err := r.Collection.FindOneAndUpdate(ctx, filter, update, opts).Decode(&updated)
+	if err == nil {
+		return updated, nil
+	}
+	if err != mongo.ErrNoDocuments {
+		return model.Produto{}, err
+	}
+
+	var current model.Produto
+	err = r.Collection.FindOne(ctx, bson.M{"id": id, "deleted_at": bson.M{"$exists": false}}).Decode(&current)
+	if err == mongo.ErrNoDocuments {
+		return model.Produto{}, errors.New("not found")
+	}
+	if err != nil {
+		return model.Produto{}, err
+	}
+	if _, exists := current.FindReserva(reservaID); reservaID != "" && exists {
+		return model.Produto{}, ErrReservationExists
+	}
+	return model.Produto{}, ErrInsufficientStock
+}
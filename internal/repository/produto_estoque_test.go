@@ -0,0 +1,99 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"api-go-arquitetura/internal/model"
+)
+
+// TestProdutoRepository_ReserveConcurrent dispara mais reservas do que o estoque
+// comporta e verifica que o produto nunca é vendido além do estoque
+func TestProdutoRepository_ReserveConcurrent(t *testing.T) {
+	col := newIntegrationCollection(t)
+	ctx := context.Background()
+	repo := NewProdutoRepository(col)
+
+	produto, err := repo.Create(ctx, model.Produto{Nome: "Ingresso", Preco: 100, Estoque: 10})
+	if err != nil {
+		t.Fatalf("Erro ao criar produto: %v", err)
+	}
+
+	const total = 50
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	reserved := 0
+	for i := 0; i < total; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			reserva := model.Reserva{
+				ID:         fmt.Sprintf("pedido-%d", i),
+				Quantidade: 1,
+				ExpiresAt:  time.Now().Add(time.Minute),
+			}
+			_, err := repo.Reserve(ctx, produto.ID, reserva)
+			switch err {
+			case nil:
+				mu.Lock()
+				reserved++
+				mu.Unlock()
+			case ErrInsufficientStock:
+			default:
+				t.Errorf("Erro inesperado ao reservar: %v", err)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	if reserved != 10 {
+		t.Errorf("Esperadas 10 reservas, obtidas %d", reserved)
+	}
+	result, err := repo.FindByID(ctx, produto.ID)
+	if err != nil {
+		t.Fatalf("Erro ao buscar produto: %v", err)
+	}
+	if result.Reservado != 10 || len(result.Reservas) != 10 || result.Disponivel() != 0 {
+		t.Errorf("Estoque inconsistente: estoque=%d reservado=%d reservas=%d",
+			result.Estoque, result.Reservado, len(result.Reservas))
+	}
+
+	t.Run("não deve baixar o estoque abaixo do reservado", func(t *testing.T) {
+		if _, err := repo.AdjustStock(ctx, produto.ID, -1); err != ErrInsufficientStock {
+			t.Errorf("Esperado ErrInsufficientStock, obtido %v", err)
+		}
+	})
+
+	t.Run("deve preservar o estoque na atualização completa", func(t *testing.T) {
+		change, err := repo.Update(ctx, produto.ID, model.Produto{Nome: "Ingresso VIP", Preco: 200}, nil)
+		if err != nil {
+			t.Fatalf("Erro inesperado: %v", err)
+		}
+		if updated := change.After; updated.Estoque != 10 || updated.Reservado != 10 {
+			t.Errorf("Estoque alterado pela atualização: %+v", change.After)
+		}
+	})
+
+	t.Run("deve liberar reservas expiradas", func(t *testing.T) {
+		refs, err := repo.FindExpiredReservations(ctx, time.Now().Add(time.Hour), 100)
+		if err != nil {
+			t.Fatalf("Erro inesperado: %v", err)
+		}
+		if len(refs) != 10 {
+			t.Fatalf("Esperadas 10 reservas expiradas, obtidas %d", len(refs))
+		}
+		released, err := repo.Release(ctx, produto.ID, refs[0].ReservaID)
+		if err != nil {
+			t.Fatalf("Erro inesperado: %v", err)
+		}
+		if released.Reservado != 9 || released.Disponivel() != 1 {
+			t.Errorf("Reserva não liberada: reservado=%d", released.Reservado)
+		}
+		if _, err := repo.Release(ctx, produto.ID, refs[0].ReservaID); err != ErrReservationNotFound {
+			t.Errorf("Esperado ErrReservationNotFound, obtido %v", err)
+		}
+	})
+}
@@ -0,0 +1,161 @@
+package repository
+
+import (
+	"context"
+
+	"api-go-arquitetura/internal/database"
+	"api-go-arquitetura/internal/model"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// FacetOptions seleciona as facetas calculadas por Facets
+type FacetOptions struct {
+	Stats     bool // min, max, média e total de preco
+	Preco     bool // histograma de preço
+	Buckets   int  // número de faixas do histograma
+	Tags      bool // contagem por tag
+	TagsLimit int  // máximo de tags retornadas (as mais frequentes)
+	// Contagem por categoria (produtos sem categoria não são contados)
+	Categorias      bool
+	CategoriasLimit int // máximo de categorias retornadas (as mais frequentes)
+}
+
+// defaultTagsLimit é usado quando FacetOptions.TagsLimit não é informado
+const defaultTagsLimit = 50
+
+// defaultCategoriasLimit é usado quando FacetOptions.CategoriasLimit não é informado
+const defaultCategoriasLimit = 50
+
+// facetResult é o documento retornado pelo estágio $facet
+type facetResult struct {
+	Stats []model.PrecoStats `bson:"stats"`
+	Preco []struct {
+		ID struct {
+			Min float64 `bson:"min"`
+			Max float64 `bson:"max"`
+		} `bson:"_id"`
+		Count int64 `bson:"count"`
+	} `bson:"preco"`
+	Tags []struct {
+		ID    string `bson:"_id"`
+		Count int64  `bson:"count"`
+	} `bson:"tags"`
+	Categorias []struct {
+		ID    int   `bson:"_id"`
+		Count int64 `bson:"count"`
+	} `bson:"categoria"`
+}
+
+// Facets calcula as facetas dos produtos que correspondem ao filtro em uma única
+// agregação ($match seguido de $facet)
+func (r *mongoProdutoRepository) Facets(ctx context.Context, filter map[string]interface{}, opts FacetOptions) (model.ProdutoFacets, error) {
+	match := bson.M{}
+	for k, v := range filter {
+		match[k] = v
+	}
+	// Filtrar produtos deletados (soft delete)
+	match["deleted_at"] = bson.M{"$exists": false}
+
+	facets := bson.M{}
+	if opts.Stats {
+		facets["stats"] = bson.A{
+			bson.M{"$group": bson.M{
+				"_id":   nil,
+				"count": bson.M{"$sum": 1},
+				"min":   bson.M{"$min": "$preco"},
+				"max":   bson.M{"$max": "$preco"},
+				"avg":   bson.M{"$avg": "$preco"},
+			}},
+		}
+	}
+	if opts.Preco {
+		buckets := opts.Buckets
+		if buckets < 1 {
+			buckets = 10
+		}
+		facets["preco"] = bson.A{
+			bson.M{"$bucketAuto": bson.M{"groupBy": "$preco", "buckets": buckets}},
+		}
+	}
+	if opts.Tags {
+		limit := opts.TagsLimit
+		if limit < 1 {
+			limit = defaultTagsLimit
+		}
+		facets["tags"] = bson.A{
+			bson.M{"$unwind": "$tags"},
+			bson.M{"$group": bson.M{"_id": "$tags", "count": bson.M{"$sum": 1}}},
+			bson.M{"$sort": bson.D{{Key: "count", Value: -1}, {Key: "_id", Value: 1}}},
+			bson.M{"$limit": limit},
+		}
+	}
+	if opts.Categorias {
+		limit := opts.CategoriasLimit
+		if limit < 1 {
+			limit = defaultCategoriasLimit
+		}
+		facets["categoria"] = bson.A{
+			bson.M{"$match": bson.M{"categoria_id": bson.M{"$gt": 0}}},
+			bson.M{"$group": bson.M{"_id": "$categoria_id", "count": bson.M{"$sum": 1}}},
+			bson.M{"$sort": bson.D{{Key: "count", Value: -1}, {Key: "_id", Value: 1}}},
+			bson.M{"$limit": limit},
+		}
+	}
+	if len(facets) == 0 {
+		return model.ProdutoFacets{}, nil
+	}
+
+	// $match precisa ser o primeiro estágio para usar o índice de texto ($text)
+	pipeline := bson.A{bson.M{"$match": match}, bson.M{"$facet": facets}}
+
+	return database.RetryWithResult(ctx, func() (model.ProdutoFacets, error) {
+		cursor, err := r.Collection.Aggregate(ctx, pipeline)
+		if err != nil {
+			return model.ProdutoFacets{}, err
+		}
+		defer cursor.Close(ctx)
+
+		var result facetResult
+		if cursor.Next(ctx) {
+			if err := cursor.Decode(&result); err != nil {
+				return model.ProdutoFacets{}, err
+			}
+		}
+		if err := cursor.Err(); err != nil {
+			return model.ProdutoFacets{}, err
+		}
+		return newProdutoFacets(result, opts), nil
+	}, database.DefaultRetryOptions())
+}
+
+// newProdutoFacets converte o resultado da agregação
+// Sem produtos, a faceta stats é retornada zerada
+func newProdutoFacets(result facetResult, opts FacetOptions) model.ProdutoFacets {
+	var facets model.ProdutoFacets
+	if opts.Stats {
+		facets.Stats = &model.PrecoStats{}
+		if len(result.Stats) > 0 {
+			*facets.Stats = result.Stats[0]
+		}
+	}
+	if opts.Preco {
+		facets.Preco = make([]model.PrecoBucket, 0, len(result.Preco))
+		for _, b := range result.Preco {
+			facets.Preco = append(facets.Preco, model.PrecoBucket{Min: b.ID.Min, Max: b.ID.Max, Count: b.Count})
+		}
+	}
+	if opts.Tags {
+		facets.Tags = make([]model.FacetCount, 0, len(result.Tags))
+		for _, t := range result.Tags {
+			facets.Tags = append(facets.Tags, model.FacetCount{Value: t.ID, Count: t.Count})
+		}
+	}
+	if opts.Categorias {
+		facets.Categoria = make([]model.CategoriaFacetCount, 0, len(result.Categorias))
+		for _, c := range result.Categorias {
+			facets.Categoria = append(facets.Categoria, model.CategoriaFacetCount{CategoriaID: c.ID, Count: c.Count})
+		}
+	}
+	return facets
+}
@@ -0,0 +1,227 @@
+package repository
+
+import (
+	"context"
+
+	"api-go-arquitetura/internal/database"
+	"api-go-arquitetura/internal/events"
+	"api-go-arquitetura/internal/logger"
+	"api-go-arquitetura/internal/model"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// NewProdutoRepositoryWithOutbox cria uma nova instância do ProdutoRepository que
+// grava os eventos de domínio no outbox, na mesma transação de cada alteração
+// (requer replica set)
+func NewProdutoRepositoryWithOutbox(col *mongo.Collection, ids IDAllocator, outbox *events.MongoOutbox) ProdutoRepository {
+	return &mongoProdutoRepository{Collection: col, ids: ids, outbox: outbox}
+}
+
+// produtoWrite aplica uma escrita de produto e retorna o produto antes e depois
+// dela (before é nil quando não existe ou não é conhecido, como na criação)
+type produtoWrite func(ctx context.Context) (before, after *model.Produto, err error)
+
+// Estados de txSupport
+const (
+	txUnknown int32 = iota
+	txSupported
+	txUnsupported
+)
+
+// write aplica a escrita junto com o que ela gera além do próprio documento: o
+// registro da alteração de preço e, com o outbox habilitado, os eventos de domínio
+// Quando o preço muda ou o outbox está habilitado, tudo é gravado em uma
+// transação: ou todos, ou nenhum. Sem replica set e sem o outbox, o histórico de
+// preços é gravado após a escrita, sem transação (ver recordPriceChange)
+func (r *mongoProdutoRepository) write(ctx context.Context, eventType string, priceChanged bool, fn produtoWrite) error {
+	if r.outbox == nil && (!priceChanged || !r.supportsTransactions(ctx)) {
+		before, after, err := fn(ctx)
+		if err != nil {
+			return err
+		}
+		r.recordPriceChange(ctx, before, after)
+		return nil
+	}
+
+	tx, cancel, err := database.StartTransaction(ctx, r.Collection.Database().Client())
+	if err != nil {
+		return err
+	}
+	defer cancel()
+	defer tx.End()
+
+	return tx.WithTransaction(func(sc mongo.SessionContext) error {
+		before, after, err := fn(sc)
+		if err != nil {
+			return err
+		}
+		return r.recordChange(sc, eventType, before, after)
+	})
+}
+
+// supportsTransactions indica se o servidor aceita transações, consultando-o
+// apenas na primeira vez. Se a consulta falhar, assume que sim: o erro é
+// reportado pela própria transação
+func (r *mongoProdutoRepository) supportsTransactions(ctx context.Context) bool {
+	switch r.txSupport.Load() {
+	case txSupported:
+		return true
+	case txUnsupported:
+		return false
+	}
+
+	ok, err := database.SupportsTransactions(ctx, r.Collection.Database().Client())
+	if err != nil {
+		return true
+	}
+	if ok {
+		r.txSupport.Store(txSupported)
+	} else {
+		r.txSupport.Store(txUnsupported)
+		logger.Warn("MongoDB sem suporte a transações (requer replica set): o histórico de preços será gravado fora da transação da alteração")
+	}
+	return ok
+}
+
+// recordPriceChange grava o registro da alteração de preço de uma escrita feita
+// fora de transação. A alteração já foi aplicada: uma falha aqui é apenas registrada
+// no log (o histórico pode ficar incompleto)
+func (r *mongoProdutoRepository) recordPriceChange(ctx context.Context, before, after *model.Produto) {
+	if before == nil || after == nil {
+		return
+	}
+	change := newPriceChange(ctx, *before, *after)
+	if change == nil {
+		return
+	}
+	if _, err := r.priceHistory().InsertOne(ctx, change); err != nil {
+		logger.WithFields(map[string]interface{}{
+			"produto_id": after.ID,
+			"version":    after.Version,
+			"error":      err.Error(),
+		}).Error("Erro ao registrar alteração de preço")
+	}
+}
+
+// recordChange grava o registro da alteração de preço e os eventos da escrita
+func (r *mongoProdutoRepository) recordChange(ctx context.Context, eventType string, before, after *model.Produto) error {
+	if before != nil && after != nil {
+		if change := newPriceChange(ctx, *before, *after); change != nil {
+			if _, err := r.priceHistory().InsertOne(ctx, change); err != nil {
+				return err
+			}
+		}
+	}
+	if r.outbox == nil {
+		return nil
+	}
+	evs, err := produtoEvents(ctx, eventType, before, after)
+	if err != nil {
+		return err
+	}
+	return r.outbox.Add(ctx, evs...)
+}
+
+// recordBulkChanges grava as alterações de preço e os eventos das operações
+// aplicadas de um lote
+// before são os produtos lidos antes da escrita, na mesma transação
+func (r *mongoProdutoRepository) recordBulkChanges(ctx context.Context, ops []BulkOperation, results []BulkResult, before map[int]model.Produto) error {
+	after, err := r.findByIDs(ctx, ops, bson.M{})
+	if err != nil {
+		return err
+	}
+
+	if changes := bulkPriceChanges(ctx, ops, results, before, after); len(changes) > 0 {
+		if _, err := r.priceHistory().InsertMany(ctx, changes); err != nil {
+			return err
+		}
+	}
+	if r.outbox == nil {
+		return nil
+	}
+
+	var evs []events.Event
+	for i, op := range ops {
+		if results[i].Err != nil {
+			continue
+		}
+		var opEvents []events.Event
+		if op.Type == BulkInsert {
+			produto := results[i].Produto
+			opEvents, err = produtoEvents(ctx, events.ProdutoCriado, nil, &produto)
+		} else {
+			prev, current := before[op.ID], after[op.ID]
+			opEvents, err = produtoEvents(ctx, bulkEventType(op.Type), &prev, &current)
+		}
+		if err != nil {
+			return err
+		}
+		evs = append(evs, opEvents...)
+	}
+	return r.outbox.Add(ctx, evs...)
+}
+
+// recordBulkPriceChanges grava as alterações de preço de um lote aplicado fora
+// de transação; como em recordPriceChange, uma falha é apenas registrada no log
+func (r *mongoProdutoRepository) recordBulkPriceChanges(ctx context.Context, ops []BulkOperation, results []BulkResult, before map[int]model.Produto) {
+	after, err := r.findByIDs(ctx, ops, bson.M{})
+	if err == nil {
+		if changes := bulkPriceChanges(ctx, ops, results, before, after); len(changes) > 0 {
+			_, err = r.priceHistory().InsertMany(ctx, changes)
+		}
+	}
+	if err != nil {
+		logger.WithField("error", err.Error()).Error("Erro ao registrar alterações de preço do lote")
+	}
+}
+
+// bulkPriceChanges monta os registros de alteração de preço das operações
+// aplicadas de um lote (substituições e patches)
+func bulkPriceChanges(ctx context.Context, ops []BulkOperation, results []BulkResult, before, after map[int]model.Produto) []interface{} {
+	var changes []interface{}
+	for i, op := range ops {
+		if results[i].Err != nil || (op.Type != BulkReplace && op.Type != BulkPatch) {
+			continue
+		}
+		prev, ok := before[op.ID]
+		current, found := after[op.ID]
+		if !ok || !found {
+			continue
+		}
+		if change := newPriceChange(ctx, prev, current); change != nil {
+			changes = append(changes, change)
+		}
+	}
+	return changes
+}
+
+// bulkEventType retorna o tipo de evento de uma operação em lote (exceto inserção)
+func bulkEventType(opType BulkOperationType) string {
+	if opType == BulkDelete {
+		return events.ProdutoRemovido
+	}
+	return events.ProdutoAtualizado
+}
+
+// produtoEvents monta os eventos de uma escrita: o evento do tipo informado, com
+// o produto resultante como payload, e PrecoAlterado quando o preço mudou
+func produtoEvents(ctx context.Context, eventType string, before, after *model.Produto) ([]events.Event, error) {
+	event, err := events.New(ctx, eventType, events.AggregateProduto, after.ID, after.Version, after)
+	if err != nil {
+		return nil, err
+	}
+	evs := []events.Event{event}
+
+	if before != nil {
+		if change := newPriceChange(ctx, *before, *after); change != nil {
+			priceEvent, err := events.New(ctx, events.PrecoAlterado, events.AggregateProduto, after.ID, after.Version, change)
+			if err != nil {
+				return nil, err
+			}
+			evs = append(evs, priceEvent)
+		}
+	}
+	return evs, nil
+}
@@ -0,0 +1,105 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"api-go-arquitetura/internal/events"
+	"api-go-arquitetura/internal/model"
+)
+
+// TestProdutoRepository_Outbox verifica que os eventos de domínio são gravados
+// no outbox na mesma transação das alterações (requer replica set)
+func TestProdutoRepository_Outbox(t *testing.T) {
+	col := newIntegrationCollection(t)
+	ctx := context.Background()
+	outbox := events.NewMongoOutbox(col.Database())
+	store := NewMongoCounterStore(col.Database().Collection("counters"))
+	repo := NewProdutoRepositoryWithOutbox(col, NewSequentialAllocator(store, ProdutoCounterName), outbox)
+
+	// pending retorna os tipos dos eventos pendentes, dos mais antigos para os mais recentes
+	pending := func(t *testing.T) []string {
+		t.Helper()
+		messages, err := outbox.Claim(ctx, time.Now(), 0, 100)
+		if err != nil {
+			t.Fatalf("Erro ao ler o outbox: %v", err)
+		}
+		types := make([]string, len(messages))
+		for i, msg := range messages {
+			types[i] = msg.Type
+			_ = outbox.MarkPublished(ctx, msg.ID, time.Now())
+		}
+		return types
+	}
+	assertTypes := func(t *testing.T, got []string, want ...string) {
+		t.Helper()
+		if len(got) != len(want) {
+			t.Fatalf("Esperados os eventos %v, obtidos %v", want, got)
+		}
+		for i := range want {
+			if got[i] != want[i] {
+				t.Fatalf("Esperados os eventos %v, obtidos %v", want, got)
+			}
+		}
+	}
+
+	produto, err := repo.Create(ctx, model.Produto{Nome: "Notebook", Preco: 3500})
+	if err != nil {
+		t.Fatalf("Erro ao criar produto: %v", err)
+	}
+	assertTypes(t, pending(t), events.ProdutoCriado)
+
+	t.Run("deve gravar PrecoAlterado quando o preço muda", func(t *testing.T) {
+		if _, err := repo.Patch(ctx, produto.ID, map[string]interface{}{"nome": "Notebook Pro"}, nil); err != nil {
+			t.Fatalf("Erro inesperado no Patch: %v", err)
+		}
+		if _, err := repo.Update(ctx, produto.ID, model.Produto{Nome: "Notebook Pro", Preco: 3300}, nil); err != nil {
+			t.Fatalf("Erro inesperado no Update: %v", err)
+		}
+		assertTypes(t, pending(t), events.ProdutoAtualizado, events.ProdutoAtualizado, events.PrecoAlterado)
+	})
+
+	t.Run("não deve gravar eventos quando a alteração falha", func(t *testing.T) {
+		if _, err := repo.Patch(ctx, produto.ID, map[string]interface{}{"preco": 10.0}, intPtr(1)); err != ErrVersionConflict {
+			t.Fatalf("Esperado ErrVersionConflict, obtido %v", err)
+		}
+		assertTypes(t, pending(t))
+	})
+
+	t.Run("deve gravar remoção e restauração", func(t *testing.T) {
+		if _, err := repo.Delete(ctx, produto.ID, nil); err != nil {
+			t.Fatalf("Erro inesperado no Delete: %v", err)
+		}
+		if _, err := repo.Restore(ctx, produto.ID); err != nil {
+			t.Fatalf("Erro inesperado no Restore: %v", err)
+		}
+		assertTypes(t, pending(t), events.ProdutoRemovido, events.ProdutoRestaurado)
+	})
+
+	t.Run("deve gravar os eventos de um lote atômico", func(t *testing.T) {
+		ops := []BulkOperation{
+			{Type: BulkInsert, Produto: model.Produto{Nome: "Mouse", Preco: 80}},
+			{Type: BulkPatch, ID: produto.ID, Updates: map[string]interface{}{"preco": 3000.0}},
+		}
+		results, err := repo.BulkWrite(ctx, ops, true)
+		if err != nil {
+			t.Fatalf("Erro inesperado: %v", err)
+		}
+		for i, res := range results {
+			if res.Err != nil {
+				t.Fatalf("Operação %d falhou: %v", i, res.Err)
+			}
+		}
+		assertTypes(t, pending(t), events.ProdutoCriado, events.ProdutoAtualizado, events.PrecoAlterado)
+
+		ops = []BulkOperation{
+			{Type: BulkDelete, ID: produto.ID},
+			{Type: BulkDelete, ID: 999999},
+		}
+		if _, err := repo.BulkWrite(ctx, ops, true); err != nil {
+			t.Fatalf("Erro inesperado: %v", err)
+		}
+		assertTypes(t, pending(t))
+	})
+}
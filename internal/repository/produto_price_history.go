@@ -0,0 +1,71 @@
+package repository
+
+import (
+	"context"
+
+	"api-go-arquitetura/internal/model"
+	"api-go-arquitetura/internal/utils"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// PriceHistoryCollection é a coleção do histórico de preços, no mesmo banco dos produtos
+const PriceHistoryCollection = "produto_price_history"
+
+// priceHistory retorna a coleção do histórico de preços
+func (r *mongoProdutoRepository) priceHistory() *mongo.Collection {
+	return r.Collection.Database().Collection(PriceHistoryCollection)
+}
+
+// newPriceChange monta o registro da alteração de preço de before para after
+// Retorna nil quando o preço não mudou
+func newPriceChange(ctx context.Context, before, after model.Produto) *model.PriceChange {
+	if before.Preco == after.Preco {
+		return nil
+	}
+	return &model.PriceChange{
+		ProdutoID:     after.ID,
+		PrecoAnterior: before.Preco,
+		PrecoNovo:     after.Preco,
+		Version:       after.Version,
+		RequestID:     utils.RequestIDFromContext(ctx),
+		ChangedAt:     after.UpdatedAt,
+	}
+}
+
+// FindPriceHistory retorna as alterações de preço do produto, mais recentes primeiro
+// filter restringe o período (changed_at)
+func (r *mongoProdutoRepository) FindPriceHistory(ctx context.Context, produtoID int, filter map[string]interface{}, skip, limit int64) ([]model.PriceChange, error) {
+	opts := options.Find().
+		SetSkip(skip).
+		SetLimit(limit).
+		SetSort(bson.D{{Key: "changed_at", Value: -1}, {Key: "version", Value: -1}})
+
+	cursor, err := r.priceHistory().Find(ctx, priceHistoryFilter(produtoID, filter), opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	changes := []model.PriceChange{}
+	if err = cursor.All(ctx, &changes); err != nil {
+		return nil, err
+	}
+	return changes, nil
+}
+
+// CountPriceHistory conta as alterações de preço do produto no período do filtro
+func (r *mongoProdutoRepository) CountPriceHistory(ctx context.Context, produtoID int, filter map[string]interface{}) (int64, error) {
+	return r.priceHistory().CountDocuments(ctx, priceHistoryFilter(produtoID, filter))
+}
+
+// priceHistoryFilter restringe o filtro ao produto
+func priceHistoryFilter(produtoID int, filter map[string]interface{}) bson.M {
+	result := bson.M{"produto_id": produtoID}
+	for k, v := range filter {
+		result[k] = v
+	}
+	return result
+}
@@ -0,0 +1,144 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"api-go-arquitetura/internal/model"
+	"api-go-arquitetura/internal/utils"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// TestProdutoRepository_PriceHistory altera o preço por Update e Patch e verifica
+// o histórico gravado na mesma transação (requer replica set)
+func TestProdutoRepository_PriceHistory(t *testing.T) {
+	col := newIntegrationCollection(t)
+	ctx := utils.WithRequestID(context.Background(), "req-1")
+	repo := NewProdutoRepository(col)
+
+	produto, err := repo.Create(ctx, model.Produto{Nome: "Notebook", Preco: 3500})
+	if err != nil {
+		t.Fatalf("Erro ao criar produto: %v", err)
+	}
+	if _, err := repo.Update(ctx, produto.ID, model.Produto{Nome: "Notebook", Preco: 3300}, nil); err != nil {
+		t.Fatalf("Erro inesperado no Update: %v", err)
+	}
+	if _, err := repo.Patch(ctx, produto.ID, map[string]interface{}{"nome": "Notebook Pro"}, nil); err != nil {
+		t.Fatalf("Erro inesperado no Patch: %v", err)
+	}
+	change, err := repo.Patch(ctx, produto.ID, map[string]interface{}{"preco": 3100.0}, nil)
+	if err != nil {
+		t.Fatalf("Erro inesperado no Patch: %v", err)
+	}
+	patched := change.After
+	if patched.Preco != 3100 || patched.Nome != "Notebook Pro" {
+		t.Errorf("Patch deveria retornar o produto atualizado: %+v", patched)
+	}
+	if change.Before.Preco != 3300 || change.Before.Version != patched.Version-1 {
+		t.Errorf("Patch deveria retornar o produto anterior à escrita: %+v", change.Before)
+	}
+
+	changes, err := repo.FindPriceHistory(ctx, produto.ID, nil, 0, 10)
+	if err != nil {
+		t.Fatalf("Erro inesperado: %v", err)
+	}
+	if len(changes) != 2 {
+		t.Fatalf("Esperadas 2 alterações de preço, obtidas %d", len(changes))
+	}
+	if changes[0].PrecoAnterior != 3300 || changes[0].PrecoNovo != 3100 || changes[0].Version != patched.Version {
+		t.Errorf("Alteração mais recente incorreta: %+v", changes[0])
+	}
+	if changes[1].PrecoAnterior != 3500 || changes[1].RequestID != "req-1" {
+		t.Errorf("Primeira alteração incorreta: %+v", changes[1])
+	}
+
+	t.Run("não deve gravar o histórico quando o preço não muda", func(t *testing.T) {
+		if _, err := repo.Patch(ctx, produto.ID, map[string]interface{}{"preco": 3100.0}, nil); err != nil {
+			t.Fatalf("Erro inesperado no Patch: %v", err)
+		}
+		total, err := repo.CountPriceHistory(ctx, produto.ID, nil)
+		if err != nil || total != 2 {
+			t.Errorf("Esperadas 2 alterações, obtidas %d (%v)", total, err)
+		}
+	})
+
+	t.Run("não deve gravar o histórico quando a alteração falha", func(t *testing.T) {
+		_, err := repo.Patch(ctx, produto.ID, map[string]interface{}{"preco": 10.0}, intPtr(1))
+		if err != ErrVersionConflict {
+			t.Fatalf("Esperado ErrVersionConflict, obtido %v", err)
+		}
+		total, err := repo.CountPriceHistory(ctx, produto.ID, nil)
+		if err != nil || total != 2 {
+			t.Errorf("Esperadas 2 alterações, obtidas %d (%v)", total, err)
+		}
+	})
+
+	t.Run("deve filtrar pelo período", func(t *testing.T) {
+		filter := map[string]interface{}{"changed_at": bson.M{"$gte": time.Now().Add(time.Hour)}}
+		total, err := repo.CountPriceHistory(ctx, produto.ID, filter)
+		if err != nil || total != 0 {
+			t.Errorf("Esperado histórico vazio, obtidas %d alterações (%v)", total, err)
+		}
+	})
+}
+
+// TestProdutoRepository_BulkPriceHistory altera preços em lote, com e sem atomic,
+// e verifica o histórico gravado para cada produto alterado
+func TestProdutoRepository_BulkPriceHistory(t *testing.T) {
+	col := newIntegrationCollection(t)
+	ctx := utils.WithRequestID(context.Background(), "req-lote")
+	repo := NewProdutoRepository(col)
+
+	a, _ := repo.Create(ctx, model.Produto{Nome: "Notebook", Preco: 3500})
+	b, _ := repo.Create(ctx, model.Produto{Nome: "Mouse", Preco: 150})
+
+	for _, atomic := range []bool{false, true} {
+		preco := 3000.0
+		if atomic {
+			preco = 2800
+		}
+		results, err := repo.BulkWrite(ctx, []BulkOperation{
+			{Type: BulkReplace, ID: a.ID, Produto: model.Produto{Nome: "Notebook", Preco: preco}},
+			{Type: BulkPatch, ID: b.ID, Updates: map[string]interface{}{"preco": preco / 10}},
+		}, atomic)
+		if err != nil {
+			t.Fatalf("Erro inesperado (atomic=%v): %v", atomic, err)
+		}
+		for i, res := range results {
+			if res.Err != nil {
+				t.Fatalf("Operação %d falhou (atomic=%v): %v", i, atomic, res.Err)
+			}
+		}
+	}
+
+	changes, err := repo.FindPriceHistory(ctx, a.ID, nil, 0, 10)
+	if err != nil {
+		t.Fatalf("Erro inesperado: %v", err)
+	}
+	if len(changes) != 2 {
+		t.Fatalf("Esperadas 2 alterações de preço, obtidas %d", len(changes))
+	}
+	if changes[0].PrecoAnterior != 3000 || changes[0].PrecoNovo != 2800 || changes[0].RequestID != "req-lote" {
+		t.Errorf("Alteração mais recente incorreta: %+v", changes[0])
+	}
+	if changes[1].PrecoAnterior != 3500 || changes[1].PrecoNovo != 3000 {
+		t.Errorf("Primeira alteração incorreta: %+v", changes[1])
+	}
+	if total, err := repo.CountPriceHistory(ctx, b.ID, nil); err != nil || total != 2 {
+		t.Errorf("Esperadas 2 alterações do patch, obtidas %d (%v)", total, err)
+	}
+
+	t.Run("não deve gravar o histórico quando o preço não muda", func(t *testing.T) {
+		_, err := repo.BulkWrite(ctx, []BulkOperation{
+			{Type: BulkPatch, ID: a.ID, Updates: map[string]interface{}{"preco": 2800.0, "nome": "Notebook Pro"}},
+		}, false)
+		if err != nil {
+			t.Fatalf("Erro inesperado: %v", err)
+		}
+		if total, _ := repo.CountPriceHistory(ctx, a.ID, nil); total != 2 {
+			t.Errorf("Esperadas 2 alterações, obtidas %d", total)
+		}
+	})
+}
@@ -3,9 +3,11 @@ package repository
 import (
 	"context"
 	"errors"
+	"sync/atomic"
 	"time"
 
 	"api-go-arquitetura/internal/database"
+	"api-go-arquitetura/internal/events"
 	"api-go-arquitetura/internal/model"
 
 	"go.mongodb.org/mongo-driver/bson"
@@ -16,38 +18,47 @@ import (
 // mongoProdutoRepository implementa ProdutoRepository usando MongoDB
 type mongoProdutoRepository struct {
 	Collection *mongo.Collection
+	ids        IDAllocator
+	outbox     *events.MongoOutbox // nil = eventos de domínio desabilitados
+	txSupport  atomic.Int32        // txUnknown, txSupported ou txUnsupported
 }
 
 // NewProdutoRepository cria uma nova instância do ProdutoRepository
+// Os IDs são gerados pela coleção "counters" do mesmo banco de dados
 func NewProdutoRepository(col *mongo.Collection) ProdutoRepository {
-	return &mongoProdutoRepository{Collection: col}
+	store := NewMongoCounterStore(col.Database().Collection("counters"))
+	return NewProdutoRepositoryWithAllocator(col, NewSequentialAllocator(store, ProdutoCounterName))
 }
 
-func (r *mongoProdutoRepository) getNextID(ctx context.Context) (int, error) {
-	opts := options.FindOne().SetSort(bson.D{{"id", -1}})
-	var p model.Produto
-	err := r.Collection.FindOne(ctx, bson.M{}, opts).Decode(&p)
-	if err != nil {
-		if err == mongo.ErrNoDocuments {
-			return 1, nil
-		}
-		return 0, err
-	}
-	return p.ID + 1, nil
+// NewProdutoRepositoryWithAllocator cria uma nova instância do ProdutoRepository com alocador de IDs customizado
+func NewProdutoRepositoryWithAllocator(col *mongo.Collection, ids IDAllocator) ProdutoRepository {
+	return &mongoProdutoRepository{Collection: col, ids: ids}
 }
 
 func (r *mongoProdutoRepository) Create(ctx context.Context, produto model.Produto) (model.Produto, error) {
 	// Usar retry logic para operação crítica
 	retryOpts := database.DefaultRetryOptions()
+	// O ID é alocado uma única vez, fora do retry, para que uma nova tentativa
+	// após falha de rede não consuma outro valor do contador
+	id, err := r.ids.NextID(ctx)
+	if err != nil {
+		return model.Produto{}, err
+	}
+	attempt := 0
 	result, err := database.RetryWithResult(ctx, func() (model.Produto, error) {
-		id, err := r.getNextID(ctx)
-		if err != nil {
-			return model.Produto{}, err
-		}
+		attempt++
 		produto.ID = id
 		produto.BeforeCreate() // Inicializar timestamps
-		_, err = r.Collection.InsertOne(ctx, produto)
+		err := r.write(ctx, events.ProdutoCriado, false, func(ctx context.Context) (*model.Produto, *model.Produto, error) {
+			_, err := r.Collection.InsertOne(ctx, produto)
+			return nil, &produto, err
+		})
 		if err != nil {
+			// A tentativa anterior pode ter gravado o produto e falhado apenas na
+			// resposta: como o ID é o mesmo, o documento existente é o criado por ela
+			if attempt > 1 && mongo.IsDuplicateKeyError(err) {
+				return r.findCreated(ctx, id)
+			}
 			return model.Produto{}, err
 		}
 		return produto, nil
@@ -56,6 +67,15 @@ func (r *mongoProdutoRepository) Create(ctx context.Context, produto model.Produ
 	return result, err
 }
 
+// findCreated lê o produto gravado por uma tentativa anterior de Create
+func (r *mongoProdutoRepository) findCreated(ctx context.Context, id int) (model.Produto, error) {
+	var produto model.Produto
+	if err := r.Collection.FindOne(ctx, bson.M{"id": id}).Decode(&produto); err != nil {
+		return model.Produto{}, err
+	}
+	return produto, nil
+}
+
 func (r *mongoProdutoRepository) FindAll(ctx context.Context) ([]model.Produto, error) {
 	cursor, err := r.Collection.Find(ctx, bson.M{})
 	if err != nil {
@@ -68,14 +88,18 @@ func (r *mongoProdutoRepository) FindAll(ctx context.Context) ([]model.Produto,
 	return produtos, nil
 }
 
-func (r *mongoProdutoRepository) FindByID(ctx context.Context, id int) (model.Produto, error) {
+func (r *mongoProdutoRepository) FindByID(ctx context.Context, id int, fields ...string) (model.Produto, error) {
 	// Filtrar produtos deletados (soft delete)
 	filter := bson.M{
 		"id":        id,
 		"deleted_at": bson.M{"$exists": false},
 	}
+	opts := options.FindOne()
+	if projection := findProjection(fields, nil); projection != nil {
+		opts.SetProjection(projection)
+	}
 	var produto model.Produto
-	err := r.Collection.FindOne(ctx, filter).Decode(&produto)
+	err := r.Collection.FindOne(ctx, filter, opts).Decode(&produto)
 	if err != nil {
 		if err == mongo.ErrNoDocuments {
 			return model.Produto{}, errors.New("not found")
@@ -85,91 +109,213 @@ func (r *mongoProdutoRepository) FindByID(ctx context.Context, id int) (model.Pr
 	return produto, nil
 }
 
-func (r *mongoProdutoRepository) Update(ctx context.Context, id int, produto model.Produto) (model.Produto, error) {
+// maxWriteConflictRetries limita as releituras de uma escrita sem versão esperada
+// que perdeu a corrida para uma alteração concorrente
+const maxWriteConflictRetries = 3
+
+// Update substitui o produto. Se expectedVersion != nil, a operação só é aplicada
+// quando a versão armazenada for igual à informada; sem ela, uma alteração
+// concorrente entre a leitura e a escrita faz o produto ser relido
+func (r *mongoProdutoRepository) Update(ctx context.Context, id int, produto model.Produto, expectedVersion *int) (ProdutoChange, error) {
 	// Usar retry logic para operação crítica
 	retryOpts := database.DefaultRetryOptions()
-	result, err := database.RetryWithResult(ctx, func() (model.Produto, error) {
-		produto.ID = id
-		produto.BeforeUpdate() // Atualizar timestamp
-		
-		// Buscar produto existente para preservar CreatedAt e verificar se não está deletado
-		filter := bson.M{
-			"id":        id,
-			"deleted_at": bson.M{"$exists": false},
-		}
-		var existing model.Produto
-		err := r.Collection.FindOne(ctx, filter).Decode(&existing)
-		if err != nil {
-			if err == mongo.ErrNoDocuments {
-				return model.Produto{}, errors.New("not found")
+	result, err := database.RetryWithResult(ctx, func() (ProdutoChange, error) {
+		for attempt := 1; ; attempt++ {
+			result, err := r.replace(ctx, id, produto, expectedVersion)
+			if err == ErrVersionConflict && expectedVersion == nil && attempt < maxWriteConflictRetries {
+				continue
 			}
-			return model.Produto{}, err
+			return result, err
 		}
-		produto.CreatedAt = existing.CreatedAt // Preservar CreatedAt
-		produto.DeletedAt = existing.DeletedAt // Preservar DeletedAt (soft delete)
-		
+	}, retryOpts)
+	
+	return result, err
+}
+
+// replace lê o produto atual e o substitui, condicionado à versão lida
+func (r *mongoProdutoRepository) replace(ctx context.Context, id int, produto model.Produto, expectedVersion *int) (ProdutoChange, error) {
+	produto.ID = id
+	produto.BeforeUpdate() // Atualizar timestamp
+	
+	// Buscar produto existente para preservar CreatedAt e verificar se não está deletado
+	filter := bson.M{
+		"id":        id,
+		"deleted_at": bson.M{"$exists": false},
+	}
+	existing, err := r.findCurrent(ctx, filter, expectedVersion)
+	if err != nil {
+		return ProdutoChange{}, err
+	}
+	produto.CreatedAt = existing.CreatedAt // Preservar CreatedAt
+	produto.DeletedAt = existing.DeletedAt // Preservar DeletedAt (soft delete)
+	produto.KeepStock(existing)             // Preservar estoque e reservas
+	produto.Version = existing.Version + 1
+	
+	// Substituir apenas se ninguém alterou o documento desde a leitura
+	filter["version"] = versionFilter(existing.Version)
+	// Alterações de preço são gravadas junto com o histórico, na mesma transação
+	err = r.write(ctx, events.ProdutoAtualizado, produto.Preco != existing.Preco, func(ctx context.Context) (*model.Produto, *model.Produto, error) {
 		res, err := r.Collection.ReplaceOne(ctx, filter, produto)
 		if err != nil {
-			return model.Produto{}, err
+			return nil, nil, err
 		}
 		if res.MatchedCount == 0 {
-			return model.Produto{}, errors.New("not found")
+			return nil, nil, ErrVersionConflict
 		}
-		return produto, nil
-	}, retryOpts)
-	
-	return result, err
+		return &existing, &produto, nil
+	})
+	if err != nil {
+		return ProdutoChange{}, err
+	}
+	return ProdutoChange{Before: existing, After: produto}, nil
 }
 
-func (r *mongoProdutoRepository) Patch(ctx context.Context, id int, updates map[string]interface{}) (model.Produto, error) {
+// Patch atualiza parcialmente o produto. Se expectedVersion != nil, a operação só é
+// aplicada quando a versão armazenada for igual à informada; sem ela, como em
+// Update, uma alteração concorrente faz o produto ser relido
+func (r *mongoProdutoRepository) Patch(ctx context.Context, id int, updates map[string]interface{}, expectedVersion *int) (ProdutoChange, error) {
 	// Adicionar updated_at automaticamente
 	updates["updated_at"] = time.Now()
 	
+	for attempt := 1; ; attempt++ {
+		change, err := r.patch(ctx, id, updates, expectedVersion)
+		if err == ErrVersionConflict && expectedVersion == nil && attempt < maxWriteConflictRetries {
+			continue
+		}
+		return change, err
+	}
+}
+
+// patch lê o produto atual e aplica as alterações, condicionado à versão lida
+func (r *mongoProdutoRepository) patch(ctx context.Context, id int, updates map[string]interface{}, expectedVersion *int) (ProdutoChange, error) {
 	// Filtrar produtos deletados (soft delete)
 	filter := bson.M{
 		"id":        id,
 		"deleted_at": bson.M{"$exists": false},
 	}
+	existing, err := r.findCurrent(ctx, filter, expectedVersion)
+	if err != nil {
+		return ProdutoChange{}, err
+	}
+	filter["version"] = versionFilter(existing.Version)
 	
-	update := bson.M{"$set": updates}
-	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+	update := bson.M{
+		"$set": updates,
+		"$inc": bson.M{"version": 1},
+	}
+	// Só abre a transação se o preço informado for diferente do atual
+	priceChanged := false
+	if preco, ok := updates["preco"]; ok {
+		v, isFloat := preco.(float64)
+		priceChanged = !isFloat || v != existing.Preco
+	}
 	var updated model.Produto
-	err := r.Collection.FindOneAndUpdate(ctx, filter, update, opts).Decode(&updated)
+	// Alterações de preço são gravadas junto com o histórico, na mesma transação
+	err = r.write(ctx, events.ProdutoAtualizado, priceChanged, func(ctx context.Context) (*model.Produto, *model.Produto, error) {
+		opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+		if err := r.Collection.FindOneAndUpdate(ctx, filter, update, opts).Decode(&updated); err != nil {
+			if err == mongo.ErrNoDocuments {
+				return nil, nil, ErrVersionConflict
+			}
+			return nil, nil, err
+		}
+		return &existing, &updated, nil
+	})
 	if err != nil {
+		return ProdutoChange{}, err
+	}
+	return ProdutoChange{Before: existing, After: updated}, nil
+}
+
+// findCurrent lê o produto que será alterado e verifica a versão esperada
+func (r *mongoProdutoRepository) findCurrent(ctx context.Context, filter bson.M, expectedVersion *int) (model.Produto, error) {
+	var existing model.Produto
+	if err := r.Collection.FindOne(ctx, filter).Decode(&existing); err != nil {
 		if err == mongo.ErrNoDocuments {
 			return model.Produto{}, errors.New("not found")
 		}
 		return model.Produto{}, err
 	}
-	return updated, nil
+	if expectedVersion != nil && existing.Version != *expectedVersion {
+		return model.Produto{}, ErrVersionConflict
+	}
+	return existing, nil
 }
 
-func (r *mongoProdutoRepository) Delete(ctx context.Context, id int) error {
+// Delete remove o produto (soft delete). Se expectedVersion != nil, a operação só é
+// aplicada quando a versão armazenada for igual à informada
+func (r *mongoProdutoRepository) Delete(ctx context.Context, id int, expectedVersion *int) (ProdutoChange, error) {
 	// Soft delete: marcar como deletado ao invés de remover
 	retryOpts := database.DefaultRetryOptions()
-	err := database.Retry(ctx, func() error {
+	return database.RetryWithResult(ctx, func() (ProdutoChange, error) {
 		now := time.Now()
 		update := bson.M{
 			"$set": bson.M{
 				"deleted_at": now,
 				"updated_at": now,
 			},
+			"$inc": bson.M{"version": 1},
 		}
-		res, err := r.Collection.UpdateOne(ctx, bson.M{"id": id, "deleted_at": bson.M{"$exists": false}}, update)
-		if err != nil {
-			return err
+		filter := bson.M{"id": id, "deleted_at": bson.M{"$exists": false}}
+		if expectedVersion != nil {
+			filter["version"] = versionFilter(*expectedVersion)
 		}
-		if res.MatchedCount == 0 {
-			return errors.New("not found")
+		var change ProdutoChange
+		err := r.write(ctx, events.ProdutoRemovido, false, func(ctx context.Context) (*model.Produto, *model.Produto, error) {
+			// O documento anterior é o que foi de fato removido; o resultante
+			// decorre dele e do próprio update
+			opts := options.FindOneAndUpdate().SetReturnDocument(options.Before)
+			if err := r.Collection.FindOneAndUpdate(ctx, filter, update, opts).Decode(&change.Before); err != nil {
+				return nil, nil, err
+			}
+			change.After = change.Before
+			change.After.DeletedAt = &now
+			change.After.UpdatedAt = now
+			change.After.Version = change.Before.Version + 1
+			return &change.Before, &change.After, nil
+		})
+		if err == mongo.ErrNoDocuments {
+			return ProdutoChange{}, r.notFoundOrConflict(ctx, id, expectedVersion)
+		}
+		if err != nil {
+			return ProdutoChange{}, err
 		}
-		return nil
+		return change, nil
 	}, retryOpts)
-	
-	return err
+}
+
+// notFoundOrConflict diferencia, após uma escrita condicional sem efeito, se o
+// produto não existe ou se a versão informada está desatualizada
+// Sem versão esperada, a escrita só deixa de ter efeito se o produto não existir
+func (r *mongoProdutoRepository) notFoundOrConflict(ctx context.Context, id int, expectedVersion *int) error {
+	if expectedVersion == nil {
+		return errors.New("not found")
+	}
+	filter := bson.M{
+		"id":         id,
+		"deleted_at": bson.M{"$exists": false},
+	}
+	count, err := r.Collection.CountDocuments(ctx, filter)
+	if err != nil {
+		return err
+	}
+	if count == 0 {
+		return errors.New("not found")
+	}
+	return ErrVersionConflict
+}
+
+// versionFilter monta o filtro de versão, tratando documentos criados antes
+// da existência do campo version como versão 0
+func versionFilter(version int) interface{} {
+	if version == 0 {
+		return bson.M{"$in": bson.A{0, nil}}
+	}
+	return version
 }
 
 // FindAllPaginated retorna produtos paginados com filtros e ordenação
-func (r *mongoProdutoRepository) FindAllPaginated(ctx context.Context, skip, limit int64, filter map[string]interface{}, sort bson.D) ([]model.Produto, error) {
+func (r *mongoProdutoRepository) FindAllPaginated(ctx context.Context, skip, limit int64, filter map[string]interface{}, sort bson.D, fields ...string) ([]model.Produto, error) {
 	// Converter filter para bson.M
 	mongoFilter := bson.M{}
 	if filter != nil {
@@ -188,6 +334,9 @@ func (r *mongoProdutoRepository) FindAllPaginated(ctx context.Context, skip, lim
 		SetSkip(skip).
 		SetLimit(limit).
 		SetSort(sort)
+	if projection := findProjection(fields, sort); projection != nil {
+		opts.SetProjection(projection)
+	}
 
 	cursor, err := r.Collection.Find(ctx, mongoFilter, opts)
 	if err != nil {
@@ -202,6 +351,79 @@ func (r *mongoProdutoRepository) FindAllPaginated(ctx context.Context, skip, lim
 	return produtos, nil
 }
 
+// Stream percorre os produtos com filtros e ordenação diretamente do cursor
+func (r *mongoProdutoRepository) Stream(ctx context.Context, filter map[string]interface{}, sort bson.D, fn func(model.Produto) error) error {
+	mongoFilter := bson.M{}
+	if filter != nil {
+		mongoFilter = bson.M(filter)
+	}
+	// Filtrar produtos deletados (soft delete)
+	mongoFilter["deleted_at"] = bson.M{"$exists": false}
+
+	if len(sort) == 0 {
+		sort = bson.D{{Key: "id", Value: 1}}
+	}
+
+	// Lotes maiores reduzem as idas ao banco em exportações grandes
+	opts := options.Find().SetSort(sort).SetBatchSize(1000)
+	if projection := textScoreProjection(sort); projection != nil {
+		opts.SetProjection(projection)
+	}
+	cursor, err := r.Collection.Find(ctx, mongoFilter, opts)
+	if err != nil {
+		return err
+	}
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		var produto model.Produto
+		if err := cursor.Decode(&produto); err != nil {
+			return err
+		}
+		if err := fn(produto); err != nil {
+			return err
+		}
+	}
+	return cursor.Err()
+}
+
+// textScoreProjection retorna a projeção do score da busca textual quando a
+// ordenação usa {$meta: "textScore"} (exigida pelo MongoDB em versões anteriores à 4.4)
+func textScoreProjection(sort bson.D) bson.M {
+	for _, e := range sort {
+		if meta, ok := e.Value.(bson.M); ok && meta["$meta"] == "textScore" {
+			return bson.M{e.Key: meta}
+		}
+	}
+	return nil
+}
+
+// projectionBaseFields são sempre lidos em uma projeção: o id identifica o
+// produto e version/updated_at alimentam os validadores (ETag e Last-Modified)
+var projectionBaseFields = []string{"id", "version", "updated_at"}
+
+// findProjection monta a projeção para os campos informados, incluindo os campos
+// da ordenação (usados pelo cursor da paginação keyset) e o score da busca textual
+// Sem campos, apenas o score é projetado (quando necessário)
+func findProjection(fields []string, sort bson.D) bson.M {
+	projection := textScoreProjection(sort)
+	if len(fields) == 0 {
+		return projection
+	}
+	if projection == nil {
+		projection = bson.M{}
+	}
+	for _, field := range append(append([]string{}, projectionBaseFields...), fields...) {
+		projection[field] = 1
+	}
+	for _, e := range sort {
+		if _, ok := projection[e.Key]; !ok {
+			projection[e.Key] = 1
+		}
+	}
+	return projection
+}
+
 // Count retorna o total de documentos que correspondem ao filtro
 func (r *mongoProdutoRepository) Count(ctx context.Context, filter map[string]interface{}) (int64, error) {
 	// Converter filter para bson.M
@@ -218,3 +440,102 @@ func (r *mongoProdutoRepository) Count(ctx context.Context, filter map[string]in
 	}
 	return count, nil
 }
+
+// Restore restaura um produto removido (soft delete)
+func (r *mongoProdutoRepository) Restore(ctx context.Context, id int) (ProdutoChange, error) {
+	retryOpts := database.DefaultRetryOptions()
+	return database.RetryWithResult(ctx, func() (ProdutoChange, error) {
+		now := time.Now()
+		filter := bson.M{
+			"id":         id,
+			"deleted_at": bson.M{"$exists": true},
+		}
+		update := bson.M{
+			"$unset": bson.M{"deleted_at": ""},
+			"$set":   bson.M{"updated_at": now},
+			"$inc":   bson.M{"version": 1},
+		}
+		var change ProdutoChange
+		err := r.write(ctx, events.ProdutoRestaurado, false, func(ctx context.Context) (*model.Produto, *model.Produto, error) {
+			opts := options.FindOneAndUpdate().SetReturnDocument(options.Before)
+			if err := r.Collection.FindOneAndUpdate(ctx, filter, update, opts).Decode(&change.Before); err != nil {
+				return nil, nil, err
+			}
+			change.After = change.Before
+			change.After.DeletedAt = nil
+			change.After.UpdatedAt = now
+			change.After.Version = change.Before.Version + 1
+			return &change.Before, &change.After, nil
+		})
+		if err != nil {
+			if err == mongo.ErrNoDocuments {
+				return ProdutoChange{}, errors.New("not found")
+			}
+			return ProdutoChange{}, err
+		}
+		return change, nil
+	}, retryOpts)
+}
+
+// FindDeletedPaginated retorna produtos removidos (soft delete) paginados
+func (r *mongoProdutoRepository) FindDeletedPaginated(ctx context.Context, skip, limit int64, sort bson.D) ([]model.Produto, error) {
+	filter := bson.M{"deleted_at": bson.M{"$exists": true}}
+
+	// Se sort estiver vazio, os removidos mais recentemente vêm primeiro
+	if len(sort) == 0 {
+		sort = bson.D{{Key: "deleted_at", Value: -1}}
+	}
+
+	opts := options.Find().
+		SetSkip(skip).
+		SetLimit(limit).
+		SetSort(sort)
+
+	cursor, err := r.Collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var produtos []model.Produto
+	if err = cursor.All(ctx, &produtos); err != nil {
+		return nil, err
+	}
+	return produtos, nil
+}
+
+// CountDeleted retorna o total de produtos removidos (soft delete) que correspondem ao filtro
+func (r *mongoProdutoRepository) CountDeleted(ctx context.Context, filter map[string]interface{}) (int64, error) {
+	mongoFilter := bson.M{}
+	for key, value := range filter {
+		mongoFilter[key] = value
+	}
+	mongoFilter["deleted_at"] = bson.M{"$exists": true}
+	return r.Collection.CountDocuments(ctx, mongoFilter)
+}
+
+// FindDeletedByID retorna um produto da lixeira (soft delete)
+func (r *mongoProdutoRepository) FindDeletedByID(ctx context.Context, id int) (model.Produto, error) {
+	filter := bson.M{"id": id, "deleted_at": bson.M{"$exists": true}}
+	var produto model.Produto
+	if err := r.Collection.FindOne(ctx, filter).Decode(&produto); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return model.Produto{}, errors.New("not found")
+		}
+		return model.Produto{}, err
+	}
+	return produto, nil
+}
+
+// PurgeDeleted remove permanentemente os produtos removidos (soft delete) antes de before
+func (r *mongoProdutoRepository) PurgeDeleted(ctx context.Context, before time.Time) (int64, error) {
+	retryOpts := database.DefaultRetryOptions()
+	return database.RetryWithResult(ctx, func() (int64, error) {
+		filter := bson.M{"deleted_at": bson.M{"$lt": before}}
+		res, err := r.Collection.DeleteMany(ctx, filter)
+		if err != nil {
+			return 0, err
+		}
+		return res.DeletedCount, nil
+	}, retryOpts)
+}
@@ -2,9 +2,19 @@ package repository
 
 import (
 	"context"
+	"fmt"
+	"os"
+	"sync"
 	"testing"
+	"time"
 
+	"api-go-arquitetura/internal/database"
+	"api-go-arquitetura/internal/dto"
 	"api-go-arquitetura/internal/model"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 // TestProdutoRepository_Interface verifica se mongoProdutoRepository implementa a interface
@@ -14,15 +24,115 @@ func TestProdutoRepository_Interface(t *testing.T) {
 	var _ ProdutoRepository = (*mongoProdutoRepository)(nil)
 }
 
-// TestProdutoRepository_GetNextID_EmptyCollection testa getNextID quando não há produtos
-func TestProdutoRepository_GetNextID_EmptyCollection(t *testing.T) {
-	// Este é um teste de integração que requer MongoDB rodando
-	// Para testes unitários completos, seria necessário usar mocks do MongoDB
-	// ou usar uma biblioteca como mtest (requer MongoDB em memória)
-	
-	// Por enquanto, este teste serve como documentação
-	// de que a função getNextID deve retornar 1 quando não há produtos
-	t.Skip("Teste de integração - requer MongoDB rodando")
+// intPtr retorna um ponteiro para a versão esperada
+func intPtr(v int) *int {
+	return &v
+}
+
+// newIntegrationCollection conecta ao MongoDB indicado por MONGO_TEST_URI
+// e retorna uma coleção temporária, pulando o teste se a variável não estiver definida
+func newIntegrationCollection(t *testing.T) *mongo.Collection {
+	t.Helper()
+	uri := os.Getenv("MONGO_TEST_URI")
+	if uri == "" {
+		t.Skip("Teste de integração - defina MONGO_TEST_URI para executar")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	client, err := mongo.Connect(ctx, options.Client().ApplyURI(uri))
+	if err != nil {
+		t.Fatalf("Erro ao conectar ao MongoDB: %v", err)
+	}
+
+	db := client.Database(fmt.Sprintf("api_go_test_%d", time.Now().UnixNano()))
+	t.Cleanup(func() {
+		_ = db.Drop(context.Background())
+		_ = client.Disconnect(context.Background())
+	})
+	return db.Collection("produtos")
+}
+
+// TestProdutoRepository_CreateConcurrent dispara centenas de criações em paralelo
+// e verifica que nenhuma colide no índice único de ID
+func TestProdutoRepository_CreateConcurrent(t *testing.T) {
+	col := newIntegrationCollection(t)
+	ctx := context.Background()
+
+	_, err := col.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "id", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	})
+	if err != nil {
+		t.Fatalf("Erro ao criar índice: %v", err)
+	}
+
+	repo := NewProdutoRepository(col)
+	const total = 300
+
+	var wg sync.WaitGroup
+	ids := make(chan int, total)
+	errs := make(chan error, total)
+	for i := 0; i < total; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			p, err := repo.Create(ctx, model.Produto{Nome: fmt.Sprintf("Produto %d", i), Preco: 10})
+			if err != nil {
+				errs <- err
+				return
+			}
+			ids <- p.ID
+		}(i)
+	}
+	wg.Wait()
+	close(ids)
+	close(errs)
+
+	for err := range errs {
+		t.Errorf("Erro inesperado ao criar produto: %v", err)
+	}
+	seen := make(map[int]bool)
+	for id := range ids {
+		if seen[id] {
+			t.Errorf("ID %d atribuído mais de uma vez", id)
+		}
+		seen[id] = true
+	}
+	if len(seen) != total {
+		t.Errorf("Esperados %d IDs distintos, obtidos %d", total, len(seen))
+	}
+}
+
+// fixedAllocator sempre retorna o mesmo ID
+type fixedAllocator int
+
+func (a fixedAllocator) NextID(ctx context.Context) (int, error) {
+	return int(a), nil
+}
+
+// TestProdutoRepository_CreateDuplicateID verifica que um ID já usado por outro
+// produto é rejeitado na primeira tentativa: apenas uma nova tentativa do mesmo
+// Create aceita o documento existente como o criado por ela
+func TestProdutoRepository_CreateDuplicateID(t *testing.T) {
+	col := newIntegrationCollection(t)
+	ctx := context.Background()
+
+	_, err := col.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "id", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	})
+	if err != nil {
+		t.Fatalf("Erro ao criar índice: %v", err)
+	}
+
+	repo := NewProdutoRepositoryWithAllocator(col, fixedAllocator(1))
+	if _, err := repo.Create(ctx, model.Produto{Nome: "Original", Preco: 10}); err != nil {
+		t.Fatalf("Erro ao criar produto: %v", err)
+	}
+	if _, err := repo.Create(ctx, model.Produto{Nome: "Outro", Preco: 20}); !mongo.IsDuplicateKeyError(err) {
+		t.Errorf("Esperado erro de chave duplicada, obtido %v", err)
+	}
 }
 
 // TestProdutoRepository_ErrorHandling testa tratamento de erros
@@ -43,3 +153,244 @@ func TestProdutoRepository_ErrorHandling(t *testing.T) {
 	})
 }
 
+
+// TestProdutoRepository_BulkWrite aplica um lote misto e verifica o resultado de cada operação
+func TestProdutoRepository_BulkWrite(t *testing.T) {
+	col := newIntegrationCollection(t)
+	ctx := context.Background()
+	repo := NewProdutoRepository(col)
+
+	existing, err := repo.Create(ctx, model.Produto{Nome: "Notebook", Preco: 3500})
+	if err != nil {
+		t.Fatalf("Erro ao criar produto: %v", err)
+	}
+
+	results, err := repo.BulkWrite(ctx, []BulkOperation{
+		{Type: BulkInsert, Produto: model.Produto{Nome: "Mouse", Preco: 150}},
+		{Type: BulkPatch, ID: existing.ID, Updates: map[string]interface{}{"preco": 3200.0}, ExpectedVersion: &existing.Version},
+		{Type: BulkDelete, ID: 999},
+	}, false)
+	if err != nil {
+		t.Fatalf("Erro inesperado: %v", err)
+	}
+
+	if results[0].Err != nil || results[0].Produto.ID == 0 {
+		t.Errorf("Inserção deveria ser aplicada: %v", results[0].Err)
+	}
+	if results[1].Err != nil || results[1].Produto.Preco != 3200 || results[1].Produto.Version != existing.Version+1 {
+		t.Errorf("Patch deveria ser aplicado: %+v", results[1])
+	}
+	if results[2].Err == nil || results[2].Err.Error() != "not found" {
+		t.Errorf("Produto inexistente deveria retornar not found, obtido %v", results[2].Err)
+	}
+
+	// Versão desatualizada resulta em conflito sem afetar as demais operações
+	results, err = repo.BulkWrite(ctx, []BulkOperation{
+		{Type: BulkDelete, ID: existing.ID, ExpectedVersion: &existing.Version},
+	}, false)
+	if err != nil {
+		t.Fatalf("Erro inesperado: %v", err)
+	}
+	if results[0].Err != ErrVersionConflict {
+		t.Errorf("Esperado ErrVersionConflict, obtido %v", results[0].Err)
+	}
+}
+
+// TestProdutoRepository_KeysetPagination percorre todas as páginas por cursor, para
+// cada campo de ordenação, inserindo produtos entre as páginas, e verifica que
+// nenhum produto existente é repetido ou pulado
+func TestProdutoRepository_KeysetPagination(t *testing.T) {
+	col := newIntegrationCollection(t)
+	ctx := context.Background()
+	repo := NewProdutoRepository(col)
+
+	const total = 23
+	for i := 0; i < total; i++ {
+		// Valores repetidos forçam o desempate pelo ID
+		_, err := repo.Create(ctx, model.Produto{Nome: fmt.Sprintf("Produto %d", i%4), Preco: float64(i % 3), Descricao: "x"})
+		if err != nil {
+			t.Fatalf("Erro ao criar produto: %v", err)
+		}
+	}
+
+	for _, field := range []string{"", "id", "nome", "preco", "descricao", "created_at", "updated_at"} {
+		for _, order := range []string{"asc", "desc"} {
+			sort := dto.SortRequest{Field: field, Order: order}
+			if err := sort.Validate(); err != nil {
+				t.Fatalf("Ordenação inválida: %v", err)
+			}
+
+			seen := make(map[int]int)
+			cursor := ""
+			for pages := 0; pages < 2*total; pages++ {
+				filter := map[string]interface{}{"id": bson.M{"$lte": total}}
+				keyset, err := sort.KeysetFilter(cursor)
+				if err != nil {
+					t.Fatalf("Erro ao decodificar cursor: %v", err)
+				}
+				if keyset != nil {
+					filter["$and"] = []interface{}{keyset}
+				}
+				produtos, err := repo.FindAllPaginated(ctx, 0, 5, filter, sort.ToMongoKeysetSort())
+				if err != nil {
+					t.Fatalf("Erro ao paginar: %v", err)
+				}
+				if len(produtos) == 0 {
+					break
+				}
+				for _, p := range produtos {
+					seen[p.ID]++
+				}
+				// Inserções concorrentes não devem afetar os produtos já existentes
+				_, _ = repo.Create(ctx, model.Produto{Nome: "Novo", Preco: 1})
+				if cursor, err = sort.EncodeCursor(produtos[len(produtos)-1]); err != nil {
+					t.Fatalf("Erro ao gerar cursor: %v", err)
+				}
+			}
+
+			if len(seen) != total {
+				t.Errorf("%s:%s - esperados %d produtos distintos, obtidos %d", field, order, total, len(seen))
+			}
+			for id, count := range seen {
+				if count != 1 {
+					t.Errorf("%s:%s - produto %d retornado %d vezes", field, order, id, count)
+				}
+			}
+		}
+	}
+}
+
+// TestProdutoRepository_TextSearch verifica o índice de texto: stemming em
+// português e maior peso para ocorrências no nome
+func TestProdutoRepository_TextSearch(t *testing.T) {
+	col := newIntegrationCollection(t)
+	ctx := context.Background()
+
+	if err := database.CreateIndexes(ctx, col.Database().Client(), col.Database().Name(), col.Name()); err != nil {
+		t.Fatalf("Erro ao criar índices: %v", err)
+	}
+	repo := NewProdutoRepository(col)
+
+	for _, p := range []model.Produto{
+		{Nome: "Mochila", Preco: 200, Descricao: "Compartimento para notebooks de até 15 polegadas"},
+		{Nome: "Notebook", Preco: 3500, Descricao: "Processador rápido"},
+		{Nome: "Mouse", Preco: 150, Descricao: "Sem fio"},
+	} {
+		if _, err := repo.Create(ctx, p); err != nil {
+			t.Fatalf("Erro ao criar produto: %v", err)
+		}
+	}
+
+	q := "notebooks"
+	filter := dto.FilterRequest{Q: &q}
+	sort := dto.SortRequest{}
+	if err := sort.ValidateForFilter(filter); err != nil {
+		t.Fatalf("Ordenação inválida: %v", err)
+	}
+
+	produtos, err := repo.FindAllPaginated(ctx, 0, 10, filter.ToMongoFilter(), sort.ToMongoSort())
+	if err != nil {
+		t.Fatalf("Erro na busca: %v", err)
+	}
+	if len(produtos) != 2 {
+		t.Fatalf("Esperados 2 resultados, obtidos %d", len(produtos))
+	}
+	if produtos[0].Nome != "Notebook" {
+		t.Errorf("Ocorrência no nome deveria vir primeiro, obtido %s", produtos[0].Nome)
+	}
+}
+
+func TestProdutoRepository_Facets(t *testing.T) {
+	col := newIntegrationCollection(t)
+	ctx := context.Background()
+	repo := NewProdutoRepository(col)
+
+	for _, p := range []model.Produto{
+		{Nome: "Notebook", Preco: 3000, Tags: []string{"informatica"}, CategoriaID: 1},
+		{Nome: "Mouse", Preco: 100, Tags: []string{"informatica", "promocao"}, CategoriaID: 1},
+		{Nome: "Cadeira", Preco: 500, CategoriaID: 2},
+	} {
+		if _, err := repo.Create(ctx, p); err != nil {
+			t.Fatalf("Erro ao criar produto: %v", err)
+		}
+	}
+	if _, err := repo.Create(ctx, model.Produto{Nome: "Sem categoria", Preco: 200}); err != nil {
+		t.Fatalf("Erro ao criar produto: %v", err)
+	}
+	removed, err := repo.Create(ctx, model.Produto{Nome: "Removido", Preco: 9999, Tags: []string{"informatica"}, CategoriaID: 2})
+	if err != nil {
+		t.Fatalf("Erro ao criar produto: %v", err)
+	}
+	if _, err := repo.Delete(ctx, removed.ID, nil); err != nil {
+		t.Fatalf("Erro ao remover produto: %v", err)
+	}
+
+	facets, err := repo.Facets(ctx, map[string]interface{}{"preco": map[string]interface{}{"$ne": 200}}, FacetOptions{Stats: true, Preco: true, Buckets: 2, Tags: true, Categorias: true})
+	if err != nil {
+		t.Fatalf("Erro nas facetas: %v", err)
+	}
+	if facets.Stats == nil || facets.Stats.Count != 3 || facets.Stats.Min != 100 || facets.Stats.Max != 3000 || facets.Stats.Avg != 1200 {
+		t.Errorf("Estatísticas incorretas (produtos removidos não contam): %+v", facets.Stats)
+	}
+	var bucketed int64
+	for _, b := range facets.Preco {
+		bucketed += b.Count
+	}
+	if len(facets.Preco) != 2 || bucketed != 3 {
+		t.Errorf("Esperadas 2 faixas com 3 produtos, obtido %+v", facets.Preco)
+	}
+	if len(facets.Tags) != 2 || facets.Tags[0] != (model.FacetCount{Value: "informatica", Count: 2}) {
+		t.Errorf("Contagem por tag incorreta: %+v", facets.Tags)
+	}
+	if len(facets.Categoria) != 2 || facets.Categoria[0] != (model.CategoriaFacetCount{CategoriaID: 1, Count: 2}) || facets.Categoria[1] != (model.CategoriaFacetCount{CategoriaID: 2, Count: 1}) {
+		t.Errorf("Contagem por categoria incorreta: %+v", facets.Categoria)
+	}
+
+	// Produtos sem categoria não entram na faceta de categorias
+	uncategorized, err := repo.Facets(ctx, map[string]interface{}{"preco": 200.0}, FacetOptions{Categorias: true})
+	if err != nil {
+		t.Fatalf("Erro nas facetas: %v", err)
+	}
+	if len(uncategorized.Categoria) != 0 {
+		t.Errorf("Nenhuma categoria esperada, obtido %+v", uncategorized.Categoria)
+	}
+
+	// Filtro sem resultados: stats zerada, demais facetas vazias
+	empty, err := repo.Facets(ctx, map[string]interface{}{"preco": map[string]interface{}{"$gt": 5000}}, FacetOptions{Stats: true, Tags: true})
+	if err != nil {
+		t.Fatalf("Erro nas facetas: %v", err)
+	}
+	if empty.Stats == nil || empty.Stats.Count != 0 || len(empty.Tags) != 0 {
+		t.Errorf("Facetas vazias esperadas, obtido %+v", empty)
+	}
+}
+
+func TestProdutoRepository_Projection(t *testing.T) {
+	col := newIntegrationCollection(t)
+	ctx := context.Background()
+	repo := NewProdutoRepository(col)
+
+	created, err := repo.Create(ctx, model.Produto{Nome: "Notebook", Preco: 3500, Descricao: "Alta performance"})
+	if err != nil {
+		t.Fatalf("Erro ao criar produto: %v", err)
+	}
+
+	produto, err := repo.FindByID(ctx, created.ID, "id", "nome")
+	if err != nil {
+		t.Fatalf("Erro ao buscar produto: %v", err)
+	}
+	if produto.Nome != "Notebook" || produto.Preco != 0 || produto.Descricao != "" {
+		t.Errorf("Apenas nome deveria ser lido, obtido %+v", produto)
+	}
+	if produto.Version != created.Version || produto.UpdatedAt.IsZero() {
+		t.Errorf("version e updated_at devem ser lidos sempre, obtido %+v", produto)
+	}
+
+	produtos, err := repo.FindAllPaginated(ctx, 0, 10, nil, bson.D{{Key: "preco", Value: -1}}, "id", "nome")
+	if err != nil {
+		t.Fatalf("Erro ao listar produtos: %v", err)
+	}
+	if len(produtos) != 1 || produtos[0].Preco != 3500 || produtos[0].Descricao != "" {
+		t.Errorf("O campo da ordenação deve ser lido junto com os campos pedidos, obtido %+v", produtos)
+	}
+}
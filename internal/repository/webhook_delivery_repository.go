@@ -0,0 +1,193 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"api-go-arquitetura/internal/model"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// WebhookDeliveryCollection é a coleção das entregas de webhooks
+const WebhookDeliveryCollection = "webhook_deliveries"
+
+// WebhookDeliveryRepository define a interface para as entregas de webhooks
+type WebhookDeliveryRepository interface {
+	// Enqueue grava as entregas como pendentes, ignorando as que já existem
+	Enqueue(ctx context.Context, deliveries []model.WebhookDelivery) error
+	// Claim reserva até limit entregas pendentes com NextAttemptAt <= now, das mais
+	// antigas para as mais recentes, adiando NextAttemptAt para now+lease
+	Claim(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]model.WebhookDelivery, error)
+	// RecordAttempt registra uma tentativa e a nova situação da entrega
+	// next é a próxima tentativa (usada apenas quando status é pendente)
+	RecordAttempt(ctx context.Context, id string, attempt model.WebhookAttempt, status string, next time.Time) error
+	FindByID(ctx context.Context, id string) (model.WebhookDelivery, error)
+	// FindByWebhook retorna as entregas do webhook, mais recentes primeiro
+	// status vazio não restringe a busca
+	FindByWebhook(ctx context.Context, webhookID int, status string, skip, limit int64) ([]model.WebhookDelivery, error)
+	CountByWebhook(ctx context.Context, webhookID int, status string) (int64, error)
+	// Redeliver volta a entrega para pendente, com novo ciclo de tentativas a partir de now
+	Redeliver(ctx context.Context, id string, now time.Time) (model.WebhookDelivery, error)
+	DeleteByWebhook(ctx context.Context, webhookID int) (int64, error)
+}
+
+// mongoWebhookDeliveryRepository implementa WebhookDeliveryRepository usando MongoDB
+type mongoWebhookDeliveryRepository struct {
+	Collection *mongo.Collection
+}
+
+// NewWebhookDeliveryRepository cria uma nova instância do WebhookDeliveryRepository
+// na coleção webhook_deliveries do banco
+func NewWebhookDeliveryRepository(db *mongo.Database) WebhookDeliveryRepository {
+	// Os payloads dos eventos são lidos como mapas (e não bson.D), para que sejam
+	// enviados no mesmo formato em que foram gravados
+	opts := options.Collection().SetBSONOptions(&options.BSONOptions{DefaultDocumentM: true})
+	return &mongoWebhookDeliveryRepository{Collection: db.Collection(WebhookDeliveryCollection, opts)}
+}
+
+func (r *mongoWebhookDeliveryRepository) Enqueue(ctx context.Context, deliveries []model.WebhookDelivery) error {
+	if len(deliveries) == 0 {
+		return nil
+	}
+	docs := make([]interface{}, len(deliveries))
+	for i, d := range deliveries {
+		docs[i] = d
+	}
+	_, err := r.Collection.InsertMany(ctx, docs, options.InsertMany().SetOrdered(false))
+	if err == nil {
+		return nil
+	}
+
+	// Entregas já existentes (evento publicado novamente) não são erro
+	var bwe mongo.BulkWriteException
+	if errors.As(err, &bwe) && bwe.WriteConcernError == nil {
+		for _, we := range bwe.WriteErrors {
+			if !mongo.IsDuplicateKeyError(we) {
+				return err
+			}
+		}
+		return nil
+	}
+	return err
+}
+
+func (r *mongoWebhookDeliveryRepository) Claim(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]model.WebhookDelivery, error) {
+	filter := bson.M{
+		"status":          model.DeliveryPending,
+		"next_attempt_at": bson.M{"$lte": now},
+	}
+	update := bson.M{"$set": bson.M{"next_attempt_at": now.Add(lease)}}
+	opts := options.FindOneAndUpdate().
+		SetSort(bson.D{{Key: "next_attempt_at", Value: 1}, {Key: "_id", Value: 1}}).
+		SetReturnDocument(options.After)
+
+	deliveries := []model.WebhookDelivery{}
+	for len(deliveries) < limit {
+		var delivery model.WebhookDelivery
+		err := r.Collection.FindOneAndUpdate(ctx, filter, update, opts).Decode(&delivery)
+		if err == mongo.ErrNoDocuments {
+			break
+		}
+		if err != nil {
+			return deliveries, err
+		}
+		deliveries = append(deliveries, delivery)
+	}
+	return deliveries, nil
+}
+
+func (r *mongoWebhookDeliveryRepository) RecordAttempt(ctx context.Context, id string, attempt model.WebhookAttempt, status string, next time.Time) error {
+	set := bson.M{"status": status}
+	switch status {
+	case model.DeliveryPending:
+		set["next_attempt_at"] = next
+	case model.DeliveryDelivered:
+		set["delivered_at"] = attempt.At
+	}
+	update := bson.M{
+		"$set":  set,
+		"$inc":  bson.M{"attempts": 1},
+		"$push": bson.M{"history": attempt},
+	}
+	res, err := r.Collection.UpdateOne(ctx, bson.M{"_id": id}, update)
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return errors.New("not found")
+	}
+	return nil
+}
+
+func (r *mongoWebhookDeliveryRepository) FindByID(ctx context.Context, id string) (model.WebhookDelivery, error) {
+	var delivery model.WebhookDelivery
+	err := r.Collection.FindOne(ctx, bson.M{"_id": id}).Decode(&delivery)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return model.WebhookDelivery{}, errors.New("not found")
+		}
+		return model.WebhookDelivery{}, err
+	}
+	return delivery, nil
+}
+
+func (r *mongoWebhookDeliveryRepository) FindByWebhook(ctx context.Context, webhookID int, status string, skip, limit int64) ([]model.WebhookDelivery, error) {
+	opts := options.Find().
+		SetSkip(skip).
+		SetLimit(limit).
+		SetSort(bson.D{{Key: "created_at", Value: -1}, {Key: "_id", Value: -1}})
+
+	cursor, err := r.Collection.Find(ctx, deliveryFilter(webhookID, status), opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	deliveries := []model.WebhookDelivery{}
+	if err := cursor.All(ctx, &deliveries); err != nil {
+		return nil, err
+	}
+	return deliveries, nil
+}
+
+func (r *mongoWebhookDeliveryRepository) CountByWebhook(ctx context.Context, webhookID int, status string) (int64, error) {
+	return r.Collection.CountDocuments(ctx, deliveryFilter(webhookID, status))
+}
+
+func (r *mongoWebhookDeliveryRepository) Redeliver(ctx context.Context, id string, now time.Time) (model.WebhookDelivery, error) {
+	update := bson.M{
+		"$set":   bson.M{"status": model.DeliveryPending, "attempts": 0, "next_attempt_at": now},
+		"$unset": bson.M{"delivered_at": ""},
+	}
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+	var delivery model.WebhookDelivery
+	err := r.Collection.FindOneAndUpdate(ctx, bson.M{"_id": id}, update, opts).Decode(&delivery)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return model.WebhookDelivery{}, errors.New("not found")
+		}
+		return model.WebhookDelivery{}, err
+	}
+	return delivery, nil
+}
+
+func (r *mongoWebhookDeliveryRepository) DeleteByWebhook(ctx context.Context, webhookID int) (int64, error) {
+	res, err := r.Collection.DeleteMany(ctx, bson.M{"webhook_id": webhookID})
+	if err != nil {
+		return 0, err
+	}
+	return res.DeletedCount, nil
+}
+
+// deliveryFilter restringe as entregas ao webhook e, se informada, à situação
+func deliveryFilter(webhookID int, status string) bson.M {
+	filter := bson.M{"webhook_id": webhookID}
+	if status != "" {
+		filter["status"] = status
+	}
+	return filter
+}
@@ -0,0 +1,131 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"api-go-arquitetura/internal/database"
+	"api-go-arquitetura/internal/model"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// WebhookCounterName é o nome do contador usado para gerar IDs de webhooks
+const WebhookCounterName = "webhooks"
+
+// WebhookRepository define a interface para operações de webhook no repositório
+type WebhookRepository interface {
+	Create(ctx context.Context, webhook model.Webhook) (model.Webhook, error)
+	FindByID(ctx context.Context, id int) (model.Webhook, error)
+	// FindAll retorna todos os webhooks ordenados por ID
+	FindAll(ctx context.Context) ([]model.Webhook, error)
+	Update(ctx context.Context, webhook model.Webhook) (model.Webhook, error)
+	Delete(ctx context.Context, id int) error
+	// FindActiveByEvent retorna os webhooks ativos inscritos no tipo de evento
+	FindActiveByEvent(ctx context.Context, eventType string) ([]model.Webhook, error)
+}
+
+// mongoWebhookRepository implementa WebhookRepository usando MongoDB
+type mongoWebhookRepository struct {
+	Collection *mongo.Collection
+	ids        IDAllocator
+}
+
+// NewWebhookRepository cria uma nova instância do WebhookRepository
+// Os IDs são gerados pela coleção "counters" do mesmo banco de dados
+func NewWebhookRepository(col *mongo.Collection) WebhookRepository {
+	store := NewMongoCounterStore(col.Database().Collection("counters"))
+	return NewWebhookRepositoryWithAllocator(col, NewSequentialAllocator(store, WebhookCounterName))
+}
+
+// NewWebhookRepositoryWithAllocator cria uma nova instância do WebhookRepository com alocador de IDs customizado
+func NewWebhookRepositoryWithAllocator(col *mongo.Collection, ids IDAllocator) WebhookRepository {
+	return &mongoWebhookRepository{Collection: col, ids: ids}
+}
+
+func (r *mongoWebhookRepository) Create(ctx context.Context, webhook model.Webhook) (model.Webhook, error) {
+	id, err := r.ids.NextID(ctx)
+	if err != nil {
+		return model.Webhook{}, err
+	}
+	webhook.ID = id
+	webhook.BeforeCreate()
+
+	err = database.Retry(ctx, func() error {
+		_, err := r.Collection.InsertOne(ctx, webhook)
+		return err
+	}, database.DefaultRetryOptions())
+	if err != nil {
+		return model.Webhook{}, err
+	}
+	return webhook, nil
+}
+
+func (r *mongoWebhookRepository) FindByID(ctx context.Context, id int) (model.Webhook, error) {
+	var webhook model.Webhook
+	err := r.Collection.FindOne(ctx, bson.M{"id": id}).Decode(&webhook)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return model.Webhook{}, errors.New("not found")
+		}
+		return model.Webhook{}, err
+	}
+	return webhook, nil
+}
+
+func (r *mongoWebhookRepository) FindAll(ctx context.Context) ([]model.Webhook, error) {
+	return r.find(ctx, bson.M{})
+}
+
+func (r *mongoWebhookRepository) Update(ctx context.Context, webhook model.Webhook) (model.Webhook, error) {
+	existing, err := r.FindByID(ctx, webhook.ID)
+	if err != nil {
+		return model.Webhook{}, err
+	}
+	webhook.CreatedAt = existing.CreatedAt
+	webhook.BeforeUpdate()
+
+	res, err := r.Collection.ReplaceOne(ctx, bson.M{"id": webhook.ID}, webhook)
+	if err != nil {
+		return model.Webhook{}, err
+	}
+	if res.MatchedCount == 0 {
+		return model.Webhook{}, errors.New("not found")
+	}
+	return webhook, nil
+}
+
+func (r *mongoWebhookRepository) Delete(ctx context.Context, id int) error {
+	res, err := r.Collection.DeleteOne(ctx, bson.M{"id": id})
+	if err != nil {
+		return err
+	}
+	if res.DeletedCount == 0 {
+		return errors.New("not found")
+	}
+	return nil
+}
+
+func (r *mongoWebhookRepository) FindActiveByEvent(ctx context.Context, eventType string) ([]model.Webhook, error) {
+	return r.find(ctx, bson.M{
+		"active": true,
+		"events": bson.M{"$in": []string{eventType, model.WebhookAllEvents}},
+	})
+}
+
+// find retorna os webhooks do filtro ordenados por ID
+func (r *mongoWebhookRepository) find(ctx context.Context, filter bson.M) ([]model.Webhook, error) {
+	cursor, err := r.Collection.Find(ctx, filter, options.Find().SetSort(bson.D{{Key: "id", Value: 1}}))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	webhooks := []model.Webhook{}
+	if err := cursor.All(ctx, &webhooks); err != nil {
+		return nil, err
+	}
+	return webhooks, nil
+}
@@ -0,0 +1,184 @@
+package service
+
+import (
+	"context"
+
+	"api-go-arquitetura/internal/errors"
+	"api-go-arquitetura/internal/logger"
+	"api-go-arquitetura/internal/model"
+	"api-go-arquitetura/internal/repository"
+	"api-go-arquitetura/internal/utils"
+)
+
+// categoriaService implementa a lógica de negócio para categorias
+type categoriaService struct {
+	repo     repository.CategoriaRepository
+	produtos repository.ProdutoRepository
+}
+
+// NewCategoriaService cria uma nova instância do CategoriaService
+// O repositório de produtos é usado para impedir a remoção de categorias em uso
+func NewCategoriaService(repo repository.CategoriaRepository, produtos repository.ProdutoRepository) CategoriaService {
+	return &categoriaService{repo: repo, produtos: produtos}
+}
+
+// Create cria uma nova categoria sob a categoria pai (ParentID = 0 cria uma raiz)
+func (s *categoriaService) Create(ctx context.Context, categoria model.Categoria) (model.Categoria, error) {
+	if err := normalizeCategoria(&categoria); err != nil {
+		return model.Categoria{}, err
+	}
+
+	parent, err := s.findParent(ctx, categoria.ParentID)
+	if err != nil {
+		return model.Categoria{}, err
+	}
+	categoria.PlaceUnder(parent)
+
+	result, err := s.repo.Create(ctx, categoria)
+	if err != nil {
+		if err == repository.ErrSlugConflict {
+			return model.Categoria{}, errors.ErrCategoriaSlugConflict
+		}
+		return model.Categoria{}, errors.WrapError(err, errors.ErrDatabase)
+	}
+	return result, nil
+}
+
+// FindByID retorna uma categoria pelo ID
+func (s *categoriaService) FindByID(ctx context.Context, id int) (model.Categoria, error) {
+	if id <= 0 {
+		return model.Categoria{}, errors.ErrInvalidID
+	}
+
+	result, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		if err.Error() == "not found" {
+			return model.Categoria{}, errors.ErrCategoriaNotFound
+		}
+		return model.Categoria{}, errors.WrapError(err, errors.ErrDatabase)
+	}
+	return result, nil
+}
+
+// FindAll retorna todas as categorias, ordenadas por path
+func (s *categoriaService) FindAll(ctx context.Context) ([]model.Categoria, error) {
+	result, err := s.repo.FindAll(ctx)
+	if err != nil {
+		return nil, errors.WrapError(err, errors.ErrDatabase)
+	}
+	return result, nil
+}
+
+// Update renomeia e/ou move a categoria; os descendentes acompanham a mudança
+func (s *categoriaService) Update(ctx context.Context, id int, categoria model.Categoria) (model.Categoria, error) {
+	if _, err := s.FindByID(ctx, id); err != nil {
+		return model.Categoria{}, err
+	}
+	if err := normalizeCategoria(&categoria); err != nil {
+		return model.Categoria{}, err
+	}
+
+	parent, err := s.findParent(ctx, categoria.ParentID)
+	if err != nil {
+		return model.Categoria{}, err
+	}
+	// Mover a categoria para baixo dela mesma criaria um ciclo
+	if parent != nil && (parent.ID == id || parent.IsDescendantOf(id)) {
+		return model.Categoria{}, errors.ErrCategoriaHierarquiaInvalida
+	}
+
+	categoria.ID = id
+	categoria.PlaceUnder(parent)
+
+	result, err := s.repo.Update(ctx, categoria)
+	if err != nil {
+		if err.Error() == "not found" {
+			return model.Categoria{}, errors.ErrCategoriaNotFound
+		}
+		if err == repository.ErrSlugConflict {
+			return model.Categoria{}, errors.ErrCategoriaSlugConflict
+		}
+		if err == repository.ErrCategoriaConflict {
+			return model.Categoria{}, errors.ErrCategoriaConflict
+		}
+		return model.Categoria{}, errors.WrapError(err, errors.ErrDatabase)
+	}
+	return result, nil
+}
+
+// Delete remove uma categoria sem subcategorias e sem produtos
+func (s *categoriaService) Delete(ctx context.Context, id int) error {
+	if _, err := s.FindByID(ctx, id); err != nil {
+		return err
+	}
+
+	children, err := s.repo.CountChildren(ctx, id)
+	if err != nil {
+		return errors.WrapError(err, errors.ErrDatabase)
+	}
+	if children > 0 {
+		return errors.ErrCategoriaEmUso.WithDetailsf("a categoria possui %d subcategoria(s)", children)
+	}
+
+	if s.produtos != nil {
+		filter := map[string]interface{}{"categoria_id": id}
+		produtos, err := s.produtos.Count(ctx, filter)
+		if err != nil {
+			return errors.WrapError(err, errors.ErrDatabase)
+		}
+		if produtos > 0 {
+			return errors.ErrCategoriaEmUso.WithDetailsf("a categoria possui %d produto(s)", produtos)
+		}
+		// Produtos na lixeira também contam: restaurados, voltariam a apontar
+		// para a categoria removida
+		trashed, err := s.produtos.CountDeleted(ctx, filter)
+		if err != nil {
+			return errors.WrapError(err, errors.ErrDatabase)
+		}
+		if trashed > 0 {
+			return errors.ErrCategoriaEmUso.WithDetailsf("a categoria possui %d produto(s) na lixeira", trashed)
+		}
+	}
+
+	if err := s.repo.Delete(ctx, id); err != nil {
+		if err.Error() == "not found" {
+			return errors.ErrCategoriaNotFound
+		}
+		return errors.WrapError(err, errors.ErrDatabase)
+	}
+
+	logger.WithFields(map[string]interface{}{
+		"categoria_id": id,
+	}).Debug("Categoria removida")
+	return nil
+}
+
+// findParent busca a categoria pai (nil para categorias raiz)
+func (s *categoriaService) findParent(ctx context.Context, parentID int) (*model.Categoria, error) {
+	if parentID == 0 {
+		return nil, nil
+	}
+	parent, err := s.repo.FindByID(ctx, parentID)
+	if err != nil {
+		if err.Error() == "not found" {
+			return nil, errors.ErrCategoriaInvalida.WithDetailsf("categoria pai %d não encontrada", parentID)
+		}
+		return nil, errors.WrapError(err, errors.ErrDatabase)
+	}
+	return &parent, nil
+}
+
+// normalizeCategoria valida o nome e gera o slug a partir do nome quando não informado
+func normalizeCategoria(categoria *model.Categoria) error {
+	if categoria.Nome == "" {
+		return errors.ErrNomeObrigatorio
+	}
+	if categoria.Slug == "" {
+		categoria.Slug = categoria.Nome
+	}
+	categoria.Slug = utils.Slugify(categoria.Slug)
+	if categoria.Slug == "" {
+		return errors.ErrInvalidInput.WithDetails("slug inválido: use letras ou números")
+	}
+	return nil
+}
@@ -0,0 +1,307 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"api-go-arquitetura/internal/dto"
+	apiErrors "api-go-arquitetura/internal/errors"
+	"api-go-arquitetura/internal/model"
+	"api-go-arquitetura/internal/repository"
+)
+
+// MockCategoriaRepository é um mock do CategoriaRepository para testes
+type MockCategoriaRepository struct {
+	categorias []model.Categoria
+	nextID     int
+}
+
+func NewMockCategoriaRepository() *MockCategoriaRepository {
+	return &MockCategoriaRepository{nextID: 1}
+}
+
+func (m *MockCategoriaRepository) Create(ctx context.Context, categoria model.Categoria) (model.Categoria, error) {
+	for _, c := range m.categorias {
+		if c.Slug == categoria.Slug {
+			return model.Categoria{}, repository.ErrSlugConflict
+		}
+	}
+	categoria.ID = m.nextID
+	categoria.BeforeCreate()
+	m.nextID++
+	m.categorias = append(m.categorias, categoria)
+	return categoria, nil
+}
+
+func (m *MockCategoriaRepository) FindByID(ctx context.Context, id int) (model.Categoria, error) {
+	for _, c := range m.categorias {
+		if c.ID == id {
+			return c, nil
+		}
+	}
+	return model.Categoria{}, errors.New("not found")
+}
+
+func (m *MockCategoriaRepository) FindAll(ctx context.Context) ([]model.Categoria, error) {
+	return m.categorias, nil
+}
+
+func (m *MockCategoriaRepository) Update(ctx context.Context, categoria model.Categoria) (model.Categoria, error) {
+	for i, c := range m.categorias {
+		if c.ID == categoria.ID {
+			categoria.BeforeUpdate()
+			m.categorias[i] = categoria
+			return categoria, nil
+		}
+	}
+	return model.Categoria{}, errors.New("not found")
+}
+
+func (m *MockCategoriaRepository) Delete(ctx context.Context, id int) error {
+	for i, c := range m.categorias {
+		if c.ID == id {
+			m.categorias = append(m.categorias[:i], m.categorias[i+1:]...)
+			return nil
+		}
+	}
+	return errors.New("not found")
+}
+
+func (m *MockCategoriaRepository) FindDescendantIDs(ctx context.Context, id int) ([]int, error) {
+	var ids []int
+	for _, c := range m.categorias {
+		if c.IsDescendantOf(id) {
+			ids = append(ids, c.ID)
+		}
+	}
+	return ids, nil
+}
+
+func (m *MockCategoriaRepository) CountChildren(ctx context.Context, id int) (int64, error) {
+	var count int64
+	for _, c := range m.categorias {
+		if c.ParentID == id {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// categoriaCountRepository conta os produtos pela categoria do filtro
+type categoriaCountRepository struct {
+	*MockRepository
+}
+
+func (r categoriaCountRepository) Count(ctx context.Context, filter map[string]interface{}) (int64, error) {
+	var count int64
+	for _, p := range r.produtos {
+		if !p.IsDeleted() && p.CategoriaID == filter["categoria_id"] {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (r categoriaCountRepository) CountDeleted(ctx context.Context, filter map[string]interface{}) (int64, error) {
+	var count int64
+	for _, p := range r.produtos {
+		if p.IsDeleted() && p.CategoriaID == filter["categoria_id"] {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// conflictingCategoriaRepository simula uma categoria alterada por outra
+// requisição entre a leitura e a escrita
+type conflictingCategoriaRepository struct {
+	*MockCategoriaRepository
+}
+
+func (r conflictingCategoriaRepository) Update(ctx context.Context, categoria model.Categoria) (model.Categoria, error) {
+	return model.Categoria{}, repository.ErrCategoriaConflict
+}
+
+func assertAPIError(t *testing.T, err error, expected *apiErrors.APIError) {
+	t.Helper()
+	if apiErr := apiErrors.AsAPIError(err); apiErr == nil || apiErr.Code != expected.Code {
+		t.Errorf("Esperado %s, obtido %v", expected.Code, err)
+	}
+}
+
+func TestCategoriaService_Create(t *testing.T) {
+	ctx := context.Background()
+	service := NewCategoriaService(NewMockCategoriaRepository(), nil)
+
+	raiz, err := service.Create(ctx, model.Categoria{Nome: "Eletrônicos"})
+	if err != nil {
+		t.Fatalf("Erro inesperado: %v", err)
+	}
+	if raiz.Slug != "eletronicos" || raiz.Path != "eletronicos" || len(raiz.Ancestors) != 0 {
+		t.Errorf("Categoria raiz incorreta: %+v", raiz)
+	}
+
+	t.Run("deve materializar o caminho da subcategoria", func(t *testing.T) {
+		filha, err := service.Create(ctx, model.Categoria{Nome: "Notebooks Gamer", ParentID: raiz.ID})
+		if err != nil {
+			t.Fatalf("Erro inesperado: %v", err)
+		}
+		if filha.Path != "eletronicos/notebooks-gamer" || !reflect.DeepEqual(filha.Ancestors, []int{raiz.ID}) {
+			t.Errorf("Posição incorreta: path=%s ancestors=%v", filha.Path, filha.Ancestors)
+		}
+	})
+
+	t.Run("deve rejeitar pai inexistente", func(t *testing.T) {
+		_, err := service.Create(ctx, model.Categoria{Nome: "Órfã", ParentID: 99})
+		assertAPIError(t, err, apiErrors.ErrCategoriaInvalida)
+	})
+
+	t.Run("deve rejeitar slug repetido", func(t *testing.T) {
+		_, err := service.Create(ctx, model.Categoria{Nome: "Outra", Slug: "Eletrônicos"})
+		assertAPIError(t, err, apiErrors.ErrCategoriaSlugConflict)
+	})
+}
+
+func TestCategoriaService_Update(t *testing.T) {
+	ctx := context.Background()
+	service := NewCategoriaService(NewMockCategoriaRepository(), nil)
+
+	a, _ := service.Create(ctx, model.Categoria{Nome: "A"})
+	b, _ := service.Create(ctx, model.Categoria{Nome: "B", ParentID: a.ID})
+	c, _ := service.Create(ctx, model.Categoria{Nome: "C", ParentID: b.ID})
+	d, _ := service.Create(ctx, model.Categoria{Nome: "D"})
+
+	t.Run("deve impedir ciclos na hierarquia", func(t *testing.T) {
+		_, err := service.Update(ctx, a.ID, model.Categoria{Nome: "A", ParentID: a.ID})
+		assertAPIError(t, err, apiErrors.ErrCategoriaHierarquiaInvalida)
+
+		_, err = service.Update(ctx, a.ID, model.Categoria{Nome: "A", ParentID: c.ID})
+		assertAPIError(t, err, apiErrors.ErrCategoriaHierarquiaInvalida)
+	})
+
+	t.Run("deve mover a categoria para outro pai", func(t *testing.T) {
+		moved, err := service.Update(ctx, b.ID, model.Categoria{Nome: "B", ParentID: d.ID})
+		if err != nil {
+			t.Fatalf("Erro inesperado: %v", err)
+		}
+		if moved.Path != "d/b" || !reflect.DeepEqual(moved.Ancestors, []int{d.ID}) {
+			t.Errorf("Posição incorreta: path=%s ancestors=%v", moved.Path, moved.Ancestors)
+		}
+	})
+
+	t.Run("deve retornar 404 para categoria inexistente", func(t *testing.T) {
+		_, err := service.Update(ctx, 99, model.Categoria{Nome: "X"})
+		assertAPIError(t, err, apiErrors.ErrCategoriaNotFound)
+	})
+
+	t.Run("deve retornar 409 quando a categoria muda durante a escrita", func(t *testing.T) {
+		conflicting := NewCategoriaService(conflictingCategoriaRepository{NewMockCategoriaRepository()}, nil)
+		created, _ := conflicting.Create(ctx, model.Categoria{Nome: "E"})
+		_, err := conflicting.Update(ctx, created.ID, model.Categoria{Nome: "E2"})
+		assertAPIError(t, err, apiErrors.ErrCategoriaConflict)
+	})
+}
+
+func TestCategoriaService_Delete(t *testing.T) {
+	ctx := context.Background()
+	produtos := categoriaCountRepository{NewMockRepository().(*MockRepository)}
+	service := NewCategoriaService(NewMockCategoriaRepository(), produtos)
+
+	pai, _ := service.Create(ctx, model.Categoria{Nome: "Pai"})
+	filha, _ := service.Create(ctx, model.Categoria{Nome: "Filha", ParentID: pai.ID})
+	produtos.Create(ctx, model.Produto{Nome: "Produto", Preco: 10, CategoriaID: filha.ID})
+
+	t.Run("deve rejeitar categoria com subcategorias", func(t *testing.T) {
+		assertAPIError(t, service.Delete(ctx, pai.ID), apiErrors.ErrCategoriaEmUso)
+	})
+
+	t.Run("deve rejeitar categoria com produtos", func(t *testing.T) {
+		assertAPIError(t, service.Delete(ctx, filha.ID), apiErrors.ErrCategoriaEmUso)
+	})
+
+	t.Run("deve rejeitar categoria com produtos na lixeira", func(t *testing.T) {
+		lixeira, _ := service.Create(ctx, model.Categoria{Nome: "Lixeira"})
+		produto, _ := produtos.Create(ctx, model.Produto{Nome: "Removido", Preco: 10, CategoriaID: lixeira.ID})
+		if _, err := produtos.Delete(ctx, produto.ID, nil); err != nil {
+			t.Fatalf("Erro ao remover produto: %v", err)
+		}
+		assertAPIError(t, service.Delete(ctx, lixeira.ID), apiErrors.ErrCategoriaEmUso)
+	})
+
+	t.Run("deve remover categoria sem uso", func(t *testing.T) {
+		vazia, _ := service.Create(ctx, model.Categoria{Nome: "Vazia"})
+		if err := service.Delete(ctx, vazia.ID); err != nil {
+			t.Fatalf("Erro inesperado: %v", err)
+		}
+		_, err := service.FindByID(ctx, vazia.ID)
+		assertAPIError(t, err, apiErrors.ErrCategoriaNotFound)
+	})
+}
+
+func TestProdutoService_Categoria(t *testing.T) {
+	ctx := context.Background()
+	categorias := NewMockCategoriaRepository()
+	repo := &sortCapturingRepository{ProdutoRepository: NewMockRepository()}
+	service := NewProdutoServiceWithOptions(repo, nil, ProdutoServiceOptions{Categorias: categorias})
+
+	catService := NewCategoriaService(categorias, nil)
+	raiz, _ := catService.Create(ctx, model.Categoria{Nome: "Raiz"})
+	filha, _ := catService.Create(ctx, model.Categoria{Nome: "Filha", ParentID: raiz.ID})
+	neta, _ := catService.Create(ctx, model.Categoria{Nome: "Neta", ParentID: filha.ID})
+
+	t.Run("deve validar a categoria do produto", func(t *testing.T) {
+		_, err := service.Create(ctx, model.Produto{Nome: "Produto", Preco: 10, CategoriaID: 99})
+		assertAPIError(t, err, apiErrors.ErrCategoriaInvalida)
+
+		created, err := service.Create(ctx, model.Produto{Nome: "Produto", Preco: 10, CategoriaID: filha.ID})
+		if err != nil {
+			t.Fatalf("Erro inesperado: %v", err)
+		}
+
+		_, err = service.Patch(ctx, created.ID, map[string]interface{}{"categoria_id": 99}, nil)
+		assertAPIError(t, err, apiErrors.ErrCategoriaInvalida)
+
+		_, err = service.Update(ctx, created.ID, model.Produto{Nome: "Produto", Preco: 10, CategoriaID: 99}, nil)
+		assertAPIError(t, err, apiErrors.ErrCategoriaInvalida)
+	})
+
+	t.Run("deve validar a categoria no lote", func(t *testing.T) {
+		results, err := service.Batch(ctx, []BatchOperation{
+			{Op: dto.BatchOpCreate, Produto: model.Produto{Nome: "Lote", Preco: 10, CategoriaID: 99}},
+		}, false)
+		if err != nil {
+			t.Fatalf("Erro inesperado: %v", err)
+		}
+		if results[0].Err == nil || results[0].Err.Code != apiErrors.ErrCategoriaInvalida.Code {
+			t.Errorf("Esperado %s, obtido %v", apiErrors.ErrCategoriaInvalida.Code, results[0].Err)
+		}
+	})
+
+	t.Run("deve filtrar pela categoria e pelas subcategorias", func(t *testing.T) {
+		categoria := raiz.ID
+		_, err := service.FindAllPaginated(ctx, dto.PaginationRequest{}, dto.FilterRequest{Categoria: &categoria}, dto.SortRequest{})
+		if err != nil {
+			t.Fatalf("Erro inesperado: %v", err)
+		}
+		if repo.filter["categoria_id"] != raiz.ID {
+			t.Errorf("Filtro categoria_id = %d esperado, obtido %v", raiz.ID, repo.filter)
+		}
+
+		_, err = service.FindAllPaginated(ctx, dto.PaginationRequest{}, dto.FilterRequest{Categoria: &categoria, Subcategorias: true}, dto.SortRequest{})
+		if err != nil {
+			t.Fatalf("Erro inesperado: %v", err)
+		}
+		in, _ := repo.filter["categoria_id"].(map[string]interface{})
+		if expected := []int{raiz.ID, filha.ID, neta.ID}; !reflect.DeepEqual(in["$in"], expected) {
+			t.Errorf("Filtro $in %v esperado, obtido %v", expected, repo.filter)
+		}
+	})
+
+	t.Run("deve rejeitar categoria inválida no filtro", func(t *testing.T) {
+		categoria := 0
+		_, err := service.FindAllPaginated(ctx, dto.PaginationRequest{}, dto.FilterRequest{Categoria: &categoria}, dto.SortRequest{})
+		assertAPIError(t, err, apiErrors.ErrInvalidInput)
+	})
+}
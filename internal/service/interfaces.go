@@ -2,20 +2,105 @@ package service
 
 import (
 	"context"
+	"time"
 
 	"api-go-arquitetura/internal/dto"
+	"api-go-arquitetura/internal/errors"
 	"api-go-arquitetura/internal/model"
 )
 
+// ProdutoPage representa uma página de produtos com os metadados da listagem
+type ProdutoPage struct {
+	Produtos   []model.Produto
+	Pagination dto.PaginationResponse
+	ETag       string // Derivado do conteúdo da página (o mesmo armazenado no cache)
+}
+
+// ProdutoCursorPage representa uma página de produtos obtida por cursor (keyset)
+type ProdutoCursorPage struct {
+	Produtos   []model.Produto
+	Pagination dto.CursorPaginationResponse
+	ETag       string
+}
+
+// BatchOperation descreve uma operação do lote (dto.BatchOpCreate, BatchOpUpdate, BatchOpPatch ou BatchOpDelete)
+type BatchOperation struct {
+	Op              string
+	ID              int
+	ExpectedVersion *int // != nil habilita o controle de concorrência otimista
+	Produto         model.Produto
+	Updates         map[string]interface{}
+}
+
+// BatchResult é o resultado de uma operação do lote, na mesma posição da operação
+type BatchResult struct {
+	Produto model.Produto
+	Err     *errors.APIError // nil quando a operação foi aplicada
+}
+
 // ProdutoService define a interface para operações de produto
 type ProdutoService interface {
 	Create(ctx context.Context, produto model.Produto) (model.Produto, error)
 	FindAll(ctx context.Context) ([]model.Produto, error)
-	FindByID(ctx context.Context, id int) (model.Produto, error)
-	Update(ctx context.Context, id int, produto model.Produto) (model.Produto, error)
-	Patch(ctx context.Context, id int, updates map[string]interface{}) (model.Produto, error)
-	Delete(ctx context.Context, id int) error
+	// fields (dto.ParseFields) restringe os campos lidos do banco; vazio lê o produto inteiro
+	FindByID(ctx context.Context, id int, fields ...string) (model.Produto, error)
+	// expectedVersion != nil habilita o controle de concorrência otimista (If-Match)
+	Update(ctx context.Context, id int, produto model.Produto, expectedVersion *int) (model.Produto, error)
+	Patch(ctx context.Context, id int, updates map[string]interface{}, expectedVersion *int) (model.Produto, error)
+	Delete(ctx context.Context, id int, expectedVersion *int) error
 	// Novos métodos para paginação e filtros
-	FindAllPaginated(ctx context.Context, pagination dto.PaginationRequest, filter dto.FilterRequest, sort dto.SortRequest) ([]model.Produto, dto.PaginationResponse, error)
+	FindAllPaginated(ctx context.Context, pagination dto.PaginationRequest, filter dto.FilterRequest, sort dto.SortRequest, fields ...string) (ProdutoPage, error)
+	// FindAllByCursor pagina por cursor (keyset): o custo não cresce com a profundidade
+	// e inserções concorrentes não causam itens repetidos ou pulados
+	FindAllByCursor(ctx context.Context, pagination dto.CursorPaginationRequest, filter dto.FilterRequest, sort dto.SortRequest, fields ...string) (ProdutoCursorPage, error)
+	// Export percorre todos os produtos que correspondem ao filtro, na ordem pedida,
+	// sem carregá-los em memória; erros retornados por fn são repassados sem alteração
+	Export(ctx context.Context, filter dto.FilterRequest, sort dto.SortRequest, fn func(model.Produto) error) error
+	// Facets calcula estatísticas de preço, histograma e contagem por tag dos
+	// produtos que correspondem ao filtro (resultado armazenado no cache)
+	Facets(ctx context.Context, filter dto.FilterRequest, facets dto.FacetRequest) (model.ProdutoFacets, error)
+	// Métodos para a lixeira (produtos com soft delete)
+	Restore(ctx context.Context, id int) (model.Produto, error)
+	FindTrashPaginated(ctx context.Context, pagination dto.PaginationRequest, sort dto.SortRequest) ([]model.Produto, dto.PaginationResponse, error)
+	PurgeTrash(ctx context.Context, before time.Time) (int64, error)
+	// Batch aplica várias operações de uma vez; com atomic = true, tudo ou nada
+	Batch(ctx context.Context, ops []BatchOperation, atomic bool) ([]BatchResult, error)
+	// Estoque: o disponível (estoque - reservado) nunca fica negativo
+	AdjustStock(ctx context.Context, id int, delta int) (model.Produto, error)
+	// Reserve reserva quantidade até ttl (0 = DefaultReservationTTL); sem reservaID, um ID é gerado
+	Reserve(ctx context.Context, id int, quantidade int, reservaID string, ttl time.Duration) (model.Reserva, model.Produto, error)
+	Release(ctx context.Context, id int, reservaID string) (model.Produto, error)
+	// ReleaseExpired libera as reservas expiradas em now (usado pelo ReservationSweeper)
+	ReleaseExpired(ctx context.Context, now time.Time) (int, error)
+	// PriceHistory retorna as alterações de preço registradas por Update e Patch, mais recentes primeiro
+	PriceHistory(ctx context.Context, id int, pagination dto.PaginationRequest, filter dto.PriceHistoryFilter) ([]model.PriceChange, dto.PaginationResponse, error)
 }
 
+
+// CategoriaService define a interface para operações de categoria
+type CategoriaService interface {
+	Create(ctx context.Context, categoria model.Categoria) (model.Categoria, error)
+	FindByID(ctx context.Context, id int) (model.Categoria, error)
+	// FindAll retorna todas as categorias ordenadas por path (pais antes dos filhos)
+	FindAll(ctx context.Context) ([]model.Categoria, error)
+	// Update renomeia e/ou move a categoria (ParentID); os descendentes acompanham a mudança
+	Update(ctx context.Context, id int, categoria model.Categoria) (model.Categoria, error)
+	// Delete falha com ErrCategoriaEmUso se a categoria tiver subcategorias ou produtos
+	Delete(ctx context.Context, id int) error
+}
+
+// WebhookService define a interface para os webhooks e suas entregas
+type WebhookService interface {
+	// Create cadastra o webhook; sem secret, um secret aleatório é gerado
+	Create(ctx context.Context, webhook model.Webhook) (model.Webhook, error)
+	FindByID(ctx context.Context, id int) (model.Webhook, error)
+	FindAll(ctx context.Context) ([]model.Webhook, error)
+	// Update substitui o webhook; sem secret, o secret atual é mantido
+	Update(ctx context.Context, id int, webhook model.Webhook) (model.Webhook, error)
+	// Delete remove o webhook e o histórico de suas entregas
+	Delete(ctx context.Context, id int) error
+	// Deliveries retorna as entregas do webhook, mais recentes primeiro (status vazio = todas)
+	Deliveries(ctx context.Context, id int, status string, pagination dto.PaginationRequest) ([]model.WebhookDelivery, dto.PaginationResponse, error)
+	// Redeliver agenda uma nova entrega imediata, com novo ciclo de tentativas
+	Redeliver(ctx context.Context, id int, deliveryID string) (model.WebhookDelivery, error)
+}
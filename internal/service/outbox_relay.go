@@ -0,0 +1,180 @@
+package service
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"api-go-arquitetura/internal/database"
+	"api-go-arquitetura/internal/events"
+	"api-go-arquitetura/internal/logger"
+	"api-go-arquitetura/internal/metrics"
+)
+
+// outboxLeaseMargin é somada ao pior tempo de publicação de um lote na reserva dos eventos
+const outboxLeaseMargin = 30 * time.Second
+
+// OutboxRelay publica periodicamente os eventos pendentes do outbox
+// A entrega é "pelo menos uma vez": um evento só é marcado como publicado após
+// o publisher confirmar, e é publicado novamente se o relay parar antes disso
+type OutboxRelay struct {
+	outbox    events.Outbox
+	publisher events.Publisher
+	interval       time.Duration
+	timeout        time.Duration
+	publishTimeout time.Duration // Limite de cada tentativa de publicação
+	batchSize      int
+	retry          database.RetryOptions // Tentativas imediatas de cada publicação
+	backoff        database.RetryOptions // Espera até a próxima tentativa agendada, por número de tentativas
+
+	mu      sync.Mutex
+	running bool
+	stop    chan struct{}
+	done    chan struct{}
+}
+
+// NewOutboxRelay cria um novo OutboxRelay
+// interval <= 0 desabilita a execução agendada
+func NewOutboxRelay(outbox events.Outbox, publisher events.Publisher, interval time.Duration) *OutboxRelay {
+	return &OutboxRelay{
+		outbox:    outbox,
+		publisher: publisher,
+		interval:       interval,
+		timeout:        15 * time.Minute,
+		publishTimeout: events.DefaultPublishTimeout,
+		batchSize:      20,
+		retry:          database.DefaultRetryOptions(),
+		backoff: database.RetryOptions{
+			InitialDelay: time.Second,
+			MaxDelay:     10 * time.Minute,
+			Multiplier:   2.0,
+		},
+	}
+}
+
+// lease retorna por quanto tempo os eventos de um lote ficam reservados: o tempo
+// para publicá-los se todas as tentativas de todos esgotarem o timeout, mais uma
+// margem. Assim, outra instância não publica um evento que ainda está sendo publicado
+func (r *OutboxRelay) lease() time.Duration {
+	attempts := r.retry.MaxAttempts
+	if attempts < 1 {
+		attempts = 1
+	}
+	perMessage := time.Duration(attempts) * r.publishTimeout
+	for attempt := 1; attempt < attempts; attempt++ {
+		perMessage += r.retry.Delay(attempt)
+	}
+	return time.Duration(r.batchSize)*perMessage + outboxLeaseMargin
+}
+
+// Start inicia a execução agendada em background
+func (r *OutboxRelay) Start() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.running || r.interval <= 0 {
+		return
+	}
+	r.running = true
+	r.stop = make(chan struct{})
+	r.done = make(chan struct{})
+
+	go r.loop(r.stop, r.done)
+
+	logger.WithField("interval", r.interval.String()).Info("Relay do outbox iniciado")
+}
+
+// Stop interrompe a execução agendada e aguarda a execução em andamento terminar
+func (r *OutboxRelay) Stop() {
+	r.mu.Lock()
+	if !r.running {
+		r.mu.Unlock()
+		return
+	}
+	r.running = false
+	close(r.stop)
+	done := r.done
+	r.mu.Unlock()
+
+	<-done
+	logger.Info("Relay do outbox encerrado")
+}
+
+// loop publica os eventos pendentes a cada intervalo até receber o sinal de parada
+func (r *OutboxRelay) loop(stop <-chan struct{}, done chan<- struct{}) {
+	defer close(done)
+
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			ctx, cancel := context.WithTimeout(context.Background(), r.timeout)
+			_, _ = r.Run(ctx)
+			cancel()
+		}
+	}
+}
+
+// Run publica imediatamente os eventos pendentes, em lotes, até não haver mais
+// eventos disponíveis. Retorna quantos foram publicados
+// Falhas de publicação não interrompem a execução: o evento é reagendado com
+// backoff exponencial e publicado em uma execução posterior
+// Um novo lote só é reservado se o prazo de ctx cobrir a reserva inteira
+func (r *OutboxRelay) Run(ctx context.Context) (int, error) {
+	published := 0
+	lease := r.lease()
+	for {
+		if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < lease {
+			return published, nil
+		}
+		messages, err := r.outbox.Claim(ctx, time.Now(), lease, r.batchSize)
+		if err != nil {
+			logger.WithField("error", err).Error("Erro ao buscar eventos pendentes do outbox")
+			return published, err
+		}
+
+		for _, msg := range messages {
+			ok, err := r.publish(ctx, msg)
+			if err != nil {
+				return published, err
+			}
+			if ok {
+				published++
+			}
+		}
+
+		if len(messages) < r.batchSize {
+			return published, nil
+		}
+	}
+}
+
+// publish publica a mensagem e registra o resultado no outbox
+// Retorna se a mensagem foi publicada; o erro indica falha ao atualizar o outbox
+func (r *OutboxRelay) publish(ctx context.Context, msg events.Message) (bool, error) {
+	err := database.Retry(ctx, func() error {
+		publishCtx, cancel := context.WithTimeout(ctx, r.publishTimeout)
+		defer cancel()
+		return r.publisher.Publish(publishCtx, msg.Event)
+	}, r.retry)
+	if err == nil {
+		metrics.RecordOutboxPublication(msg.Type, "published")
+		return true, r.outbox.MarkPublished(ctx, msg.ID, time.Now())
+	}
+
+	metrics.RecordOutboxPublication(msg.Type, "failed")
+	attempts := msg.Attempts + 1
+	next := time.Now().Add(r.backoff.Delay(attempts))
+	logger.WithFields(map[string]interface{}{
+		"event_id":     msg.ID,
+		"event_type":   msg.Type,
+		"attempts":     attempts,
+		"next_attempt": next,
+		"error":        err,
+	}).Warn("Erro ao publicar evento do outbox, nova tentativa agendada")
+	return false, r.outbox.MarkFailed(ctx, msg.ID, err.Error(), next)
+}
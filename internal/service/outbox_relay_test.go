@@ -0,0 +1,198 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"api-go-arquitetura/internal/events"
+)
+
+// newTestRelay cria um relay sem espera entre as tentativas
+func newTestRelay(outbox events.Outbox, publisher events.Publisher) *OutboxRelay {
+	relay := NewOutboxRelay(outbox, publisher, 0)
+	relay.retry.InitialDelay = time.Millisecond
+	relay.retry.MaxDelay = time.Millisecond
+	relay.backoff.InitialDelay = 0
+	relay.backoff.MaxDelay = 0
+	return relay
+}
+
+// addEvents grava no outbox um evento de cada tipo informado, em ordem
+func addEvents(t *testing.T, outbox events.Outbox, types ...string) {
+	t.Helper()
+	for i, eventType := range types {
+		event, err := events.New(context.Background(), eventType, events.AggregateProduto, i+1, 1, map[string]interface{}{"id": i + 1})
+		if err != nil {
+			t.Fatalf("Erro ao criar evento: %v", err)
+		}
+		event.OccurredAt = time.Now().Add(time.Duration(i-len(types)) * time.Second)
+		if err := outbox.Add(context.Background(), event); err != nil {
+			t.Fatalf("Erro ao gravar evento: %v", err)
+		}
+	}
+}
+
+func TestOutboxRelay_Run(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("deve publicar os eventos pendentes em ordem", func(t *testing.T) {
+		outbox := events.NewMemoryOutbox()
+		addEvents(t, outbox, events.ProdutoCriado, events.ProdutoAtualizado, events.PrecoAlterado)
+
+		bus := events.NewBus()
+		var received []string
+		bus.Subscribe("", func(ctx context.Context, event events.Event) error {
+			received = append(received, event.Type)
+			return nil
+		})
+
+		relay := newTestRelay(outbox, bus)
+		published, err := relay.Run(ctx)
+		if err != nil {
+			t.Fatalf("Erro inesperado: %v", err)
+		}
+		if published != 3 {
+			t.Errorf("Esperados 3 eventos publicados, obtidos %d", published)
+		}
+		want := []string{events.ProdutoCriado, events.ProdutoAtualizado, events.PrecoAlterado}
+		for i := range want {
+			if i >= len(received) || received[i] != want[i] {
+				t.Fatalf("Esperada a ordem %v, obtida %v", want, received)
+			}
+		}
+		for _, msg := range outbox.Messages() {
+			if msg.Status != events.StatusPublished || msg.PublishedAt == nil || msg.Attempts != 1 {
+				t.Errorf("Evento deveria estar publicado: %+v", msg)
+			}
+		}
+
+		published, err = relay.Run(ctx)
+		if err != nil || published != 0 {
+			t.Errorf("Eventos publicados não devem ser publicados novamente: %d (%v)", published, err)
+		}
+	})
+
+	t.Run("deve repetir imediatamente falhas transitórias", func(t *testing.T) {
+		outbox := events.NewMemoryOutbox()
+		addEvents(t, outbox, events.ProdutoCriado)
+
+		calls := 0
+		bus := events.NewBus()
+		bus.Subscribe(events.ProdutoCriado, func(ctx context.Context, event events.Event) error {
+			calls++
+			if calls < 3 {
+				return events.Transient(errors.New("indisponível"))
+			}
+			return nil
+		})
+
+		published, err := newTestRelay(outbox, bus).Run(ctx)
+		if err != nil || published != 1 {
+			t.Fatalf("Esperado 1 evento publicado, obtidos %d (%v)", published, err)
+		}
+		if calls != 3 {
+			t.Errorf("Esperadas 3 tentativas, obtidas %d", calls)
+		}
+	})
+
+	t.Run("deve reagendar falhas e entregar na execução seguinte", func(t *testing.T) {
+		outbox := events.NewMemoryOutbox()
+		addEvents(t, outbox, events.ProdutoRemovido)
+
+		calls := 0
+		bus := events.NewBus()
+		bus.Subscribe(events.ProdutoRemovido, func(ctx context.Context, event events.Event) error {
+			calls++
+			if calls == 1 {
+				return errors.New("payload rejeitado")
+			}
+			return nil
+		})
+		relay := newTestRelay(outbox, bus)
+
+		published, err := relay.Run(ctx)
+		if err != nil || published != 0 {
+			t.Fatalf("Nenhum evento deveria ser publicado: %d (%v)", published, err)
+		}
+		if calls != 1 {
+			t.Errorf("Falhas não transitórias não devem ser repetidas imediatamente: %d tentativas", calls)
+		}
+		msg := outbox.Messages()[0]
+		if msg.Status != events.StatusPending || msg.Attempts != 1 || msg.LastError != "payload rejeitado" {
+			t.Errorf("Evento deveria continuar pendente com o erro registrado: %+v", msg)
+		}
+
+		published, err = relay.Run(ctx)
+		if err != nil || published != 1 {
+			t.Fatalf("Esperado 1 evento publicado, obtidos %d (%v)", published, err)
+		}
+		if msg := outbox.Messages()[0]; msg.Status != events.StatusPublished || msg.Attempts != 2 {
+			t.Errorf("Evento deveria estar publicado após 2 tentativas: %+v", msg)
+		}
+	})
+
+	t.Run("não deve publicar eventos reservados por outra instância", func(t *testing.T) {
+		outbox := events.NewMemoryOutbox()
+		addEvents(t, outbox, events.ProdutoCriado, events.ProdutoAtualizado)
+
+		claimed, err := outbox.Claim(ctx, time.Now(), time.Minute, 1)
+		if err != nil || len(claimed) != 1 {
+			t.Fatalf("Esperado 1 evento reservado, obtidos %d (%v)", len(claimed), err)
+		}
+
+		published, err := newTestRelay(outbox, events.NewBus()).Run(ctx)
+		if err != nil || published != 1 {
+			t.Errorf("Apenas o evento não reservado deveria ser publicado: %d (%v)", published, err)
+		}
+	})
+	t.Run("a reserva deve cobrir a publicação do lote inteiro", func(t *testing.T) {
+		relay := NewOutboxRelay(events.NewMemoryOutbox(), events.NewBus(), 0)
+		worst := time.Duration(relay.batchSize*relay.retry.MaxAttempts) * relay.publishTimeout
+		if lease := relay.lease(); lease <= worst {
+			t.Errorf("Reserva de %v não cobre %d publicações com %d tentativas de até %v", lease, relay.batchSize, relay.retry.MaxAttempts, relay.publishTimeout)
+		}
+		if relay.lease() >= relay.timeout {
+			t.Errorf("A reserva (%v) deve caber no timeout de cada execução (%v)", relay.lease(), relay.timeout)
+		}
+	})
+
+	t.Run("não deve reservar eventos sem prazo para publicá-los", func(t *testing.T) {
+		outbox := events.NewMemoryOutbox()
+		addEvents(t, outbox, events.ProdutoCriado)
+
+		relay := newTestRelay(outbox, events.NewBus())
+		runCtx, cancel := context.WithTimeout(ctx, relay.lease()/2)
+		defer cancel()
+
+		published, err := relay.Run(runCtx)
+		if err != nil || published != 0 {
+			t.Fatalf("Nenhum evento deveria ser publicado: %d (%v)", published, err)
+		}
+		if msg := outbox.Messages()[0]; msg.Status != events.StatusPending || msg.Attempts != 0 {
+			t.Errorf("Evento não deveria ter sido reservado: %+v", msg)
+		}
+	})
+
+	t.Run("deve limitar o tempo de cada tentativa de publicação", func(t *testing.T) {
+		outbox := events.NewMemoryOutbox()
+		addEvents(t, outbox, events.ProdutoCriado)
+
+		bus := events.NewBus()
+		bus.Subscribe(events.ProdutoCriado, func(ctx context.Context, event events.Event) error {
+			<-ctx.Done()
+			return events.Transient(ctx.Err())
+		})
+		relay := newTestRelay(outbox, bus)
+		relay.publishTimeout = 10 * time.Millisecond
+
+		published, err := relay.Run(ctx)
+		if err != nil || published != 0 {
+			t.Fatalf("Nenhum evento deveria ser publicado: %d (%v)", published, err)
+		}
+		if msg := outbox.Messages()[0]; msg.Status != events.StatusPending || msg.Attempts != 1 {
+			t.Errorf("Evento deveria ser reagendado após o timeout: %+v", msg)
+		}
+	})
+}
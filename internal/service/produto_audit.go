@@ -0,0 +1,61 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"api-go-arquitetura/internal/audit"
+	"api-go-arquitetura/internal/logger"
+	"api-go-arquitetura/internal/metrics"
+	"api-go-arquitetura/internal/model"
+	"api-go-arquitetura/internal/utils"
+
+	"github.com/google/uuid"
+)
+
+// auditIgnoredFields não entram no diff: mudam em toda alteração
+var auditIgnoredFields = []string{"updated_at"}
+
+// recordAudit registra a alteração do produto no log de auditoria, com o autor e
+// o request ID do contexto. A alteração já foi aplicada: falhas ao gravar o
+// registro são reportadas em log e métricas, sem retornar erro
+func (s *produtoService) recordAudit(ctx context.Context, action string, id int, before, after *model.Produto) {
+	if s.auditor == nil {
+		return
+	}
+
+	changes, err := audit.Diff(before, after, auditIgnoredFields...)
+	if err != nil {
+		metrics.RecordAuditEntry(audit.EntityProduto, action, "error")
+		logger.WithField("error", err).Error("Erro ao calcular alterações para auditoria")
+		return
+	}
+
+	actor := utils.ActorFromContext(ctx)
+	if actor == "" {
+		actor = audit.AnonymousActor
+	}
+	entry := audit.Entry{
+		ID:           uuid.New().String(),
+		Entity:       audit.EntityProduto,
+		EntityID:     id,
+		Action:       action,
+		Actor:        actor,
+		ClaimedActor: utils.ClaimedActorFromContext(ctx),
+		RequestID:    utils.RequestIDFromContext(ctx),
+		Changes:      changes,
+		Timestamp:    time.Now().UTC().Truncate(time.Millisecond),
+	}
+
+	// O registro é gravado mesmo se o cliente desconectar após a alteração
+	if err := s.auditor.Record(context.WithoutCancel(ctx), entry); err != nil {
+		metrics.RecordAuditEntry(audit.EntityProduto, action, "error")
+		logger.WithFields(map[string]interface{}{
+			"error":      err,
+			"produto_id": id,
+			"action":     action,
+		}).Error("Erro ao gravar registro de auditoria")
+		return
+	}
+	metrics.RecordAuditEntry(audit.EntityProduto, action, "success")
+}
@@ -0,0 +1,149 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"api-go-arquitetura/internal/audit"
+	"api-go-arquitetura/internal/dto"
+	"api-go-arquitetura/internal/model"
+	"api-go-arquitetura/internal/repository"
+	"api-go-arquitetura/internal/utils"
+)
+
+func TestProdutoService_Audit(t *testing.T) {
+	ctx := utils.WithActor(utils.WithRequestID(context.Background(), "req-1"), "maria")
+	ctx = utils.WithClaimedActor(ctx, "joao")
+	sink := audit.NewMemorySink()
+	service := NewProdutoServiceWithOptions(NewMockRepository(), nil, ProdutoServiceOptions{Auditor: sink})
+
+	produto, _ := service.Create(ctx, model.Produto{Nome: "Notebook", Preco: 3500})
+	_, _ = service.Update(ctx, produto.ID, model.Produto{Nome: "Notebook", Preco: 3300}, nil)
+	_, _ = service.Patch(ctx, produto.ID, map[string]interface{}{"nome": "Notebook Pro"}, nil)
+	_ = service.Delete(ctx, produto.ID, nil)
+	_, _ = service.Restore(ctx, produto.ID)
+
+	entries := sink.Entries()
+	actions := []string{audit.ActionCreate, audit.ActionUpdate, audit.ActionPatch, audit.ActionDelete, audit.ActionRestore}
+	if len(entries) != len(actions) {
+		t.Fatalf("Esperados %d registros, obtidos %d", len(actions), len(entries))
+	}
+	for i, action := range actions {
+		e := entries[i]
+		if e.Action != action || e.Entity != audit.EntityProduto || e.EntityID != produto.ID {
+			t.Errorf("Registro %d incorreto: %+v", i, e)
+		}
+		if e.Actor != "maria" || e.ClaimedActor != "joao" || e.RequestID != "req-1" {
+			t.Errorf("Registro %d sem autor ou request ID: %+v", i, e)
+		}
+		if _, ok := e.Changes["updated_at"]; ok {
+			t.Errorf("Registro %d não deveria incluir updated_at", i)
+		}
+	}
+
+	t.Run("create deve registrar todos os campos", func(t *testing.T) {
+		change := entries[0].Changes["nome"]
+		if change.Before != nil || change.After != "Notebook" {
+			t.Errorf("Alteração de nome incorreta: %+v", change)
+		}
+	})
+
+	t.Run("update deve registrar apenas os campos alterados", func(t *testing.T) {
+		changes := entries[1].Changes
+		if preco := changes["preco"]; preco.Before != 3500.0 || preco.After != 3300.0 {
+			t.Errorf("Alteração de preço incorreta: %+v", preco)
+		}
+		if _, ok := changes["nome"]; ok {
+			t.Error("Nome não mudou e não deveria aparecer no diff")
+		}
+	})
+
+	t.Run("delete e restore devem registrar deleted_at", func(t *testing.T) {
+		if deleted := entries[3].Changes["deleted_at"]; deleted.Before != nil || deleted.After == nil {
+			t.Errorf("Remoção incorreta: %+v", deleted)
+		}
+		if restored := entries[4].Changes["deleted_at"]; restored.Before == nil || restored.After != nil {
+			t.Errorf("Restauração incorreta: %+v", restored)
+		}
+	})
+
+	t.Run("não deve registrar alterações que falharam", func(t *testing.T) {
+		_, _ = service.Update(ctx, 999, model.Produto{Nome: "X", Preco: 1}, nil)
+		_, _ = service.Patch(ctx, produto.ID, map[string]interface{}{"nome": "Y"}, intPtr(99))
+		if total := len(sink.Entries()); total != len(actions) {
+			t.Errorf("Esperados %d registros, obtidos %d", len(actions), total)
+		}
+	})
+
+	t.Run("deve registrar as operações em lote com autor anônimo", func(t *testing.T) {
+		_, err := service.Batch(context.Background(), []BatchOperation{
+			{Op: dto.BatchOpCreate, Produto: model.Produto{Nome: "Mouse", Preco: 50}},
+			{Op: dto.BatchOpPatch, ID: produto.ID, Updates: map[string]interface{}{"preco": 3000.0}},
+		}, false)
+		if err != nil {
+			t.Fatalf("Erro inesperado: %v", err)
+		}
+		entries := sink.Entries()[len(actions):]
+		if len(entries) != 2 || entries[0].Action != audit.ActionCreate || entries[1].Action != audit.ActionPatch {
+			t.Fatalf("Registros do lote incorretos: %+v", entries)
+		}
+		if entries[1].Actor != audit.AnonymousActor {
+			t.Errorf("Autor esperado %q, obtido %q", audit.AnonymousActor, entries[1].Actor)
+		}
+	})
+}
+
+// concurrentWriteRepository aplica uma escrita concorrente antes de cada Update e
+// conta as leituras por ID (a auditoria não deve depender delas)
+type concurrentWriteRepository struct {
+	*MockRepository
+	reads int
+}
+
+func (r *concurrentWriteRepository) FindByID(ctx context.Context, id int, fields ...string) (model.Produto, error) {
+	r.reads++
+	return r.MockRepository.FindByID(ctx, id, fields...)
+}
+
+func (r *concurrentWriteRepository) FindDeletedByID(ctx context.Context, id int) (model.Produto, error) {
+	r.reads++
+	return r.MockRepository.FindDeletedByID(ctx, id)
+}
+
+func (r *concurrentWriteRepository) Update(ctx context.Context, id int, produto model.Produto, expectedVersion *int) (repository.ProdutoChange, error) {
+	_, _ = r.MockRepository.Patch(ctx, id, map[string]interface{}{"preco": 3400.0}, nil)
+	return r.MockRepository.Update(ctx, id, produto, expectedVersion)
+}
+
+func TestProdutoService_AuditUsesWriteResult(t *testing.T) {
+	ctx := context.Background()
+	sink := audit.NewMemorySink()
+	repo := &concurrentWriteRepository{MockRepository: NewMockRepository().(*MockRepository)}
+	service := NewProdutoServiceWithOptions(repo, nil, ProdutoServiceOptions{Auditor: sink})
+
+	produto, _ := service.Create(ctx, model.Produto{Nome: "Notebook", Preco: 3500})
+	if _, err := service.Update(ctx, produto.ID, model.Produto{Nome: "Notebook", Preco: 3300}, nil); err != nil {
+		t.Fatalf("Erro inesperado: %v", err)
+	}
+	if err := service.Delete(ctx, produto.ID, nil); err != nil {
+		t.Fatalf("Erro inesperado: %v", err)
+	}
+
+	entries := sink.Entries()
+	if len(entries) != 3 {
+		t.Fatalf("Esperados 3 registros, obtidos %d", len(entries))
+	}
+	// O diff parte do produto substituído, incluindo a escrita concorrente
+	if preco := entries[1].Changes["preco"]; preco.Before != 3400.0 || preco.After != 3300.0 {
+		t.Errorf("Alteração de preço incorreta: %+v", preco)
+	}
+	if version := entries[1].Changes["version"]; version.Before != 2.0 || version.After != 3.0 {
+		t.Errorf("Alteração de versão incorreta: %+v", version)
+	}
+	if deleted := entries[2].Changes["deleted_at"]; deleted.Before != nil || deleted.After == nil {
+		t.Errorf("Remoção incorreta: %+v", deleted)
+	}
+	if repo.reads != 0 {
+		t.Errorf("A auditoria não deveria ler o produto, leituras: %d", repo.reads)
+	}
+}
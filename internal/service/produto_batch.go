@@ -0,0 +1,181 @@
+package service
+
+import (
+	"context"
+
+	"api-go-arquitetura/internal/audit"
+	"api-go-arquitetura/internal/dto"
+	"api-go-arquitetura/internal/errors"
+	"api-go-arquitetura/internal/logger"
+	"api-go-arquitetura/internal/model"
+	"api-go-arquitetura/internal/repository"
+)
+
+// bulkOperationTypes mapeia as operações do lote para os tipos do repositório
+var bulkOperationTypes = map[string]repository.BulkOperationType{
+	dto.BatchOpCreate: repository.BulkInsert,
+	dto.BatchOpUpdate: repository.BulkReplace,
+	dto.BatchOpPatch:  repository.BulkPatch,
+	dto.BatchOpDelete: repository.BulkDelete,
+}
+
+// Batch aplica as operações em lote
+// As operações inválidas recebem o erro correspondente e não são enviadas ao banco;
+// no modo atômico, uma única operação inválida impede a aplicação de todo o lote
+func (s *produtoService) Batch(ctx context.Context, ops []BatchOperation, atomic bool) ([]BatchResult, error) {
+	results := make([]BatchResult, len(ops))
+	bulkOps := make([]repository.BulkOperation, 0, len(ops))
+	bulkIndex := make([]int, 0, len(ops)) // posição da operação de cada item enviado ao repositório
+	seen := make(map[int]bool)
+
+	for i, op := range ops {
+		if err := validateBatchOperation(op, seen); err != nil {
+			results[i].Err = err
+			continue
+		}
+		if err := s.checkCategoria(ctx, batchCategoriaID(op)); err != nil {
+			results[i].Err = err
+			continue
+		}
+		bulkOps = append(bulkOps, repository.BulkOperation{
+			Type:            bulkOperationTypes[op.Op],
+			ID:              op.ID,
+			Produto:         op.Produto,
+			Updates:         op.Updates,
+			ExpectedVersion: op.ExpectedVersion,
+		})
+		bulkIndex = append(bulkIndex, i)
+	}
+
+	if len(bulkOps) < len(ops) && atomic {
+		for _, i := range bulkIndex {
+			results[i].Err = errors.ErrBatchAborted
+		}
+		return results, nil
+	}
+	if len(bulkOps) == 0 {
+		return results, nil
+	}
+
+	bulkResults, err := s.repo.BulkWrite(ctx, bulkOps, atomic)
+	if err != nil {
+		return nil, errors.WrapError(err, errors.ErrDatabase)
+	}
+
+	changed := false
+	for j, res := range bulkResults {
+		i := bulkIndex[j]
+		if res.Err != nil {
+			results[i].Err = bulkError(res.Err)
+			continue
+		}
+		changed = true
+		results[i].Produto = res.Produto
+		s.recordBatchAudit(ctx, ops[i], res)
+		if ops[i].Op != dto.BatchOpCreate {
+			s.invalidateProdutoKey(ctx, ops[i].ID)
+		}
+	}
+	if changed {
+		s.invalidateListCache(ctx)
+	}
+
+	logger.WithFields(map[string]interface{}{
+		"operations": len(ops),
+		"atomic":     atomic,
+	}).Debug("Lote de produtos processado")
+
+	return results, nil
+}
+
+// recordBatchAudit registra no log de auditoria uma operação do lote aplicada
+// O diff usa o produto antes e depois da própria escrita, retornados pelo repositório
+func (s *produtoService) recordBatchAudit(ctx context.Context, op BatchOperation, res repository.BulkResult) {
+	if s.auditor == nil {
+		return
+	}
+	switch op.Op {
+	case dto.BatchOpCreate:
+		s.recordAudit(ctx, audit.ActionCreate, res.Produto.ID, nil, &res.Produto)
+	case dto.BatchOpDelete:
+		s.recordAudit(ctx, audit.ActionDelete, op.ID, &res.Before, &res.Produto)
+	case dto.BatchOpUpdate:
+		s.recordAudit(ctx, audit.ActionUpdate, op.ID, &res.Before, &res.Produto)
+	case dto.BatchOpPatch:
+		s.recordAudit(ctx, audit.ActionPatch, op.ID, &res.Before, &res.Produto)
+	}
+}
+
+// batchCategoriaID retorna a categoria atribuída pela operação (0 = nenhuma)
+func batchCategoriaID(op BatchOperation) int {
+	switch op.Op {
+	case dto.BatchOpCreate, dto.BatchOpUpdate:
+		return op.Produto.CategoriaID
+	case dto.BatchOpPatch:
+		id, _ := op.Updates["categoria_id"].(int)
+		return id
+	}
+	return 0
+}
+
+// validateBatchOperation aplica as mesmas validações das operações individuais
+// Um mesmo produto não pode aparecer em mais de uma operação do lote
+func validateBatchOperation(op BatchOperation, seen map[int]bool) *errors.APIError {
+	if _, ok := bulkOperationTypes[op.Op]; !ok {
+		return errors.ErrInvalidInput.WithDetailsf("operação '%s' inválida (use create, update, patch ou delete)", op.Op)
+	}
+
+	if op.Op != dto.BatchOpCreate {
+		if op.ID <= 0 {
+			return errors.ErrInvalidID
+		}
+		if seen[op.ID] {
+			return errors.ErrInvalidInput.WithDetailsf("produto %d aparece em mais de uma operação do lote", op.ID)
+		}
+		seen[op.ID] = true
+	}
+
+	switch op.Op {
+	case dto.BatchOpCreate, dto.BatchOpUpdate:
+		if op.Produto.Estoque < 0 {
+			return errors.ErrInvalidInput.WithDetails("estoque não pode ser negativo")
+		}
+		return validateProduto(op.Produto)
+	case dto.BatchOpPatch:
+		if len(op.Updates) == 0 {
+			return errors.ErrInvalidInput.WithDetails("nenhum campo informado para atualização")
+		}
+		if nome, ok := op.Updates["nome"].(string); ok && nome == "" {
+			return errors.ErrNomeObrigatorio
+		}
+		if preco, ok := op.Updates["preco"].(float64); ok && preco <= 0 {
+			return errors.ErrPrecoInvalido
+		}
+	}
+	return nil
+}
+
+// validateProduto aplica as validações de negócio de criação e atualização completa
+func validateProduto(produto model.Produto) *errors.APIError {
+	if produto.Nome == "" {
+		return errors.ErrNomeObrigatorio
+	}
+	if produto.Preco <= 0 {
+		return errors.ErrPrecoInvalido
+	}
+	return nil
+}
+
+// bulkError converte o erro de uma operação do repositório para APIError
+func bulkError(err error) *errors.APIError {
+	switch {
+	case err.Error() == "not found":
+		return errors.ErrProdutoNotFound
+	case err == repository.ErrVersionConflict:
+		return errors.ErrPreconditionFailed
+	case err == repository.ErrBulkAborted:
+		return errors.ErrBatchAborted
+	default:
+		return errors.WrapError(err, errors.ErrDatabase)
+	}
+}
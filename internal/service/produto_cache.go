@@ -0,0 +1,184 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"api-go-arquitetura/internal/cache"
+	"api-go-arquitetura/internal/errors"
+	"api-go-arquitetura/internal/logger"
+	"api-go-arquitetura/internal/metrics"
+)
+
+// revalidateTimeout limita a recarga em background de uma entrada vencida
+const revalidateTimeout = 30 * time.Second
+
+// cacheOps são os nomes das operações de leitura e escrita nas métricas de cache
+type cacheOps struct {
+	get string
+	set string
+}
+
+var (
+	produtoCacheOps = cacheOps{get: "get", set: "set"}
+	listCacheOps    = cacheOps{get: "get_list", set: "set_list"}
+	facetsCacheOps  = cacheOps{get: "get_facets", set: "set_facets"}
+)
+
+// cacheLoader carrega do banco o valor (serializado) de uma chave do cache
+// Os erros retornados já devem ser erros da API
+type cacheLoader func(ctx context.Context) ([]byte, error)
+
+// cachedLoad retorna o valor da chave, lido do cache ou carregado com load
+// decode recebe o valor (do cache ou carregado); um valor do cache que não pode
+// ser decodificado é tratado como ausente. Na falta, requisições concorrentes com
+// a mesma chave compartilham uma única carga. Com staleTTL, uma entrada vencida
+// é servida enquanto uma única recarga em background a substitui
+func (s *produtoService) cachedLoad(ctx context.Context, ops cacheOps, key string, decode func([]byte) error, load cacheLoader) ([]byte, error) {
+	data, fresh, ok := s.cacheGet(ctx, ops, key, decode)
+	if ok && fresh {
+		return data, nil
+	}
+	if ok && s.staleTTL > 0 {
+		metrics.RecordCacheStale(ops.get)
+		s.revalidate(ops, key, load)
+		return data, nil
+	}
+
+	data, err := s.loadShared(ctx, ops, key, load)
+	if err != nil {
+		return nil, err
+	}
+	if err := decode(data); err != nil {
+		return nil, errors.WrapError(err, errors.ErrInternalServer)
+	}
+	return data, nil
+}
+
+// loadDirect carrega e decodifica o valor sem passar pelo cache
+func loadDirect(ctx context.Context, decode func([]byte) error, load cacheLoader) ([]byte, error) {
+	data, err := load(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := decode(data); err != nil {
+		return nil, errors.WrapError(err, errors.ErrInternalServer)
+	}
+	return data, nil
+}
+
+// cacheGet lê a chave do cache e decodifica o valor
+// fresh indica se o valor ainda está dentro do TTL; ok, se foi encontrado
+func (s *produtoService) cacheGet(ctx context.Context, ops cacheOps, key string, decode func([]byte) error) (data []byte, fresh bool, ok bool) {
+	start := time.Now()
+	entry, err := s.cache.Get(ctx, key)
+	duration := time.Since(start)
+	if err != nil {
+		if err == cache.ErrCacheMiss {
+			metrics.RecordCacheMiss(ops.get, duration)
+		} else {
+			metrics.RecordCacheError(ops.get, duration)
+		}
+		return nil, false, false
+	}
+
+	data, freshUntil, err := cache.UnwrapEntry(entry)
+	if err == nil {
+		err = decode(data)
+	}
+	if err != nil {
+		metrics.RecordCacheError(ops.get, duration)
+		return nil, false, false
+	}
+
+	fresh = time.Now().Before(freshUntil)
+	if fresh {
+		metrics.RecordCacheHit(ops.get, duration)
+		logger.WithField("cache_key", key).Debug("Cache hit")
+	}
+	return data, fresh, true
+}
+
+// cacheSet armazena o valor, válido por ttl e mantido por mais staleTTL
+func (s *produtoService) cacheSet(ctx context.Context, ops cacheOps, key string, data []byte) {
+	start := time.Now()
+	entry := cache.WrapEntry(data, start.Add(s.ttl))
+	if err := s.cache.Set(ctx, key, entry, s.ttl+s.staleTTL); err != nil {
+		metrics.RecordCacheError(ops.set, time.Since(start))
+		logger.WithFields(map[string]interface{}{
+			"cache_key": key,
+			"error":     err,
+		}).Warn("Erro ao armazenar no cache")
+		return
+	}
+	metrics.RecordCacheOperation(ops.set, "success", time.Since(start))
+}
+
+// cacheSnapshot identifica as invalidações já feitas quando uma carga começa
+type cacheSnapshot struct {
+	invalidations uint64 // Invalidações feitas por este serviço
+	generation    int64  // Geração das listas, incrementada a cada alteração em qualquer instância
+}
+
+// snapshotCache retorna o estado atual das invalidações
+// Retorna false se a geração das listas não pôde ser lida
+func (s *produtoService) snapshotCache(ctx context.Context) (cacheSnapshot, bool) {
+	snapshot := cacheSnapshot{invalidations: s.invalidations.Load()}
+	generation, err := s.cache.Generation(ctx, cache.ProdutoListNamespace)
+	if err != nil {
+		return snapshot, false
+	}
+	snapshot.generation = generation
+	return snapshot, true
+}
+
+// loadShared carrega e armazena a chave uma única vez para todas as requisições
+// concorrentes com a mesma chave
+// A carga não é cancelada com a requisição que a iniciou, já que outras aguardam o
+// resultado. Se um produto foi alterado durante a carga, o valor carregado pode
+// estar desatualizado: ele é retornado, mas não é armazenado
+func (s *produtoService) loadShared(ctx context.Context, ops cacheOps, key string, load cacheLoader) ([]byte, error) {
+	leader := false
+	v, err, _ := s.flight.Do(key, func() (interface{}, error) {
+		leader = true
+		ctx := context.WithoutCancel(ctx)
+		before, ok := s.snapshotCache(ctx)
+		data, err := load(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if after, same := s.snapshotCache(ctx); ok && same && after == before {
+			s.cacheSet(ctx, ops, key, data)
+		} else {
+			logger.WithField("cache_key", key).Debug("Valor carregado durante uma alteração não foi armazenado no cache")
+		}
+		return data, nil
+	})
+	if !leader {
+		metrics.RecordCacheCoalesced(ops.get)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return v.([]byte), nil
+}
+
+// revalidate recarrega a chave em background, se ela ainda não estiver sendo recarregada
+func (s *produtoService) revalidate(ops cacheOps, key string, load cacheLoader) {
+	if _, running := s.revalidating.LoadOrStore(key, struct{}{}); running {
+		return
+	}
+
+	go func() {
+		defer s.revalidating.Delete(key)
+
+		ctx, cancel := context.WithTimeout(context.Background(), revalidateTimeout)
+		defer cancel()
+		if _, err := s.loadShared(ctx, ops, key, load); err != nil {
+			logger.WithFields(map[string]interface{}{
+				"cache_key": key,
+				"error":     err,
+			}).Warn("Erro ao recarregar entrada vencida do cache")
+		}
+	}()
+}
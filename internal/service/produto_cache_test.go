@@ -0,0 +1,171 @@
+package service
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"api-go-arquitetura/internal/cache"
+	"api-go-arquitetura/internal/model"
+	"api-go-arquitetura/internal/repository"
+)
+
+// gatedRepository conta as buscas por ID e as bloqueia enquanto o gate estiver fechado
+// Com readFirst, o produto é lido antes de aguardar o gate (simula uma leitura lenta)
+type gatedRepository struct {
+	repository.ProdutoRepository
+	finds     int32
+	gate      chan struct{}
+	readFirst bool
+}
+
+func (r *gatedRepository) FindByID(ctx context.Context, id int, fields ...string) (model.Produto, error) {
+	if r.readFirst {
+		produto, err := r.ProdutoRepository.FindByID(ctx, id, fields...)
+		atomic.AddInt32(&r.finds, 1)
+		<-r.gate
+		return produto, err
+	}
+	atomic.AddInt32(&r.finds, 1)
+	if r.gate != nil {
+		<-r.gate
+	}
+	return r.ProdutoRepository.FindByID(ctx, id, fields...)
+}
+
+func (r *gatedRepository) findCount() int {
+	return int(atomic.LoadInt32(&r.finds))
+}
+
+// waitFor aguarda a condição por até um segundo
+func waitFor(t *testing.T, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatal("Condição não atingida no tempo esperado")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestProdutoService_CacheMissCoalescing(t *testing.T) {
+	ctx := context.Background()
+	mockRepo := NewMockRepository()
+	created, _ := mockRepo.Create(ctx, model.Produto{Nome: "Notebook", Preco: 3500})
+
+	repo := &gatedRepository{ProdutoRepository: mockRepo, gate: make(chan struct{})}
+	service := NewProdutoService(repo, cache.NewMemoryCache())
+
+	const requests = 20
+	var wg sync.WaitGroup
+	errs := make(chan error, requests)
+	for i := 0; i < requests; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			produto, err := service.FindByID(ctx, created.ID)
+			if err == nil && produto.Nome != created.Nome {
+				t.Errorf("Nome esperado %s, obtido %s", created.Nome, produto.Nome)
+			}
+			errs <- err
+		}()
+	}
+
+	// Dar tempo para todas as requisições aguardarem a mesma carga
+	waitFor(t, func() bool { return repo.findCount() > 0 })
+	time.Sleep(50 * time.Millisecond)
+	close(repo.gate)
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		if err != nil {
+			t.Fatalf("Erro inesperado: %v", err)
+		}
+	}
+	if repo.findCount() != 1 {
+		t.Errorf("Esperada 1 consulta ao banco, obtidas %d", repo.findCount())
+	}
+}
+
+func TestProdutoService_StaleWhileRevalidate(t *testing.T) {
+	ctx := context.Background()
+	mockRepo := NewMockRepository().(*MockRepository)
+	created, _ := mockRepo.Create(ctx, model.Produto{Nome: "Notebook", Preco: 3500})
+
+	repo := &gatedRepository{ProdutoRepository: mockRepo}
+	service := NewProdutoServiceWithOptions(repo, cache.NewMemoryCache(), ProdutoServiceOptions{TTL: 100 * time.Millisecond, StaleTTL: time.Minute})
+
+	if _, err := service.FindByID(ctx, created.ID); err != nil {
+		t.Fatalf("Erro inesperado: %v", err)
+	}
+
+	// Alteração fora do serviço: o cache não é invalidado
+	mockRepo.produtos[0].Nome = "Notebook Pro"
+	time.Sleep(120 * time.Millisecond)
+
+	repo.gate = make(chan struct{})
+	for i := 0; i < 5; i++ {
+		produto, err := service.FindByID(ctx, created.ID)
+		if err != nil {
+			t.Fatalf("Erro inesperado: %v", err)
+		}
+		if produto.Nome != "Notebook" {
+			t.Errorf("Entrada vencida esperada enquanto recarrega, obtido %s", produto.Nome)
+		}
+	}
+
+	waitFor(t, func() bool { return repo.findCount() == 2 })
+	close(repo.gate)
+
+	waitFor(t, func() bool {
+		produto, err := service.FindByID(ctx, created.ID)
+		return err == nil && produto.Nome == "Notebook Pro"
+	})
+	if repo.findCount() != 2 {
+		t.Errorf("Esperada uma única recarga, obtidas %d consultas ao banco", repo.findCount())
+	}
+}
+
+func TestProdutoService_SlowLoadDuringUpdate(t *testing.T) {
+	ctx := context.Background()
+	mockRepo := NewMockRepository()
+	created, _ := mockRepo.Create(ctx, model.Produto{Nome: "Notebook", Preco: 3500})
+
+	repo := &gatedRepository{ProdutoRepository: mockRepo, gate: make(chan struct{}), readFirst: true}
+	service := NewProdutoService(repo, cache.NewMemoryCache())
+
+	// A carga lê o produto antes da atualização e só termina depois dela
+	loaded := make(chan model.Produto, 1)
+	go func() {
+		produto, err := service.FindByID(ctx, created.ID)
+		if err != nil {
+			t.Errorf("Erro inesperado: %v", err)
+		}
+		loaded <- produto
+	}()
+	waitFor(t, func() bool { return repo.findCount() == 1 })
+
+	if _, err := service.Update(ctx, created.ID, model.Produto{Nome: "Notebook Pro", Preco: 3500}, nil); err != nil {
+		t.Fatalf("Erro inesperado: %v", err)
+	}
+
+	close(repo.gate)
+	if produto := <-loaded; produto.Nome != "Notebook" {
+		t.Fatalf("A carga lenta deveria retornar o valor lido antes da atualização, obtido %s", produto.Nome)
+	}
+
+	produto, err := service.FindByID(ctx, created.ID)
+	if err != nil {
+		t.Fatalf("Erro inesperado: %v", err)
+	}
+	if produto.Nome != "Notebook Pro" {
+		t.Errorf("O valor lido antes da atualização não deveria ser armazenado no cache, obtido %s", produto.Nome)
+	}
+	if repo.findCount() != 2 {
+		t.Errorf("Esperadas 2 consultas ao banco, obtidas %d", repo.findCount())
+	}
+}
@@ -0,0 +1,151 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"api-go-arquitetura/internal/errors"
+	"api-go-arquitetura/internal/logger"
+	"api-go-arquitetura/internal/model"
+	"api-go-arquitetura/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// DefaultReservationTTL é a validade de uma reserva quando nenhuma é informada
+	DefaultReservationTTL = 15 * time.Minute
+	// MaxReservationTTL é a maior validade aceita para uma reserva
+	MaxReservationTTL = 24 * time.Hour
+
+	// expiredReservationsBatch é o número de reservas expiradas lidas por vez
+	expiredReservationsBatch = 100
+)
+
+// AdjustStock soma delta ao estoque do produto (negativo para baixa)
+func (s *produtoService) AdjustStock(ctx context.Context, id int, delta int) (model.Produto, error) {
+	if id <= 0 {
+		return model.Produto{}, errors.ErrInvalidID
+	}
+	if delta == 0 {
+		return model.Produto{}, errors.ErrInvalidInput.WithDetails("delta deve ser diferente de zero")
+	}
+
+	result, err := s.repo.AdjustStock(ctx, id, delta)
+	if err != nil {
+		return model.Produto{}, stockError(err)
+	}
+
+	s.invalidateProdutoCache(ctx, id)
+	return result, nil
+}
+
+// Reserve reserva quantidade do estoque disponível até ttl (0 = DefaultReservationTTL)
+// Sem reservaID, um novo ID é gerado
+func (s *produtoService) Reserve(ctx context.Context, id int, quantidade int, reservaID string, ttl time.Duration) (model.Reserva, model.Produto, error) {
+	if id <= 0 {
+		return model.Reserva{}, model.Produto{}, errors.ErrInvalidID
+	}
+	if quantidade <= 0 {
+		return model.Reserva{}, model.Produto{}, errors.ErrInvalidInput.WithDetails("quantidade deve ser maior que zero")
+	}
+	if ttl == 0 {
+		ttl = DefaultReservationTTL
+	}
+	if ttl < 0 || ttl > MaxReservationTTL {
+		return model.Reserva{}, model.Produto{}, errors.ErrInvalidInput.WithDetailsf("a validade da reserva deve estar entre 1s e %s", MaxReservationTTL)
+	}
+	if reservaID == "" {
+		reservaID = uuid.New().String()
+	}
+
+	now := time.Now().UTC().Truncate(time.Millisecond) // Precisão armazenada pelo MongoDB
+	reserva := model.Reserva{
+		ID:         reservaID,
+		Quantidade: quantidade,
+		ExpiresAt:  now.Add(ttl),
+		CreatedAt:  now,
+	}
+
+	result, err := s.repo.Reserve(ctx, id, reserva)
+	if err != nil {
+		return model.Reserva{}, model.Produto{}, stockError(err)
+	}
+
+	s.invalidateProdutoCache(ctx, id)
+	return reserva, result, nil
+}
+
+// Release libera a reserva, devolvendo a quantidade ao estoque disponível
+func (s *produtoService) Release(ctx context.Context, id int, reservaID string) (model.Produto, error) {
+	if id <= 0 {
+		return model.Produto{}, errors.ErrInvalidID
+	}
+	if reservaID == "" {
+		return model.Produto{}, errors.ErrInvalidInput.WithDetails("reservationId é obrigatório")
+	}
+
+	result, err := s.repo.Release(ctx, id, reservaID)
+	if err != nil {
+		return model.Produto{}, stockError(err)
+	}
+
+	s.invalidateProdutoCache(ctx, id)
+	return result, nil
+}
+
+// ReleaseExpired libera as reservas expiradas em now e retorna quantas foram liberadas
+// Reservas liberadas concorrentemente (ex.: pelo cliente) são ignoradas
+func (s *produtoService) ReleaseExpired(ctx context.Context, now time.Time) (int, error) {
+	released := 0
+	// As listas são invalidadas uma única vez, inclusive se a liberação parar no meio
+	defer func() {
+		if released > 0 {
+			s.invalidateListCache(ctx)
+		}
+	}()
+	for {
+		refs, err := s.repo.FindExpiredReservations(ctx, now, expiredReservationsBatch)
+		if err != nil {
+			return released, errors.WrapError(err, errors.ErrDatabase)
+		}
+
+		progress := 0
+		for _, ref := range refs {
+			_, err := s.repo.Release(ctx, ref.ProdutoID, ref.ReservaID)
+			if err == repository.ErrReservationNotFound {
+				continue
+			}
+			if err != nil {
+				return released, errors.WrapError(err, errors.ErrDatabase)
+			}
+			s.invalidateProdutoKey(ctx, ref.ProdutoID)
+			released++
+			progress++
+			logger.WithFields(map[string]interface{}{
+				"produto_id": ref.ProdutoID,
+				"reserva_id": ref.ReservaID,
+			}).Debug("Reserva expirada liberada")
+		}
+
+		// Lote incompleto: não há mais reservas expiradas
+		if len(refs) < expiredReservationsBatch || progress == 0 {
+			return released, nil
+		}
+	}
+}
+
+// stockError converte os erros de estoque do repositório em erros da API
+func stockError(err error) error {
+	switch {
+	case err.Error() == "not found":
+		return errors.ErrProdutoNotFound
+	case err == repository.ErrInsufficientStock:
+		return errors.ErrEstoqueInsuficiente
+	case err == repository.ErrReservationNotFound:
+		return errors.ErrReservaNotFound
+	case err == repository.ErrReservationExists:
+		return errors.ErrReservaDuplicada
+	}
+	return errors.WrapError(err, errors.ErrDatabase)
+}
@@ -0,0 +1,153 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	apiErrors "api-go-arquitetura/internal/errors"
+	"api-go-arquitetura/internal/model"
+)
+
+func TestProdutoService_AdjustStock(t *testing.T) {
+	ctx := context.Background()
+	service := NewProdutoService(NewMockRepository(), nil)
+	produto, _ := service.Create(ctx, model.Produto{Nome: "Mouse", Preco: 50, Estoque: 5})
+
+	t.Run("deve somar e subtrair do estoque", func(t *testing.T) {
+		result, err := service.AdjustStock(ctx, produto.ID, 3)
+		if err != nil {
+			t.Fatalf("Erro inesperado: %v", err)
+		}
+		if result.Estoque != 8 {
+			t.Errorf("Esperado estoque 8, obtido %d", result.Estoque)
+		}
+		if result.Version != produto.Version+1 {
+			t.Errorf("Esperado versão %d, obtido %d", produto.Version+1, result.Version)
+		}
+	})
+
+	t.Run("não deve deixar o estoque abaixo do reservado", func(t *testing.T) {
+		if _, _, err := service.Reserve(ctx, produto.ID, 6, "pedido-1", 0); err != nil {
+			t.Fatalf("Erro inesperado: %v", err)
+		}
+		_, err := service.AdjustStock(ctx, produto.ID, -3)
+		assertAPIError(t, err, apiErrors.ErrEstoqueInsuficiente)
+	})
+
+	t.Run("deve rejeitar delta zero", func(t *testing.T) {
+		_, err := service.AdjustStock(ctx, produto.ID, 0)
+		assertAPIError(t, err, apiErrors.ErrInvalidInput)
+	})
+
+	t.Run("deve retornar erro para produto inexistente", func(t *testing.T) {
+		_, err := service.AdjustStock(ctx, 999, 1)
+		assertAPIError(t, err, apiErrors.ErrProdutoNotFound)
+	})
+}
+
+func TestProdutoService_Reserve(t *testing.T) {
+	ctx := context.Background()
+	service := NewProdutoService(NewMockRepository(), nil)
+	produto, _ := service.Create(ctx, model.Produto{Nome: "Teclado", Preco: 150, Estoque: 4})
+
+	t.Run("deve reservar e gerar o ID da reserva", func(t *testing.T) {
+		before := time.Now()
+		reserva, result, err := service.Reserve(ctx, produto.ID, 3, "", 0)
+		if err != nil {
+			t.Fatalf("Erro inesperado: %v", err)
+		}
+		if reserva.ID == "" {
+			t.Error("ID da reserva deveria ser gerado")
+		}
+		if reserva.ExpiresAt.Before(before.Add(DefaultReservationTTL - time.Second)) {
+			t.Errorf("Validade padrão não aplicada: %v", reserva.ExpiresAt)
+		}
+		if result.Reservado != 3 || result.Disponivel() != 1 {
+			t.Errorf("Esperado reservado 3 e disponível 1, obtido %d e %d", result.Reservado, result.Disponivel())
+		}
+	})
+
+	t.Run("não deve reservar além do disponível", func(t *testing.T) {
+		_, _, err := service.Reserve(ctx, produto.ID, 2, "pedido-2", 0)
+		assertAPIError(t, err, apiErrors.ErrEstoqueInsuficiente)
+	})
+
+	t.Run("não deve repetir o ID da reserva", func(t *testing.T) {
+		if _, _, err := service.Reserve(ctx, produto.ID, 1, "pedido-3", 0); err != nil {
+			t.Fatalf("Erro inesperado: %v", err)
+		}
+		_, _, err := service.Reserve(ctx, produto.ID, 1, "pedido-3", 0)
+		assertAPIError(t, err, apiErrors.ErrReservaDuplicada)
+	})
+
+	t.Run("deve validar quantidade e validade", func(t *testing.T) {
+		_, _, err := service.Reserve(ctx, produto.ID, 0, "", 0)
+		assertAPIError(t, err, apiErrors.ErrInvalidInput)
+
+		_, _, err = service.Reserve(ctx, produto.ID, 1, "", MaxReservationTTL+time.Second)
+		assertAPIError(t, err, apiErrors.ErrInvalidInput)
+	})
+}
+
+func TestProdutoService_Release(t *testing.T) {
+	ctx := context.Background()
+	service := NewProdutoService(NewMockRepository(), nil)
+	produto, _ := service.Create(ctx, model.Produto{Nome: "Monitor", Preco: 900, Estoque: 2})
+	_, _, _ = service.Reserve(ctx, produto.ID, 2, "pedido-1", 0)
+
+	t.Run("deve devolver a quantidade ao disponível", func(t *testing.T) {
+		result, err := service.Release(ctx, produto.ID, "pedido-1")
+		if err != nil {
+			t.Fatalf("Erro inesperado: %v", err)
+		}
+		if result.Reservado != 0 || result.Disponivel() != 2 || len(result.Reservas) != 0 {
+			t.Errorf("Reserva não liberada: %+v", result)
+		}
+	})
+
+	t.Run("deve retornar erro para reserva inexistente", func(t *testing.T) {
+		_, err := service.Release(ctx, produto.ID, "pedido-1")
+		assertAPIError(t, err, apiErrors.ErrReservaNotFound)
+	})
+}
+
+func TestProdutoService_ReleaseExpired(t *testing.T) {
+	ctx := context.Background()
+	service := NewProdutoService(NewMockRepository(), nil)
+	produto, _ := service.Create(ctx, model.Produto{Nome: "Cabo", Preco: 20, Estoque: 10})
+	_, _, _ = service.Reserve(ctx, produto.ID, 2, "curta", time.Second)
+	_, _, _ = service.Reserve(ctx, produto.ID, 3, "longa", time.Hour)
+
+	released, err := service.ReleaseExpired(ctx, time.Now().Add(time.Minute))
+	if err != nil {
+		t.Fatalf("Erro inesperado: %v", err)
+	}
+	if released != 1 {
+		t.Errorf("Esperado 1 reserva liberada, obtido %d", released)
+	}
+
+	result, _ := service.FindByID(ctx, produto.ID)
+	if result.Reservado != 3 {
+		t.Errorf("Esperado reservado 3, obtido %d", result.Reservado)
+	}
+	if _, ok := result.FindReserva("longa"); !ok {
+		t.Error("Reserva dentro da validade não deveria ser liberada")
+	}
+}
+
+func TestReservationSweeper_Run(t *testing.T) {
+	ctx := context.Background()
+	service := NewProdutoService(NewMockRepository(), nil)
+	produto, _ := service.Create(ctx, model.Produto{Nome: "Fone", Preco: 80, Estoque: 1})
+	_, _, _ = service.Reserve(ctx, produto.ID, 1, "pedido-1", time.Millisecond)
+	time.Sleep(5 * time.Millisecond)
+
+	released, err := NewReservationSweeper(service, 0).Run(ctx)
+	if err != nil {
+		t.Fatalf("Erro inesperado: %v", err)
+	}
+	if released != 1 {
+		t.Errorf("Esperado 1 reserva liberada, obtido %d", released)
+	}
+}
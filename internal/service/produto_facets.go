@@ -0,0 +1,91 @@
+package service
+
+import (
+	"context"
+
+	"api-go-arquitetura/internal/cache"
+	"api-go-arquitetura/internal/dto"
+	"api-go-arquitetura/internal/errors"
+	"api-go-arquitetura/internal/model"
+	"api-go-arquitetura/internal/repository"
+)
+
+// Facets calcula as facetas dos produtos que correspondem ao filtro
+// O resultado é armazenado no cache, como as páginas de FindAllPaginated
+func (s *produtoService) Facets(ctx context.Context, filter dto.FilterRequest, facets dto.FacetRequest) (model.ProdutoFacets, error) {
+	if err := s.prepareFilter(ctx, &filter); err != nil {
+		return model.ProdutoFacets{}, err
+	}
+	if err := facets.Validate(); err != nil {
+		return model.ProdutoFacets{}, errors.ErrInvalidInput.WithDetails(err.Error())
+	}
+
+	mongoFilter := filter.ToMongoFilter()
+	cacheKey, useCache := s.listCacheKey(ctx, cache.GenerateProdutosFacetsKey(cache.FacetsQuery{
+		Facets:  facets.Facets,
+		Buckets: facets.Buckets,
+		Filter:  mongoFilter,
+	}))
+
+	var result model.ProdutoFacets
+	decode := func(data []byte) error {
+		result = model.ProdutoFacets{}
+		return cache.Decode(data, &result)
+	}
+	load := func(ctx context.Context) ([]byte, error) {
+		facetsResult, err := s.repo.Facets(ctx, mongoFilter, repository.FacetOptions{
+			Stats:      facets.Has(dto.FacetStats),
+			Preco:      facets.Has(dto.FacetPreco),
+			Buckets:    facets.Buckets,
+			Tags:       facets.Has(dto.FacetTags),
+			Categorias: facets.Has(dto.FacetCategoria),
+		})
+		if err != nil {
+			return nil, errors.WrapError(err, errors.ErrDatabase)
+		}
+		if err := s.nameCategoriaFacets(ctx, facetsResult.Categoria); err != nil {
+			return nil, errors.WrapError(err, errors.ErrDatabase)
+		}
+
+		data, err := cache.Encode(facetsResult)
+		if err != nil {
+			return nil, errors.WrapError(err, errors.ErrInternalServer)
+		}
+		return data, nil
+	}
+
+	var err error
+	if useCache {
+		_, err = s.cachedLoad(ctx, facetsCacheOps, cacheKey, decode, load)
+	} else {
+		_, err = loadDirect(ctx, decode, load)
+	}
+	if err != nil {
+		return model.ProdutoFacets{}, err
+	}
+
+	return result, nil
+}
+
+// nameCategoriaFacets completa a faceta de categorias com o nome e o path de cada
+// categoria, lidos em uma única consulta
+func (s *produtoService) nameCategoriaFacets(ctx context.Context, counts []model.CategoriaFacetCount) error {
+	if len(counts) == 0 || s.categorias == nil {
+		return nil
+	}
+	categorias, err := s.categorias.FindAll(ctx)
+	if err != nil {
+		return err
+	}
+	byID := make(map[int]model.Categoria, len(categorias))
+	for _, c := range categorias {
+		byID[c.ID] = c
+	}
+	for i := range counts {
+		if c, ok := byID[counts[i].CategoriaID]; ok {
+			counts[i].Nome = c.Nome
+			counts[i].Path = c.Path
+		}
+	}
+	return nil
+}
@@ -0,0 +1,44 @@
+package service
+
+import (
+	"context"
+
+	"api-go-arquitetura/internal/dto"
+	"api-go-arquitetura/internal/errors"
+	"api-go-arquitetura/internal/model"
+)
+
+// PriceHistory retorna as alterações de preço do produto no período do filtro,
+// da mais recente para a mais antiga
+func (s *produtoService) PriceHistory(ctx context.Context, id int, pagination dto.PaginationRequest, filter dto.PriceHistoryFilter) ([]model.PriceChange, dto.PaginationResponse, error) {
+	if id <= 0 {
+		return nil, dto.PaginationResponse{}, errors.ErrInvalidID
+	}
+	pagination.Validate()
+	if err := filter.Validate(); err != nil {
+		return nil, dto.PaginationResponse{}, errors.ErrInvalidInput.WithDetails(err.Error())
+	}
+
+	mongoFilter := filter.ToMongoFilter()
+	totalItems, err := s.repo.CountPriceHistory(ctx, id, mongoFilter)
+	if err != nil {
+		return nil, dto.PaginationResponse{}, errors.WrapError(err, errors.ErrDatabase)
+	}
+
+	// Sem alterações no período: diferenciar produto inexistente de histórico vazio
+	if totalItems == 0 {
+		if _, err := s.repo.FindByID(ctx, id, "id"); err != nil {
+			if err.Error() == "not found" {
+				return nil, dto.PaginationResponse{}, errors.ErrProdutoNotFound
+			}
+			return nil, dto.PaginationResponse{}, errors.WrapError(err, errors.ErrDatabase)
+		}
+	}
+
+	changes, err := s.repo.FindPriceHistory(ctx, id, mongoFilter, pagination.GetSkip(), pagination.GetLimit())
+	if err != nil {
+		return nil, dto.PaginationResponse{}, errors.WrapError(err, errors.ErrDatabase)
+	}
+
+	return changes, dto.NewPaginationResponse(pagination.Page, pagination.PageSize, int(totalItems)), nil
+}
@@ -0,0 +1,60 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"api-go-arquitetura/internal/dto"
+	apiErrors "api-go-arquitetura/internal/errors"
+	"api-go-arquitetura/internal/model"
+	"api-go-arquitetura/internal/utils"
+)
+
+func TestProdutoService_PriceHistory(t *testing.T) {
+	ctx := utils.WithRequestID(context.Background(), "req-1")
+	service := NewProdutoService(NewMockRepository(), nil)
+	produto, _ := service.Create(ctx, model.Produto{Nome: "Notebook", Preco: 3500})
+
+	_, _ = service.Update(ctx, produto.ID, model.Produto{Nome: "Notebook", Preco: 3300}, nil)
+	_, _ = service.Patch(ctx, produto.ID, map[string]interface{}{"nome": "Notebook Pro"}, nil)
+	_, _ = service.Patch(ctx, produto.ID, map[string]interface{}{"preco": 3100.0}, nil)
+
+	t.Run("deve listar as alterações de preço mais recentes primeiro", func(t *testing.T) {
+		changes, pagination, err := service.PriceHistory(ctx, produto.ID, dto.PaginationRequest{}, dto.PriceHistoryFilter{})
+		if err != nil {
+			t.Fatalf("Erro inesperado: %v", err)
+		}
+		if pagination.TotalItems != 2 || len(changes) != 2 {
+			t.Fatalf("Esperadas 2 alterações, obtidas %d", len(changes))
+		}
+		if changes[0].PrecoAnterior != 3300 || changes[0].PrecoNovo != 3100 {
+			t.Errorf("Alteração mais recente incorreta: %+v", changes[0])
+		}
+		if changes[1].RequestID != "req-1" {
+			t.Errorf("Request ID esperado req-1, obtido %q", changes[1].RequestID)
+		}
+	})
+
+	t.Run("deve filtrar pelo período", func(t *testing.T) {
+		future := time.Now().Add(time.Hour)
+		changes, _, err := service.PriceHistory(ctx, produto.ID, dto.PaginationRequest{}, dto.PriceHistoryFilter{From: &future})
+		if err != nil {
+			t.Fatalf("Erro inesperado: %v", err)
+		}
+		if len(changes) != 0 {
+			t.Errorf("Esperado histórico vazio, obtidas %d alterações", len(changes))
+		}
+	})
+
+	t.Run("deve validar o período", func(t *testing.T) {
+		from, to := time.Now(), time.Now().Add(-time.Hour)
+		_, _, err := service.PriceHistory(ctx, produto.ID, dto.PaginationRequest{}, dto.PriceHistoryFilter{From: &from, To: &to})
+		assertAPIError(t, err, apiErrors.ErrInvalidInput)
+	})
+
+	t.Run("deve retornar erro para produto inexistente", func(t *testing.T) {
+		_, _, err := service.PriceHistory(ctx, 999, dto.PaginationRequest{}, dto.PriceHistoryFilter{})
+		assertAPIError(t, err, apiErrors.ErrProdutoNotFound)
+	})
+}
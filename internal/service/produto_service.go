@@ -2,8 +2,11 @@ package service
 
 import (
 	"context"
+	"sync"
+	"sync/atomic"
 	"time"
 
+	"api-go-arquitetura/internal/audit"
 	"api-go-arquitetura/internal/cache"
 	"api-go-arquitetura/internal/dto"
 	"api-go-arquitetura/internal/errors"
@@ -11,32 +14,61 @@ import (
 	"api-go-arquitetura/internal/metrics"
 	"api-go-arquitetura/internal/model"
 	"api-go-arquitetura/internal/repository"
+	"api-go-arquitetura/internal/utils"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"golang.org/x/sync/singleflight"
 )
 
 // produtoService implementa a lógica de negócio para produtos
 type produtoService struct {
-	repo  repository.ProdutoRepository
-	cache cache.Cache
-	ttl   time.Duration
+	repo       repository.ProdutoRepository
+	categorias repository.CategoriaRepository // nil: produtos não podem ter categoria
+	auditor    audit.Sink                     // nil: alterações não são auditadas
+	cache      cache.Cache
+	ttl        time.Duration
+	staleTTL   time.Duration // > 0: entradas vencidas são servidas durante a recarga
+
+	flight        singleflight.Group // Cargas do banco em andamento, por chave de cache
+	revalidating  sync.Map           // Chaves sendo recarregadas em background
+	invalidations atomic.Uint64      // Incrementado a cada invalidação feita por este serviço
 }
 
-// NewProdutoService cria uma nova instância do ProdutoService
-func NewProdutoService(repo repository.ProdutoRepository, cache cache.Cache) ProdutoService {
-	// TTL padrão de 5 minutos para cache
-	ttl := 5 * time.Minute
-	return &produtoService{
-		repo:  repo,
-		cache: cache,
-		ttl:   ttl,
+// ProdutoServiceOptions configura o ProdutoService
+type ProdutoServiceOptions struct {
+	Categorias repository.CategoriaRepository // Valida a categoria dos produtos e resolve as subcategorias no filtro
+	Auditor    audit.Sink                     // Registra as alterações dos produtos no log de auditoria
+	TTL        time.Duration                  // TTL das entradas do cache (padrão: 5 minutos)
+	StaleTTL   time.Duration                  // Por quanto tempo após o TTL a entrada vencida é servida durante a recarga
+}
+
+// DefaultProdutoServiceOptions retorna as opções padrão do ProdutoService
+func DefaultProdutoServiceOptions() ProdutoServiceOptions {
+	return ProdutoServiceOptions{
+		TTL: 5 * time.Minute,
 	}
 }
 
-// NewProdutoServiceWithTTL cria uma nova instância do ProdutoService com TTL customizado
-func NewProdutoServiceWithTTL(repo repository.ProdutoRepository, cache cache.Cache, ttl time.Duration) ProdutoService {
+// NewProdutoService cria uma nova instância do ProdutoService com as opções padrão
+func NewProdutoService(repo repository.ProdutoRepository, cache cache.Cache) ProdutoService {
+	return NewProdutoServiceWithOptions(repo, cache, DefaultProdutoServiceOptions())
+}
+
+// NewProdutoServiceWithOptions cria uma nova instância do ProdutoService com as
+// opções informadas
+// Com StaleTTL, as entradas vencidas do cache continuam sendo servidas enquanto
+// uma única recarga em background as substitui
+func NewProdutoServiceWithOptions(repo repository.ProdutoRepository, cache cache.Cache, opts ProdutoServiceOptions) ProdutoService {
+	if opts.TTL <= 0 {
+		opts.TTL = DefaultProdutoServiceOptions().TTL
+	}
 	return &produtoService{
-		repo:  repo,
-		cache: cache,
-		ttl:   ttl,
+		repo:       repo,
+		categorias: opts.Categorias,
+		auditor:    opts.Auditor,
+		cache:      cache,
+		ttl:        opts.TTL,
+		staleTTL:   opts.StaleTTL,
 	}
 }
 
@@ -49,18 +81,24 @@ func (s *produtoService) Create(ctx context.Context, produto model.Produto) (mod
 	if produto.Preco <= 0 {
 		return model.Produto{}, errors.ErrPrecoInvalido
 	}
+	if produto.Estoque < 0 {
+		return model.Produto{}, errors.ErrInvalidInput.WithDetails("estoque não pode ser negativo")
+	}
+	if err := s.checkCategoria(ctx, produto.CategoriaID); err != nil {
+		return model.Produto{}, err
+	}
+
+	// Reservas só são criadas por Reserve
+	produto.Reservado, produto.Reservas = 0, nil
 
 	result, err := s.repo.Create(ctx, produto)
 	if err != nil {
 		return model.Produto{}, errors.WrapError(err, errors.ErrDatabase)
 	}
+	s.recordAudit(ctx, audit.ActionCreate, result.ID, nil, &result)
 
 	// Invalidar cache de listas (novo produto adicionado)
-	if s.cache != nil {
-		// Em produção, seria melhor usar padrões de chave ou tags do Redis
-		// Por enquanto, o cache será invalidado naturalmente pelo TTL
-		logger.Debug("Cache de listas será invalidado pelo TTL após criação de produto")
-	}
+	s.invalidateListCache(ctx)
 
 	return result, nil
 }
@@ -75,64 +113,67 @@ func (s *produtoService) FindAll(ctx context.Context) ([]model.Produto, error) {
 }
 
 // FindByID retorna um produto pelo ID
-func (s *produtoService) FindByID(ctx context.Context, id int) (model.Produto, error) {
+// Com fields, o produto em cache (completo) ainda é usado; o que vem do banco
+// com projeção não é armazenado no cache
+func (s *produtoService) FindByID(ctx context.Context, id int, fields ...string) (model.Produto, error) {
 	if id <= 0 {
 		return model.Produto{}, errors.ErrInvalidID
 	}
 
-	// Tentar buscar do cache primeiro
-	if s.cache != nil {
-		cacheKey := cache.GenerateProdutoKey(id)
-		start := time.Now()
-		cachedData, err := s.cache.Get(ctx, cacheKey)
-		duration := time.Since(start)
-		
-		if err == nil {
-			produto, err := cache.DecodeProduto(cachedData)
-			if err == nil {
-				metrics.RecordCacheHit("get", duration)
-				logger.WithFields(map[string]interface{}{
-					"id":        id,
-					"cache_key": cacheKey,
-				}).Debug("Cache hit para produto")
-				return produto, nil
-			}
-			metrics.RecordCacheError("get", duration)
-		} else {
-			metrics.RecordCacheMiss("get", duration)
+	var produto model.Produto
+	decode := func(data []byte) (err error) {
+		produto, err = cache.DecodeProduto(data)
+		return err
+	}
+	load := func(ctx context.Context) ([]byte, error) {
+		result, err := s.repo.FindByID(ctx, id)
+		if err != nil {
+			return nil, produtoFindError(err)
+		}
+		data, err := cache.EncodeProduto(result)
+		if err != nil {
+			return nil, errors.WrapError(err, errors.ErrInternalServer)
 		}
+		return data, nil
 	}
 
-	// Cache miss ou erro - buscar do banco
-	result, err := s.repo.FindByID(ctx, id)
-	if err != nil {
-		// Verificar se é erro de "not found" do repository
-		if err.Error() == "not found" {
-			return model.Produto{}, errors.ErrProdutoNotFound
+	if s.cache == nil {
+		result, err := s.repo.FindByID(ctx, id, dto.ProjectionFields(fields)...)
+		if err != nil {
+			return model.Produto{}, produtoFindError(err)
 		}
-		return model.Produto{}, errors.WrapError(err, errors.ErrDatabase)
+		return result, nil
 	}
 
-	// Armazenar no cache
-	if s.cache != nil {
-		cacheKey := cache.GenerateProdutoKey(id)
-		cachedData, err := cache.EncodeProduto(result)
-		if err == nil {
-			start := time.Now()
-			if err := s.cache.Set(ctx, cacheKey, cachedData, s.ttl); err != nil {
-				metrics.RecordCacheError("set", time.Since(start))
-				logger.WithField("error", err).Warn("Erro ao armazenar produto no cache")
-			} else {
-				metrics.RecordCacheOperation("set", "success", time.Since(start))
-			}
+	cacheKey := cache.GenerateProdutoKey(id)
+	if len(fields) > 0 {
+		// Apenas o produto completo e atual do cache é usado; a projeção não é armazenada
+		if _, fresh, ok := s.cacheGet(ctx, produtoCacheOps, cacheKey, decode); ok && fresh {
+			return produto, nil
 		}
+		result, err := s.repo.FindByID(ctx, id, dto.ProjectionFields(fields)...)
+		if err != nil {
+			return model.Produto{}, produtoFindError(err)
+		}
+		return result, nil
 	}
 
-	return result, nil
+	if _, err := s.cachedLoad(ctx, produtoCacheOps, cacheKey, decode, load); err != nil {
+		return model.Produto{}, err
+	}
+	return produto, nil
+}
+
+// produtoFindError converte o erro da busca de um produto em erro da API
+func produtoFindError(err error) error {
+	if err.Error() == "not found" {
+		return errors.ErrProdutoNotFound
+	}
+	return errors.WrapError(err, errors.ErrDatabase)
 }
 
 // Update atualiza um produto completamente
-func (s *produtoService) Update(ctx context.Context, id int, produto model.Produto) (model.Produto, error) {
+func (s *produtoService) Update(ctx context.Context, id int, produto model.Produto, expectedVersion *int) (model.Produto, error) {
 	if id <= 0 {
 		return model.Produto{}, errors.ErrInvalidID
 	}
@@ -142,34 +183,32 @@ func (s *produtoService) Update(ctx context.Context, id int, produto model.Produ
 	if produto.Preco <= 0 {
 		return model.Produto{}, errors.ErrPrecoInvalido
 	}
+	if err := s.checkCategoria(ctx, produto.CategoriaID); err != nil {
+		return model.Produto{}, err
+	}
 
-	result, err := s.repo.Update(ctx, id, produto)
+	change, err := s.repo.Update(ctx, id, produto, expectedVersion)
 	if err != nil {
 		if err.Error() == "not found" {
 			return model.Produto{}, errors.ErrProdutoNotFound
 		}
+		if err == repository.ErrVersionConflict {
+			return model.Produto{}, errors.ErrPreconditionFailed
+		}
 		return model.Produto{}, errors.WrapError(err, errors.ErrDatabase)
 	}
 
+	s.recordAudit(ctx, audit.ActionUpdate, id, &change.Before, &change.After)
+
 	// Invalidar cache do produto atualizado
-	if s.cache != nil {
-		cacheKey := cache.GenerateProdutoKey(id)
-		start := time.Now()
-		if err := s.cache.Delete(ctx, cacheKey); err != nil {
-			metrics.RecordCacheError("delete", time.Since(start))
-			logger.WithField("error", err).Warn("Erro ao invalidar cache do produto")
-		} else {
-			metrics.RecordCacheOperation("delete", "success", time.Since(start))
-		}
-		// Invalidar cache de listas também
-		logger.Debug("Cache invalidado após atualização de produto")
-	}
+	s.invalidateProdutoCache(ctx, id)
+	logger.Debug("Cache invalidado após atualização de produto")
 
-	return result, nil
+	return change.After, nil
 }
 
 // Patch atualiza um produto parcialmente
-func (s *produtoService) Patch(ctx context.Context, id int, updates map[string]interface{}) (model.Produto, error) {
+func (s *produtoService) Patch(ctx context.Context, id int, updates map[string]interface{}, expectedVersion *int) (model.Produto, error) {
 	if id <= 0 {
 		return model.Produto{}, errors.ErrInvalidID
 	}
@@ -181,71 +220,70 @@ func (s *produtoService) Patch(ctx context.Context, id int, updates map[string]i
 	if preco, ok := updates["preco"].(float64); ok && preco <= 0 {
 		return model.Produto{}, errors.ErrPrecoInvalido
 	}
+	if categoriaID, ok := updates["categoria_id"].(int); ok {
+		if err := s.checkCategoria(ctx, categoriaID); err != nil {
+			return model.Produto{}, err
+		}
+	}
 
-	result, err := s.repo.Patch(ctx, id, updates)
+	change, err := s.repo.Patch(ctx, id, updates, expectedVersion)
 	if err != nil {
 		if err.Error() == "not found" {
 			return model.Produto{}, errors.ErrProdutoNotFound
 		}
+		if err == repository.ErrVersionConflict {
+			return model.Produto{}, errors.ErrPreconditionFailed
+		}
 		return model.Produto{}, errors.WrapError(err, errors.ErrDatabase)
 	}
 
+	s.recordAudit(ctx, audit.ActionPatch, id, &change.Before, &change.After)
+
 	// Invalidar cache do produto atualizado
-	if s.cache != nil {
-		cacheKey := cache.GenerateProdutoKey(id)
-		start := time.Now()
-		if err := s.cache.Delete(ctx, cacheKey); err != nil {
-			metrics.RecordCacheError("delete", time.Since(start))
-			logger.WithField("error", err).Warn("Erro ao invalidar cache do produto")
-		} else {
-			metrics.RecordCacheOperation("delete", "success", time.Since(start))
-		}
-		// Invalidar cache de listas também
-		logger.Debug("Cache invalidado após patch de produto")
-	}
+	s.invalidateProdutoCache(ctx, id)
+	logger.Debug("Cache invalidado após patch de produto")
 
-	return result, nil
+	return change.After, nil
 }
 
 // Delete remove um produto
-func (s *produtoService) Delete(ctx context.Context, id int) error {
+func (s *produtoService) Delete(ctx context.Context, id int, expectedVersion *int) error {
 	if id <= 0 {
 		return errors.ErrInvalidID
 	}
 
-	err := s.repo.Delete(ctx, id)
+	change, err := s.repo.Delete(ctx, id, expectedVersion)
 	if err != nil {
 		if err.Error() == "not found" {
 			return errors.ErrProdutoNotFound
 		}
+		if err == repository.ErrVersionConflict {
+			return errors.ErrPreconditionFailed
+		}
 		return errors.WrapError(err, errors.ErrDatabase)
 	}
 
+	s.recordAudit(ctx, audit.ActionDelete, id, &change.Before, &change.After)
+
 	// Invalidar cache do produto deletado
-	if s.cache != nil {
-		cacheKey := cache.GenerateProdutoKey(id)
-		start := time.Now()
-		if err := s.cache.Delete(ctx, cacheKey); err != nil {
-			metrics.RecordCacheError("delete", time.Since(start))
-			logger.WithField("error", err).Warn("Erro ao invalidar cache do produto")
-		} else {
-			metrics.RecordCacheOperation("delete", "success", time.Since(start))
-		}
-		// Invalidar cache de listas também
-		logger.Debug("Cache invalidado após deleção de produto")
-	}
+	s.invalidateProdutoCache(ctx, id)
+	logger.Debug("Cache invalidado após deleção de produto")
 
 	return nil
 }
 
 // FindAllPaginated retorna produtos paginados com filtros e ordenação
-func (s *produtoService) FindAllPaginated(ctx context.Context, pagination dto.PaginationRequest, filter dto.FilterRequest, sort dto.SortRequest) ([]model.Produto, dto.PaginationResponse, error) {
+// fields (dto.ParseFields) restringe os campos lidos do banco e faz parte da chave de cache
+func (s *produtoService) FindAllPaginated(ctx context.Context, pagination dto.PaginationRequest, filter dto.FilterRequest, sort dto.SortRequest, fields ...string) (ProdutoPage, error) {
 	// Validar paginação
 	pagination.Validate()
 
-	// Validar ordenação
-	if err := sort.Validate(); err != nil {
-		return nil, dto.PaginationResponse{}, errors.ErrInvalidInput.WithDetails(err.Error())
+	// Validar filtro e ordenação (com busca textual, a ordenação padrão é por relevância)
+	if err := s.prepareFilter(ctx, &filter); err != nil {
+		return ProdutoPage{}, err
+	}
+	if err := sort.ValidateForFilter(filter); err != nil {
+		return ProdutoPage{}, errors.ErrInvalidInput.WithDetails(err.Error())
 	}
 
 	// Converter filtro para MongoDB
@@ -255,70 +293,295 @@ func (s *produtoService) FindAllPaginated(ctx context.Context, pagination dto.Pa
 	mongoSort := sort.ToMongoSort()
 
 	// Gerar chave de cache para a lista
-	cacheKey := cache.GenerateProdutosListKey(pagination.Page, pagination.PageSize, mongoFilter)
-
-	// Tentar buscar do cache primeiro
-	if s.cache != nil {
-		start := time.Now()
-		cachedData, err := s.cache.Get(ctx, cacheKey)
-		duration := time.Since(start)
-		
-		if err == nil {
-			// Cache hit
-			var cachedResult struct {
-				Produtos []model.Produto
-				Total    int64
-			}
-			if err := cache.Decode(cachedData, &cachedResult); err == nil {
-				metrics.RecordCacheHit("get_list", duration)
-				logger.WithFields(map[string]interface{}{
-					"cache_key": cacheKey,
-					"page":       pagination.Page,
-				}).Debug("Cache hit para lista de produtos")
-				
-				paginationResp := dto.NewPaginationResponse(pagination.Page, pagination.PageSize, int(cachedResult.Total))
-				return cachedResult.Produtos, paginationResp, nil
-			}
-			metrics.RecordCacheError("get_list", duration)
-		} else {
-			metrics.RecordCacheMiss("get_list", duration)
+	cacheKey, useCache := s.listCacheKey(ctx, cache.GenerateProdutosListKey(cache.ListQuery{
+		Pagination: pagination,
+		Filter:     mongoFilter,
+		Sort:       mongoSort,
+		Fields:     fields,
+	}))
+
+	// Página serializada: o mesmo conteúdo é armazenado no cache e usado para o ETag
+	var cachedResult cachedProdutoPage
+	decode := func(data []byte) error {
+		cachedResult = cachedProdutoPage{}
+		return cache.Decode(data, &cachedResult)
+	}
+	load := func(ctx context.Context) ([]byte, error) {
+		// Contar total de documentos
+		totalItems, err := s.repo.Count(ctx, mongoFilter)
+		if err != nil {
+			return nil, errors.WrapError(err, errors.ErrDatabase)
+		}
+
+		// Buscar produtos paginados
+		produtos, err := s.repo.FindAllPaginated(ctx, pagination.GetSkip(), pagination.GetLimit(), mongoFilter, mongoSort, dto.ProjectionFields(fields)...)
+		if err != nil {
+			return nil, errors.WrapError(err, errors.ErrDatabase)
 		}
+
+		data, err := cache.Encode(cachedProdutoPage{Produtos: produtos, Total: totalItems})
+		if err != nil {
+			return nil, errors.WrapError(err, errors.ErrInternalServer)
+		}
+		return data, nil
 	}
 
-	// Contar total de documentos
-	totalItems, err := s.repo.Count(ctx, mongoFilter)
+	var cachedData []byte
+	var err error
+	if useCache {
+		cachedData, err = s.cachedLoad(ctx, listCacheOps, cacheKey, decode, load)
+	} else {
+		cachedData, err = loadDirect(ctx, decode, load)
+	}
 	if err != nil {
-		return nil, dto.PaginationResponse{}, errors.WrapError(err, errors.ErrDatabase)
+		return ProdutoPage{}, err
+	}
+
+	return newProdutoPage(cachedResult, pagination, cachedData), nil
+}
+
+// FindAllByCursor retorna a página seguinte ao cursor, com filtros e ordenação
+// O resultado não é armazenado no cache: cada cursor tende a ser lido uma única vez
+func (s *produtoService) FindAllByCursor(ctx context.Context, pagination dto.CursorPaginationRequest, filter dto.FilterRequest, sort dto.SortRequest, fields ...string) (ProdutoCursorPage, error) {
+	pagination.Validate()
+
+	if err := s.prepareFilter(ctx, &filter); err != nil {
+		return ProdutoCursorPage{}, err
+	}
+	if err := sort.Validate(); err != nil {
+		return ProdutoCursorPage{}, errors.ErrInvalidInput.WithDetails(err.Error())
+	}
+	keyset, err := sort.KeysetFilter(pagination.Cursor)
+	if err != nil {
+		return ProdutoCursorPage{}, errors.ErrInvalidInput.WithDetails(err.Error())
 	}
 
-	// Buscar produtos paginados
-	produtos, err := s.repo.FindAllPaginated(ctx, pagination.GetSkip(), pagination.GetLimit(), mongoFilter, mongoSort)
+	mongoFilter := filter.ToMongoFilter()
+	if keyset != nil {
+		and, _ := mongoFilter["$and"].([]interface{})
+		mongoFilter["$and"] = append(and, keyset)
+	}
+
+	// Buscar um item a mais para saber se existe próxima página
+	limit := int64(pagination.PageSize)
+	produtos, err := s.repo.FindAllPaginated(ctx, 0, limit+1, mongoFilter, sort.ToMongoKeysetSort(), dto.ProjectionFields(fields)...)
 	if err != nil {
-		return nil, dto.PaginationResponse{}, errors.WrapError(err, errors.ErrDatabase)
+		return ProdutoCursorPage{}, errors.WrapError(err, errors.ErrDatabase)
+	}
+
+	page := ProdutoCursorPage{
+		Pagination: dto.CursorPaginationResponse{PageSize: pagination.PageSize},
+	}
+	if int64(len(produtos)) > limit {
+		produtos = produtos[:limit]
+		next, err := sort.EncodeCursor(produtos[len(produtos)-1])
+		if err != nil {
+			return ProdutoCursorPage{}, errors.WrapError(err, errors.ErrInternalServer)
+		}
+		page.Pagination.NextCursor = next
+		page.Pagination.HasNext = true
+	}
+	page.Produtos = produtos
+
+	data, err := cache.Encode(page.Produtos)
+	if err != nil {
+		return ProdutoCursorPage{}, errors.WrapError(err, errors.ErrInternalServer)
+	}
+	page.ETag = utils.ContentETag(data)
+
+	return page, nil
+}
+
+// prepareFilter valida o filtro e, com subcategorias, resolve os IDs das
+// categorias abaixo da categoria filtrada
+func (s *produtoService) prepareFilter(ctx context.Context, filter *dto.FilterRequest) error {
+	if err := filter.Validate(); err != nil {
+		return errors.ErrInvalidInput.WithDetails(err.Error())
+	}
+	filter.CategoriaIDs = nil
+	if filter.Categoria == nil || !filter.Subcategorias || s.categorias == nil {
+		return nil
+	}
+
+	ids, err := s.categorias.FindDescendantIDs(ctx, *filter.Categoria)
+	if err != nil {
+		return errors.WrapError(err, errors.ErrDatabase)
 	}
+	filter.CategoriaIDs = append([]int{*filter.Categoria}, ids...)
+	return nil
+}
 
-	// Armazenar no cache
-	if s.cache != nil {
-		cachedResult := struct {
-			Produtos []model.Produto
-			Total    int64
-		}{
-			Produtos: produtos,
-			Total:    totalItems,
+// checkCategoria verifica se a categoria do produto existe (0 = sem categoria)
+func (s *produtoService) checkCategoria(ctx context.Context, id int) *errors.APIError {
+	if id == 0 {
+		return nil
+	}
+	if s.categorias == nil {
+		return errors.ErrCategoriaInvalida
+	}
+	if _, err := s.categorias.FindByID(ctx, id); err != nil {
+		if err.Error() == "not found" {
+			return errors.ErrCategoriaInvalida.WithDetailsf("categoria %d não encontrada", id)
 		}
-		if cachedData, err := cache.Encode(cachedResult); err == nil {
-			start := time.Now()
-			if err := s.cache.Set(ctx, cacheKey, cachedData, s.ttl); err != nil {
-				metrics.RecordCacheError("set_list", time.Since(start))
-				logger.WithField("error", err).Warn("Erro ao armazenar lista no cache")
-			} else {
-				metrics.RecordCacheOperation("set_list", "success", time.Since(start))
-			}
+		return errors.WrapError(err, errors.ErrDatabase)
+	}
+	return nil
+}
+
+// cachedProdutoPage é o formato de uma página de produtos armazenada no cache
+type cachedProdutoPage struct {
+	Produtos []model.Produto
+	Total    int64
+}
+
+// newProdutoPage monta a página de resposta a partir do conteúdo (serializado) da página
+func newProdutoPage(cached cachedProdutoPage, pagination dto.PaginationRequest, data []byte) ProdutoPage {
+	return ProdutoPage{
+		Produtos:   cached.Produtos,
+		Pagination: dto.NewPaginationResponse(pagination.Page, pagination.PageSize, int(cached.Total)),
+		ETag:       utils.ContentETag(data),
+	}
+}
+
+// Export percorre os produtos com os mesmos filtros e ordenação da listagem
+func (s *produtoService) Export(ctx context.Context, filter dto.FilterRequest, sort dto.SortRequest, fn func(model.Produto) error) error {
+	if err := s.prepareFilter(ctx, &filter); err != nil {
+		return err
+	}
+	if err := sort.ValidateForFilter(filter); err != nil {
+		return errors.ErrInvalidInput.WithDetails(err.Error())
+	}
+
+	// Diferenciar os erros de fn (ex.: cliente desconectado) dos erros do banco
+	var fnErr error
+	err := s.repo.Stream(ctx, filter.ToMongoFilter(), sort.ToMongoSort(), func(produto model.Produto) error {
+		fnErr = fn(produto)
+		return fnErr
+	})
+	if fnErr != nil {
+		return fnErr
+	}
+	if err != nil {
+		return errors.WrapError(err, errors.ErrDatabase)
+	}
+	return nil
+}
+
+// Restore restaura um produto removido (soft delete)
+func (s *produtoService) Restore(ctx context.Context, id int) (model.Produto, error) {
+	if id <= 0 {
+		return model.Produto{}, errors.ErrInvalidID
+	}
+
+	change, err := s.repo.Restore(ctx, id)
+	if err != nil {
+		if err.Error() == "not found" {
+			return model.Produto{}, errors.ErrProdutoNotFound.WithDetails("produto não está na lixeira")
 		}
+		return model.Produto{}, errors.WrapError(err, errors.ErrDatabase)
+	}
+
+	s.recordAudit(ctx, audit.ActionRestore, id, &change.Before, &change.After)
+
+	// Invalidar cache do produto restaurado
+	s.invalidateProdutoCache(ctx, id)
+	logger.Debug("Cache invalidado após restauração de produto")
+
+	return change.After, nil
+}
+
+// FindTrashPaginated retorna os produtos da lixeira paginados e ordenados
+func (s *produtoService) FindTrashPaginated(ctx context.Context, pagination dto.PaginationRequest, sort dto.SortRequest) ([]model.Produto, dto.PaginationResponse, error) {
+	pagination.Validate()
+
+	if err := sort.ValidateTrash(); err != nil {
+		return nil, dto.PaginationResponse{}, errors.ErrInvalidInput.WithDetails(err.Error())
+	}
+
+	// Sem campo explícito, o repositório ordena por deleted_at decrescente
+	var mongoSort bson.D
+	if sort.Field != "" {
+		mongoSort = sort.ToMongoSort()
+	}
+
+	totalItems, err := s.repo.CountDeleted(ctx, nil)
+	if err != nil {
+		return nil, dto.PaginationResponse{}, errors.WrapError(err, errors.ErrDatabase)
+	}
+
+	produtos, err := s.repo.FindDeletedPaginated(ctx, pagination.GetSkip(), pagination.GetLimit(), mongoSort)
+	if err != nil {
+		return nil, dto.PaginationResponse{}, errors.WrapError(err, errors.ErrDatabase)
 	}
 
-	// Criar resposta de paginação
 	paginationResp := dto.NewPaginationResponse(pagination.Page, pagination.PageSize, int(totalItems))
 
 	return produtos, paginationResp, nil
 }
+
+// PurgeTrash remove permanentemente os produtos da lixeira removidos antes de before
+func (s *produtoService) PurgeTrash(ctx context.Context, before time.Time) (int64, error) {
+	purged, err := s.repo.PurgeDeleted(ctx, before)
+	if err != nil {
+		return 0, errors.WrapError(err, errors.ErrDatabase)
+	}
+	return purged, nil
+}
+
+// invalidateProdutoCache remove o produto do cache e invalida as listas
+func (s *produtoService) invalidateProdutoCache(ctx context.Context, id int) {
+	s.invalidateProdutoKey(ctx, id)
+	s.invalidateListCache(ctx)
+}
+
+// invalidateProdutoKey remove apenas o produto do cache
+// Usado nas operações com vários produtos, que invalidam as listas uma única vez
+func (s *produtoService) invalidateProdutoKey(ctx context.Context, id int) {
+	if s.cache == nil {
+		return
+	}
+	cacheKey := cache.GenerateProdutoKey(id)
+	// Uma carga em andamento pode ter lido o produto antes da alteração: as
+	// próximas leituras não devem aguardá-la, e ela não será armazenada
+	s.invalidations.Add(1)
+	s.flight.Forget(cacheKey)
+	start := time.Now()
+	if err := s.cache.Delete(ctx, cacheKey); err != nil {
+		metrics.RecordCacheError("delete", time.Since(start))
+		logger.WithField("error", err).Warn("Erro ao invalidar cache do produto")
+	} else {
+		metrics.RecordCacheOperation("delete", "success", time.Since(start))
+	}
+}
+
+// invalidateListCache invalida todas as listas e facetas de produtos em cache
+func (s *produtoService) invalidateListCache(ctx context.Context) {
+	if s.cache == nil {
+		return
+	}
+	s.invalidations.Add(1)
+	start := time.Now()
+	if err := cache.InvalidateListCache(ctx, s.cache); err != nil {
+		metrics.RecordCacheError("invalidate_list", time.Since(start))
+		logger.WithField("error", err).Warn("Erro ao invalidar cache de listas")
+	} else {
+		metrics.RecordCacheOperation("invalidate_list", "success", time.Since(start))
+	}
+}
+
+// listCacheKey retorna a chave de cache da lista na geração atual das listas
+// Retorna false (sem cache) se não há cache ou a geração não pôde ser lida:
+// sem ela, uma página armazenada poderia sobreviver à próxima invalidação
+func (s *produtoService) listCacheKey(ctx context.Context, key string) (string, bool) {
+	if s.cache == nil {
+		return key, false
+	}
+	start := time.Now()
+	namespaced, err := cache.NamespacedKey(ctx, s.cache, cache.ProdutoListNamespace, key)
+	if err != nil {
+		metrics.RecordCacheError("generation", time.Since(start))
+		logger.WithField("error", err).Warn("Erro ao ler geração do cache de listas")
+		return key, false
+	}
+	return namespaced, true
+}
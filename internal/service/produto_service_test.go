@@ -3,17 +3,30 @@ package service
 import (
 	"context"
 	"errors"
+	"reflect"
 	"testing"
+	"time"
 
+	"api-go-arquitetura/internal/cache"
+	"api-go-arquitetura/internal/dto"
 	apiErrors "api-go-arquitetura/internal/errors"
 	"api-go-arquitetura/internal/model"
 	"api-go-arquitetura/internal/repository"
+	"api-go-arquitetura/internal/utils"
+
+	"go.mongodb.org/mongo-driver/bson"
 )
 
 // MockRepository é um mock do ProdutoRepository para testes
 type MockRepository struct {
-	produtos []model.Produto
-	nextID   int
+	produtos     []model.Produto
+	nextID       int
+	priceHistory []model.PriceChange
+}
+
+// intPtr retorna um ponteiro para a versão esperada
+func intPtr(v int) *int {
+	return &v
 }
 
 func NewMockRepository() repository.ProdutoRepository {
@@ -25,6 +38,7 @@ func NewMockRepository() repository.ProdutoRepository {
 
 func (m *MockRepository) Create(ctx context.Context, produto model.Produto) (model.Produto, error) {
 	produto.ID = m.nextID
+	produto.BeforeCreate()
 	m.nextID++
 	m.produtos = append(m.produtos, produto)
 	return produto, nil
@@ -34,29 +48,39 @@ func (m *MockRepository) FindAll(ctx context.Context) ([]model.Produto, error) {
 	return m.produtos, nil
 }
 
-func (m *MockRepository) FindByID(ctx context.Context, id int) (model.Produto, error) {
+func (m *MockRepository) FindByID(ctx context.Context, id int, fields ...string) (model.Produto, error) {
 	for _, p := range m.produtos {
-		if p.ID == id {
+		if p.ID == id && !p.IsDeleted() {
 			return p, nil
 		}
 	}
 	return model.Produto{}, errors.New("not found")
 }
 
-func (m *MockRepository) Update(ctx context.Context, id int, produto model.Produto) (model.Produto, error) {
+func (m *MockRepository) Update(ctx context.Context, id int, produto model.Produto, expectedVersion *int) (repository.ProdutoChange, error) {
 	for i, p := range m.produtos {
-		if p.ID == id {
+		if p.ID == id && !p.IsDeleted() {
+			if expectedVersion != nil && p.Version != *expectedVersion {
+				return repository.ProdutoChange{}, repository.ErrVersionConflict
+			}
 			produto.ID = id
+			produto.Version = p.Version + 1
 			m.produtos[i] = produto
-			return produto, nil
+			m.recordPriceChange(ctx, p, produto)
+			return repository.ProdutoChange{Before: p, After: produto}, nil
 		}
 	}
-	return model.Produto{}, errors.New("not found")
+	return repository.ProdutoChange{}, errors.New("not found")
 }
 
-func (m *MockRepository) Patch(ctx context.Context, id int, updates map[string]interface{}) (model.Produto, error) {
+func (m *MockRepository) Patch(ctx context.Context, id int, updates map[string]interface{}, expectedVersion *int) (repository.ProdutoChange, error) {
 	for i, p := range m.produtos {
-		if p.ID == id {
+		if p.ID == id && !p.IsDeleted() {
+			if expectedVersion != nil && p.Version != *expectedVersion {
+				return repository.ProdutoChange{}, repository.ErrVersionConflict
+			}
+			before := p
+			p.Version++
 			if nome, ok := updates["nome"].(string); ok {
 				p.Nome = nome
 			}
@@ -66,6 +90,199 @@ func (m *MockRepository) Patch(ctx context.Context, id int, updates map[string]i
 			if descricao, ok := updates["descricao"].(string); ok {
 				p.Descricao = descricao
 			}
+			m.recordPriceChange(ctx, before, p)
+			m.produtos[i] = p
+			return repository.ProdutoChange{Before: before, After: p}, nil
+		}
+	}
+	return repository.ProdutoChange{}, errors.New("not found")
+}
+
+func (m *MockRepository) Delete(ctx context.Context, id int, expectedVersion *int) (repository.ProdutoChange, error) {
+	for i, p := range m.produtos {
+		if p.ID == id && !p.IsDeleted() {
+			if expectedVersion != nil && p.Version != *expectedVersion {
+				return repository.ProdutoChange{}, repository.ErrVersionConflict
+			}
+			before := p
+			p.SoftDelete()
+			p.Version++
+			m.produtos[i] = p
+			return repository.ProdutoChange{Before: before, After: p}, nil
+		}
+	}
+	return repository.ProdutoChange{}, errors.New("not found")
+}
+
+func (m *MockRepository) Restore(ctx context.Context, id int) (repository.ProdutoChange, error) {
+	for i, p := range m.produtos {
+		if p.ID == id && p.IsDeleted() {
+			before := p
+			p.Restore()
+			p.Version++
+			m.produtos[i] = p
+			return repository.ProdutoChange{Before: before, After: p}, nil
+		}
+	}
+	return repository.ProdutoChange{}, errors.New("not found")
+}
+
+func (m *MockRepository) FindDeletedByID(ctx context.Context, id int) (model.Produto, error) {
+	for _, p := range m.produtos {
+		if p.ID == id && p.IsDeleted() {
+			return p, nil
+		}
+	}
+	return model.Produto{}, errors.New("not found")
+}
+
+func (m *MockRepository) FindDeletedPaginated(ctx context.Context, skip, limit int64, sort bson.D) ([]model.Produto, error) {
+	var deleted []model.Produto
+	for _, p := range m.produtos {
+		if p.IsDeleted() {
+			deleted = append(deleted, p)
+		}
+	}
+	return deleted, nil
+}
+
+func (m *MockRepository) PurgeDeleted(ctx context.Context, before time.Time) (int64, error) {
+	var kept []model.Produto
+	var purged int64
+	for _, p := range m.produtos {
+		if p.IsDeleted() && p.DeletedAt.Before(before) {
+			purged++
+			continue
+		}
+		kept = append(kept, p)
+	}
+	m.produtos = kept
+	return purged, nil
+}
+
+func (m *MockRepository) CountDeleted(ctx context.Context, filter map[string]interface{}) (int64, error) {
+	deleted, _ := m.FindDeletedPaginated(ctx, 0, 0, nil)
+	return int64(len(deleted)), nil
+}
+
+func (m *MockRepository) FindAllPaginated(ctx context.Context, skip, limit int64, filter map[string]interface{}, sort bson.D, fields ...string) ([]model.Produto, error) {
+	start := int(skip)
+	if start > len(m.produtos) {
+		start = len(m.produtos)
+	}
+	end := start + int(limit)
+	if end > len(m.produtos) {
+		end = len(m.produtos)
+	}
+	return m.produtos[start:end], nil
+}
+
+func (m *MockRepository) Count(ctx context.Context, filter map[string]interface{}) (int64, error) {
+	return int64(len(m.produtos)), nil
+}
+
+func (m *MockRepository) Stream(ctx context.Context, filter map[string]interface{}, sort bson.D, fn func(model.Produto) error) error {
+	for _, p := range m.produtos {
+		if p.IsDeleted() {
+			continue
+		}
+		if err := fn(p); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m *MockRepository) Facets(ctx context.Context, filter map[string]interface{}, opts repository.FacetOptions) (model.ProdutoFacets, error) {
+	var facets model.ProdutoFacets
+	stats := model.PrecoStats{}
+	tags := map[string]int64{}
+	categorias := map[int]int64{}
+	for _, p := range m.produtos {
+		if p.IsDeleted() {
+			continue
+		}
+		if p.CategoriaID > 0 {
+			categorias[p.CategoriaID]++
+		}
+		if stats.Count == 0 || p.Preco < stats.Min {
+			stats.Min = p.Preco
+		}
+		if p.Preco > stats.Max {
+			stats.Max = p.Preco
+		}
+		stats.Avg += p.Preco
+		stats.Count++
+		for _, tag := range p.Tags {
+			tags[tag]++
+		}
+	}
+	if stats.Count > 0 {
+		stats.Avg /= float64(stats.Count)
+	}
+	if opts.Stats {
+		facets.Stats = &stats
+	}
+	if opts.Preco && stats.Count > 0 {
+		facets.Preco = []model.PrecoBucket{{Min: stats.Min, Max: stats.Max, Count: stats.Count}}
+	}
+	if opts.Tags {
+		for tag, count := range tags {
+			facets.Tags = append(facets.Tags, model.FacetCount{Value: tag, Count: count})
+		}
+	}
+	if opts.Categorias {
+		for id, count := range categorias {
+			facets.Categoria = append(facets.Categoria, model.CategoriaFacetCount{CategoriaID: id, Count: count})
+		}
+	}
+	return facets, nil
+}
+
+func (m *MockRepository) BulkWrite(ctx context.Context, ops []repository.BulkOperation, atomic bool) ([]repository.BulkResult, error) {
+	snapshot := append([]model.Produto(nil), m.produtos...)
+	results := make([]repository.BulkResult, len(ops))
+	failed := false
+	for i, op := range ops {
+		var (
+			res    repository.BulkResult
+			change repository.ProdutoChange
+		)
+		switch op.Type {
+		case repository.BulkInsert:
+			res.Produto, res.Err = m.Create(ctx, op.Produto)
+		case repository.BulkReplace:
+			change, res.Err = m.Update(ctx, op.ID, op.Produto, op.ExpectedVersion)
+		case repository.BulkPatch:
+			change, res.Err = m.Patch(ctx, op.ID, op.Updates, op.ExpectedVersion)
+		case repository.BulkDelete:
+			change, res.Err = m.Delete(ctx, op.ID, op.ExpectedVersion)
+		}
+		if op.Type != repository.BulkInsert {
+			res.Before, res.Produto = change.Before, change.After
+		}
+		failed = failed || res.Err != nil
+		results[i] = res
+	}
+	if atomic && failed {
+		m.produtos = snapshot
+		for i := range results {
+			if results[i].Err == nil {
+				results[i] = repository.BulkResult{Err: repository.ErrBulkAborted}
+			}
+		}
+	}
+	return results, nil
+}
+
+func (m *MockRepository) AdjustStock(ctx context.Context, id int, delta int) (model.Produto, error) {
+	for i, p := range m.produtos {
+		if p.ID == id && !p.IsDeleted() {
+			if p.Estoque+delta < p.Reservado {
+				return model.Produto{}, repository.ErrInsufficientStock
+			}
+			p.Estoque += delta
+			p.Version++
 			m.produtos[i] = p
 			return p, nil
 		}
@@ -73,20 +290,115 @@ func (m *MockRepository) Patch(ctx context.Context, id int, updates map[string]i
 	return model.Produto{}, errors.New("not found")
 }
 
-func (m *MockRepository) Delete(ctx context.Context, id int) error {
+func (m *MockRepository) Reserve(ctx context.Context, id int, reserva model.Reserva) (model.Produto, error) {
 	for i, p := range m.produtos {
-		if p.ID == id {
-			m.produtos = append(m.produtos[:i], m.produtos[i+1:]...)
-			return nil
+		if p.ID == id && !p.IsDeleted() {
+			if _, exists := p.FindReserva(reserva.ID); exists {
+				return model.Produto{}, repository.ErrReservationExists
+			}
+			if p.Disponivel() < reserva.Quantidade {
+				return model.Produto{}, repository.ErrInsufficientStock
+			}
+			p.Reservas = append(append([]model.Reserva(nil), p.Reservas...), reserva)
+			p.Reservado += reserva.Quantidade
+			p.Version++
+			m.produtos[i] = p
+			return p, nil
+		}
+	}
+	return model.Produto{}, errors.New("not found")
+}
+
+func (m *MockRepository) Release(ctx context.Context, id int, reservaID string) (model.Produto, error) {
+	for i, p := range m.produtos {
+		if p.ID != id {
+			continue
+		}
+		reserva, ok := p.FindReserva(reservaID)
+		if !ok {
+			return model.Produto{}, repository.ErrReservationNotFound
+		}
+		var reservas []model.Reserva
+		for _, r := range p.Reservas {
+			if r.ID != reservaID {
+				reservas = append(reservas, r)
+			}
 		}
+		p.Reservas = reservas
+		p.Reservado -= reserva.Quantidade
+		p.Version++
+		m.produtos[i] = p
+		return p, nil
 	}
-	return errors.New("not found")
+	return model.Produto{}, errors.New("not found")
+}
+
+func (m *MockRepository) FindExpiredReservations(ctx context.Context, before time.Time, limit int) ([]repository.ReservaRef, error) {
+	var refs []repository.ReservaRef
+	for _, p := range m.produtos {
+		for _, r := range p.Reservas {
+			if r.IsExpired(before) && len(refs) < limit {
+				refs = append(refs, repository.ReservaRef{ProdutoID: p.ID, ReservaID: r.ID})
+			}
+		}
+	}
+	return refs, nil
+}
+
+// recordPriceChange simula o histórico gravado pelo repositório quando o preço muda
+func (m *MockRepository) recordPriceChange(ctx context.Context, before, after model.Produto) {
+	if before.Preco == after.Preco {
+		return
+	}
+	m.priceHistory = append(m.priceHistory, model.PriceChange{
+		ProdutoID:     after.ID,
+		PrecoAnterior: before.Preco,
+		PrecoNovo:     after.Preco,
+		Version:       after.Version,
+		RequestID:     utils.RequestIDFromContext(ctx),
+		ChangedAt:     time.Now(),
+	})
+}
+
+func (m *MockRepository) FindPriceHistory(ctx context.Context, produtoID int, filter map[string]interface{}, skip, limit int64) ([]model.PriceChange, error) {
+	changes := []model.PriceChange{}
+	for i := len(m.priceHistory) - 1; i >= 0; i-- {
+		c := m.priceHistory[i]
+		if c.ProdutoID == produtoID && inPeriod(c.ChangedAt, filter) {
+			changes = append(changes, c)
+		}
+	}
+	if skip >= int64(len(changes)) {
+		return []model.PriceChange{}, nil
+	}
+	end := skip + limit
+	if end > int64(len(changes)) {
+		end = int64(len(changes))
+	}
+	return changes[skip:end], nil
+}
+
+func (m *MockRepository) CountPriceHistory(ctx context.Context, produtoID int, filter map[string]interface{}) (int64, error) {
+	changes, _ := m.FindPriceHistory(ctx, produtoID, filter, 0, int64(len(m.priceHistory)))
+	return int64(len(changes)), nil
+}
+
+// inPeriod aplica o filtro de período (changed_at) do histórico de preços
+func inPeriod(t time.Time, filter map[string]interface{}) bool {
+	period, _ := filter["changed_at"].(map[string]interface{})
+	if from, ok := period["$gte"].(time.Time); ok && t.Before(from) {
+		return false
+	}
+	if to, ok := period["$lte"].(time.Time); ok && t.After(to) {
+		return false
+	}
+	return true
 }
 
 func TestProdutoService_Create(t *testing.T) {
 	ctx := context.Background()
 	mockRepo := NewMockRepository()
-	service := NewProdutoService(mockRepo)
+	service := NewProdutoService(mockRepo, nil)
 
 	t.Run("deve criar produto com dados válidos", func(t *testing.T) {
 		produto := model.Produto{
@@ -151,7 +463,7 @@ func TestProdutoService_Create(t *testing.T) {
 func TestProdutoService_FindByID(t *testing.T) {
 	ctx := context.Background()
 	mockRepo := NewMockRepository()
-	service := NewProdutoService(mockRepo)
+	service := NewProdutoService(mockRepo, nil)
 
 	// Criar produto de teste
 	produto := model.Produto{
@@ -200,7 +512,7 @@ func TestProdutoService_FindByID(t *testing.T) {
 func TestProdutoService_Delete(t *testing.T) {
 	ctx := context.Background()
 	mockRepo := NewMockRepository()
-	service := NewProdutoService(mockRepo)
+	service := NewProdutoService(mockRepo, nil)
 
 	// Criar produto de teste
 	produto := model.Produto{
@@ -211,7 +523,7 @@ func TestProdutoService_Delete(t *testing.T) {
 	created, _ := service.Create(ctx, produto)
 
 	t.Run("deve deletar produto existente", func(t *testing.T) {
-		err := service.Delete(ctx, created.ID)
+		err := service.Delete(ctx, created.ID, nil)
 		if err != nil {
 			t.Fatalf("Erro inesperado: %v", err)
 		}
@@ -224,7 +536,7 @@ func TestProdutoService_Delete(t *testing.T) {
 	})
 
 	t.Run("deve retornar erro quando produto não existe", func(t *testing.T) {
-		err := service.Delete(ctx, 999)
+		err := service.Delete(ctx, 999, nil)
 		if err == nil {
 			t.Error("Esperado erro, mas nenhum erro foi retornado")
 		}
@@ -236,3 +548,579 @@ func TestProdutoService_Delete(t *testing.T) {
 	})
 }
 
+
+func TestProdutoService_Restore(t *testing.T) {
+	ctx := context.Background()
+	mockRepo := NewMockRepository()
+	service := NewProdutoService(mockRepo, nil)
+
+	created, _ := service.Create(ctx, model.Produto{Nome: "Notebook", Preco: 3500.00})
+	_ = service.Delete(ctx, created.ID, nil)
+
+	t.Run("deve listar produto removido na lixeira", func(t *testing.T) {
+		produtos, pagination, err := service.FindTrashPaginated(ctx, dto.PaginationRequest{Page: 1, PageSize: 10}, dto.SortRequest{Field: "deleted_at", Order: "desc"})
+		if err != nil {
+			t.Fatalf("Erro inesperado: %v", err)
+		}
+		if len(produtos) != 1 || pagination.TotalItems != 1 {
+			t.Errorf("Esperado 1 produto na lixeira, obtido %d (total %d)", len(produtos), pagination.TotalItems)
+		}
+	})
+
+	t.Run("deve restaurar produto removido", func(t *testing.T) {
+		restored, err := service.Restore(ctx, created.ID)
+		if err != nil {
+			t.Fatalf("Erro inesperado: %v", err)
+		}
+		if restored.IsDeleted() {
+			t.Error("Produto restaurado não deveria estar marcado como removido")
+		}
+		if _, err := service.FindByID(ctx, created.ID); err != nil {
+			t.Errorf("Produto deveria ser encontrado após restauração: %v", err)
+		}
+	})
+
+	t.Run("deve retornar erro quando produto não está na lixeira", func(t *testing.T) {
+		_, err := service.Restore(ctx, created.ID)
+		apiErr := apiErrors.AsAPIError(err)
+		if apiErr == nil || apiErr.Code != "PRODUTO_NOT_FOUND" {
+			t.Errorf("Código de erro esperado PRODUTO_NOT_FOUND, obtido %v", apiErr)
+		}
+	})
+
+	t.Run("deve rejeitar ordenação por campo inválido", func(t *testing.T) {
+		_, _, err := service.FindTrashPaginated(ctx, dto.PaginationRequest{Page: 1, PageSize: 10}, dto.SortRequest{Field: "senha"})
+		apiErr := apiErrors.AsAPIError(err)
+		if apiErr == nil || apiErr.Code != "INVALID_INPUT" {
+			t.Errorf("Código de erro esperado INVALID_INPUT, obtido %v", apiErr)
+		}
+	})
+}
+
+func TestProdutoService_OptimisticConcurrency(t *testing.T) {
+	ctx := context.Background()
+	mockRepo := NewMockRepository()
+	service := NewProdutoService(mockRepo, nil)
+
+	created, _ := service.Create(ctx, model.Produto{Nome: "Notebook", Preco: 3500.00})
+	if created.Version != 1 {
+		t.Fatalf("Versão inicial esperada 1, obtida %d", created.Version)
+	}
+
+	t.Run("deve incrementar a versão a cada escrita", func(t *testing.T) {
+		updated, err := service.Update(ctx, created.ID, model.Produto{Nome: "Notebook Pro", Preco: 4000}, &created.Version)
+		if err != nil {
+			t.Fatalf("Erro inesperado: %v", err)
+		}
+		if updated.Version != 2 {
+			t.Errorf("Versão esperada 2, obtida %d", updated.Version)
+		}
+
+		patched, err := service.Patch(ctx, created.ID, map[string]interface{}{"preco": 4100.0}, &updated.Version)
+		if err != nil {
+			t.Fatalf("Erro inesperado: %v", err)
+		}
+		if patched.Version != 3 {
+			t.Errorf("Versão esperada 3, obtida %d", patched.Version)
+		}
+	})
+
+	t.Run("deve retornar PRECONDITION_FAILED para versão desatualizada", func(t *testing.T) {
+		_, err := service.Update(ctx, created.ID, model.Produto{Nome: "Notebook", Preco: 3500}, intPtr(1))
+		apiErr := apiErrors.AsAPIError(err)
+		if apiErr == nil || apiErr.Code != "PRECONDITION_FAILED" {
+			t.Errorf("Código de erro esperado PRECONDITION_FAILED, obtido %v", apiErr)
+		}
+
+		_, err = service.Patch(ctx, created.ID, map[string]interface{}{"preco": 1.0}, intPtr(2))
+		apiErr = apiErrors.AsAPIError(err)
+		if apiErr == nil || apiErr.Code != "PRECONDITION_FAILED" {
+			t.Errorf("Código de erro esperado PRECONDITION_FAILED, obtido %v", apiErr)
+		}
+
+		err = service.Delete(ctx, created.ID, intPtr(2))
+		apiErr = apiErrors.AsAPIError(err)
+		if apiErr == nil || apiErr.Code != "PRECONDITION_FAILED" {
+			t.Errorf("Código de erro esperado PRECONDITION_FAILED, obtido %v", apiErr)
+		}
+	})
+
+	t.Run("deve ignorar a versão quando nenhuma é informada", func(t *testing.T) {
+		if err := service.Delete(ctx, created.ID, nil); err != nil {
+			t.Errorf("Erro inesperado: %v", err)
+		}
+	})
+}
+
+func TestProdutoService_FindAllPaginated_ETag(t *testing.T) {
+	ctx := context.Background()
+	mockRepo := NewMockRepository()
+	service := NewProdutoService(mockRepo, cache.NewMemoryCache())
+
+	_, _ = service.Create(ctx, model.Produto{Nome: "Notebook", Preco: 3500.00})
+	pagination := dto.PaginationRequest{Page: 1, PageSize: 10}
+
+	first, err := service.FindAllPaginated(ctx, pagination, dto.FilterRequest{}, dto.SortRequest{})
+	if err != nil {
+		t.Fatalf("Erro inesperado: %v", err)
+	}
+	if first.ETag == "" {
+		t.Fatal("Página deveria ter ETag")
+	}
+
+	t.Run("página servida do cache deve manter o ETag", func(t *testing.T) {
+		cached, err := service.FindAllPaginated(ctx, pagination, dto.FilterRequest{}, dto.SortRequest{})
+		if err != nil {
+			t.Fatalf("Erro inesperado: %v", err)
+		}
+		if cached.ETag != first.ETag {
+			t.Errorf("ETag esperado %s, obtido %s", first.ETag, cached.ETag)
+		}
+	})
+
+	t.Run("páginas com conteúdo diferente devem ter ETags diferentes", func(t *testing.T) {
+		other, err := service.FindAllPaginated(ctx, dto.PaginationRequest{Page: 2, PageSize: 10}, dto.FilterRequest{}, dto.SortRequest{})
+		if err != nil {
+			t.Fatalf("Erro inesperado: %v", err)
+		}
+		if other.ETag == first.ETag {
+			t.Error("ETags de páginas diferentes não deveriam coincidir")
+		}
+	})
+}
+
+func TestProdutoService_ListCacheInvalidation(t *testing.T) {
+	ctx := context.Background()
+	service := NewProdutoService(NewMockRepository(), cache.NewMemoryCache())
+	pagination := dto.PaginationRequest{Page: 1, PageSize: 10}
+
+	total := func() int {
+		t.Helper()
+		page, err := service.FindAllPaginated(ctx, pagination, dto.FilterRequest{}, dto.SortRequest{})
+		if err != nil {
+			t.Fatalf("Erro inesperado: %v", err)
+		}
+		return page.Pagination.TotalItems
+	}
+
+	created, _ := service.Create(ctx, model.Produto{Nome: "Notebook", Preco: 3500.00})
+	if n := total(); n != 1 {
+		t.Fatalf("Esperado 1 produto, obtidos %d", n)
+	}
+
+	t.Run("criação deve invalidar as páginas em cache", func(t *testing.T) {
+		_, _ = service.Create(ctx, model.Produto{Nome: "Mouse", Preco: 50.00})
+		if n := total(); n != 2 {
+			t.Errorf("Esperados 2 produtos, obtidos %d (página antiga servida do cache)", n)
+		}
+	})
+
+	t.Run("atualização deve invalidar as páginas em cache", func(t *testing.T) {
+		_, _ = service.Patch(ctx, created.ID, map[string]interface{}{"nome": "Notebook Pro"}, nil)
+		page, _ := service.FindAllPaginated(ctx, pagination, dto.FilterRequest{}, dto.SortRequest{})
+		if page.Produtos[0].Nome != "Notebook Pro" {
+			t.Errorf("Página deveria refletir a atualização: %+v", page.Produtos[0])
+		}
+	})
+
+	t.Run("remoção deve invalidar as páginas em cache", func(t *testing.T) {
+		_ = service.Delete(ctx, created.ID, nil)
+		// O mock não filtra os removidos: a página nova traz o produto marcado como removido
+		page, _ := service.FindAllPaginated(ctx, pagination, dto.FilterRequest{}, dto.SortRequest{})
+		if !page.Produtos[0].IsDeleted() {
+			t.Errorf("Página deveria refletir a remoção: %+v", page.Produtos[0])
+		}
+	})
+
+	t.Run("lote deve invalidar as páginas em cache", func(t *testing.T) {
+		_, err := service.Batch(ctx, []BatchOperation{{Op: dto.BatchOpCreate, Produto: model.Produto{Nome: "Teclado", Preco: 150.00}}}, false)
+		if err != nil {
+			t.Fatalf("Erro inesperado: %v", err)
+		}
+		if n := total(); n != 3 {
+			t.Errorf("Esperados 3 produtos, obtidos %d", n)
+		}
+	})
+}
+
+func TestProdutoService_Batch(t *testing.T) {
+	ctx := context.Background()
+	mockRepo := NewMockRepository()
+	service := NewProdutoService(mockRepo, nil)
+
+	created, _ := service.Create(ctx, model.Produto{Nome: "Notebook", Preco: 3500.00})
+
+	t.Run("deve validar cada operação individualmente", func(t *testing.T) {
+		results, err := service.Batch(ctx, []BatchOperation{
+			{Op: dto.BatchOpCreate, Produto: model.Produto{Nome: "Mouse", Preco: 150}},
+			{Op: dto.BatchOpCreate, Produto: model.Produto{Nome: "Teclado", Preco: -1}},
+			{Op: dto.BatchOpPatch, ID: created.ID, Updates: map[string]interface{}{"preco": 3200.0}},
+			{Op: dto.BatchOpDelete, ID: created.ID},
+		}, false)
+		if err != nil {
+			t.Fatalf("Erro inesperado: %v", err)
+		}
+
+		if results[0].Err != nil || results[0].Produto.ID == 0 {
+			t.Error("Criação válida deveria ser aplicada")
+		}
+		if results[1].Err != apiErrors.ErrPrecoInvalido {
+			t.Errorf("Esperado PRECO_INVALIDO, obtido %v", results[1].Err)
+		}
+		if results[2].Err != nil || results[2].Produto.Preco != 3200 {
+			t.Errorf("Patch deveria ser aplicado, obtido %v", results[2].Err)
+		}
+		if results[3].Err == nil || results[3].Err.Code != apiErrors.ErrInvalidInput.Code {
+			t.Error("Produto repetido no lote deveria retornar INVALID_INPUT")
+		}
+	})
+
+	t.Run("deve desfazer o lote atômico quando uma operação falha", func(t *testing.T) {
+		before, _ := service.FindAll(ctx)
+
+		results, err := service.Batch(ctx, []BatchOperation{
+			{Op: dto.BatchOpCreate, Produto: model.Produto{Nome: "Monitor", Preco: 900}},
+			{Op: dto.BatchOpDelete, ID: 999},
+		}, true)
+		if err != nil {
+			t.Fatalf("Erro inesperado: %v", err)
+		}
+
+		if results[0].Err != apiErrors.ErrBatchAborted {
+			t.Errorf("Esperado BATCH_ABORTED, obtido %v", results[0].Err)
+		}
+		if results[1].Err != apiErrors.ErrProdutoNotFound {
+			t.Errorf("Esperado PRODUTO_NOT_FOUND, obtido %v", results[1].Err)
+		}
+		if after, _ := service.FindAll(ctx); len(after) != len(before) {
+			t.Error("Nenhum produto deveria ser criado")
+		}
+	})
+}
+
+func TestProdutoService_FindAllByCursor(t *testing.T) {
+	ctx := context.Background()
+	mockRepo := NewMockRepository()
+	service := NewProdutoService(mockRepo, nil)
+	for i := 0; i < 3; i++ {
+		_, _ = service.Create(ctx, model.Produto{Nome: "Produto", Preco: 10})
+	}
+
+	sort := dto.SortRequest{Field: "preco", Order: "desc"}
+
+	t.Run("deve retornar nextCursor quando há mais itens", func(t *testing.T) {
+		page, err := service.FindAllByCursor(ctx, dto.CursorPaginationRequest{PageSize: 2}, dto.FilterRequest{}, sort)
+		if err != nil {
+			t.Fatalf("Erro inesperado: %v", err)
+		}
+		if len(page.Produtos) != 2 || !page.Pagination.HasNext || page.Pagination.NextCursor == "" {
+			t.Errorf("Página inesperada: %d produtos, %+v", len(page.Produtos), page.Pagination)
+		}
+		if page.ETag == "" {
+			t.Error("Página deveria ter ETag")
+		}
+
+		// O cursor só é válido para a mesma ordenação
+		_, err = service.FindAllByCursor(ctx, dto.CursorPaginationRequest{Cursor: page.Pagination.NextCursor, PageSize: 2}, dto.FilterRequest{}, dto.SortRequest{Field: "nome"})
+		if apiErr := apiErrors.AsAPIError(err); apiErr == nil || apiErr.Code != apiErrors.ErrInvalidInput.Code {
+			t.Errorf("Esperado INVALID_INPUT para cursor de outra ordenação, obtido %v", err)
+		}
+	})
+
+	t.Run("deve rejeitar cursor malformado", func(t *testing.T) {
+		_, err := service.FindAllByCursor(ctx, dto.CursorPaginationRequest{Cursor: "%%%"}, dto.FilterRequest{}, sort)
+		if apiErr := apiErrors.AsAPIError(err); apiErr == nil || apiErr.Code != apiErrors.ErrInvalidInput.Code {
+			t.Errorf("Esperado INVALID_INPUT, obtido %v", err)
+		}
+	})
+}
+
+// sortCapturingRepository registra o filtro, a ordenação e os campos recebidos pelo repositório
+type sortCapturingRepository struct {
+	repository.ProdutoRepository
+	filter map[string]interface{}
+	sort   bson.D
+	fields []string
+	finds  int
+}
+
+func (r *sortCapturingRepository) FindAllPaginated(ctx context.Context, skip, limit int64, filter map[string]interface{}, sort bson.D, fields ...string) ([]model.Produto, error) {
+	r.filter, r.sort, r.fields = filter, sort, fields
+	r.finds++
+	return r.ProdutoRepository.FindAllPaginated(ctx, skip, limit, filter, sort, fields...)
+}
+
+func (r *sortCapturingRepository) FindByID(ctx context.Context, id int, fields ...string) (model.Produto, error) {
+	r.fields = fields
+	r.finds++
+	return r.ProdutoRepository.FindByID(ctx, id, fields...)
+}
+
+func TestProdutoService_ListCacheKeyIncludesSort(t *testing.T) {
+	ctx := context.Background()
+	repo := &sortCapturingRepository{ProdutoRepository: NewMockRepository()}
+	service := NewProdutoService(repo, cache.NewMemoryCache())
+	pagination := dto.PaginationRequest{Page: 1, PageSize: 10}
+
+	asc := dto.SortRequest{Field: "preco", Order: "asc"}
+	desc := dto.SortRequest{Field: "preco", Order: "desc"}
+	for _, sort := range []dto.SortRequest{asc, desc, asc, desc} {
+		if _, err := service.FindAllPaginated(ctx, pagination, dto.FilterRequest{}, sort); err != nil {
+			t.Fatalf("Erro inesperado: %v", err)
+		}
+	}
+	if repo.finds != 2 {
+		t.Errorf("Esperadas 2 consultas ao banco (uma por ordenação), obtidas %d", repo.finds)
+	}
+}
+
+func TestProdutoService_TextSearch(t *testing.T) {
+	ctx := context.Background()
+	repo := &sortCapturingRepository{ProdutoRepository: NewMockRepository()}
+	service := NewProdutoService(repo, nil)
+	q := "notebook gamer"
+
+	t.Run("deve ordenar por relevância por padrão com q", func(t *testing.T) {
+		_, err := service.FindAllPaginated(ctx, dto.PaginationRequest{}, dto.FilterRequest{Q: &q}, dto.SortRequest{})
+		if err != nil {
+			t.Fatalf("Erro inesperado: %v", err)
+		}
+		text, ok := repo.filter["$text"].(map[string]interface{})
+		if !ok || text["$search"] != q {
+			t.Errorf("Filtro $text esperado, obtido %v", repo.filter)
+		}
+		if len(repo.sort) == 0 || repo.sort[0].Key != dto.ScoreSortField {
+			t.Errorf("Ordenação por score esperada, obtida %v", repo.sort)
+		}
+	})
+
+	t.Run("deve respeitar a ordenação explícita com q", func(t *testing.T) {
+		_, err := service.FindAllPaginated(ctx, dto.PaginationRequest{}, dto.FilterRequest{Q: &q}, dto.SortRequest{Field: "preco", Order: "asc"})
+		if err != nil {
+			t.Fatalf("Erro inesperado: %v", err)
+		}
+		if repo.sort[0].Key != "preco" {
+			t.Errorf("Ordenação por preco esperada, obtida %v", repo.sort)
+		}
+	})
+
+	t.Run("deve rejeitar score sem q e score na paginação por cursor", func(t *testing.T) {
+		_, err := service.FindAllPaginated(ctx, dto.PaginationRequest{}, dto.FilterRequest{}, dto.SortRequest{Field: dto.ScoreSortField})
+		if apiErr := apiErrors.AsAPIError(err); apiErr == nil || apiErr.Code != apiErrors.ErrInvalidInput.Code {
+			t.Errorf("Esperado INVALID_INPUT para score sem q, obtido %v", err)
+		}
+
+		_, err = service.FindAllByCursor(ctx, dto.CursorPaginationRequest{}, dto.FilterRequest{Q: &q}, dto.SortRequest{Field: dto.ScoreSortField})
+		if apiErr := apiErrors.AsAPIError(err); apiErr == nil || apiErr.Code != apiErrors.ErrInvalidInput.Code {
+			t.Errorf("Esperado INVALID_INPUT para score com cursor, obtido %v", err)
+		}
+	})
+}
+
+func TestProdutoService_FilterExpression(t *testing.T) {
+	ctx := context.Background()
+	repo := &sortCapturingRepository{ProdutoRepository: NewMockRepository()}
+	service := NewProdutoService(repo, nil)
+
+	t.Run("deve escapar a busca literal por nome", func(t *testing.T) {
+		nome := "(a+)+.*"
+		_, err := service.FindAllPaginated(ctx, dto.PaginationRequest{}, dto.FilterRequest{Nome: &nome}, dto.SortRequest{})
+		if err != nil {
+			t.Fatalf("Erro inesperado: %v", err)
+		}
+		regex := repo.filter["nome"].(map[string]interface{})["$regex"]
+		if regex != `\(a\+\)\+\.\*` {
+			t.Errorf("Regex escapada esperada, obtida %v", regex)
+		}
+	})
+
+	t.Run("deve traduzir a expressão de filtro para BSON", func(t *testing.T) {
+		expr := `preco:between:100|500,nome:startswith:Note.,created_at:gte:2024-01-01T00:00:00Z,descricao:contains:a\,b`
+		_, err := service.FindAllPaginated(ctx, dto.PaginationRequest{}, dto.FilterRequest{Filter: &expr}, dto.SortRequest{})
+		if err != nil {
+			t.Fatalf("Erro inesperado: %v", err)
+		}
+		and, ok := repo.filter["$and"].([]interface{})
+		if !ok || len(and) != 4 {
+			t.Fatalf("Esperadas 4 condições em $and, obtido %v", repo.filter)
+		}
+		if preco := and[0].(bson.M)["preco"].(bson.M); preco["$gte"] != 100.0 || preco["$lte"] != 500.0 {
+			t.Errorf("Intervalo de preço incorreto: %v", preco)
+		}
+		if nome := and[1].(bson.M)["nome"].(bson.M); nome["$regex"] != `^Note\.` {
+			t.Errorf("Prefixo escapado esperado, obtido %v", nome)
+		}
+		if _, ok := and[2].(bson.M)["created_at"].(bson.M)["$gte"].(time.Time); !ok {
+			t.Errorf("Data esperada em created_at, obtido %v", and[2])
+		}
+		if descricao := and[3].(bson.M)["descricao"].(bson.M); descricao["$regex"] != "a,b" {
+			t.Errorf("Vírgula escapada esperada no valor, obtido %v", descricao)
+		}
+	})
+
+	t.Run("deve rejeitar campos, operadores e valores inválidos", func(t *testing.T) {
+		for _, expr := range []string{
+			"estoque:gt:1",
+			"nome:gt:a",
+			"preco:regex:.*",
+			"preco:gte:abc",
+			"preco:between:100",
+			"created_at:gte:ontem",
+			"preco",
+		} {
+			expr := expr
+			_, err := service.FindAllPaginated(ctx, dto.PaginationRequest{}, dto.FilterRequest{Filter: &expr}, dto.SortRequest{})
+			if apiErr := apiErrors.AsAPIError(err); apiErr == nil || apiErr.Code != apiErrors.ErrInvalidInput.Code {
+				t.Errorf("Esperado INVALID_INPUT para %q, obtido %v", expr, err)
+			}
+		}
+	})
+}
+
+// facetsCountingRepository conta as agregações de facetas executadas no repositório
+type facetsCountingRepository struct {
+	repository.ProdutoRepository
+	calls int
+	opts  repository.FacetOptions
+}
+
+func (r *facetsCountingRepository) Facets(ctx context.Context, filter map[string]interface{}, opts repository.FacetOptions) (model.ProdutoFacets, error) {
+	r.calls++
+	r.opts = opts
+	return r.ProdutoRepository.Facets(ctx, filter, opts)
+}
+
+func TestProdutoService_Facets(t *testing.T) {
+	ctx := context.Background()
+	repo := &facetsCountingRepository{ProdutoRepository: NewMockRepository()}
+	service := NewProdutoService(repo, cache.NewMemoryCache())
+
+	for _, p := range []model.Produto{
+		{Nome: "Notebook", Preco: 3000, Tags: []string{"informatica"}},
+		{Nome: "Mouse", Preco: 100, Tags: []string{"informatica", "promocao"}},
+	} {
+		if _, err := service.Create(ctx, p); err != nil {
+			t.Fatalf("Erro ao criar produto: %v", err)
+		}
+	}
+
+	t.Run("deve calcular todas as facetas por padrão", func(t *testing.T) {
+		facets, err := service.Facets(ctx, dto.FilterRequest{}, dto.FacetRequest{})
+		if err != nil {
+			t.Fatalf("Erro inesperado: %v", err)
+		}
+		if facets.Stats == nil || facets.Stats.Count != 2 || facets.Stats.Min != 100 || facets.Stats.Max != 3000 || facets.Stats.Avg != 1550 {
+			t.Errorf("Estatísticas incorretas: %+v", facets.Stats)
+		}
+		if len(facets.Tags) != 2 || len(facets.Preco) != 1 {
+			t.Errorf("Esperadas 2 tags e 1 faixa de preço, obtido %+v", facets)
+		}
+		if !repo.opts.Stats || !repo.opts.Preco || !repo.opts.Tags || !repo.opts.Categorias || repo.opts.Buckets != dto.DefaultPrecoBuckets {
+			t.Errorf("Opções incorretas repassadas ao repositório: %+v", repo.opts)
+		}
+	})
+
+	t.Run("deve usar o cache para a mesma consulta", func(t *testing.T) {
+		calls := repo.calls
+		request := dto.FacetRequest{Facets: []string{"tags", "stats", "tags"}, Buckets: 500}
+		first, err := service.Facets(ctx, dto.FilterRequest{}, request)
+		if err != nil {
+			t.Fatalf("Erro inesperado: %v", err)
+		}
+		// Mesmas facetas em outra ordem usam a mesma chave de cache
+		second, err := service.Facets(ctx, dto.FilterRequest{}, dto.FacetRequest{Facets: []string{"stats", "tags"}, Buckets: dto.MaxPrecoBuckets})
+		if err != nil {
+			t.Fatalf("Erro inesperado: %v", err)
+		}
+		if repo.calls != calls+1 {
+			t.Errorf("Esperada 1 agregação no repositório, obtidas %d", repo.calls-calls)
+		}
+		if second.Stats == nil || *second.Stats != *first.Stats || second.Preco != nil {
+			t.Errorf("Resultado do cache diferente do original: %+v", second)
+		}
+	})
+
+	t.Run("deve rejeitar facetas e filtros inválidos", func(t *testing.T) {
+		_, err := service.Facets(ctx, dto.FilterRequest{}, dto.FacetRequest{Facets: []string{"categoria_inexistente"}})
+		if apiErr := apiErrors.AsAPIError(err); apiErr == nil || apiErr.Code != apiErrors.ErrInvalidInput.Code {
+			t.Errorf("Esperado INVALID_INPUT para faceta inválida, obtido %v", err)
+		}
+		expr := "estoque:gt:1"
+		_, err = service.Facets(ctx, dto.FilterRequest{Filter: &expr}, dto.FacetRequest{})
+		if apiErr := apiErrors.AsAPIError(err); apiErr == nil || apiErr.Code != apiErrors.ErrInvalidInput.Code {
+			t.Errorf("Esperado INVALID_INPUT para filtro inválido, obtido %v", err)
+		}
+	})
+}
+
+func TestProdutoService_CategoriaFacet(t *testing.T) {
+	ctx := context.Background()
+	categorias := NewMockCategoriaRepository()
+	service := NewProdutoServiceWithOptions(NewMockRepository(), cache.NewMemoryCache(), ProdutoServiceOptions{Categorias: categorias})
+
+	informatica, _ := categorias.Create(ctx, model.Categoria{Nome: "Informática", Slug: "informatica", Path: "informatica"})
+	for _, p := range []model.Produto{
+		{Nome: "Notebook", Preco: 3000, CategoriaID: informatica.ID},
+		{Nome: "Mouse", Preco: 100, CategoriaID: informatica.ID},
+		{Nome: "Caneta", Preco: 5},
+	} {
+		if _, err := service.Create(ctx, p); err != nil {
+			t.Fatalf("Erro ao criar produto: %v", err)
+		}
+	}
+
+	facets, err := service.Facets(ctx, dto.FilterRequest{}, dto.FacetRequest{Facets: []string{dto.FacetCategoria}})
+	if err != nil {
+		t.Fatalf("Erro inesperado: %v", err)
+	}
+	expected := []model.CategoriaFacetCount{{CategoriaID: informatica.ID, Nome: "Informática", Path: "informatica", Count: 2}}
+	if !reflect.DeepEqual(facets.Categoria, expected) {
+		t.Errorf("Faceta de categorias esperada %+v, obtida %+v", expected, facets.Categoria)
+	}
+	if facets.Stats != nil || facets.Tags != nil {
+		t.Errorf("Apenas a faceta de categorias deveria ser calculada: %+v", facets)
+	}
+}
+
+func TestProdutoService_Fields(t *testing.T) {
+	ctx := context.Background()
+	repo := &sortCapturingRepository{ProdutoRepository: NewMockRepository()}
+	service := NewProdutoService(repo, cache.NewMemoryCache())
+
+	if _, err := service.Create(ctx, model.Produto{Nome: "Notebook", Preco: 3500}); err != nil {
+		t.Fatalf("Erro ao criar produto: %v", err)
+	}
+	pagination := dto.PaginationRequest{Page: 1, PageSize: 10}
+
+	t.Run("deve repassar os campos e separar o cache por campos", func(t *testing.T) {
+		finds := repo.finds
+		if _, err := service.FindAllPaginated(ctx, pagination, dto.FilterRequest{}, dto.SortRequest{}, "id", "nome"); err != nil {
+			t.Fatalf("Erro inesperado: %v", err)
+		}
+		if len(repo.fields) != 2 || repo.fields[1] != "nome" {
+			t.Errorf("Campos não repassados ao repositório: %v", repo.fields)
+		}
+		// Mesmos campos: cache; outros campos ou nenhum: nova consulta
+		_, _ = service.FindAllPaginated(ctx, pagination, dto.FilterRequest{}, dto.SortRequest{}, "id", "nome")
+		_, _ = service.FindAllPaginated(ctx, pagination, dto.FilterRequest{}, dto.SortRequest{}, "id", "preco")
+		_, _ = service.FindAllPaginated(ctx, pagination, dto.FilterRequest{}, dto.SortRequest{})
+		if repo.finds-finds != 3 {
+			t.Errorf("Esperadas 3 consultas ao repositório, obtidas %d", repo.finds-finds)
+		}
+	})
+
+	t.Run("não deve armazenar no cache o produto lido com projeção", func(t *testing.T) {
+		finds := repo.finds
+		_, _ = service.FindByID(ctx, 1, "id", "nome")
+		_, _ = service.FindByID(ctx, 1, "id", "nome")
+		if repo.finds-finds != 2 {
+			t.Errorf("Esperadas 2 consultas com projeção, obtidas %d", repo.finds-finds)
+		}
+		// O produto completo vai para o cache e atende as consultas seguintes
+		_, _ = service.FindByID(ctx, 1)
+		_, _ = service.FindByID(ctx, 1, "id", "nome")
+		if repo.finds-finds != 3 {
+			t.Errorf("Esperadas 3 consultas no total, obtidas %d", repo.finds-finds)
+		}
+	})
+}
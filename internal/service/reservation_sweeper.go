@@ -0,0 +1,103 @@
+package service
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"api-go-arquitetura/internal/logger"
+	"api-go-arquitetura/internal/metrics"
+)
+
+// ReservationSweeper libera periodicamente as reservas de estoque expiradas
+type ReservationSweeper struct {
+	service  ProdutoService
+	interval time.Duration
+	timeout  time.Duration
+
+	mu      sync.Mutex
+	running bool
+	stop    chan struct{}
+	done    chan struct{}
+}
+
+// NewReservationSweeper cria um novo ReservationSweeper
+// interval <= 0 desabilita a execução agendada
+func NewReservationSweeper(svc ProdutoService, interval time.Duration) *ReservationSweeper {
+	return &ReservationSweeper{
+		service:  svc,
+		interval: interval,
+		timeout:  time.Minute,
+	}
+}
+
+// Start inicia a execução agendada em background
+func (s *ReservationSweeper) Start() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.running || s.interval <= 0 {
+		return
+	}
+	s.running = true
+	s.stop = make(chan struct{})
+	s.done = make(chan struct{})
+
+	go s.loop(s.stop, s.done)
+
+	logger.WithField("interval", s.interval.String()).Info("Liberação de reservas expiradas iniciada")
+}
+
+// Stop interrompe a execução agendada e aguarda a execução em andamento terminar
+func (s *ReservationSweeper) Stop() {
+	s.mu.Lock()
+	if !s.running {
+		s.mu.Unlock()
+		return
+	}
+	s.running = false
+	close(s.stop)
+	done := s.done
+	s.mu.Unlock()
+
+	<-done
+	logger.Info("Liberação de reservas expiradas encerrada")
+}
+
+// loop executa a liberação a cada intervalo até receber o sinal de parada
+func (s *ReservationSweeper) loop(stop <-chan struct{}, done chan<- struct{}) {
+	defer close(done)
+
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			ctx, cancel := context.WithTimeout(context.Background(), s.timeout)
+			_, _ = s.Run(ctx)
+			cancel()
+		}
+	}
+}
+
+// Run libera imediatamente as reservas expiradas
+func (s *ReservationSweeper) Run(ctx context.Context) (int, error) {
+	released, err := s.service.ReleaseExpired(ctx, time.Now())
+	if err != nil {
+		metrics.RecordReservationSweep("error", released)
+		logger.WithFields(map[string]interface{}{
+			"released": released,
+			"error":    err,
+		}).Error("Erro ao liberar reservas expiradas")
+		return released, err
+	}
+
+	metrics.RecordReservationSweep("success", released)
+	if released > 0 {
+		logger.WithField("released", released).Info("Reservas expiradas liberadas")
+	}
+	return released, nil
+}
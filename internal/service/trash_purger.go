@@ -0,0 +1,139 @@
+package service
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"api-go-arquitetura/internal/logger"
+	"api-go-arquitetura/internal/metrics"
+)
+
+// Origens de execução da limpeza da lixeira
+const (
+	PurgeTriggerScheduled = "scheduled"
+	PurgeTriggerManual    = "manual"
+)
+
+// PurgeResult descreve o resultado de uma execução da limpeza da lixeira
+type PurgeResult struct {
+	Purged    int64         `json:"purged"`
+	Cutoff    time.Time     `json:"cutoff"`
+	Retention time.Duration `json:"-"`
+}
+
+// TrashPurger remove periodicamente os produtos que estão na lixeira há mais tempo que a retenção
+type TrashPurger struct {
+	service   ProdutoService
+	retention time.Duration
+	interval  time.Duration
+	timeout   time.Duration
+
+	mu      sync.Mutex
+	running bool
+	stop    chan struct{}
+	done    chan struct{}
+}
+
+// NewTrashPurger cria um novo TrashPurger
+// interval <= 0 desabilita a execução agendada (a limpeza manual continua disponível)
+func NewTrashPurger(svc ProdutoService, retention, interval time.Duration) *TrashPurger {
+	return &TrashPurger{
+		service:   svc,
+		retention: retention,
+		interval:  interval,
+		timeout:   time.Minute,
+	}
+}
+
+// Retention retorna a retenção configurada
+func (p *TrashPurger) Retention() time.Duration {
+	return p.retention
+}
+
+// Start inicia a execução agendada em background
+func (p *TrashPurger) Start() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.running || p.interval <= 0 {
+		return
+	}
+	p.running = true
+	p.stop = make(chan struct{})
+	p.done = make(chan struct{})
+
+	go p.loop(p.stop, p.done)
+
+	logger.WithFields(map[string]interface{}{
+		"retention": p.retention.String(),
+		"interval":  p.interval.String(),
+	}).Info("Limpeza agendada da lixeira iniciada")
+}
+
+// Stop interrompe a execução agendada e aguarda a execução em andamento terminar
+func (p *TrashPurger) Stop() {
+	p.mu.Lock()
+	if !p.running {
+		p.mu.Unlock()
+		return
+	}
+	p.running = false
+	close(p.stop)
+	done := p.done
+	p.mu.Unlock()
+
+	<-done
+	logger.Info("Limpeza agendada da lixeira encerrada")
+}
+
+// loop executa a limpeza a cada intervalo até receber o sinal de parada
+func (p *TrashPurger) loop(stop <-chan struct{}, done chan<- struct{}) {
+	defer close(done)
+
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			ctx, cancel := context.WithTimeout(context.Background(), p.timeout)
+			_, _ = p.Run(ctx, PurgeTriggerScheduled)
+			cancel()
+		}
+	}
+}
+
+// Run executa a limpeza imediatamente usando a retenção configurada
+func (p *TrashPurger) Run(ctx context.Context, trigger string) (PurgeResult, error) {
+	return p.RunWithRetention(ctx, trigger, p.retention)
+}
+
+// RunWithRetention executa a limpeza imediatamente removendo os produtos
+// que estão na lixeira há mais tempo que retention
+func (p *TrashPurger) RunWithRetention(ctx context.Context, trigger string, retention time.Duration) (PurgeResult, error) {
+	cutoff := time.Now().Add(-retention)
+	result := PurgeResult{Cutoff: cutoff, Retention: retention}
+
+	purged, err := p.service.PurgeTrash(ctx, cutoff)
+	if err != nil {
+		metrics.RecordPurge(trigger, "error", 0)
+		logger.WithFields(map[string]interface{}{
+			"trigger": trigger,
+			"error":   err,
+		}).Error("Erro ao limpar lixeira")
+		return result, err
+	}
+
+	result.Purged = purged
+	metrics.RecordPurge(trigger, "success", purged)
+	logger.WithFields(map[string]interface{}{
+		"trigger": trigger,
+		"purged":  purged,
+		"cutoff":  cutoff,
+	}).Info("Lixeira limpa")
+
+	return result, nil
+}
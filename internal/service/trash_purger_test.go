@@ -0,0 +1,97 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"api-go-arquitetura/internal/model"
+)
+
+func TestTrashPurger_Run(t *testing.T) {
+	ctx := context.Background()
+	mockRepo := NewMockRepository()
+	service := NewProdutoService(mockRepo, nil)
+
+	antigo, _ := service.Create(ctx, model.Produto{Nome: "Antigo", Preco: 10})
+	recente, _ := service.Create(ctx, model.Produto{Nome: "Recente", Preco: 10})
+	ativo, _ := service.Create(ctx, model.Produto{Nome: "Ativo", Preco: 10})
+	_ = service.Delete(ctx, antigo.ID, nil)
+	_ = service.Delete(ctx, recente.ID, nil)
+
+	// Simular remoção antiga
+	repo := mockRepo.(*MockRepository)
+	old := time.Now().Add(-48 * time.Hour)
+	repo.produtos[0].DeletedAt = &old
+
+	purger := NewTrashPurger(service, 24*time.Hour, 0)
+
+	t.Run("deve remover apenas produtos além da retenção", func(t *testing.T) {
+		result, err := purger.Run(ctx, PurgeTriggerManual)
+		if err != nil {
+			t.Fatalf("Erro inesperado: %v", err)
+		}
+		if result.Purged != 1 {
+			t.Errorf("Esperado 1 produto removido, obtido %d", result.Purged)
+		}
+		if _, err := service.Restore(ctx, recente.ID); err != nil {
+			t.Errorf("Produto recente deveria continuar na lixeira: %v", err)
+		}
+		if _, err := service.FindByID(ctx, ativo.ID); err != nil {
+			t.Errorf("Produto ativo não deveria ser afetado: %v", err)
+		}
+	})
+
+	t.Run("deve aceitar retenção customizada", func(t *testing.T) {
+		_ = service.Delete(ctx, recente.ID, nil)
+		result, err := purger.RunWithRetention(ctx, PurgeTriggerManual, 0)
+		if err != nil {
+			t.Fatalf("Erro inesperado: %v", err)
+		}
+		if result.Purged != 1 {
+			t.Errorf("Esperado 1 produto removido, obtido %d", result.Purged)
+		}
+	})
+}
+
+// notifyingService sinaliza cada execução de PurgeTrash
+type notifyingService struct {
+	ProdutoService
+	purged chan int64
+}
+
+func (s *notifyingService) PurgeTrash(ctx context.Context, before time.Time) (int64, error) {
+	n, err := s.ProdutoService.PurgeTrash(ctx, before)
+	s.purged <- n
+	return n, err
+}
+
+func TestTrashPurger_StartStop(t *testing.T) {
+	ctx := context.Background()
+	mockRepo := NewMockRepository()
+	svc := NewProdutoService(mockRepo, nil)
+
+	created, _ := svc.Create(ctx, model.Produto{Nome: "Notebook", Preco: 10})
+	_ = svc.Delete(ctx, created.ID, nil)
+
+	notifying := &notifyingService{ProdutoService: svc, purged: make(chan int64, 100)}
+	purger := NewTrashPurger(notifying, 0, 10*time.Millisecond)
+	purger.Start()
+	purger.Start() // chamadas repetidas não devem iniciar outro worker
+
+	select {
+	case n := <-notifying.purged:
+		if n != 1 {
+			t.Errorf("Esperado 1 produto removido na primeira execução, obtido %d", n)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Limpeza agendada não executou a tempo")
+	}
+
+	purger.Stop()
+	purger.Stop() // deve ser idempotente
+
+	if count, _ := mockRepo.CountDeleted(ctx, nil); count != 0 {
+		t.Errorf("Lixeira deveria estar vazia, contém %d produtos", count)
+	}
+}
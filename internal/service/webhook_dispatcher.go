@@ -0,0 +1,56 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"api-go-arquitetura/internal/events"
+	"api-go-arquitetura/internal/model"
+	"api-go-arquitetura/internal/repository"
+)
+
+// WebhookDispatcher cria, para cada evento, as entregas dos webhooks inscritos
+// Implementa events.Publisher para ser usado pelo OutboxRelay; o envio é feito
+// pelo WebhookWorker
+type WebhookDispatcher struct {
+	webhooks   repository.WebhookRepository
+	deliveries repository.WebhookDeliveryRepository
+}
+
+// Garante em tempo de compilação que WebhookDispatcher implementa events.Publisher
+var _ events.Publisher = (*WebhookDispatcher)(nil)
+
+// NewWebhookDispatcher cria um novo WebhookDispatcher
+func NewWebhookDispatcher(webhooks repository.WebhookRepository, deliveries repository.WebhookDeliveryRepository) *WebhookDispatcher {
+	return &WebhookDispatcher{webhooks: webhooks, deliveries: deliveries}
+}
+
+// Publish cria as entregas pendentes do evento
+// Um evento publicado novamente não duplica as entregas já criadas
+func (d *WebhookDispatcher) Publish(ctx context.Context, event events.Event) error {
+	webhooks, err := d.webhooks.FindActiveByEvent(ctx, event.Type)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	deliveries := make([]model.WebhookDelivery, len(webhooks))
+	for i, webhook := range webhooks {
+		deliveries[i] = model.WebhookDelivery{
+			ID:            webhookDeliveryID(event.ID, webhook.ID),
+			WebhookID:     webhook.ID,
+			Event:         event,
+			Status:        model.DeliveryPending,
+			NextAttemptAt: now,
+			History:       []model.WebhookAttempt{},
+			CreatedAt:     now,
+		}
+	}
+	return d.deliveries.Enqueue(ctx, deliveries)
+}
+
+// webhookDeliveryID identifica a entrega do evento ao webhook
+func webhookDeliveryID(eventID string, webhookID int) string {
+	return fmt.Sprintf("%s-%d", eventID, webhookID)
+}
@@ -0,0 +1,179 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"time"
+
+	"api-go-arquitetura/internal/dto"
+	"api-go-arquitetura/internal/errors"
+	"api-go-arquitetura/internal/logger"
+	"api-go-arquitetura/internal/model"
+	"api-go-arquitetura/internal/repository"
+)
+
+// webhookService implementa a lógica de negócio para webhooks
+type webhookService struct {
+	repo       repository.WebhookRepository
+	deliveries repository.WebhookDeliveryRepository
+}
+
+// NewWebhookService cria uma nova instância do WebhookService
+func NewWebhookService(repo repository.WebhookRepository, deliveries repository.WebhookDeliveryRepository) WebhookService {
+	return &webhookService{repo: repo, deliveries: deliveries}
+}
+
+// Create cadastra o webhook, gerando o secret quando omitido
+func (s *webhookService) Create(ctx context.Context, webhook model.Webhook) (model.Webhook, error) {
+	if webhook.Secret == "" {
+		secret, err := newWebhookSecret()
+		if err != nil {
+			return model.Webhook{}, errors.WrapError(err, errors.ErrInternalServer)
+		}
+		webhook.Secret = secret
+	}
+
+	result, err := s.repo.Create(ctx, webhook)
+	if err != nil {
+		return model.Webhook{}, errors.WrapError(err, errors.ErrDatabase)
+	}
+	return result, nil
+}
+
+// FindByID retorna um webhook pelo ID
+func (s *webhookService) FindByID(ctx context.Context, id int) (model.Webhook, error) {
+	if id <= 0 {
+		return model.Webhook{}, errors.ErrInvalidID
+	}
+
+	result, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		if err.Error() == "not found" {
+			return model.Webhook{}, errors.ErrWebhookNotFound
+		}
+		return model.Webhook{}, errors.WrapError(err, errors.ErrDatabase)
+	}
+	return result, nil
+}
+
+// FindAll retorna todos os webhooks
+func (s *webhookService) FindAll(ctx context.Context) ([]model.Webhook, error) {
+	result, err := s.repo.FindAll(ctx)
+	if err != nil {
+		return nil, errors.WrapError(err, errors.ErrDatabase)
+	}
+	return result, nil
+}
+
+// Update substitui o webhook, mantendo o secret atual quando omitido
+func (s *webhookService) Update(ctx context.Context, id int, webhook model.Webhook) (model.Webhook, error) {
+	existing, err := s.FindByID(ctx, id)
+	if err != nil {
+		return model.Webhook{}, err
+	}
+	webhook.ID = id
+	if webhook.Secret == "" {
+		webhook.Secret = existing.Secret
+	}
+
+	result, err := s.repo.Update(ctx, webhook)
+	if err != nil {
+		if err.Error() == "not found" {
+			return model.Webhook{}, errors.ErrWebhookNotFound
+		}
+		return model.Webhook{}, errors.WrapError(err, errors.ErrDatabase)
+	}
+	return result, nil
+}
+
+// Delete remove o webhook e o histórico de suas entregas
+// Entregas pendentes deixam de ser enviadas
+func (s *webhookService) Delete(ctx context.Context, id int) error {
+	if _, err := s.FindByID(ctx, id); err != nil {
+		return err
+	}
+	if err := s.repo.Delete(ctx, id); err != nil {
+		if err.Error() == "not found" {
+			return errors.ErrWebhookNotFound
+		}
+		return errors.WrapError(err, errors.ErrDatabase)
+	}
+
+	removed, err := s.deliveries.DeleteByWebhook(ctx, id)
+	if err != nil {
+		return errors.WrapError(err, errors.ErrDatabase)
+	}
+
+	logger.WithFields(map[string]interface{}{
+		"webhook_id": id,
+		"deliveries": removed,
+	}).Debug("Webhook removido")
+	return nil
+}
+
+// Deliveries retorna as entregas do webhook, mais recentes primeiro
+func (s *webhookService) Deliveries(ctx context.Context, id int, status string, pagination dto.PaginationRequest) ([]model.WebhookDelivery, dto.PaginationResponse, error) {
+	switch status {
+	case "", model.DeliveryPending, model.DeliveryDelivered, model.DeliveryDead:
+	default:
+		return nil, dto.PaginationResponse{}, errors.ErrInvalidInput.WithDetails("status deve ser pending, delivered ou dead")
+	}
+	if _, err := s.FindByID(ctx, id); err != nil {
+		return nil, dto.PaginationResponse{}, err
+	}
+	pagination.Validate()
+
+	totalItems, err := s.deliveries.CountByWebhook(ctx, id, status)
+	if err != nil {
+		return nil, dto.PaginationResponse{}, errors.WrapError(err, errors.ErrDatabase)
+	}
+	deliveries, err := s.deliveries.FindByWebhook(ctx, id, status, pagination.GetSkip(), pagination.GetLimit())
+	if err != nil {
+		return nil, dto.PaginationResponse{}, errors.WrapError(err, errors.ErrDatabase)
+	}
+
+	return deliveries, dto.NewPaginationResponse(pagination.Page, pagination.PageSize, int(totalItems)), nil
+}
+
+// Redeliver agenda uma nova entrega imediata, inclusive de entregas já concluídas
+// ou esgotadas (dead); o histórico das tentativas anteriores é mantido
+func (s *webhookService) Redeliver(ctx context.Context, id int, deliveryID string) (model.WebhookDelivery, error) {
+	if _, err := s.FindByID(ctx, id); err != nil {
+		return model.WebhookDelivery{}, err
+	}
+
+	delivery, err := s.deliveries.FindByID(ctx, deliveryID)
+	if err != nil {
+		if err.Error() == "not found" {
+			return model.WebhookDelivery{}, errors.ErrWebhookDeliveryNotFound
+		}
+		return model.WebhookDelivery{}, errors.WrapError(err, errors.ErrDatabase)
+	}
+	if delivery.WebhookID != id {
+		return model.WebhookDelivery{}, errors.ErrWebhookDeliveryNotFound
+	}
+
+	result, err := s.deliveries.Redeliver(ctx, deliveryID, time.Now())
+	if err != nil {
+		if err.Error() == "not found" {
+			return model.WebhookDelivery{}, errors.ErrWebhookDeliveryNotFound
+		}
+		return model.WebhookDelivery{}, errors.WrapError(err, errors.ErrDatabase)
+	}
+
+	logger.WithFields(map[string]interface{}{
+		"webhook_id":  id,
+		"delivery_id": deliveryID,
+	}).Info("Reenvio de webhook agendado")
+	return result, nil
+}
+
+// newWebhookSecret gera um secret aleatório de 32 bytes (hex)
+func newWebhookSecret() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
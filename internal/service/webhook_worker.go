@@ -0,0 +1,322 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"api-go-arquitetura/internal/database"
+	"api-go-arquitetura/internal/logger"
+	"api-go-arquitetura/internal/metrics"
+	"api-go-arquitetura/internal/model"
+	"api-go-arquitetura/internal/repository"
+)
+
+// Headers enviados em cada entrega de webhook (além de X-Event-ID e X-Event-Type)
+const (
+	WebhookSignatureHeader = "X-Webhook-Signature" // "sha256=<hex>", ver SignWebhookPayload
+	WebhookTimestampHeader = "X-Webhook-Timestamp" // Unix (segundos) usado na assinatura
+	WebhookDeliveryHeader  = "X-Webhook-Delivery"  // ID da entrega (o mesmo em todas as tentativas)
+)
+
+// SignWebhookPayload retorna a assinatura HMAC-SHA256 de "<timestamp>.<body>"
+// com o secret do webhook, no formato "sha256=<hex>"
+// O timestamp na assinatura permite ao receptor rejeitar reenvios antigos (replay)
+func SignWebhookPayload(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// webhookLeaseMargin é somada ao pior tempo de envio de um lote na reserva das entregas
+const webhookLeaseMargin = 30 * time.Second
+
+// WebhookWorker envia periodicamente as entregas de webhook pendentes
+// Cada falha reagenda a entrega com backoff exponencial (retry.Delay); após
+// retry.MaxAttempts tentativas, a entrega vai para dead e só é reenviada manualmente
+type WebhookWorker struct {
+	webhooks      repository.WebhookRepository
+	deliveries    repository.WebhookDeliveryRepository
+	client        *http.Client
+	interval      time.Duration
+	timeout       time.Duration // Duração máxima de uma execução agendada
+	recordTimeout time.Duration // Prazo para registrar cada tentativa
+	batchSize     int
+	concurrency   int // Envios simultâneos em cada lote
+	retry         database.RetryOptions
+
+	mu      sync.Mutex
+	running bool
+	stop    chan struct{}
+	done    chan struct{}
+}
+
+// NewWebhookWorker cria um novo WebhookWorker
+// interval <= 0 desabilita a execução agendada
+func NewWebhookWorker(webhooks repository.WebhookRepository, deliveries repository.WebhookDeliveryRepository, interval time.Duration, retry database.RetryOptions) *WebhookWorker {
+	return &WebhookWorker{
+		webhooks:      webhooks,
+		deliveries:    deliveries,
+		client:        &http.Client{Timeout: 10 * time.Second},
+		interval:      interval,
+		timeout:       5 * time.Minute,
+		recordTimeout: 10 * time.Second,
+		batchSize:     50,
+		concurrency:   10,
+		retry:         retry,
+	}
+}
+
+// lease retorna por quanto tempo as entregas de um lote ficam reservadas: o
+// tempo para enviá-las se todas esgotarem o timeout do cliente, mais uma margem
+// Assim, outra instância não reenvia uma entrega que ainda está sendo enviada
+func (w *WebhookWorker) lease() time.Duration {
+	rounds := (w.batchSize + w.concurrency - 1) / w.concurrency
+	return time.Duration(rounds)*w.client.Timeout + webhookLeaseMargin
+}
+
+// Start inicia a execução agendada em background
+func (w *WebhookWorker) Start() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.running || w.interval <= 0 {
+		return
+	}
+	w.running = true
+	w.stop = make(chan struct{})
+	w.done = make(chan struct{})
+
+	go w.loop(w.stop, w.done)
+
+	logger.WithField("interval", w.interval.String()).Info("Entrega de webhooks iniciada")
+}
+
+// Stop interrompe a execução agendada e aguarda a execução em andamento terminar
+func (w *WebhookWorker) Stop() {
+	w.mu.Lock()
+	if !w.running {
+		w.mu.Unlock()
+		return
+	}
+	w.running = false
+	close(w.stop)
+	done := w.done
+	w.mu.Unlock()
+
+	<-done
+	logger.Info("Entrega de webhooks encerrada")
+}
+
+// loop envia as entregas pendentes a cada intervalo até receber o sinal de parada
+func (w *WebhookWorker) loop(stop <-chan struct{}, done chan<- struct{}) {
+	defer close(done)
+
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			ctx, cancel := context.WithTimeout(context.Background(), w.timeout)
+			_, _ = w.Run(ctx)
+			cancel()
+		}
+	}
+}
+
+// Run envia imediatamente as entregas pendentes, em lotes, até não haver mais
+// entregas disponíveis. Retorna quantas foram confirmadas pelos receptores
+// Com prazo em ctx, um novo lote só é reservado se houver tempo para enviá-lo
+func (w *WebhookWorker) Run(ctx context.Context) (int, error) {
+	delivered := 0
+	lease := w.lease()
+	webhooks := make(map[int]*model.Webhook) // Webhooks já lidos nesta execução (nil = removido)
+	for {
+		if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < lease {
+			return delivered, nil
+		}
+		deliveries, err := w.deliveries.Claim(ctx, time.Now(), lease, w.batchSize)
+		if err != nil {
+			logger.WithField("error", err).Error("Erro ao buscar entregas de webhook pendentes")
+			return delivered, err
+		}
+
+		for _, delivery := range deliveries {
+			if _, ok := webhooks[delivery.WebhookID]; ok {
+				continue
+			}
+			webhook, err := w.findWebhook(ctx, delivery.WebhookID)
+			if err != nil {
+				return delivered, err
+			}
+			webhooks[delivery.WebhookID] = webhook
+		}
+
+		n, err := w.deliverBatch(ctx, deliveries, webhooks)
+		delivered += n
+		if err != nil {
+			return delivered, err
+		}
+
+		if len(deliveries) < w.batchSize {
+			return delivered, nil
+		}
+	}
+}
+
+// deliverBatch envia as entregas do lote, até w.concurrency ao mesmo tempo
+// Retorna quantas foram confirmadas e o primeiro erro ao registrar uma tentativa
+func (w *WebhookWorker) deliverBatch(ctx context.Context, deliveries []model.WebhookDelivery, webhooks map[int]*model.Webhook) (int, error) {
+	var (
+		wg        sync.WaitGroup
+		mu        sync.Mutex
+		delivered int
+		firstErr  error
+	)
+	slots := make(chan struct{}, w.concurrency)
+	for _, delivery := range deliveries {
+		delivery, webhook := delivery, webhooks[delivery.WebhookID]
+		slots <- struct{}{}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-slots }()
+
+			status, err := w.deliver(ctx, webhook, delivery)
+			mu.Lock()
+			defer mu.Unlock()
+			switch {
+			case err != nil:
+				if firstErr == nil {
+					firstErr = err
+				}
+			case status == model.DeliveryDelivered:
+				delivered++
+			}
+		}()
+	}
+	wg.Wait()
+	return delivered, firstErr
+}
+
+// findWebhook lê o webhook da entrega (nil se foi removido)
+func (w *WebhookWorker) findWebhook(ctx context.Context, id int) (*model.Webhook, error) {
+	webhook, err := w.webhooks.FindByID(ctx, id)
+	if err != nil {
+		if err.Error() == "not found" {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &webhook, nil
+}
+
+// deliver envia a entrega e registra a tentativa. Retorna a nova situação da
+// entrega; o erro indica falha ao registrar a tentativa
+func (w *WebhookWorker) deliver(ctx context.Context, webhook *model.Webhook, delivery model.WebhookDelivery) (string, error) {
+	var attempt model.WebhookAttempt
+	switch {
+	case webhook == nil:
+		attempt = model.WebhookAttempt{At: time.Now(), Error: "webhook removido"}
+	case !webhook.Active:
+		attempt = model.WebhookAttempt{At: time.Now(), Error: "webhook inativo"}
+	default:
+		attempt = w.send(ctx, *webhook, delivery)
+	}
+
+	attempts := delivery.Attempts + 1
+	status, next := model.DeliveryDelivered, time.Time{}
+	if attempt.Error != "" {
+		status = model.DeliveryPending
+		next = time.Now().Add(w.retry.Delay(attempts))
+		// Webhooks removidos ou inativos não recebem novas tentativas automáticas
+		if attempts >= w.retry.MaxAttempts || webhook == nil || !webhook.Active {
+			status = model.DeliveryDead
+		}
+	}
+
+	// A tentativa é registrada mesmo que ctx tenha expirado durante o envio
+	recordCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), w.recordTimeout)
+	defer cancel()
+	if err := w.deliveries.RecordAttempt(recordCtx, delivery.ID, attempt, status, next); err != nil {
+		logger.WithFields(map[string]interface{}{
+			"delivery_id": delivery.ID,
+			"error":       err,
+		}).Error("Erro ao registrar tentativa de entrega de webhook")
+		return "", err
+	}
+	metrics.RecordWebhookDelivery(status)
+
+	if attempt.Error != "" {
+		fields := map[string]interface{}{
+			"webhook_id":  delivery.WebhookID,
+			"delivery_id": delivery.ID,
+			"event_type":  delivery.Event.Type,
+			"attempts":    attempts,
+			"error":       attempt.Error,
+		}
+		if status == model.DeliveryDead {
+			logger.WithFields(fields).Warn("Entrega de webhook esgotou as tentativas")
+		} else {
+			fields["next_attempt"] = next
+			logger.WithFields(fields).Debug("Erro na entrega de webhook, nova tentativa agendada")
+		}
+	}
+	return status, nil
+}
+
+// send envia o evento ao webhook com o payload assinado
+// Apenas respostas 2xx confirmam a entrega
+func (w *WebhookWorker) send(ctx context.Context, webhook model.Webhook, delivery model.WebhookDelivery) (attempt model.WebhookAttempt) {
+	start := time.Now()
+	attempt.At = start
+	defer func() {
+		attempt.DurationMs = time.Since(start).Milliseconds()
+	}()
+
+	body, err := json.Marshal(delivery.Event)
+	if err != nil {
+		attempt.Error = err.Error()
+		return attempt
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhook.URL, bytes.NewReader(body))
+	if err != nil {
+		attempt.Error = err.Error()
+		return attempt
+	}
+	timestamp := start.Unix()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Event-ID", delivery.Event.ID)
+	req.Header.Set("X-Event-Type", delivery.Event.Type)
+	req.Header.Set(WebhookDeliveryHeader, delivery.ID)
+	req.Header.Set(WebhookTimestampHeader, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(WebhookSignatureHeader, SignWebhookPayload(webhook.Secret, timestamp, body))
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		attempt.Error = err.Error()
+		return attempt
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	attempt.StatusCode = resp.StatusCode
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		attempt.Error = fmt.Sprintf("receptor respondeu com status %d", resp.StatusCode)
+	}
+	return attempt
+}
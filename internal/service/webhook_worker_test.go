@@ -0,0 +1,548 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"api-go-arquitetura/internal/database"
+	"api-go-arquitetura/internal/dto"
+	apiErrors "api-go-arquitetura/internal/errors"
+	"api-go-arquitetura/internal/events"
+	"api-go-arquitetura/internal/model"
+)
+
+// MockWebhookRepository é um mock do WebhookRepository para testes
+type MockWebhookRepository struct {
+	webhooks []model.Webhook
+	nextID   int
+}
+
+func NewMockWebhookRepository() *MockWebhookRepository {
+	return &MockWebhookRepository{nextID: 1}
+}
+
+func (m *MockWebhookRepository) Create(ctx context.Context, webhook model.Webhook) (model.Webhook, error) {
+	webhook.ID = m.nextID
+	webhook.BeforeCreate()
+	m.nextID++
+	m.webhooks = append(m.webhooks, webhook)
+	return webhook, nil
+}
+
+func (m *MockWebhookRepository) FindByID(ctx context.Context, id int) (model.Webhook, error) {
+	for _, w := range m.webhooks {
+		if w.ID == id {
+			return w, nil
+		}
+	}
+	return model.Webhook{}, errors.New("not found")
+}
+
+func (m *MockWebhookRepository) FindAll(ctx context.Context) ([]model.Webhook, error) {
+	return m.webhooks, nil
+}
+
+func (m *MockWebhookRepository) Update(ctx context.Context, webhook model.Webhook) (model.Webhook, error) {
+	for i, w := range m.webhooks {
+		if w.ID == webhook.ID {
+			webhook.CreatedAt = w.CreatedAt
+			webhook.BeforeUpdate()
+			m.webhooks[i] = webhook
+			return webhook, nil
+		}
+	}
+	return model.Webhook{}, errors.New("not found")
+}
+
+func (m *MockWebhookRepository) Delete(ctx context.Context, id int) error {
+	for i, w := range m.webhooks {
+		if w.ID == id {
+			m.webhooks = append(m.webhooks[:i], m.webhooks[i+1:]...)
+			return nil
+		}
+	}
+	return errors.New("not found")
+}
+
+func (m *MockWebhookRepository) FindActiveByEvent(ctx context.Context, eventType string) ([]model.Webhook, error) {
+	var result []model.Webhook
+	for _, w := range m.webhooks {
+		if w.Active && w.Accepts(eventType) {
+			result = append(result, w)
+		}
+	}
+	return result, nil
+}
+
+// MockWebhookDeliveryRepository é um mock do WebhookDeliveryRepository para testes
+type MockWebhookDeliveryRepository struct {
+	mu         sync.Mutex
+	deliveries map[string]*model.WebhookDelivery
+}
+
+func NewMockWebhookDeliveryRepository() *MockWebhookDeliveryRepository {
+	return &MockWebhookDeliveryRepository{deliveries: make(map[string]*model.WebhookDelivery)}
+}
+
+func (m *MockWebhookDeliveryRepository) Enqueue(ctx context.Context, deliveries []model.WebhookDelivery) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, d := range deliveries {
+		if _, ok := m.deliveries[d.ID]; !ok {
+			d := d
+			m.deliveries[d.ID] = &d
+		}
+	}
+	return nil
+}
+
+func (m *MockWebhookDeliveryRepository) Claim(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]model.WebhookDelivery, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var claimed []model.WebhookDelivery
+	for _, d := range m.sorted() {
+		if len(claimed) >= limit {
+			break
+		}
+		if d.Status == model.DeliveryPending && !d.NextAttemptAt.After(now) {
+			d.NextAttemptAt = now.Add(lease)
+			claimed = append(claimed, *d)
+		}
+	}
+	return claimed, nil
+}
+
+func (m *MockWebhookDeliveryRepository) RecordAttempt(ctx context.Context, id string, attempt model.WebhookAttempt, status string, next time.Time) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	d, ok := m.deliveries[id]
+	if !ok {
+		return errors.New("not found")
+	}
+	d.Status = status
+	d.Attempts++
+	d.History = append(d.History, attempt)
+	switch status {
+	case model.DeliveryPending:
+		d.NextAttemptAt = next
+	case model.DeliveryDelivered:
+		at := attempt.At
+		d.DeliveredAt = &at
+	}
+	return nil
+}
+
+func (m *MockWebhookDeliveryRepository) FindByID(ctx context.Context, id string) (model.WebhookDelivery, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if d, ok := m.deliveries[id]; ok {
+		return *d, nil
+	}
+	return model.WebhookDelivery{}, errors.New("not found")
+}
+
+func (m *MockWebhookDeliveryRepository) FindByWebhook(ctx context.Context, webhookID int, status string, skip, limit int64) ([]model.WebhookDelivery, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var result []model.WebhookDelivery
+	for _, d := range m.sorted() {
+		if d.WebhookID == webhookID && (status == "" || d.Status == status) {
+			result = append([]model.WebhookDelivery{*d}, result...)
+		}
+	}
+	if skip >= int64(len(result)) {
+		return []model.WebhookDelivery{}, nil
+	}
+	result = result[skip:]
+	if limit < int64(len(result)) {
+		result = result[:limit]
+	}
+	return result, nil
+}
+
+func (m *MockWebhookDeliveryRepository) CountByWebhook(ctx context.Context, webhookID int, status string) (int64, error) {
+	all, _ := m.FindByWebhook(ctx, webhookID, status, 0, 1<<31)
+	return int64(len(all)), nil
+}
+
+func (m *MockWebhookDeliveryRepository) Redeliver(ctx context.Context, id string, now time.Time) (model.WebhookDelivery, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	d, ok := m.deliveries[id]
+	if !ok {
+		return model.WebhookDelivery{}, errors.New("not found")
+	}
+	d.Status = model.DeliveryPending
+	d.Attempts = 0
+	d.NextAttemptAt = now
+	d.DeliveredAt = nil
+	return *d, nil
+}
+
+func (m *MockWebhookDeliveryRepository) DeleteByWebhook(ctx context.Context, webhookID int) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var removed int64
+	for id, d := range m.deliveries {
+		if d.WebhookID == webhookID {
+			delete(m.deliveries, id)
+			removed++
+		}
+	}
+	return removed, nil
+}
+
+// sorted retorna as entregas em ordem de criação
+func (m *MockWebhookDeliveryRepository) sorted() []*model.WebhookDelivery {
+	result := make([]*model.WebhookDelivery, 0, len(m.deliveries))
+	for _, d := range m.deliveries {
+		result = append(result, d)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if !result[i].CreatedAt.Equal(result[j].CreatedAt) {
+			return result[i].CreatedAt.Before(result[j].CreatedAt)
+		}
+		return result[i].ID < result[j].ID
+	})
+	return result
+}
+
+// webhookReceiver é um receptor de webhooks que responde com os status informados,
+// em ordem (o último se repete), e guarda as requisições recebidas
+type webhookReceiver struct {
+	*httptest.Server
+	mu       sync.Mutex
+	statuses []int
+	bodies   [][]byte
+	headers  []http.Header
+}
+
+func newWebhookReceiver(t *testing.T, statuses ...int) *webhookReceiver {
+	t.Helper()
+	rec := &webhookReceiver{statuses: statuses}
+	rec.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		rec.mu.Lock()
+		status := rec.statuses[0]
+		if len(rec.statuses) > 1 {
+			rec.statuses = rec.statuses[1:]
+		}
+		rec.bodies = append(rec.bodies, body)
+		rec.headers = append(rec.headers, r.Header.Clone())
+		rec.mu.Unlock()
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(rec.Close)
+	return rec
+}
+
+func (r *webhookReceiver) calls() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.bodies)
+}
+
+// newTestWebhookWorker cria um worker sem espera entre as tentativas
+func newTestWebhookWorker(webhooks *MockWebhookRepository, deliveries *MockWebhookDeliveryRepository, maxAttempts int) *WebhookWorker {
+	return NewWebhookWorker(webhooks, deliveries, 0, database.RetryOptions{
+		MaxAttempts: maxAttempts,
+		Multiplier:  2.0,
+	})
+}
+
+// dispatchEvent cria as entregas de um evento aos webhooks inscritos
+func dispatchEvent(t *testing.T, webhooks *MockWebhookRepository, deliveries *MockWebhookDeliveryRepository, eventType string) events.Event {
+	t.Helper()
+	event, err := events.New(context.Background(), eventType, events.AggregateProduto, 1, 1, map[string]interface{}{"id": 1})
+	if err != nil {
+		t.Fatalf("Erro ao criar evento: %v", err)
+	}
+	if err := NewWebhookDispatcher(webhooks, deliveries).Publish(context.Background(), event); err != nil {
+		t.Fatalf("Erro ao criar entregas: %v", err)
+	}
+	return event
+}
+
+func TestWebhookDispatcher_Publish(t *testing.T) {
+	ctx := context.Background()
+	webhooks := NewMockWebhookRepository()
+	deliveries := NewMockWebhookDeliveryRepository()
+	_, _ = webhooks.Create(ctx, model.Webhook{URL: "http://a", Events: []string{events.PrecoAlterado}, Active: true})
+	_, _ = webhooks.Create(ctx, model.Webhook{URL: "http://b", Events: []string{model.WebhookAllEvents}, Active: true})
+	_, _ = webhooks.Create(ctx, model.Webhook{URL: "http://c", Events: []string{events.ProdutoCriado}, Active: true})
+	_, _ = webhooks.Create(ctx, model.Webhook{URL: "http://d", Events: []string{model.WebhookAllEvents}, Active: false})
+
+	event := dispatchEvent(t, webhooks, deliveries, events.PrecoAlterado)
+	if len(deliveries.deliveries) != 2 {
+		t.Fatalf("Esperadas 2 entregas (webhooks 1 e 2), obtidas %d", len(deliveries.deliveries))
+	}
+
+	// Publicar o mesmo evento novamente não duplica as entregas
+	if err := NewWebhookDispatcher(webhooks, deliveries).Publish(ctx, event); err != nil {
+		t.Fatalf("Erro inesperado: %v", err)
+	}
+	if len(deliveries.deliveries) != 2 {
+		t.Errorf("Evento publicado novamente não deve duplicar entregas: %d", len(deliveries.deliveries))
+	}
+}
+
+func TestWebhookWorker_Run(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("deve enviar o payload assinado com HMAC-SHA256", func(t *testing.T) {
+		receiver := newWebhookReceiver(t, http.StatusOK)
+		webhooks := NewMockWebhookRepository()
+		deliveries := NewMockWebhookDeliveryRepository()
+		webhook, _ := webhooks.Create(ctx, model.Webhook{URL: receiver.URL, Events: []string{"*"}, Secret: "segredo-do-parceiro", Active: true})
+		event := dispatchEvent(t, webhooks, deliveries, events.ProdutoCriado)
+
+		delivered, err := newTestWebhookWorker(webhooks, deliveries, 3).Run(ctx)
+		if err != nil || delivered != 1 {
+			t.Fatalf("Esperada 1 entrega, obtidas %d (%v)", delivered, err)
+		}
+
+		header := receiver.headers[0]
+		timestamp, err := strconv.ParseInt(header.Get(WebhookTimestampHeader), 10, 64)
+		if err != nil {
+			t.Fatalf("Timestamp inválido: %q", header.Get(WebhookTimestampHeader))
+		}
+		if want := SignWebhookPayload(webhook.Secret, timestamp, receiver.bodies[0]); header.Get(WebhookSignatureHeader) != want {
+			t.Errorf("Assinatura esperada %s, obtida %s", want, header.Get(WebhookSignatureHeader))
+		}
+		if header.Get("X-Event-ID") != event.ID || header.Get("X-Event-Type") != events.ProdutoCriado {
+			t.Errorf("Headers do evento incorretos: %v", header)
+		}
+
+		delivery, _ := deliveries.FindByID(ctx, header.Get(WebhookDeliveryHeader))
+		if delivery.Status != model.DeliveryDelivered || delivery.DeliveredAt == nil || len(delivery.History) != 1 || delivery.History[0].StatusCode != http.StatusOK {
+			t.Errorf("Entrega deveria estar concluída: %+v", delivery)
+		}
+	})
+
+	t.Run("deve reagendar falhas e ir para dead após o máximo de tentativas", func(t *testing.T) {
+		receiver := newWebhookReceiver(t, http.StatusInternalServerError)
+		webhooks := NewMockWebhookRepository()
+		deliveries := NewMockWebhookDeliveryRepository()
+		_, _ = webhooks.Create(ctx, model.Webhook{URL: receiver.URL, Events: []string{"*"}, Secret: "s", Active: true})
+		dispatchEvent(t, webhooks, deliveries, events.ProdutoRemovido)
+		worker := newTestWebhookWorker(webhooks, deliveries, 3)
+
+		for i := 1; i <= 3; i++ {
+			if delivered, err := worker.Run(ctx); err != nil || delivered != 0 {
+				t.Fatalf("Nenhuma entrega deveria ser confirmada: %d (%v)", delivered, err)
+			}
+		}
+		if receiver.calls() != 3 {
+			t.Errorf("Esperadas 3 tentativas, obtidas %d", receiver.calls())
+		}
+		delivery := deliveries.sorted()[0]
+		if delivery.Status != model.DeliveryDead || delivery.Attempts != 3 || len(delivery.History) != 3 {
+			t.Fatalf("Entrega deveria estar dead após 3 tentativas: %+v", delivery)
+		}
+		if delivery.History[0].StatusCode != http.StatusInternalServerError || delivery.History[0].Error == "" {
+			t.Errorf("Tentativa deveria registrar status e erro: %+v", delivery.History[0])
+		}
+
+		if _, err := worker.Run(ctx); err != nil || receiver.calls() != 3 {
+			t.Errorf("Entregas dead não devem ser enviadas novamente: %d (%v)", receiver.calls(), err)
+		}
+	})
+
+	t.Run("deve aplicar backoff exponencial entre as tentativas", func(t *testing.T) {
+		receiver := newWebhookReceiver(t, http.StatusServiceUnavailable)
+		webhooks := NewMockWebhookRepository()
+		deliveries := NewMockWebhookDeliveryRepository()
+		_, _ = webhooks.Create(ctx, model.Webhook{URL: receiver.URL, Events: []string{"*"}, Secret: "s", Active: true})
+		dispatchEvent(t, webhooks, deliveries, events.ProdutoCriado)
+		worker := NewWebhookWorker(webhooks, deliveries, 0, database.RetryOptions{
+			MaxAttempts:  5,
+			InitialDelay: time.Hour,
+			MaxDelay:     4 * time.Hour,
+			Multiplier:   2.0,
+		})
+
+		_, _ = worker.Run(ctx)
+		delivery := deliveries.sorted()[0]
+		if wait := time.Until(delivery.NextAttemptAt); wait < 59*time.Minute || wait > time.Hour {
+			t.Errorf("Próxima tentativa esperada em ~1h, obtida em %v", wait)
+		}
+		if _, _ = worker.Run(ctx); receiver.calls() != 1 {
+			t.Errorf("Entrega não deve ser reenviada antes da próxima tentativa: %d envios", receiver.calls())
+		}
+	})
+
+	t.Run("não deve enviar entregas de webhooks inativos", func(t *testing.T) {
+		receiver := newWebhookReceiver(t, http.StatusOK)
+		webhooks := NewMockWebhookRepository()
+		deliveries := NewMockWebhookDeliveryRepository()
+		webhook, _ := webhooks.Create(ctx, model.Webhook{URL: receiver.URL, Events: []string{"*"}, Secret: "s", Active: true})
+		dispatchEvent(t, webhooks, deliveries, events.ProdutoCriado)
+		webhook.Active = false
+		_, _ = webhooks.Update(ctx, webhook)
+
+		if _, err := newTestWebhookWorker(webhooks, deliveries, 3).Run(ctx); err != nil {
+			t.Fatalf("Erro inesperado: %v", err)
+		}
+		if receiver.calls() != 0 {
+			t.Errorf("Webhook inativo não deveria receber entregas: %d", receiver.calls())
+		}
+		if delivery := deliveries.sorted()[0]; delivery.Status != model.DeliveryDead {
+			t.Errorf("Entrega de webhook inativo deveria ir para dead: %+v", delivery)
+		}
+	})
+}
+
+func TestWebhookWorker_Concurrency(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("deve enviar o lote com concorrência limitada", func(t *testing.T) {
+		var mu sync.Mutex
+		inFlight, maxInFlight := 0, 0
+		receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			mu.Lock()
+			inFlight++
+			if inFlight > maxInFlight {
+				maxInFlight = inFlight
+			}
+			mu.Unlock()
+			time.Sleep(20 * time.Millisecond)
+			mu.Lock()
+			inFlight--
+			mu.Unlock()
+		}))
+		t.Cleanup(receiver.Close)
+
+		webhooks := NewMockWebhookRepository()
+		deliveries := NewMockWebhookDeliveryRepository()
+		for i := 0; i < 6; i++ {
+			_, _ = webhooks.Create(ctx, model.Webhook{URL: receiver.URL, Events: []string{"*"}, Secret: "s", Active: true})
+		}
+		dispatchEvent(t, webhooks, deliveries, events.ProdutoCriado)
+		worker := newTestWebhookWorker(webhooks, deliveries, 3)
+		worker.concurrency = 2
+
+		delivered, err := worker.Run(ctx)
+		if err != nil || delivered != 6 {
+			t.Fatalf("Esperadas 6 entregas, obtidas %d (%v)", delivered, err)
+		}
+		if maxInFlight != 2 {
+			t.Errorf("Esperados 2 envios simultâneos, obtidos %d", maxInFlight)
+		}
+	})
+
+	t.Run("a reserva deve cobrir o envio do lote inteiro", func(t *testing.T) {
+		worker := newTestWebhookWorker(NewMockWebhookRepository(), NewMockWebhookDeliveryRepository(), 3)
+		rounds := (worker.batchSize + worker.concurrency - 1) / worker.concurrency
+		if lease := worker.lease(); lease <= time.Duration(rounds)*worker.client.Timeout {
+			t.Errorf("Reserva de %v não cobre %d envios de até %v", lease, rounds, worker.client.Timeout)
+		}
+	})
+
+	t.Run("deve registrar a tentativa mesmo com o contexto cancelado durante o envio", func(t *testing.T) {
+		runCtx, cancel := context.WithCancel(ctx)
+		defer cancel()
+		receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			cancel()
+			select {
+			case <-r.Context().Done():
+			case <-time.After(100 * time.Millisecond):
+			}
+		}))
+		t.Cleanup(receiver.Close)
+
+		webhooks := NewMockWebhookRepository()
+		deliveries := NewMockWebhookDeliveryRepository()
+		_, _ = webhooks.Create(ctx, model.Webhook{URL: receiver.URL, Events: []string{"*"}, Secret: "s", Active: true})
+		dispatchEvent(t, webhooks, deliveries, events.ProdutoCriado)
+
+		if _, err := newTestWebhookWorker(webhooks, deliveries, 3).Run(runCtx); err != nil {
+			t.Fatalf("Erro inesperado: %v", err)
+		}
+		delivery := deliveries.sorted()[0]
+		if delivery.Status != model.DeliveryPending || delivery.Attempts != 1 || delivery.History[0].Error == "" {
+			t.Errorf("A tentativa interrompida deveria ser registrada e reagendada: %+v", delivery)
+		}
+	})
+}
+
+func TestWebhookService(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("deve gerar o secret e mantê-lo na atualização", func(t *testing.T) {
+		svc := NewWebhookService(NewMockWebhookRepository(), NewMockWebhookDeliveryRepository())
+		created, err := svc.Create(ctx, model.Webhook{URL: "http://a", Events: []string{"*"}, Active: true})
+		if err != nil || len(created.Secret) != 64 {
+			t.Fatalf("Secret de 64 caracteres esperado, obtido %q (%v)", created.Secret, err)
+		}
+		updated, err := svc.Update(ctx, created.ID, model.Webhook{URL: "http://b", Events: []string{"*"}})
+		if err != nil || updated.Secret != created.Secret || updated.URL != "http://b" {
+			t.Errorf("Secret deveria ser mantido: %+v (%v)", updated, err)
+		}
+		if _, err := svc.FindByID(ctx, 99); err != apiErrors.ErrWebhookNotFound {
+			t.Errorf("ErrWebhookNotFound esperado, obtido %v", err)
+		}
+	})
+
+	t.Run("deve reenviar entregas dead e listar o histórico", func(t *testing.T) {
+		receiver := newWebhookReceiver(t, http.StatusBadGateway, http.StatusNoContent)
+		webhooks := NewMockWebhookRepository()
+		deliveries := NewMockWebhookDeliveryRepository()
+		svc := NewWebhookService(webhooks, deliveries)
+		webhook, _ := svc.Create(ctx, model.Webhook{URL: receiver.URL, Events: []string{"*"}, Active: true})
+		other, _ := svc.Create(ctx, model.Webhook{URL: "http://outro", Events: []string{events.ProdutoCriado}, Active: true})
+		dispatchEvent(t, webhooks, deliveries, events.PrecoAlterado)
+		worker := newTestWebhookWorker(webhooks, deliveries, 1)
+
+		_, _ = worker.Run(ctx)
+		dead, pagination, err := svc.Deliveries(ctx, webhook.ID, model.DeliveryDead, dto.PaginationRequest{Page: 1, PageSize: 10})
+		if err != nil || len(dead) != 1 || pagination.TotalItems != 1 {
+			t.Fatalf("Esperada 1 entrega dead, obtidas %d (%v)", len(dead), err)
+		}
+
+		if _, err := svc.Redeliver(ctx, other.ID, dead[0].ID); err != apiErrors.ErrWebhookDeliveryNotFound {
+			t.Errorf("Entrega de outro webhook não deve ser reenviada: %v", err)
+		}
+		redelivered, err := svc.Redeliver(ctx, webhook.ID, dead[0].ID)
+		if err != nil || redelivered.Status != model.DeliveryPending || redelivered.Attempts != 0 {
+			t.Fatalf("Entrega deveria voltar para pendente: %+v (%v)", redelivered, err)
+		}
+
+		if delivered, err := worker.Run(ctx); err != nil || delivered != 1 {
+			t.Fatalf("Esperada 1 entrega após o reenvio, obtidas %d (%v)", delivered, err)
+		}
+		delivery, _ := deliveries.FindByID(ctx, dead[0].ID)
+		if delivery.Status != model.DeliveryDelivered || len(delivery.History) != 2 {
+			t.Errorf("Histórico deveria manter as 2 tentativas: %+v", delivery)
+		}
+
+		if _, _, err := svc.Deliveries(ctx, webhook.ID, "falhou", dto.PaginationRequest{}); err == nil {
+			t.Error("Status inválido deveria ser rejeitado")
+		}
+	})
+
+	t.Run("deve remover o histórico de entregas com o webhook", func(t *testing.T) {
+		webhooks := NewMockWebhookRepository()
+		deliveries := NewMockWebhookDeliveryRepository()
+		svc := NewWebhookService(webhooks, deliveries)
+		webhook, _ := svc.Create(ctx, model.Webhook{URL: "http://a", Events: []string{"*"}, Active: true})
+		dispatchEvent(t, webhooks, deliveries, events.ProdutoCriado)
+
+		if err := svc.Delete(ctx, webhook.ID); err != nil {
+			t.Fatalf("Erro inesperado: %v", err)
+		}
+		if len(deliveries.deliveries) != 0 {
+			t.Errorf("Entregas do webhook deveriam ser removidas: %d", len(deliveries.deliveries))
+		}
+	})
+}
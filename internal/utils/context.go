@@ -0,0 +1,56 @@
+package utils
+
+import "context"
+
+// contextKey é o tipo das chaves de contexto deste pacote
+type contextKey string
+
+const (
+	requestIDKey contextKey = "request_id"
+	actorKey     contextKey = "actor"
+	claimedKey   contextKey = "claimed_actor"
+)
+
+// WithRequestID retorna uma cópia do contexto com o request ID da requisição
+// As camadas de serviço e repositório o leem com RequestIDFromContext
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	if requestID == "" {
+		return ctx
+	}
+	return context.WithValue(ctx, requestIDKey, requestID)
+}
+
+// RequestIDFromContext retorna o request ID do contexto (vazio se ausente)
+func RequestIDFromContext(ctx context.Context) string {
+	requestID, _ := ctx.Value(requestIDKey).(string)
+	return requestID
+}
+
+// WithActor retorna uma cópia do contexto com o autor da requisição
+func WithActor(ctx context.Context, actor string) context.Context {
+	if actor == "" {
+		return ctx
+	}
+	return context.WithValue(ctx, actorKey, actor)
+}
+
+// ActorFromContext retorna o autor da requisição (vazio se ausente)
+func ActorFromContext(ctx context.Context) string {
+	actor, _ := ctx.Value(actorKey).(string)
+	return actor
+}
+
+// WithClaimedActor retorna uma cópia do contexto com o autor declarado pelo
+// cliente, que não foi verificado
+func WithClaimedActor(ctx context.Context, actor string) context.Context {
+	if actor == "" {
+		return ctx
+	}
+	return context.WithValue(ctx, claimedKey, actor)
+}
+
+// ClaimedActorFromContext retorna o autor declarado pelo cliente (vazio se ausente)
+func ClaimedActorFromContext(ctx context.Context) string {
+	actor, _ := ctx.Value(claimedKey).(string)
+	return actor
+}
@@ -54,6 +54,10 @@ func getErrorMessage(err validator.FieldError) string {
 		return fmt.Sprintf("O campo '%s' deve ser maior que %s", toLowerFirst(field), param)
 	case "lt":
 		return fmt.Sprintf("O campo '%s' deve ser menor que %s", toLowerFirst(field), param)
+	case "oneof":
+		return fmt.Sprintf("O campo '%s' deve ser um dos valores: %s", toLowerFirst(field), param)
+	case "http_url":
+		return fmt.Sprintf("O campo '%s' deve ser uma URL http ou https", toLowerFirst(field))
 	default:
 		return fmt.Sprintf("O campo '%s' é inválido", toLowerFirst(field))
 	}